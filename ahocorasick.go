@@ -0,0 +1,115 @@
+package glob
+
+// acAutomaton is an Aho–Corasick automaton finding in a single pass the
+// indexes of patterns, the required literals of which a string contains.
+type acAutomaton struct {
+	states []acState
+}
+
+type acState struct {
+	next map[byte]int
+	fail int
+
+	// ends are the indexes of patterns, the literals of which end at the
+	// state, and out are the same including those reached by the fail
+	// links.
+	ends []int
+	out  []int
+}
+
+// add adds the literal of the i-th pattern to the automaton. The automaton
+// must be built again before it is used.
+func (a *acAutomaton) add(lit string, i int) {
+	if len(a.states) == 0 {
+		a.states = append(a.states, acState{})
+	}
+	var s int
+	for j := 0; j < len(lit); j++ {
+		n, ok := a.states[s].next[lit[j]]
+		if !ok {
+			n = len(a.states)
+			a.states = append(a.states, acState{})
+			if a.states[s].next == nil {
+				a.states[s].next = make(map[byte]int)
+			}
+			a.states[s].next[lit[j]] = n
+		}
+		s = n
+	}
+	a.states[s].ends = append(a.states[s].ends, i)
+}
+
+// remove removes the i-th pattern with the literal from the automaton and
+// decrements indexes of the following patterns. The automaton must be built
+// again before it is used.
+func (a *acAutomaton) remove(lit string, i int) {
+	if len(a.states) == 0 {
+		return
+	}
+	var s int
+	for j := 0; j < len(lit); j++ {
+		s = a.states[s].next[lit[j]]
+	}
+	a.states[s].ends = removeIndex(a.states[s].ends, i)
+	for j := range a.states {
+		shiftIndexes(a.states[j].ends, i)
+	}
+}
+
+// build computes the fail links of the automaton. It could be called again
+// after adding or removing patterns.
+func (a *acAutomaton) build() {
+	if len(a.states) == 0 {
+		return
+	}
+	for j := range a.states {
+		a.states[j].out = append(a.states[j].out[:0], a.states[j].ends...)
+	}
+	queue := make([]int, 0, len(a.states))
+	for _, n := range a.states[0].next {
+		queue = append(queue, n)
+	}
+	for len(queue) > 0 {
+		s := queue[0]
+		queue = queue[1:]
+		for c, n := range a.states[s].next {
+			f := a.states[s].fail
+			for {
+				if m, ok := a.states[f].next[c]; ok {
+					f = m
+					break
+				}
+				if f == 0 {
+					break
+				}
+				f = a.states[f].fail
+			}
+			a.states[n].fail = f
+			a.states[n].out = append(a.states[n].out, a.states[f].out...)
+			queue = append(queue, n)
+		}
+	}
+}
+
+// find appends to buf the indexes of patterns, the literals of which s
+// contains. Indexes could be repeated and are not ordered.
+func (a *acAutomaton) find(s string, buf []int) []int {
+	if len(a.states) == 0 {
+		return buf
+	}
+	var state int
+	for i := 0; i < len(s); i++ {
+		for {
+			if n, ok := a.states[state].next[s[i]]; ok {
+				state = n
+				break
+			}
+			if state == 0 {
+				break
+			}
+			state = a.states[state].fail
+		}
+		buf = append(buf, a.states[state].out...)
+	}
+	return buf
+}
@@ -0,0 +1,39 @@
+package glob
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestAhoCorasick(t *testing.T) {
+	var a acAutomaton
+	for i, lit := range []string{"he", "she", "his", "hers", "e"} {
+		a.add(lit, i)
+	}
+	a.build()
+	for _, test := range []struct {
+		s     string
+		found []int
+	}{
+		{"", nil},
+		{"x", nil},
+		{"ushers", []int{0, 1, 3, 4}},
+		{"this", []int{2}},
+		{"hhe", []int{0, 4}},
+	} {
+		found := a.find(test.s, nil)
+		seen := make(map[int]bool)
+		var act []int
+		for _, i := range found {
+			if !seen[i] {
+				seen[i] = true
+				act = append(act, i)
+			}
+		}
+		sort.Ints(act)
+		if !reflect.DeepEqual(act, test.found) {
+			t.Errorf("find(%q) = %v; want %v", test.s, act, test.found)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package glob
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"strings"
+)
+
+// ArchiveName returns the name of an archive entry normalized for matching.
+// Archivers often write names like "./dir/" for directories, so leading
+// "./" and '/' and trailing '/' are removed, giving "dir".
+func ArchiveName(name string) string {
+	for {
+		switch {
+		case strings.HasPrefix(name, "./"):
+			name = name[2:]
+		case strings.HasPrefix(name, "/"):
+			name = name[1:]
+		default:
+			return strings.TrimRight(name, "/")
+		}
+	}
+}
+
+// FilterZip returns files of the zip archive, the normalized names of which
+// match any of the globs. See ArchiveName for details.
+func FilterZip(r *zip.Reader, globs ...Glob) []*zip.File {
+	var files []*zip.File
+	for _, f := range r.File {
+		if matchArchiveName(f.Name, globs) {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// TarFilter reads entries of the tar archive, the normalized names of which
+// match any of the globs. See ArchiveName for details.
+type TarFilter struct {
+	r     *tar.Reader
+	globs []Glob
+}
+
+// NewTarFilter returns TarFilter reading from r.
+func NewTarFilter(r *tar.Reader, globs ...Glob) *TarFilter {
+	return &TarFilter{r, globs}
+}
+
+// Next advances to the next matching entry of the archive and returns its
+// header. At the end of the archive it returns io.EOF.
+func (f *TarFilter) Next() (*tar.Header, error) {
+	for {
+		h, err := f.r.Next()
+		if err != nil {
+			return nil, err
+		}
+		if matchArchiveName(h.Name, f.globs) {
+			return h, nil
+		}
+	}
+}
+
+// Read reads from the current entry of the archive.
+func (f *TarFilter) Read(b []byte) (int, error) {
+	return f.r.Read(b)
+}
+
+func matchArchiveName(name string, globs []Glob) bool {
+	name = ArchiveName(name)
+	if name == "" {
+		return false
+	}
+	for _, g := range globs {
+		if g.Match(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package glob
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestArchiveName(t *testing.T) {
+	for _, test := range []struct {
+		name, exp string
+	}{
+		{"a/b.go", "a/b.go"},
+		{"./a/b/", "a/b"},
+		{"/a", "a"},
+		{"././/a//", "a"},
+		{"./", ""},
+	} {
+		if act := ArchiveName(test.name); act != test.exp {
+			t.Errorf("ArchiveName(%q) = %q; want %q", test.name, act, test.exp)
+		}
+	}
+}
+
+func TestFilterArchive(t *testing.T) {
+	names := []string{"./", "./src/", "./src/a.go", "./src/a_test.go", "./docs/", "./docs/readme.md", "/abs.go"}
+	globs := []Glob{
+		MustCompile("src/*.go", '/'),
+		MustCompile("docs", '/'),
+		MustCompile("*.go", '/'),
+	}
+	exp := []string{"./src/a.go", "./src/a_test.go", "./docs/", "/abs.go"}
+
+	var zbuf bytes.Buffer
+	zw := zip.NewWriter(&zbuf)
+	for _, name := range names {
+		if _, err := zw.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(zbuf.Bytes()), int64(zbuf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var act []string
+	for _, f := range FilterZip(zr, globs...) {
+		act = append(act, f.Name)
+	}
+	if strings.Join(act, " ") != strings.Join(exp, " ") {
+		t.Errorf("FilterZip() = %q; want %q", act, exp)
+	}
+
+	var tbuf bytes.Buffer
+	tw := tar.NewWriter(&tbuf)
+	for _, name := range names {
+		h := &tar.Header{Name: name, Mode: 0644, Size: int64(len(name))}
+		if strings.HasSuffix(name, "/") {
+			h.Typeflag, h.Size = tar.TypeDir, 0
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(name[:h.Size])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	act = nil
+	f := NewTarFilter(tar.NewReader(&tbuf), globs...)
+	for {
+		h, err := f.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := ioutil.ReadAll(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != h.Name[:h.Size] {
+			t.Errorf("unexpected contents of %q: %q", h.Name, data)
+		}
+		act = append(act, h.Name)
+	}
+	if strings.Join(act, " ") != strings.Join(exp, " ") {
+		t.Errorf("TarFilter read %q; want %q", act, exp)
+	}
+}
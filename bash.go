@@ -0,0 +1,138 @@
+package glob
+
+import (
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// BashOption is a set of bash shell options affecting pathname expansion.
+type BashOption int
+
+// Shell options, as set by the shopt builtin of bash.
+const (
+	// BashExtglob enables extended patterns `@(a|b)`, `?(a|b)`, `*(a|b)`
+	// and `+(a|b)`, which match one, at most one, any number and at least
+	// one of the alternatives respectively, and `!(a|b)`, which matches
+	// anything within a file name except the alternatives.
+	BashExtglob BashOption = 1 << iota
+
+	// BashGlobstar makes `**` path segment match any number of
+	// directories, including none. Otherwise it is the same as `*`.
+	BashGlobstar
+
+	// BashNocaseglob enables case-insensitive matching.
+	BashNocaseglob
+
+	// BashDotglob makes wildcards and character classes match period at
+	// the beginning of a file name.
+	BashDotglob
+
+	// BashNullglob makes patterns which match nothing expand to nothing.
+	// It does not affect matching of a single path.
+	BashNullglob
+)
+
+// WithBash sets DialectBash with given shell options. Other options
+// affecting the dialect, like WithCaseFold or WithLeadingPeriod, should not
+// be used along with it.
+func WithBash(opts BashOption) Option {
+	return func(o *options) {
+		o.dialect = DialectBash
+		o.bash = opts
+		o.extglob = opts&BashExtglob != 0
+		o.caseFold = opts&BashNocaseglob != 0
+		o.period = opts&BashDotglob == 0
+	}
+}
+
+// WithBashDefaults sets DialectBash with all shell options disabled, as bash
+// does by default.
+func WithBashDefaults() Option {
+	return WithBash(0)
+}
+
+// bashTree returns syntax tree matching the same paths as the pattern does
+// in pathname expansion of bash. See DialectBash for details.
+func bashTree(pattern string, opts BashOption) (*ast.Node, error) {
+	sh := shell{
+		seps:    []rune{'/'},
+		extglob: opts&BashExtglob != 0,
+	}
+	tree := ast.NewNode(ast.KindPattern, nil)
+	if opts&BashGlobstar == 0 {
+		nodes, err := sh.nodes(pattern)
+		if err != nil {
+			return nil, err
+		}
+		ast.Insert(tree, nodes...)
+		return tree, nil
+	}
+
+	var segments []string
+	for _, s := range bashSegments(pattern) {
+		// Consecutive `**` segments are the same as one.
+		if s == "**" && len(segments) > 0 && segments[len(segments)-1] == "**" {
+			continue
+		}
+		segments = append(segments, s)
+	}
+	var dirs bool // previous segment was `**`
+	for i, s := range segments {
+		if s == "**" {
+			switch {
+			case len(segments) == 1:
+				ast.Insert(tree, ast.NewNode(ast.KindSuper, nil))
+			case i == len(segments)-1:
+				// Trailing `/**` matches everything inside of the
+				// directory.
+				ast.Insert(tree,
+					ast.NewNode(ast.KindText, ast.Text{Text: "/"}),
+					ast.NewNode(ast.KindSuper, nil),
+				)
+			case i == 0:
+				ast.Insert(tree, anyDirs())
+			default:
+				ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: "/"}), anyDirs())
+			}
+			dirs = true
+			continue
+		}
+		if i > 0 && !dirs {
+			ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: "/"}))
+		}
+		nodes, err := sh.nodes(s)
+		if err != nil {
+			return nil, err
+		}
+		ast.Insert(tree, nodes...)
+		dirs = false
+	}
+	return tree, nil
+}
+
+// bashSegments splits the pattern into path segments. Separators inside of
+// parentheses of extended patterns do not split it.
+func bashSegments(pattern string) []string {
+	var (
+		segments []string
+		depth    int
+		start    int
+	)
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case '/':
+			if depth == 0 {
+				segments = append(segments, pattern[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(segments, pattern[start:])
+}
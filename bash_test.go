@@ -0,0 +1,208 @@
+package glob
+
+import "testing"
+
+func TestDialectBash(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    BashOption
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: `*.go`,
+			match:   []string{"a.go", "a.b.go"},
+			miss:    []string{".a.go", "x/a.go", "a.GO"},
+		},
+		{
+			pattern: `*/[!a]*`,
+			match:   []string{"x/b", "x/bc"},
+			miss:    []string{"x/a", "x/.b", ".x/b", "x/y/b"},
+		},
+		{
+			pattern: `*.go`,
+			opts:    BashDotglob | BashNocaseglob,
+			match:   []string{"a.go", ".a.go", "A.GO"},
+			miss:    []string{"x/a.go"},
+		},
+		{
+			pattern: `[!a-c]x`,
+			opts:    BashNocaseglob,
+			match:   []string{"dX"},
+			miss:    []string{"bx", "Bx", "/x"},
+		},
+		{
+			pattern: `**/*.go`,
+			match:   []string{"x/a.go"},
+			miss:    []string{"a.go", "x/y/a.go"},
+		},
+		{
+			pattern: `**/*.go`,
+			opts:    BashGlobstar,
+			match:   []string{"a.go", "x/a.go", "x/y/a.go"},
+			miss:    []string{"x/.a.go", "a.c"},
+		},
+		{
+			pattern: `a/**/**/b`,
+			opts:    BashGlobstar,
+			match:   []string{"a/b", "a/x/b", "a/x/y/b"},
+			miss:    []string{"ab", "a/xb"},
+		},
+		{
+			pattern: `a/**`,
+			opts:    BashGlobstar,
+			match:   []string{"a/", "a/x", "a/x/y"},
+			miss:    []string{"a", "b/x"},
+		},
+		{
+			pattern: `a**b`,
+			opts:    BashGlobstar,
+			match:   []string{"ab", "axyb"},
+			miss:    []string{"a/b"},
+		},
+		{
+			pattern: `*.@(go|md)`,
+			opts:    BashExtglob,
+			match:   []string{"a.go", "a.md"},
+			miss:    []string{"a.", "a.c", "a.@(go|md)"},
+		},
+		{
+			pattern: `a?(b|c*)d`,
+			opts:    BashExtglob,
+			match:   []string{"ad", "abd", "acd", "acxyd"},
+			miss:    []string{"abbd", "ac/d"},
+		},
+		{
+			pattern: `@(a|b/c)/d`,
+			opts:    BashExtglob | BashGlobstar,
+			match:   []string{"a/d", "b/c/d"},
+			miss:    []string{"b/d"},
+		},
+		{
+			pattern: `*(a|b)`,
+			opts:    BashExtglob,
+			match:   []string{"", "a", "abba"},
+			miss:    []string{"c", "a/b", ".a"},
+		},
+		{
+			pattern: `+(a|b)c`,
+			opts:    BashExtglob,
+			match:   []string{"ac", "abc"},
+			miss:    []string{"c", "a/c"},
+		},
+		{
+			pattern: `*(.x)`,
+			opts:    BashExtglob,
+			match:   []string{".x", ".x.x"},
+			miss:    []string{"x"},
+		},
+		{
+			pattern: `*(x)`,
+			opts:    BashExtglob,
+			match:   []string{"x"},
+			miss:    []string{".x"},
+		},
+		{
+			pattern: `+(a)`,
+			opts:    BashExtglob | BashNocaseglob,
+			match:   []string{"a", "aA"},
+			miss:    []string{"", "b"},
+		},
+		{
+			pattern: `!(*.c)`,
+			opts:    BashExtglob,
+			match:   []string{"x", "x.h"},
+			miss:    []string{"x.c", ".x", "x/y"},
+		},
+		{
+			pattern: `!(.b)`,
+			opts:    BashExtglob,
+			match:   []string{"a", "b"},
+			miss:    []string{".b", ".a"},
+		},
+		{
+			pattern: `!(a)`,
+			opts:    BashExtglob | BashDotglob,
+			match:   []string{"", "aa", ".a"},
+			miss:    []string{"a"},
+		},
+		{
+			pattern: `*.@(go|md)`,
+			match:   []string{"a.@(go|md)"},
+			miss:    []string{"a.go"},
+		},
+		{
+			pattern: `a@(b`,
+			opts:    BashExtglob,
+			match:   []string{"a@(b"},
+			miss:    []string{"ab"},
+		},
+		{
+			pattern: `{a,b}`,
+			match:   []string{"{a,b}"},
+			miss:    []string{"a"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithBash(test.opts))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+}
+
+// TestBashNocasematch compares matching with BashNocaseglob to the results
+// of `[[ $subject == $pattern ]]` with nocasematch and extglob set in bash,
+// for which leading periods are not special.
+func TestBashNocasematch(t *testing.T) {
+	subjects := []string{
+		"a", "A", "b", "B", "ab", "AB", "aB", "abab", "ABab", "x.txt", "X.TXT",
+		"foo", "FOO", "Bar", "xy", "xay", "XAAY", "abc", "aBc", "x.c", "X.C",
+		"bx", "Bx", "dX", "aXc", "",
+	}
+	for _, test := range []struct {
+		pattern string
+		match   []string
+	}{
+		{`[!a]`, []string{"b", "B"}},
+		{`[!a-c]x`, []string{"dX"}},
+		{`[A-C]`, []string{"a", "A", "b", "B"}},
+		{`*.TXT`, []string{"x.txt", "X.TXT"}},
+		{`@(foo|BAR)`, []string{"foo", "FOO", "Bar"}},
+		{`?(A)b`, []string{"b", "B", "ab", "AB", "aB"}},
+		{`*(a|B)`, []string{"a", "A", "b", "B", "ab", "AB", "aB", "abab", "ABab", ""}},
+		{`+(ab)`, []string{"ab", "AB", "aB", "abab", "ABab"}},
+		{`!(a*)`, []string{"b", "B", "x.txt", "X.TXT", "foo", "FOO", "Bar", "xy", "xay", "XAAY", "x.c", "X.C", "bx", "Bx", "dX", ""}},
+		{`!(*.C)`, []string{"a", "A", "b", "B", "ab", "AB", "aB", "abab", "ABab", "x.txt", "X.TXT", "foo", "FOO", "Bar", "xy", "xay", "XAAY", "abc", "aBc", "bx", "Bx", "dX", "aXc", ""}},
+		{`x*(A)y`, []string{"xy", "xay", "XAAY"}},
+		{`[^B]?`, []string{"ab", "AB", "aB", "xy", "dX"}},
+		{`a[!B]c`, []string{"aXc"}},
+		{`+([!a])`, []string{"b", "B", "x.txt", "X.TXT", "foo", "FOO", "xy", "x.c", "X.C", "bx", "Bx", "dX"}},
+		{`@(a|!(B))`, []string{"a", "A", "ab", "AB", "aB", "abab", "ABab", "x.txt", "X.TXT", "foo", "FOO", "Bar", "xy", "xay", "XAAY", "abc", "aBc", "x.c", "X.C", "bx", "Bx", "dX", "aXc", ""}},
+	} {
+		g, err := CompileWith(test.pattern, WithBash(BashExtglob|BashNocaseglob|BashDotglob))
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.pattern, err)
+			continue
+		}
+		match := make(map[string]bool)
+		for _, s := range test.match {
+			match[s] = true
+		}
+		for _, s := range subjects {
+			if act := g.Match(s); act != match[s] {
+				t.Errorf("%q matching %q = %t; want %t", test.pattern, s, act, match[s])
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package glob
+
+import "github.com/gobwas/glob/syntax/ast"
+
+// Batch compiles many patterns, like the ones of ignore files or routes, as
+// a whole. Globs and their syntax trees are allocated from slabs shared by
+// the batch, which makes tens of thousands of patterns fewer objects for the
+// garbage collector to track, and keeps the parts of each glob close in
+// memory. Structurally equal parts of the matchers of the batch are shared
+// as well.
+//
+// Release frees all the globs of the batch at once. Batch is not safe for
+// concurrent use, while the globs it compiles are.
+type Batch struct {
+	opts []Option
+	in   interner
+
+	globs    []compiled
+	nodes    []ast.Node
+	children []*ast.Node
+
+	// slabs are all the slabs allocated, to be cleared by Release.
+	globSlabs  [][]compiled
+	nodeSlabs  [][]ast.Node
+	childSlabs [][]*ast.Node
+}
+
+// batchGlobs and batchNodes are the numbers of globs and of nodes of syntax
+// trees allocated at once.
+const (
+	batchGlobs = 64
+	batchNodes = 1024
+)
+
+// NewBatch returns Batch compiling patterns with the options.
+func NewBatch(opts ...Option) *Batch {
+	b := &Batch{in: newInterner()}
+	b.opts = append(append(make([]Option, 0, len(opts)+1), opts...), func(o *options) {
+		o.batch = b
+	})
+	return b
+}
+
+// Compile returns the glob compiled from the pattern with the options of the
+// batch, as CompileWith does.
+func (b *Batch) Compile(pattern string) (Glob, error) {
+	g, err := CompileWith(pattern, b.opts...)
+	if err != nil {
+		return nil, err
+	}
+	c := g.(*compiled)
+	c.Matcher = b.in.intern(c.Matcher)
+	c.tree = b.tree(c.tree, nil)
+	return c, nil
+}
+
+// Release clears all the globs compiled by the batch so far, so that their
+// memory is freed even if some of them are still referred to, and empties
+// the batch. The globs must not be used after that.
+func (b *Batch) Release() {
+	for _, s := range b.globSlabs {
+		for i := range s {
+			s[i] = compiled{}
+		}
+	}
+	for _, s := range b.nodeSlabs {
+		for i := range s {
+			s[i] = ast.Node{}
+		}
+	}
+	for _, s := range b.childSlabs {
+		for i := range s {
+			s[i] = nil
+		}
+	}
+	*b = Batch{opts: b.opts, in: newInterner()}
+}
+
+// alloc returns the zero glob from the slab.
+func (b *Batch) alloc() *compiled {
+	if len(b.globs) == cap(b.globs) {
+		b.globs = make([]compiled, 0, batchGlobs)
+		b.globSlabs = append(b.globSlabs, b.globs[:batchGlobs])
+	}
+	b.globs = b.globs[:len(b.globs)+1]
+	return &b.globs[len(b.globs)-1]
+}
+
+// tree returns the copy of the syntax tree allocated from the slabs.
+func (b *Batch) tree(n *ast.Node, parent *ast.Node) *ast.Node {
+	if len(b.nodes) == cap(b.nodes) {
+		b.nodes = make([]ast.Node, 0, batchNodes)
+		b.nodeSlabs = append(b.nodeSlabs, b.nodes[:batchNodes])
+	}
+	b.nodes = b.nodes[:len(b.nodes)+1]
+	c := &b.nodes[len(b.nodes)-1]
+	c.Parent, c.Kind, c.Value = parent, n.Kind, n.Value
+
+	if k := len(n.Children); k > 0 {
+		if len(b.children)+k > cap(b.children) {
+			size := batchNodes
+			if k > size {
+				size = k
+			}
+			b.children = make([]*ast.Node, 0, size)
+			b.childSlabs = append(b.childSlabs, b.children[:size])
+		}
+		start := len(b.children)
+		b.children = b.children[:start+k]
+		// Appending to the children must not overwrite the ones of
+		// other nodes.
+		c.Children = b.children[start : start+k : start+k]
+		for i, child := range n.Children {
+			c.Children[i] = b.tree(child, c)
+		}
+	}
+	return c
+}
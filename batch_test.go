@@ -0,0 +1,49 @@
+package glob
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	b := NewBatch(WithSeparators('/'))
+	var (
+		patterns []string
+		globs    []Glob
+	)
+	for i := 0; i < 200; i++ {
+		p := fmt.Sprintf("{src,lib}/**/dir%d/*.{go,txt,[a-c]}", i)
+		g, err := b.Compile(p)
+		if err != nil {
+			t.Fatalf("Compile(%q): unexpected error: %s", p, err)
+		}
+		patterns = append(patterns, p)
+		globs = append(globs, g)
+	}
+	if _, err := b.Compile("[a"); err == nil {
+		t.Errorf("Compile(): expected error")
+	}
+	for i, p := range patterns {
+		g := MustCompileWith(p, WithSeparators('/'))
+		if !g.(*compiled).tree.Equal(globs[i].(*compiled).tree) {
+			t.Errorf("#%d syntax tree of %q differs from the one of CompileWith", i, p)
+		}
+		for _, s := range []string{
+			fmt.Sprintf("src/a/dir%d/x.go", i), fmt.Sprintf("lib/dir%d/x.b", i),
+			fmt.Sprintf("src/dir%d/x/y.go", i), fmt.Sprintf("bin/dir%d/x.go", i),
+		} {
+			if g.Match(s) != globs[i].Match(s) {
+				t.Errorf("#%d %q: Match(%q) differs from the one of CompileWith", i, p, s)
+			}
+		}
+	}
+
+	b.Release()
+	if c := globs[0].(*compiled); c.Matcher != nil || c.tree != nil {
+		t.Errorf("glob is not cleared by Release()")
+	}
+	g, err := b.Compile("*.go")
+	if err != nil || !g.Match("x.go") {
+		t.Errorf("Compile() after Release() = %v, %v; want glob matching", g, err)
+	}
+}
@@ -0,0 +1,183 @@
+package glob
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+)
+
+// defaultCacheSize is the number of globs cached by CachedMatch.
+const defaultCacheSize = 512
+
+var defaultCache = NewCache(defaultCacheSize)
+
+// CachedMatch reports whether s matches the pattern compiled without
+// options. Compiled patterns are kept in a cache shared by the callers of
+// CachedMatch, which holds the 512 most recently used ones.
+func CachedMatch(pattern, s string) (bool, error) {
+	return defaultCache.Match(pattern, s)
+}
+
+// Cache holds globs compiled from patterns, so that patterns used over and
+// over, like the ones of requests, are compiled once. Once there are more
+// patterns than the size of the cache, the least recently used ones are
+// evicted. Patterns are compiled once even if they are asked for by several
+// goroutines at the same time, and errors are cached as well.
+//
+// Globs are cached by the pattern along with the options, except for the
+// ones with placeholders or WithStats, which are compiled on every call. Cache is safe
+// for concurrent use.
+type Cache struct {
+	size int
+
+	mu    sync.Mutex
+	order list.List // of *cacheEntry, most recently used first
+	items map[cacheKey]*list.Element
+}
+
+// NewCache returns Cache holding at most size globs. It panics if size is
+// not positive.
+func NewCache(size int) *Cache {
+	if size <= 0 {
+		panic("glob: non-positive cache size")
+	}
+	return &Cache{
+		size:  size,
+		items: make(map[cacheKey]*list.Element),
+	}
+}
+
+// cacheKey is the pattern along with the options affecting globs compiled
+// from it.
+type cacheKey struct {
+	pattern    string
+	separators string
+	checkSeps  bool
+	dialect    Dialect
+	caseFold   bool
+	noEscape   bool
+	period     bool
+	bash       BashOption
+	extglob    bool
+	windows    bool
+	urlPath    bool
+	hostname   bool
+	multiLabel bool
+	mimeType   bool
+	graphemes  bool
+	normalize  string
+	invalid    InvalidUTF8
+	engine     Engine
+	maxSteps   int
+	empty      EmptyPattern
+	captures   string
+
+	maxLength       int
+	maxAlternatives int
+	maxNesting      int
+	maxExpansions   int
+}
+
+type cacheEntry struct {
+	key  cacheKey
+	once sync.Once
+	glob Glob
+	err  error
+}
+
+// newCacheKey returns the key of the pattern compiled with the options, or
+// false if globs compiled with them could not be cached.
+func newCacheKey(pattern string, opts []Option) (cacheKey, bool) {
+	if len(opts) == 0 {
+		return cacheKey{pattern: pattern}, true
+	}
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if len(o.placeholders) > 0 || o.stats != nil || o.normalizer != nil || o.collator != nil {
+		return cacheKey{}, false
+	}
+	k := cacheKey{
+		pattern:    pattern,
+		separators: string(o.separators),
+		checkSeps:  o.checkSeps,
+		dialect:    o.dialect,
+		caseFold:   o.caseFold,
+		noEscape:   o.noEscape,
+		period:     o.period,
+		bash:       o.bash,
+		extglob:    o.extglob,
+		windows:    o.windows,
+		urlPath:    o.urlPath,
+		hostname:   o.hostname,
+		multiLabel: o.multiLabel,
+		mimeType:   o.mimeType,
+		graphemes:  o.graphemes,
+		invalid:    o.invalid,
+		engine:     o.engine,
+		maxSteps:   o.maxSteps,
+		empty:      o.empty,
+
+		maxLength:       o.maxLength,
+		maxAlternatives: o.maxAlternatives,
+		maxNesting:      o.maxNesting,
+		maxExpansions:   o.maxExpansions,
+	}
+	if len(o.captureNames) > 0 {
+		k.captures = fmt.Sprintf("%q", o.captureNames)
+	}
+	if o.normalize != nil {
+		name, ok := normalizerName(o.normalize)
+		if !ok {
+			return cacheKey{}, false
+		}
+		k.normalize = name
+	}
+	return k, true
+}
+
+// Compile returns the glob compiled from the pattern with the options, as
+// CompileWith does, compiling it only if it is not cached.
+func (c *Cache) Compile(pattern string, opts ...Option) (Glob, error) {
+	key, ok := newCacheKey(pattern, opts)
+	if !ok {
+		return CompileWith(pattern, opts...)
+	}
+	c.mu.Lock()
+	var e *cacheEntry
+	if el, ok := c.items[key]; ok {
+		c.order.MoveToFront(el)
+		e = el.Value.(*cacheEntry)
+	} else {
+		e = &cacheEntry{key: key}
+		c.items[key] = c.order.PushFront(e)
+		if c.order.Len() > c.size {
+			last := c.order.Back()
+			c.order.Remove(last)
+			delete(c.items, last.Value.(*cacheEntry).key)
+		}
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.glob, e.err = CompileWith(pattern, opts...)
+	})
+	return e.glob, e.err
+}
+
+// Match reports whether s matches the pattern compiled with the options.
+func (c *Cache) Match(pattern, s string, opts ...Option) (bool, error) {
+	g, err := c.Compile(pattern, opts...)
+	if err != nil {
+		return false, err
+	}
+	return g.Match(s), nil
+}
+
+// Len returns the number of cached globs.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
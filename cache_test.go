@@ -0,0 +1,96 @@
+package glob
+
+import (
+	"sync"
+	"testing"
+	"unicode"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(2)
+	a, err := c.Compile("*.go", WithSeparators('/'))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g, _ := c.Compile("*.go", WithSeparators('/')); g != a {
+		t.Errorf("glob compiled with the same options is not cached")
+	}
+	if g, _ := c.Compile("*.go"); g == a || !g.Match("a/b.go") {
+		t.Errorf("glob compiled with other options is cached as the same one")
+	}
+	if g, _ := c.Compile("*.GO", WithCaseFold(), WithSeparators('/')); g == a || !g.Match("b.go") || g.Match("a/b.go") {
+		t.Errorf("glob compiled with other options is cached as the same one")
+	}
+	if n := c.Len(); n != 2 {
+		t.Errorf("Len() = %d; want 2", n)
+	}
+	// The first glob is the least recently used one, so it is evicted.
+	if g, _ := c.Compile("*.go", WithSeparators('/')); g == a {
+		t.Errorf("least recently used glob is not evicted")
+	}
+	if g, _ := c.Compile("+(a)", FnmatchOptions(0)...); g.Match("aa") {
+		t.Errorf("glob compiled with other options is cached as the same one")
+	}
+	if g, _ := c.Compile("+(a)", FnmatchOptions(FNM_EXTMATCH)...); !g.Match("aa") {
+		t.Errorf("glob compiled with other options is cached as the same one")
+	}
+	if g, _ := c.Compile("*", WithCaptureNames("x")); g.(Analyzer).ReplaceAll("a", "$x") != "a" {
+		t.Errorf("glob compiled with other options is cached as the same one")
+	}
+	if _, err := c.Compile("*", WithCaptureNames("x", "y")); err == nil {
+		t.Errorf("Compile() with more capture names than wildcards returned no error")
+	}
+
+	if _, err := c.Compile("[a"); err == nil {
+		t.Errorf("Compile() of invalid pattern returned no error")
+	}
+	if ok, err := c.Match("[a", "a"); ok || err == nil {
+		t.Errorf("Match() of invalid pattern = %t, %v; want error", ok, err)
+	}
+
+	p := WithPlaceholder("%{num}", runMatcher{unicode.IsDigit, -1})
+	a, _ = c.Compile("v%{num}", p)
+	if g, _ := c.Compile("v%{num}", p); g == a {
+		t.Errorf("glob with placeholders is cached")
+	}
+	if ok, err := c.Match("v%{num}", "v12", p); !ok || err != nil {
+		t.Errorf("Match() = %t, %v; want true", ok, err)
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	c := NewCache(4)
+	var (
+		wg    sync.WaitGroup
+		globs [8]Glob
+	)
+	for i := range globs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			globs[i], _ = c.Compile("{a,b}*c", WithSeparators('.'))
+		}(i)
+	}
+	wg.Wait()
+	for i, g := range globs {
+		if g == nil || g != globs[0] {
+			t.Errorf("goroutine %d got another glob", i)
+		}
+	}
+}
+
+func TestCachedMatch(t *testing.T) {
+	for _, test := range []struct {
+		pattern, s string
+		exp        bool
+	}{
+		{"*.go", "a/b.go", true},
+		{"*.go", "a/b.c", false},
+		{"*.go", "a/b.go", true},
+	} {
+		act, err := CachedMatch(test.pattern, test.s)
+		if err != nil || act != test.exp {
+			t.Errorf("CachedMatch(%q, %q) = %t, %v; want %t", test.pattern, test.s, act, err, test.exp)
+		}
+	}
+}
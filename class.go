@@ -0,0 +1,72 @@
+package glob
+
+import (
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// setNode returns node matching a single rune from the non-empty set. It is
+// a List or a Range node, possibly negated, if the set could be expressed by
+// one, and a Class node of the set or of its complement, whichever has fewer
+// ranges, otherwise. So negated classes of shell patterns, from which
+// separators are removed, stay negated for case folding. Note that the
+// result does not depend on separators: negated nodes are used only when the
+// set does not contain runes they exclude.
+func setNode(set runes.Set) *ast.Node {
+	if n := classNode(set, false); n != nil {
+		return n
+	}
+	if n := classNode(set.Complement(), true); n != nil {
+		return n
+	}
+	not := set.Complement()
+	if len(not) < len(set) {
+		return ast.NewNode(ast.KindClass, rangesClass(not, true))
+	}
+	return ast.NewNode(ast.KindClass, rangesClass(set, false))
+}
+
+// rangesClass returns Class of the ranges of the set.
+func rangesClass(set runes.Set, not bool) ast.Class {
+	var pairs []rune
+	for _, r := range set {
+		pairs = append(pairs, r.Lo, r.Hi)
+	}
+	return ast.Class{Not: not, Ranges: string(pairs)}
+}
+
+// classNode returns List or Range node for the set, or nil if it has more
+// than one range of several runes.
+func classNode(set runes.Set, not bool) *ast.Node {
+	if len(set) == 1 && set[0].Lo != set[0].Hi {
+		return ast.NewNode(ast.KindRange, ast.Range{Not: not, Lo: set[0].Lo, Hi: set[0].Hi})
+	}
+	var chars []rune
+	for _, r := range set {
+		if r.Lo != r.Hi {
+			return nil
+		}
+		chars = append(chars, r.Lo)
+	}
+	return ast.NewNode(ast.KindList, ast.List{Not: not, Chars: string(chars)})
+}
+
+// nodeSet returns set of runes matched by List, Range or Class node.
+func nodeSet(n *ast.Node) runes.Set {
+	var (
+		set runes.Set
+		not bool
+	)
+	switch v := n.Value.(type) {
+	case ast.List:
+		set, not = runes.Of([]rune(v.Chars)...), v.Not
+	case ast.Range:
+		set, not = runes.NewSet(runes.Range{Lo: v.Lo, Hi: v.Hi}), v.Not
+	case ast.Class:
+		set, not = v.Set(), v.Not
+	}
+	if not {
+		return set.Complement()
+	}
+	return set
+}
@@ -0,0 +1,72 @@
+// Command globgen writes Go functions matching fixed patterns, for use with
+// go:generate:
+//
+//	//go:generate globgen -s / -o routes_glob.go IsStatic=/static/** IsUser=/users/*
+//
+// Every argument is a function name and a pattern separated by `=`. The
+// package name defaults to the one go generate runs for.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"github.com/gobwas/glob"
+	"io/ioutil"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+func main() {
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "package name")
+	out := flag.String("o", "", "output file, standard output if empty")
+	sep := flag.String("s", "", "comma separated list of separators characters")
+	fold := flag.Bool("i", false, "match case-insensitively")
+	flag.Parse()
+
+	if *pkg == "" || flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var separators []rune
+	if len(*sep) > 0 {
+		for _, c := range strings.Split(*sep, ",") {
+			if r, w := utf8.DecodeRuneInString(c); len(c) > w {
+				fmt.Println("only single charactered separators are allowed")
+				os.Exit(1)
+			} else {
+				separators = append(separators, r)
+			}
+		}
+	}
+	opts := []glob.Option{glob.WithSeparators(separators...)}
+	if *fold {
+		opts = append(opts, glob.WithCaseFold())
+	}
+
+	var funcs []glob.GoFunc
+	for _, arg := range flag.Args() {
+		i := strings.IndexByte(arg, '=')
+		if i == -1 {
+			fmt.Printf("argument %q is not of the form name=pattern\n", arg)
+			os.Exit(1)
+		}
+		funcs = append(funcs, glob.GoFunc{Name: arg[:i], Pattern: arg[i+1:]})
+	}
+
+	var buf bytes.Buffer
+	if err := glob.WriteGo(&buf, *pkg, funcs, opts...); err != nil {
+		fmt.Println("could not write functions:", err)
+		os.Exit(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := ioutil.WriteFile(*out, buf.Bytes(), 0644); err != nil {
+		fmt.Println("could not write file:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,62 @@
+package glob
+
+import (
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// WithCollation makes ranges of character classes, like `[a-z]`, follow the
+// collation order of the collator instead of the order of code points. The
+// collators of golang.org/x/text/collate, created for some locale, could be
+// used, so that `[a-z]` matches "é" and "ß" for the German one. A rune is in
+// the range if it is neither less than the lowest rune, nor greater than the
+// highest one, as the collator compares them as strings of a single rune.
+// Runes of classes which are not in ranges are matched as they are.
+//
+// The collator is called for runes of matched strings, which makes matching
+// slower than with ranges of code points, and might make it allocate. Since
+// collation order is not known, Regexp renders such classes as `.*`, and
+// Overlaps and Subsumes report conservative results, as they do for
+// placeholders. Globs compiled with this option are neither cached nor
+// saved.
+func WithCollation(c match.Collator) Option {
+	return func(o *options) {
+		o.collator = c
+	}
+}
+
+// collationName is the name of placeholders which classes with ranges are
+// replaced with.
+const collationName = "[]"
+
+// collationTree returns a copy of the tree, in which character classes with
+// ranges are matched by the collator. If fold is set, classes match runes
+// case-insensitively.
+func collationTree(tree *ast.Node, c match.Collator, fold bool) *ast.Node {
+	var m match.Collated
+	switch tree.Kind {
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		m = match.NewCollated(c, []rune{r.Lo, r.Hi}, nil, r.Not)
+
+	case ast.KindClass:
+		v := tree.Value.(ast.Class)
+		if v.Ranges == "" {
+			return tree
+		}
+		set := ast.Class{Chars: v.Chars, Names: v.Names}.Set()
+		m = match.NewCollated(c, []rune(v.Ranges), set, v.Not)
+
+	default:
+		n := ast.NewNode(tree.Kind, tree.Value)
+		for _, child := range tree.Children {
+			ast.Insert(n, collationTree(child, c, fold))
+		}
+		return n
+	}
+	m.Fold = fold
+	return ast.NewNode(ast.KindPlaceholder, &ast.Placeholder{
+		Name:    collationName,
+		Matcher: m,
+	})
+}
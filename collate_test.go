@@ -0,0 +1,68 @@
+package glob
+
+import (
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// dictionary orders letters alphabetically, lower case first, and accented
+// letters right after the plain ones, as collation of many locales does.
+type dictionary struct{}
+
+func (dictionary) CompareString(a, b string) int {
+	ka, kb := dictionaryKey(a), dictionaryKey(b)
+	for i := range ka {
+		switch {
+		case ka[i] < kb[i]:
+			return -1
+		case ka[i] > kb[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+func dictionaryKey(s string) [3]rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	base := unicode.ToLower(r)
+	if base == '\u00e9' || base == '\u00e8' {
+		base = 'e'
+	}
+	var upper rune
+	if unicode.IsUpper(r) {
+		upper = 1
+	}
+	return [3]rune{base, upper, r}
+}
+
+func TestWithCollation(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		s       string
+		exp     bool
+	}{
+		{"[a-z]", nil, "\u00e9", true},
+		{"[a-z]", nil, "A", true},
+		{"[a-z]", nil, "Z", false},
+		{"[a-z]", nil, "1", false},
+		{"[!a-z]", nil, "\u00e9", false},
+		{"[!a-z]", nil, "1", true},
+		{"x[0-9a-c]*", nil, "xBy", true},
+		{"[xa-c]", nil, "x", true},
+		{"[a-c]", []Option{WithCaseFold()}, "C", true},
+		{"[!a-z]*", []Option{WithLeadingPeriod()}, ".x", false},
+		{"[!a-z]*", []Option{WithLeadingPeriod()}, "1x", true},
+		{"*.[a-c]", []Option{WithSeparators('/')}, "a.B", true},
+		{"[abc]", nil, "B", false},
+	} {
+		g := MustCompileWith(test.pattern, append(test.opts, WithCollation(dictionary{}))...)
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("#%d %q Match(%q) = %t; want %t", id, test.pattern, test.s, act, test.exp)
+		}
+	}
+	if MustCompile("[a-z]").Match("\u00e9") {
+		t.Errorf("ranges should follow code points without the option")
+	}
+}
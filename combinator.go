@@ -0,0 +1,372 @@
+package glob
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/nfa"
+	"github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Not returns a glob matching exactly the strings g does not match. Its
+// matcher wraps g, so the result could be used in sets and
+// other combinators as any glob.
+//
+// The result has no literal prefix and suffix, and as it has no numbered
+// wildcards, only $0 is expanded by ReplaceAll.
+func Not(g Glob) Glob {
+	c := &combined{
+		Matcher:    match.NewNot(asMatcher(g)),
+		complexity: analyzerOf(g).Complexity(),
+		minLen:     0,
+		maxLen:     -1,
+	}
+	if n := automatonOf(g); n != nil {
+		c.nfa = n.Complement()
+		c.minLen, c.maxLen = c.nfa.LenBounds()
+	}
+	return c
+}
+
+// Or returns a glob matching the strings any of the globs matches. If all
+// the globs are compiled by this package with the same separators, the
+// result is compiled from the alternatives of their syntax trees, as if the
+// patterns were written like `{a,b}`, so that it is optimized as a single
+// pattern. Or with no globs matches nothing.
+func Or(globs ...Glob) Glob {
+	if trees, seps, ok := compiledTrees(globs...); ok && len(globs) > 0 {
+		tree := syntax.Simplify(ast.NewNode(ast.KindAnyOf, nil, trees...))
+		if g, err := CompileAST(syntax.New(tree), seps...); err == nil {
+			return g
+		}
+	}
+	c := &combined{
+		Matcher: match.NewAnyOf(asMatchers(globs)...),
+		minLen:  -1,
+	}
+	automata := make([]*nfa.NFA, len(globs))
+	for i, g := range globs {
+		a := analyzerOf(g)
+		if i == 0 {
+			c.prefix, c.suffix = a.Prefix(), a.Suffix()
+			c.literal = a.IsLiteral()
+		} else {
+			c.literal = c.literal && a.IsLiteral() && a.Prefix() == c.prefix
+			c.prefix = commonPrefix(c.prefix, a.Prefix())
+			c.suffix = commonSuffix(c.suffix, a.Suffix())
+		}
+		if n := a.MinLen(); c.minLen == -1 || n < c.minLen {
+			c.minLen = n
+		}
+		if n := a.MaxLen(); n == -1 || c.maxLen != -1 && n > c.maxLen {
+			c.maxLen = n
+		}
+		c.complexity = c.complexity.add(a.Complexity())
+		automata[i] = automatonOf(g)
+	}
+	c.complexity.Alternatives += len(globs)
+	if c.minLen == -1 {
+		// Nothing is matched.
+		c.minLen = 0
+	}
+	if known(automata) {
+		c.nfa = nfa.Union(automata...)
+	}
+	return c
+}
+
+// And returns a glob matching the strings all of the globs match. And with
+// no globs matches any string.
+func And(globs ...Glob) Glob {
+	c := &combined{
+		Matcher: match.NewEveryOf(asMatchers(globs)...),
+		maxLen:  -1,
+	}
+	automata := make([]*nfa.NFA, len(globs))
+	for i, g := range globs {
+		a := analyzerOf(g)
+		// Every matched string starts with every prefix, so the longest of
+		// them is the prefix of the result.
+		if p := a.Prefix(); len(p) > len(c.prefix) {
+			c.prefix = p
+		}
+		if s := a.Suffix(); len(s) > len(c.suffix) {
+			c.suffix = s
+		}
+		if n := a.MinLen(); n > c.minLen {
+			c.minLen = n
+		}
+		if n := a.MaxLen(); n != -1 && (c.maxLen == -1 || n < c.maxLen) {
+			c.maxLen = n
+		}
+		c.complexity = c.complexity.add(a.Complexity())
+		automata[i] = automatonOf(g)
+	}
+	for _, g := range globs {
+		if lit, ok := analyzerOf(g).Literal(); ok && c.Matcher.Match(lit) {
+			c.prefix, c.suffix, c.literal = lit, lit, true
+			break
+		}
+	}
+	if known(automata) {
+		c.nfa = nfa.Intersect(automata...)
+	}
+	return c
+}
+
+// Concat returns a glob matching the strings made of a string matched by a,
+// the sep string and a string matched by b. For instance, Concat of `src`
+// and `*.go` with "/" separator matches "src/main.go". If both globs are
+// compiled by this package with the same separators, the result is compiled
+// from the sequence of their syntax trees, so that it is optimized as a
+// single pattern.
+func Concat(a Glob, sep string, b Glob) Glob {
+	if trees, seps, ok := compiledTrees(a, b); ok {
+		tree := ast.NewNode(ast.KindPattern, nil, trees[0], ast.NewNode(ast.KindText, ast.Text{Text: sep}), trees[1])
+		if g, err := CompileAST(syntax.New(syntax.Simplify(tree)), seps...); err == nil {
+			return g
+		}
+	}
+	x, y := analyzerOf(a), analyzerOf(b)
+	var value match.Matcher = match.NewText(sep)
+	if sep == "" {
+		value = match.NewNothing()
+	}
+	c := &combined{
+		Matcher:    match.NewBTree(value, asMatcher(a), asMatcher(b)),
+		prefix:     x.Prefix(),
+		suffix:     y.Suffix(),
+		minLen:     x.MinLen() + len(sep) + y.MinLen(),
+		maxLen:     -1,
+		complexity: x.Complexity().add(y.Complexity()),
+	}
+	c.complexity.Depth++
+	if lit, ok := x.Literal(); ok {
+		c.prefix = lit + sep + y.Prefix()
+	}
+	if lit, ok := y.Literal(); ok {
+		c.suffix = x.Suffix() + sep + lit
+	}
+	c.literal = x.IsLiteral() && y.IsLiteral()
+	if x.MaxLen() != -1 && y.MaxLen() != -1 {
+		c.maxLen = x.MaxLen() + len(sep) + y.MaxLen()
+	}
+	if m, n := automatonOf(a), automatonOf(b); m != nil && n != nil {
+		text := nfa.New(ast.NewNode(ast.KindText, ast.Text{Text: sep}), nil)
+		c.nfa = nfa.Concat(m, text, n)
+	}
+	return c
+}
+
+// combined is a glob built from other globs by combinators. It matches
+// strings by the matcher, and answers other questions by the automaton,
+// which is nil if it is not known, because some of the globs were not
+// created by this package.
+type combined struct {
+	match.Matcher
+
+	nfa        *nfa.NFA
+	prefix     string
+	suffix     string
+	literal    bool
+	minLen     int
+	maxLen     int
+	complexity Complexity
+
+	regexpOnce sync.Once
+	regexp     string
+}
+
+// wholeMatch is used to expand templates for globs without numbered
+// wildcards.
+var wholeMatch = regexp.MustCompile(`(?s)^.*$`)
+
+func (c *combined) Match(s string) bool {
+	if len(s) < c.minLen || c.maxLen != -1 && len(s) > c.maxLen {
+		return false
+	}
+	return c.Matcher.Match(s)
+}
+
+func (c *combined) ReplaceAll(s, template string) string {
+	if !c.Match(s) {
+		return s
+	}
+	return string(wholeMatch.ExpandString(nil, template, s, []int{0, len(s)}))
+}
+
+func (c *combined) Prefix() string {
+	return c.prefix
+}
+
+func (c *combined) Suffix() string {
+	return c.suffix
+}
+
+func (c *combined) Literal() (string, bool) {
+	if !c.literal {
+		return "", false
+	}
+	return c.prefix, true
+}
+
+func (c *combined) IsLiteral() bool {
+	return c.literal
+}
+
+func (c *combined) CouldMatchPrefix(s string) bool {
+	if !strings.HasPrefix(s, c.prefix) && !strings.HasPrefix(c.prefix, s) {
+		return false
+	}
+	return c.nfa == nil || c.nfa.MatchPrefix(s)
+}
+
+func (c *combined) MinLen() int {
+	return c.minLen
+}
+
+func (c *combined) MaxLen() int {
+	return c.maxLen
+}
+
+func (c *combined) Complexity() Complexity {
+	return c.complexity
+}
+
+// Regexp returns expression rendered from the automaton, which could be
+// much longer than the expressions of the combined globs. If the automaton
+// is not known, the expression matches any string.
+func (c *combined) Regexp() string {
+	c.regexpOnce.Do(func() {
+		if c.nfa == nil {
+			c.regexp = `(?s)^.*$`
+		} else {
+			c.regexp = automatonRegexp(c.nfa)
+		}
+	})
+	return c.regexp
+}
+
+// unwrap returns the glob wrapped by g, if any.
+func unwrap(g Glob) Glob {
+	switch p := g.(type) {
+	case Pattern:
+		if p.Glob != nil {
+			return p.Glob
+		}
+	case *Pattern:
+		if p != nil && p.Glob != nil {
+			return p.Glob
+		}
+	}
+	return g
+}
+
+// asMatcher returns the matcher matching the same strings as g does. The
+// matchers of compiled globs are not used directly, since they rely on the
+// checks and the normalization done by Match.
+func asMatcher(g Glob) match.Matcher {
+	if c, ok := unwrap(g).(*combined); ok {
+		return c
+	}
+	return globMatcher{g}
+}
+
+// asMatchers returns the matchers of the globs.
+func asMatchers(globs []Glob) []match.Matcher {
+	ms := make([]match.Matcher, len(globs))
+	for i, g := range globs {
+		ms[i] = asMatcher(g)
+	}
+	return ms
+}
+
+// compiledTrees returns copies of the syntax trees of the globs and their
+// separators, if all of them are compiled by this package with the same
+// separators and do not normalize strings.
+func compiledTrees(globs ...Glob) (trees []*ast.Node, separators []rune, ok bool) {
+	for i, g := range globs {
+		c, ok := unwrap(g).(*compiled)
+		if !ok || c.normalize != nil || i > 0 && string(c.separators) != string(separators) {
+			return nil, nil, false
+		}
+		separators = c.separators
+		trees = append(trees, syntax.Simplify(c.tree))
+	}
+	return trees, separators, true
+}
+
+// automatonOf returns the automaton accepting the strings g matches, or nil
+// if it is not known, as for globs with placeholders. For globs normalizing
+// strings before matching it accepts the normalized strings.
+func automatonOf(g Glob) *nfa.NFA {
+	switch c := unwrap(g).(type) {
+	case *compiled:
+		if countKind(c.tree, ast.KindPlaceholder) > 0 {
+			return nil
+		}
+		return c.automaton()
+	case *combined:
+		return c.nfa
+	}
+	return nil
+}
+
+// known reports whether all the automata are known.
+func known(automata []*nfa.NFA) bool {
+	for _, n := range automata {
+		if n == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// globMatcher adapts any glob to the match.Matcher interface.
+type globMatcher struct {
+	Glob
+}
+
+func (m globMatcher) Index(s string) (int, []int) {
+	return m.AppendIndex(nil, s)
+}
+
+func (m globMatcher) AppendIndex(buf []int, s string) (int, []int) {
+	// Nothing is known about the structure of the glob, so every substring
+	// is tried.
+	start := len(buf)
+	for i := 0; ; {
+		segments := buf[:start]
+		for j := i; ; {
+			if m.Match(s[i:j]) {
+				segments = append(segments, j-i)
+			}
+			if j == len(s) {
+				break
+			}
+			_, w := utf8.DecodeRuneInString(s[j:])
+			j += w
+		}
+		if len(segments) > start {
+			return i, segments
+		}
+		if i == len(s) {
+			return -1, buf
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+}
+
+func (m globMatcher) Len() int {
+	return -1
+}
+
+func (m globMatcher) String() string {
+	return fmt.Sprintf("<glob:%s>", analyzerOf(m.Glob).Regexp())
+}
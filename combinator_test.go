@@ -0,0 +1,177 @@
+package glob
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestNot(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		match   []string
+		miss    []string
+	}{
+		{"*.go", []Option{WithSeparators('/')}, []string{"", "a.md", "cmd/a.go", "a.go.txt"}, []string{"a.go", ".go"}},
+		{"", nil, []string{"a", "ab"}, []string{""}},
+		{"**", nil, nil, []string{"", "a", "a/b"}},
+		{"[a-c]?", nil, []string{"", "a", "dx", "abc"}, []string{"ax", "c/"}},
+		{"{foo,bar}", []Option{WithCaseFold()}, []string{"baz", "fo"}, []string{"FOO", "bar"}},
+		{"*.[!ch]", []Option{WithSeparators('.')}, []string{"a.c", "a.b.x", "ä", "a."}, []string{"a.x", "ä.ö"}},
+	} {
+		g := Not(MustCompileWith(test.pattern, test.opts...)).(Analyzer)
+		re := regexp.MustCompile(g.Regexp())
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d Not(%q) should match %q", id, test.pattern, s)
+			}
+			if Not(g).Match(s) {
+				t.Errorf("#%d Not(Not(%q)) should not match %q", id, test.pattern, s)
+			}
+			if !re.MatchString(s) {
+				t.Errorf("#%d Not(%q) regexp %q should match %q", id, test.pattern, g.Regexp(), s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d Not(%q) should not match %q", id, test.pattern, s)
+			}
+			if !Not(g).Match(s) {
+				t.Errorf("#%d Not(Not(%q)) should match %q", id, test.pattern, s)
+			}
+			if re.MatchString(s) {
+				t.Errorf("#%d Not(%q) regexp %q should not match %q", id, test.pattern, g.Regexp(), s)
+			}
+		}
+	}
+
+	g := Not(MustCompileWith("/a b/*", WithURLPath(true))).(Analyzer)
+	if !g.Match("/a/b") || !g.Match("/a%zz/b") || g.Match("/a%20b/c") {
+		t.Errorf("unexpected Not() results for normalizing glob")
+	}
+
+	g = Not(MustCompile("*.go", '/')).(Analyzer)
+	if g.MinLen() != 0 || g.MaxLen() != -1 || g.Prefix() != "" || g.IsLiteral() {
+		t.Errorf("unexpected Not() properties")
+	}
+	if !g.CouldMatchPrefix("a.go") || !Not(MustCompile("a*")).(Analyzer).CouldMatchPrefix("b") || Not(MustCompile("a**")).(Analyzer).CouldMatchPrefix("a") {
+		t.Errorf("unexpected CouldMatchPrefix() results")
+	}
+	if act := g.ReplaceAll("a.md", "[$0]"); act != "[a.md]" {
+		t.Errorf("ReplaceAll() = %q; want %q", act, "[a.md]")
+	}
+	if Overlaps(g, MustCompile("*.go", '/')) || !Overlaps(g, MustCompile("cmd/*.go", '/')) {
+		t.Errorf("unexpected Overlaps() results")
+	}
+	if !Subsumes(g, MustCompile("*.md", '/')) || Subsumes(MustCompile("**.md", '/'), g) {
+		t.Errorf("unexpected Subsumes() results")
+	}
+	if !Equal(g, Not(MustCompile("*.go", '/'))) || Hash(g) != Hash(Not(MustCompile("*.go", '/'))) {
+		t.Errorf("equal complements should be Equal and have the same Hash")
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	slash := func(p string) Glob { return MustCompile(p, '/') }
+	for id, test := range []struct {
+		glob     Glob
+		compiled bool
+		match    []string
+		miss     []string
+	}{
+		{Or(slash("*.go"), slash("*.md")), true, []string{"a.go", "b.md"}, []string{"a.c", "a/b.go"}},
+		{Or(slash("*.go"), MustCompile("*.md")), false, []string{"a.go", "a/b.md"}, []string{"a/b.go"}},
+		{Or(slash("*.go"), Not(slash("a*"))), false, []string{"a.go", "b", "ab/c"}, []string{"ab"}},
+		{Or(), false, nil, []string{"", "a"}},
+		{And(slash("a*"), slash("*b"), slash("???")), false, []string{"axb", "abb"}, []string{"ab", "axxb", "bxb"}},
+		{And(slash("*.go"), Not(slash("*_test.go"))), false, []string{"a.go"}, []string{"a_test.go", "a.md"}},
+		{And(), false, []string{"", "a/b"}, nil},
+		{Concat(slash("{src,cmd}"), "/", slash("*.go")), true, []string{"src/a.go", "cmd/.go"}, []string{"src.go", "src/a/b.go"}},
+		{Concat(slash("*"), "", slash("[0-9]")), true, []string{"a1", "1"}, []string{"a", "a/1"}},
+		{Concat(MustCompile("*"), "/", slash("*.go")), false, []string{"a/b.go", "a/b/c.go", "/.go"}, []string{"a.go", "a/b/c"}},
+		{Concat(Not(slash("*")), "", slash("x")), false, []string{"a/x"}, []string{"ax", "x"}},
+		{Not(MustCompile("a*a")), false, []string{"a", "ab"}, []string{"aa", "aba"}},
+		{Or(slash("ab**b"), slash("[ab]")), true, []string{"abb", "a"}, []string{"ab"}},
+	} {
+		if _, ok := test.glob.(*compiled); ok != test.compiled {
+			t.Errorf("#%d compiled is %t; want %t", id, ok, test.compiled)
+		}
+		expr := test.glob.(Analyzer).Regexp()
+		re := regexp.MustCompile(expr)
+		for _, s := range test.match {
+			if !test.glob.Match(s) {
+				t.Errorf("#%d should match %q", id, s)
+			}
+			if !re.MatchString(s) {
+				t.Errorf("#%d regexp %q should match %q", id, expr, s)
+			}
+		}
+		for _, s := range test.miss {
+			if test.glob.Match(s) {
+				t.Errorf("#%d should not match %q", id, s)
+			}
+			if re.MatchString(s) {
+				t.Errorf("#%d regexp %q should not match %q", id, expr, s)
+			}
+		}
+	}
+
+	// The combined globs match as the globs they are made of do, including
+	// the checks and the normalization their Match does before running the
+	// matchers.
+	none := Not(MustCompile("**"))
+	for _, g := range []Glob{
+		slash("a*a"), slash("ab**b"), slash("[ab]"), slash("?*[!a]"), slash("{a,b}*{a,b}"),
+		MustCompileWith("/a b/*", WithURLPath(true)),
+		MustCompileWith("*", WithInvalidUTF8(InvalidUTF8Reject)),
+	} {
+		for _, s := range []string{"", "a", "b", "ab", "aa", "aba", "abb", "a/b", "\u00e9", "\xff", "/a%20b/c"} {
+			exp := g.Match(s)
+			if act := Not(g).Match(s); act == exp {
+				t.Errorf("Not(%s).Match(%q) = %t; want %t", g, s, act, !exp)
+			}
+			if act := Or(g, none).Match(s); act != exp {
+				t.Errorf("Or(%s, none).Match(%q) = %t; want %t", g, s, act, exp)
+			}
+			if act := And(g, Not(none)).Match(s); act != exp {
+				t.Errorf("And(%s, all).Match(%q) = %t; want %t", g, s, act, exp)
+			}
+			if act := Concat(g, "", MustCompile("")).Match(s); act != exp {
+				t.Errorf("Concat(%s, empty).Match(%q) = %t; want %t", g, s, act, exp)
+			}
+		}
+	}
+
+	for id, test := range []struct {
+		glob           Glob
+		prefix, suffix string
+		literal        bool
+		minLen, maxLen int
+	}{
+		{Or(MustCompile("ab"), slash("ab")), "ab", "ab", true, 2, 2},
+		{Or(MustCompile("ab"), slash("a")), "a", "", false, 1, 2},
+		{Or(MustCompile("a*c"), slash("ab?c")), "a", "c", false, 2, -1},
+		{And(MustCompile("a*"), slash("*ab")), "a", "ab", false, 2, -1},
+		{And(MustCompile("a*"), slash("ab")), "ab", "ab", true, 2, 2},
+		{Concat(MustCompile("a"), "/", slash("b*")), "a/b", "", false, 3, -1},
+		{Concat(MustCompile("a?"), "/", slash("b")), "a", "/b", false, 4, 7},
+	} {
+		g := test.glob.(Analyzer)
+		if g.Prefix() != test.prefix || g.Suffix() != test.suffix || g.IsLiteral() != test.literal {
+			t.Errorf("#%d unexpected literals: %q, %q, %t; want %q, %q, %t",
+				id, g.Prefix(), g.Suffix(), g.IsLiteral(), test.prefix, test.suffix, test.literal)
+		}
+		if g.MinLen() != test.minLen || g.MaxLen() != test.maxLen {
+			t.Errorf("#%d unexpected lengths: %d, %d; want %d, %d",
+				id, g.MinLen(), g.MaxLen(), test.minLen, test.maxLen)
+		}
+	}
+
+	g := Or(slash("src/*"), MustCompile("doc/*")).(Analyzer)
+	if !g.CouldMatchPrefix("src/") || g.CouldMatchPrefix("x") || !Subsumes(g, slash("src/a")) {
+		t.Errorf("unexpected analysis results of combined glob")
+	}
+	if act := Concat(MustCompile("*"), ".", slash("go")).(Analyzer).ReplaceAll("a.go", "$0!"); act != "a.go!" {
+		t.Errorf("ReplaceAll() = %q; want %q", act, "a.go!")
+	}
+}
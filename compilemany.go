@@ -0,0 +1,221 @@
+package glob
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax"
+)
+
+// CompileMany compiles the patterns with given options by CompileWith, like
+// calling it for each pattern, except that structurally equal parts of the
+// matchers are shared between the globs. It cuts memory when thousands of
+// patterns have common segments, like `**/node_modules/**`. The globs are
+// allocated by Batch, which is never released.
+func CompileMany(patterns []string, opts ...Option) ([]Glob, error) {
+	globs := make([]Glob, len(patterns))
+	b := NewBatch(opts...)
+	for i, p := range patterns {
+		g, err := b.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("pattern #%d %q: %s", i, p, err)
+		}
+		globs[i] = g
+	}
+	return globs, nil
+}
+
+// PatternError describes the failure to compile one of the patterns of a
+// batch.
+type PatternError struct {
+	// Index is the index of the pattern in the batch.
+	Index   int
+	Pattern string
+
+	// Offset is the byte offset in the pattern where the syntax error
+	// occurred, or -1 if it is not known.
+	Offset int
+	Err    error
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("pattern #%d %q: %s", e.Index, e.Pattern, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *PatternError) Unwrap() error {
+	return e.Err
+}
+
+// PatternErrors is the list of errors of the patterns of a batch, in the
+// order of the patterns.
+type PatternErrors []*PatternError
+
+func (es PatternErrors) Error() string {
+	lines := make([]string, len(es))
+	for i, e := range es {
+		lines[i] = e.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CompileAll compiles the patterns with given options by CompileWith. Unlike
+// CompileMany, it does not stop at the first bad pattern: it returns the
+// globs of all valid patterns, with nil in place of the bad ones, along with
+// PatternErrors describing every bad pattern, so that config loaders could
+// report all of them at once. The error is nil if all patterns are valid.
+func CompileAll(patterns []string, opts ...Option) ([]Glob, error) {
+	globs := make([]Glob, len(patterns))
+	var errs PatternErrors
+	for i, p := range patterns {
+		g, err := CompileWith(p, opts...)
+		if err != nil {
+			offset := -1
+			if e, ok := err.(*syntax.Error); ok {
+				offset = e.Offset
+			}
+			errs = append(errs, &PatternError{
+				Index:   i,
+				Pattern: p,
+				Offset:  offset,
+				Err:     err,
+			})
+			continue
+		}
+		globs[i] = g
+	}
+	if len(errs) > 0 {
+		return globs, errs
+	}
+	return globs, nil
+}
+
+// interner hash-conses matchers: it returns the same instance for
+// structurally equal matchers, which is safe as matchers are immutable.
+type interner struct {
+	table map[string]interned
+}
+
+type interned struct {
+	matcher match.Matcher
+	id      string
+}
+
+func newInterner() interner {
+	return interner{table: make(map[string]interned)}
+}
+
+var (
+	matcherType  = reflect.TypeOf((*match.Matcher)(nil)).Elem()
+	matchersType = reflect.TypeOf(match.Matchers(nil))
+)
+
+// intern returns the instance of the matcher equal to m, interning the
+// children of m first. Matchers which could not be told apart by canonical,
+// like those holding functions, are returned as is.
+func (in interner) intern(m match.Matcher) match.Matcher {
+	m, _, _ = in.lookup(m)
+	return m
+}
+
+// lookup interns m and returns the instance along with its id in the table,
+// or false if m could not be interned. The key of m refers to its children
+// by their ids, so that each matcher is encoded once.
+func (in interner) lookup(m match.Matcher) (match.Matcher, string, bool) {
+	v := reflect.ValueOf(m)
+	var key bytes.Buffer
+	// The type tells apart values and pointers, which canonical encodes
+	// the same way.
+	key.WriteString(v.Type().String())
+	ok := true
+	if v.Kind() == reflect.Struct {
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(v)
+		key.WriteByte('{')
+		for i := 0; i < cp.NumField(); i++ {
+			f := cp.Field(i)
+			key.WriteByte(',')
+			switch {
+			case !f.CanSet() || (f.Kind() == reflect.Interface || f.Kind() == reflect.Slice) && f.IsNil():
+			case f.Type() == matcherType:
+				c, id, cok := in.lookup(f.Interface().(match.Matcher))
+				f.Set(reflect.ValueOf(c))
+				key.WriteString(id)
+				ok = ok && cok
+				continue
+			case f.Type() == matchersType:
+				ms := make(match.Matchers, f.Len())
+				for j := range ms {
+					c, id, cok := in.lookup(f.Index(j).Interface().(match.Matcher))
+					ms[j] = c
+					key.WriteString(id)
+					key.WriteByte(';')
+					ok = ok && cok
+				}
+				f.Set(reflect.ValueOf(ms))
+				continue
+			}
+			if ok = ok && encodable(f); ok {
+				encodeValue(&key, f)
+			}
+		}
+		key.WriteByte('}')
+		m = cp.Interface().(match.Matcher)
+	} else if ok = encodable(v); ok {
+		encodeValue(&key, v)
+	}
+	if !ok {
+		return m, "", false
+	}
+	if x, found := in.table[key.String()]; found {
+		return x.matcher, x.id, true
+	}
+	id := "#" + strconv.Itoa(len(in.table))
+	in.table[key.String()] = interned{m, id}
+	return m, id, true
+}
+
+// encodable reports whether canonical encodes all of the value, so that
+// values with equal encodings are equal.
+func encodable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid, reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil() || encodable(v.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !encodable(v.Field(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !encodable(v.Index(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			if !encodable(k) || !encodable(v.MapIndex(k)) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return false
+	}
+}
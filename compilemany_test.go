@@ -0,0 +1,121 @@
+package glob
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/glob/match"
+)
+
+func TestCompileMany(t *testing.T) {
+	patterns := []string{
+		"**/node_modules/**/*.js",
+		"src/**/node_modules/**/*.js",
+		"{a,b}/**/node_modules/**/*.js",
+		"*.{js,ts}",
+		"lib/*.{js,ts}",
+		"%{num}/*",
+	}
+	opts := []Option{
+		WithSeparators('/'),
+		WithPlaceholder("%{num}", runMatcher{func(r rune) bool { return '0' <= r && r <= '9' }, -1}),
+	}
+	globs, err := CompileMany(patterns, opts...)
+	if err != nil {
+		t.Fatalf("CompileMany(): unexpected error: %s", err)
+	}
+	for i, p := range patterns {
+		g := MustCompileWith(p, opts...)
+		if !Equal(g, globs[i]) {
+			t.Errorf("#%d glob of %q differs from the one of CompileWith", i, p)
+		}
+		for _, s := range []string{
+			"x/node_modules/y/z.js", "src/node_modules/a.js", "a/b/node_modules/c/d.js",
+			"x.js", "x.ts", "lib/x.ts", "lib/x/y.ts", "12/x", "x/12",
+		} {
+			if g.Match(s) != globs[i].Match(s) {
+				t.Errorf("#%d %q: Match(%q) differs from the one of CompileWith", i, p, s)
+			}
+		}
+	}
+
+	in := newInterner()
+	in.intern(MustCompile("**/node_modules/**/*.js", '/').(*compiled).Matcher)
+	n := len(in.table)
+	in.intern(MustCompile("**/node_modules/**/*.js", '/').(*compiled).Matcher)
+	if len(in.table) != n {
+		t.Errorf("equal matchers are not shared: %d matchers interned; want %d", len(in.table), n)
+	}
+	m := MustCompile("src/**/node_modules/**/*.js", '/').(*compiled).Matcher
+	var nodes int
+	match.Walk(m, func(match.Matcher) bool { nodes++; return true })
+	if in.intern(m); len(in.table)-n >= nodes {
+		t.Errorf("common parts are not shared: %d of %d matchers interned", len(in.table)-n, nodes)
+	}
+
+	if _, err := CompileMany([]string{"a", "[b"}); err == nil {
+		t.Errorf("CompileMany(): expected error")
+	}
+
+	// Equal parts of a single glob are shared as well.
+	sets := make(map[uintptr]bool)
+	match.Walk(MustCompile("{a,b}/x/**/y/{a,b}", '/').(*compiled).Matcher, func(m match.Matcher) bool {
+		if s, ok := m.(match.Set); ok {
+			sets[reflect.ValueOf(s.Strings).Pointer()] = true
+		}
+		return true
+	})
+	if len(sets) != 1 {
+		t.Errorf("equal parts of glob are not shared: %d sets", len(sets))
+	}
+}
+
+func TestCompileAll(t *testing.T) {
+	patterns := []string{"*.go", "[a", "b", "x{a,[b}", "c?", "[z-a]"}
+	globs, err := CompileAll(patterns)
+	errs, ok := err.(PatternErrors)
+	if !ok {
+		t.Fatalf("CompileAll() error = %v; want PatternErrors", err)
+	}
+	for i, want := range []struct {
+		index  int
+		offset int
+	}{
+		{1, 2},
+		{3, 7},
+		{5, 3},
+	} {
+		if i >= len(errs) {
+			t.Errorf("missing error #%d", i)
+			continue
+		}
+		if e := errs[i]; e.Index != want.index || e.Pattern != patterns[want.index] || e.Offset != want.offset {
+			t.Errorf("error #%d is for pattern #%d at offset %d; want #%d at %d", i, e.Index, e.Offset, want.index, want.offset)
+		}
+	}
+	if len(errs) != 3 {
+		t.Errorf("got %d errors; want 3", len(errs))
+	}
+	for i, g := range globs {
+		bad := i == 1 || i == 3 || i == 5
+		if (g == nil) != bad {
+			t.Errorf("glob #%d is %v", i, g)
+		}
+	}
+	if !strings.HasPrefix(err.Error(), `pattern #1 "[a": offset 2:`) {
+		t.Errorf("unexpected error text %q", err)
+	}
+
+	_, err = CompileAll([]string{`\\[z-a]`}, WithNoEscape())
+	if errs, ok := err.(PatternErrors); !ok || errs[0].Offset != 5 {
+		t.Errorf("CompileAll() error = %v; want error at offset 5", err)
+	}
+	_, err = CompileAll([]string{"%{n}["}, WithPlaceholder("%{n}", runMatcher{func(rune) bool { return true }, -1}))
+	if errs, ok := err.(PatternErrors); !ok || errs[0].Offset != 5 {
+		t.Errorf("CompileAll() error = %v; want error at offset 5", err)
+	}
+	if _, err = CompileAll([]string{"a", "b"}); err != nil {
+		t.Errorf("CompileAll(): unexpected error: %s", err)
+	}
+}
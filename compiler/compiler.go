@@ -6,6 +6,8 @@ package compiler
 import (
 	"fmt"
 	"reflect"
+	"sort"
+	"unicode/utf8"
 
 	"github.com/gobwas/glob/match"
 	"github.com/gobwas/glob/syntax/ast"
@@ -21,16 +23,71 @@ func optimizeMatcher(matcher match.Matcher) match.Matcher {
 		}
 
 	case match.AnyOf:
+		m.Matchers = flattenMatchers(m.Matchers, func(c match.Matcher) (match.Matchers, bool) {
+			switch v := c.(type) {
+			case match.AnyOf:
+				return v.Matchers, true
+			case match.Optional:
+				return match.Matchers{match.NewNothing(), v.Matcher}, true
+			case match.Empty:
+				// Alternatives matching no strings are dropped.
+				return nil, true
+			}
+			return nil, false
+		})
+		if len(m.Matchers) == 0 {
+			return match.NewEmpty()
+		}
+		if len(m.Matchers) == 1 {
+			return m.Matchers[0]
+		}
+		if rest, ok := optionalMatchers(m.Matchers); ok {
+			o := optimizeMatcher(match.NewAnyOf(rest...))
+			if o.Match("") {
+				return o
+			}
+			return match.NewOptional(o)
+		}
+		if set, ok := classSet(m.Matchers); ok {
+			return match.NewCharClass(set, false)
+		}
+		if set, ok := textSet(m.Matchers); ok {
+			return set
+		}
+		if set, ok := foldTextSet(m.Matchers); ok {
+			return set
+		}
+
+		return m
+
+	case match.EveryOf:
+		m.Matchers = mergeLimits(flattenMatchers(m.Matchers, func(c match.Matcher) (match.Matchers, bool) {
+			e, ok := c.(match.EveryOf)
+			return e.Matchers, ok
+		}))
+		for _, c := range m.Matchers {
+			if _, ok := c.(match.Empty); ok {
+				return c
+			}
+		}
 		if len(m.Matchers) == 1 {
 			return m.Matchers[0]
 		}
 
 		return m
 
+	case match.Repeat:
+		return optimizeRepeat(m)
+
 	case match.List:
-		if m.Not == false && len(m.List) == 1 {
+		// U+FFFD is kept in the list, which matches invalid bytes with it,
+		// unlike the text.
+		if m.Not == false && len(m.List) == 1 && m.List[0] != utf8.RuneError {
 			return match.NewText(string(m.List))
 		}
+		if m.Not == false && len(m.List) == 0 {
+			return match.NewEmpty()
+		}
 
 		return m
 
@@ -38,6 +95,10 @@ func optimizeMatcher(matcher match.Matcher) match.Matcher {
 		m.Left = optimizeMatcher(m.Left)
 		m.Right = optimizeMatcher(m.Right)
 
+		if f, ok := foldValue(m.Value); ok {
+			return optimizeFoldTree(m, f)
+		}
+
 		r, ok := m.Value.(match.Text)
 		if !ok {
 			return m
@@ -88,9 +149,363 @@ func optimizeMatcher(matcher match.Matcher) match.Matcher {
 	return matcher
 }
 
+// flattenMatchers returns the matchers with the children of the nested ones
+// inlined and duplicates removed, which is what AnyOf and EveryOf of nested
+// AnyOf or EveryOf respectively match the same way. The nested ones are
+// those for which the function returns true.
+func flattenMatchers(matchers match.Matchers, nested func(match.Matcher) (match.Matchers, bool)) match.Matchers {
+	var out match.Matchers
+	var add func(match.Matchers)
+	add = func(ms match.Matchers) {
+		for _, m := range ms {
+			if children, ok := nested(m); ok {
+				add(children)
+				continue
+			}
+			if !containsMatcher(out, m) {
+				out = append(out, m)
+			}
+		}
+	}
+	add(matchers)
+	return out
+}
+
+func containsMatcher(matchers match.Matchers, m match.Matcher) bool {
+	for _, x := range matchers {
+		if reflect.DeepEqual(x, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// optimizeRepeat returns the simpler matcher of the repetition, if there is
+// one. Repetitions of single runes, like `+([!/])`, only limit the number of
+// runes, which is what Min, Max and Between do.
+func optimizeRepeat(m match.Repeat) match.Matcher {
+	switch {
+	case m.Max == 0:
+		return match.NewNothing()
+	case m.Min == 1 && m.Max == 1:
+		return m.Child
+	case m.Min == 0 && m.Max == 1:
+		if m.Child.Match("") {
+			return m.Child
+		}
+		return match.NewOptional(m.Child)
+	}
+
+	var separators []rune
+	switch c := m.Child.(type) {
+	case match.Super, match.Any:
+		// Any number of strings of runes other than separators is one
+		// such string.
+		return c
+	case match.Single:
+		separators = c.Separators
+	case match.List:
+		if !c.Not {
+			return m
+		}
+		separators = c.List
+	default:
+		return m
+	}
+
+	var parts match.Matchers
+	switch {
+	case m.Min > 0 && m.Max != -1:
+		parts = append(parts, match.NewBetween(m.Min, m.Max))
+	case m.Min > 0:
+		// Min does not match the empty string even if its limit is 0.
+		parts = append(parts, match.NewMin(m.Min))
+	case m.Max != -1:
+		parts = append(parts, match.NewMax(m.Max))
+	}
+	if len(separators) > 0 {
+		parts = append(parts, match.NewAny(separators))
+	}
+	switch len(parts) {
+	case 0:
+		return match.NewSuper()
+	case 1:
+		return parts[0]
+	}
+	return optimizeMatcher(match.NewEveryOf(parts...))
+}
+
+// optionalMatchers returns the matchers other than Nothing, if one of the
+// matchers is Nothing, so that they are matched as Optional.
+func optionalMatchers(matchers match.Matchers) (match.Matchers, bool) {
+	for i, m := range matchers {
+		if _, ok := m.(match.Nothing); ok {
+			rest := append(match.Matchers{}, matchers[:i]...)
+			return append(rest, matchers[i+1:]...), true
+		}
+	}
+	return nil, false
+}
+
+// textSet returns Set of the strings of the matchers, if all of them are
+// Text, which is what alternatives of literals, like brace expansions, are
+// compiled to.
+func textSet(matchers match.Matchers) (match.Set, bool) {
+	strs := make([]string, len(matchers))
+	for i, m := range matchers {
+		t, ok := m.(match.Text)
+		if !ok {
+			return match.Set{}, false
+		}
+		strs[i] = t.Str
+	}
+	return match.NewSet(strs...), true
+}
+
+// foldTextSet returns the set of the strings of the matchers, if all of them
+// are texts matched case-insensitively or caseless texts, and some are the
+// former ones.
+func foldTextSet(matchers match.Matchers) (match.FoldSet, bool) {
+	var (
+		strs = make([]string, len(matchers))
+		fold bool
+	)
+	for i, m := range matchers {
+		switch t := m.(type) {
+		case match.FoldText:
+			strs[i] = t.Str
+			fold = true
+		case match.Text:
+			if !caseless(t.Str) {
+				return match.FoldSet{}, false
+			}
+			strs[i] = t.Str
+		default:
+			return match.FoldSet{}, false
+		}
+	}
+	if !fold {
+		return match.FoldSet{}, false
+	}
+	return match.NewFoldSet(strs...), true
+}
+
+// classSet returns the set of runes of the matchers, if all of them match a
+// single rune of a class and some are ranges, which is what classes
+// extended with other cases of their runes are compiled to.
+func classSet(matchers match.Matchers) (runes.Set, bool) {
+	var (
+		set    runes.Set
+		ranges bool
+	)
+	for _, m := range matchers {
+		var s runes.Set
+		switch c := m.(type) {
+		case match.List:
+			if c.Not {
+				return nil, false
+			}
+			s = runes.Of(c.List...)
+		case match.Range:
+			if c.Not {
+				return nil, false
+			}
+			s = runes.NewSet(runes.Range{Lo: c.Lo, Hi: c.Hi})
+			ranges = true
+		case match.Text:
+			if c.RunesLength != 1 {
+				return nil, false
+			}
+			s = runes.Of([]rune(c.Str)...)
+		case match.CharClass:
+			if c.Not {
+				s = c.Set.Complement()
+			} else {
+				s = c.Set
+			}
+			ranges = true
+		default:
+			return nil, false
+		}
+		set = set.Union(s)
+	}
+	return set, ranges && len(matchers) > 1
+}
+
+// foldTexts returns the matchers with runs of caseless texts and classes of
+// cases of a single rune, which is what texts matched case-insensitively
+// are compiled to, merged into FoldText. Texts with cased runes, which
+// have not been folded, are left as they are.
+func foldTexts(matchers []match.Matcher) []match.Matcher {
+	var (
+		out   []match.Matcher
+		run   []rune
+		start int
+		cased bool
+	)
+	flush := func(end int) {
+		if cased && end-start > 1 {
+			out = append(out, match.NewFoldText(string(run)))
+		} else {
+			out = append(out, matchers[start:end]...)
+		}
+		run, start, cased = run[:0], end+1, false
+	}
+	for i, m := range matchers {
+		switch c := m.(type) {
+		case match.Text:
+			if caseless(c.Str) {
+				run = append(run, []rune(c.Str)...)
+				continue
+			}
+		case match.List:
+			if r, ok := foldOrbit(c); ok {
+				run = append(run, r)
+				cased = true
+				continue
+			}
+		}
+		flush(i)
+		out = append(out, m)
+	}
+	flush(len(matchers))
+	return out
+}
+
+// caseless reports whether the runes of s have no other cases. Strings
+// which are not valid UTF-8 are not merged, as their bytes match only
+// themselves.
+func caseless(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if _, ok := runes.Of(r).Fold().Single(); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// foldValue returns the folded string the value of the tree matches
+// case-insensitively, if it is FoldText or a class of cases of a rune.
+func foldValue(m match.Matcher) (string, bool) {
+	switch v := m.(type) {
+	case match.FoldText:
+		return v.Str, true
+	case match.List:
+		if r, ok := foldOrbit(v); ok {
+			return runes.FoldString(string(r)), true
+		}
+	}
+	return "", false
+}
+
+// optimizeFoldTree replaces the tree with the value matched
+// case-insensitively by a literal matcher, as it is done for texts, so that
+// the string is not searched for rune by rune.
+func optimizeFoldTree(m match.BTree, folded string) match.Matcher {
+	var (
+		leftNil  = m.Left == nil
+		rightNil = m.Right == nil
+	)
+	_, leftSuper := m.Left.(match.Super)
+	lp, leftPrefix := m.Left.(match.FoldPrefix)
+
+	_, rightSuper := m.Right.(match.Super)
+	rs, rightSuffix := m.Right.(match.FoldSuffix)
+
+	switch {
+	case leftNil && rightNil:
+		return match.NewFoldText(folded)
+
+	case leftSuper && rightSuper:
+		return match.NewFoldContains(folded)
+
+	case leftSuper && rightNil:
+		return match.NewFoldSuffix(folded)
+
+	case rightSuper && leftNil:
+		return match.NewFoldPrefix(folded)
+
+	case leftNil && rightSuffix:
+		return match.NewFoldPrefixSuffix(folded, rs.Suffix)
+
+	case rightNil && leftPrefix:
+		return match.NewFoldPrefixSuffix(lp.Prefix, folded)
+	}
+
+	return m
+}
+
+// foldOrbit returns a rune of the list, if the list is exactly all cases
+// of the rune.
+func foldOrbit(l match.List) (rune, bool) {
+	if l.Not || len(l.List) < 2 {
+		return 0, false
+	}
+	r := l.List[0]
+	return r, runes.Of(l.List...).Equal(runes.Of(r).Fold())
+}
+
+// mergeLimits merges the Min, Max and Between constraints of the matchers
+// of EveryOf into the strictest one, put where the first one was. The
+// bounds contradicting each other make the constraint Empty.
+func mergeLimits(matchers match.Matchers) match.Matchers {
+	var (
+		out      match.Matchers
+		at       = -1
+		min, max = -1, -1
+	)
+	for _, m := range matchers {
+		lmin, lmax := -1, -1
+		switch v := m.(type) {
+		case match.Min:
+			lmin = v.Limit
+		case match.Max:
+			lmax = v.Limit
+		case match.Between:
+			lmin, lmax = v.Min, v.Max
+		default:
+			out = append(out, m)
+			continue
+		}
+		if at == -1 {
+			at = len(out)
+			out = append(out, nil)
+		}
+		if lmin > min {
+			min = lmin
+		}
+		if lmax != -1 && (max == -1 || lmax < max) {
+			max = lmax
+		}
+	}
+	switch {
+	case at == -1:
+	case min == -1:
+		out[at] = match.NewMax(max)
+	case max == -1:
+		out[at] = match.NewMin(min)
+	case min > max:
+		out[at] = match.NewEmpty()
+	default:
+		out[at] = match.NewBetween(min, max)
+	}
+	return out
+}
+
+// compileMatchers returns the matcher of the sequence of the matchers. The
+// sequence of no matchers matches the empty string only, while the one
+// containing Empty matches no strings.
 func compileMatchers(matchers []match.Matcher) (match.Matcher, error) {
 	if len(matchers) == 0 {
-		return nil, fmt.Errorf("compile error: need at least one matcher")
+		return match.NewNothing(), nil
+	}
+	for _, m := range matchers {
+		if _, ok := m.(match.Empty); ok {
+			return m, nil
+		}
 	}
 	if len(matchers) == 1 {
 		return matchers[0], nil
@@ -100,11 +515,11 @@ func compileMatchers(matchers []match.Matcher) (match.Matcher, error) {
 	}
 
 	idx := -1
-	maxLen := -1
+	maxScore := -1
 	var val match.Matcher
 	for i, matcher := range matchers {
-		if l := matcher.Len(); l != -1 && l >= maxLen {
-			maxLen = l
+		if score := pivotScore(matcher); score != -1 && score >= maxScore {
+			maxScore = score
 			idx = i
 			val = matcher
 		}
@@ -143,6 +558,71 @@ func compileMatchers(matchers []match.Matcher) (match.Matcher, error) {
 	return match.NewBTree(val, l, r), nil
 }
 
+// pivotScore estimates how good the matcher is as the value of BTree, which
+// is searched for first: the higher the score, the fewer positions of
+// strings it matches at and the cheaper it is to find them. A rare long
+// literal thus beats a common short one, and both beat wildcards. The
+// score is -1 for matchers without static length, which could not be the
+// value.
+func pivotScore(matcher match.Matcher) int {
+	if matcher.Len() == -1 {
+		return -1
+	}
+	switch m := matcher.(type) {
+	case match.Text:
+		// Literals are found by strings.Index, and every rune of them
+		// narrows the positions down.
+		return 4 * m.RunesLength
+
+	case match.List:
+		if m.Not {
+			return 1
+		}
+		return 2
+
+	case match.Range:
+		if m.Not {
+			return 1
+		}
+		return 2
+
+	case match.Single:
+		return 1
+
+	case match.Row:
+		var score int
+		for _, c := range m.Matchers {
+			score += pivotScore(c)
+		}
+		return score
+
+	case match.FoldText:
+		// Folded literals are searched for nearly as fast as literals.
+		return 4 * m.RunesLength
+
+	case match.CharClass:
+		if m.Not {
+			return 1
+		}
+		return 2
+
+	case match.Set:
+		// Strings are looked up at once, but each of them could match.
+		return 4*m.RunesLength - 1
+
+	case match.AnyOf:
+		// Every alternative is searched for, and each could match.
+		score := -1
+		for _, c := range m.Matchers {
+			if s := pivotScore(c); score == -1 || s < score {
+				score = s
+			}
+		}
+		return score
+	}
+	return matcher.Len()
+}
+
 func glueMatchers(matchers []match.Matcher) match.Matcher {
 	if m := glueMatchersAsEvery(matchers); m != nil {
 		return m
@@ -235,68 +715,130 @@ func glueMatchersAsEvery(matchers []match.Matcher) match.Matcher {
 		return match.NewAny(separator)
 	}
 
-	if (hasAny || hasSuper) && min > 0 && len(separator) == 0 {
-		return match.NewMin(min)
+	var limit match.Matcher = match.NewMin(min)
+	if !hasAny && !hasSuper {
+		limit = match.NewBetween(min, min)
+	}
+	if len(separator) == 0 {
+		return limit
 	}
 
-	every := match.NewEveryOf()
-
-	if min > 0 {
-		every.Add(match.NewMin(min))
+	return match.NewEveryOf(limit, match.NewAny(separator))
+}
 
-		if !hasAny && !hasSuper {
-			every.Add(match.NewMax(min))
+// minimizeMatchers glues runs of adjacent matchers as glueMatchers does:
+// runs of matchers of fixed length into Row, and runs of wildcards with the
+// same separators into Super, Any, Min, Between or EveryOf. The runs are
+// found in a single pass. A matcher which belongs to runs of both kinds,
+// like Single, goes to the longer run, or to the former one, if they are
+// equal.
+func minimizeMatchers(matchers []match.Matcher) []match.Matcher {
+	var runs []matcherRun
+	row, every := -1, -1
+	var separators []rune
+	for i := 0; i <= len(matchers); i++ {
+		var (
+			fixed    bool
+			sep      []rune
+			wildcard bool
+		)
+		if i < len(matchers) {
+			fixed = matchers[i].Len() != -1
+			sep, wildcard = wildcardSeparators(matchers[i])
+		}
+		if row != -1 && !fixed {
+			runs = append(runs, matcherRun{row, i, false})
+			row = -1
+		}
+		if every != -1 && (!wildcard || !runes.Equal(sep, separators)) {
+			runs = append(runs, matcherRun{every, i, true})
+			every = -1
+		}
+		if fixed && row == -1 {
+			row = i
+		}
+		if wildcard && every == -1 {
+			every, separators = i, sep
 		}
 	}
 
-	if len(separator) > 0 {
-		every.Add(match.NewContains(string(separator), true))
+	// Runs of the same kind never overlap, and runs of different kinds
+	// overlap by their ends, or one contains the other. So matchers left to
+	// a run by longer ones are adjacent.
+	sort.Stable(byRunLength(runs))
+	owner := make([]int, len(matchers))
+	for i := range owner {
+		owner[i] = -1
 	}
-
-	return every
-}
-
-func minimizeMatchers(matchers []match.Matcher) []match.Matcher {
-	var done match.Matcher
-	var left, right, count int
-
-	for l := 0; l < len(matchers); l++ {
-		for r := len(matchers); r > l; r-- {
-			if glued := glueMatchers(matchers[l:r]); glued != nil {
-				var swap bool
-
-				if done == nil {
-					swap = true
-				} else {
-					cl, gl := done.Len(), glued.Len()
-					swap = cl > -1 && gl > -1 && gl > cl
-					swap = swap || count < r-l
-				}
-
-				if swap {
-					done = glued
-					left = l
-					right = r
-					count = r - l
-				}
+	for r, run := range runs {
+		for i := run.lo; i < run.hi; i++ {
+			if owner[i] == -1 {
+				owner[i] = r
 			}
 		}
 	}
 
-	if done == nil {
-		return matchers
+	var next []match.Matcher
+	for i := 0; i < len(matchers); {
+		j := i + 1
+		for j < len(matchers) && owner[j] != -1 && owner[j] == owner[i] {
+			j++
+		}
+		var glued match.Matcher
+		switch {
+		case j-i < 2:
+		case runs[owner[i]].every:
+			glued = glueMatchersAsEvery(matchers[i:j])
+		default:
+			glued = glueMatchersAsRow(matchers[i:j])
+		}
+		if glued != nil {
+			next = append(next, glued)
+		} else {
+			next = append(next, matchers[i:j]...)
+		}
+		i = j
 	}
+	return next
+}
 
-	next := append(append([]match.Matcher{}, matchers[:left]...), done)
-	if right < len(matchers) {
-		next = append(next, matchers[right:]...)
-	}
+// matcherRun is the run of matchers[lo:hi] which are glued together, either
+// as wildcards, or as matchers of fixed length.
+type matcherRun struct {
+	lo, hi int
+	every  bool
+}
+
+// byRunLength sorts runs from the longest, and the runs of equal lengths by
+// their positions, wildcards first.
+type byRunLength []matcherRun
 
-	if len(next) == len(matchers) {
-		return next
+func (r byRunLength) Len() int      { return len(r) }
+func (r byRunLength) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byRunLength) Less(i, j int) bool {
+	if li, lj := r[i].hi-r[i].lo, r[j].hi-r[j].lo; li != lj {
+		return li > lj
+	}
+	if r[i].lo != r[j].lo {
+		return r[i].lo < r[j].lo
 	}
+	return r[i].every && !r[j].every
+}
 
-	return minimizeMatchers(next)
+// wildcardSeparators returns the separators of the matcher, if it is one of
+// the wildcards which glueMatchersAsEvery glues.
+func wildcardSeparators(m match.Matcher) ([]rune, bool) {
+	switch m := m.(type) {
+	case match.Super:
+		return []rune{}, true
+	case match.Any:
+		return m.Separators, true
+	case match.Single:
+		return m.Separators, true
+	case match.List:
+		return m.List, m.Not
+	}
+	return nil, false
 }
 
 // minimizeAnyOf tries to apply some heuristics to minimize number of nodes in given tree
@@ -446,6 +988,115 @@ func leastChildren(nodes []*ast.Node) int {
 	return idx
 }
 
+// maxExpansions limits the number of branches the alternatives of a pattern
+// are expanded into.
+const maxExpansions = 16
+
+// compileExpanded compiles the pattern with its alternatives expanded into
+// alternatives of whole patterns, so that `{a,b}*{c,d}` is compiled like
+// `{a*c,a*d,b*c,b*d}`. Each branch is a simple pattern, which is often
+// compiled to a single matcher without backtracking, like PrefixSuffix. It
+// returns false if there are no alternatives to expand or there would be
+// more than maxExpansions branches.
+func compileExpanded(tree *ast.Node, sep []rune) (match.Matcher, bool) {
+	branches, ok := expandAlternatives(tree.Children)
+	if !ok || len(branches) <= 1 {
+		return nil, false
+	}
+	matchers := make([]match.Matcher, len(branches))
+	for i, b := range branches {
+		n := ast.NewNode(ast.KindPattern, nil)
+		for _, c := range b {
+			ast.Insert(n, cloneNode(c))
+		}
+		m, err := compile(n, sep)
+		if err != nil {
+			return nil, false
+		}
+		matchers[i] = m
+	}
+	return optimizeMatcher(match.NewAnyOf(matchers...)), true
+}
+
+// expandAlternatives returns the sequences of nodes the sequence expands to,
+// when every alternative is replaced by each of its branches. Branches are
+// expanded recursively.
+func expandAlternatives(nodes []*ast.Node) ([][]*ast.Node, bool) {
+	seqs := [][]*ast.Node{nil}
+	for _, n := range nodes {
+		if n.Kind != ast.KindAnyOf {
+			for i := range seqs {
+				seqs[i] = append(seqs[i], n)
+			}
+			continue
+		}
+		var alts [][]*ast.Node
+		for _, alt := range n.Children {
+			children := []*ast.Node{alt}
+			if alt.Kind == ast.KindPattern {
+				children = alt.Children
+			}
+			subs, ok := expandAlternatives(children)
+			if !ok {
+				return nil, false
+			}
+			alts = append(alts, subs...)
+		}
+		if len(seqs)*len(alts) > maxExpansions {
+			return nil, false
+		}
+		next := make([][]*ast.Node, 0, len(seqs)*len(alts))
+		for _, seq := range seqs {
+			for _, alt := range alts {
+				x := make([]*ast.Node, 0, len(seq)+len(alt))
+				next = append(next, append(append(x, seq...), alt...))
+			}
+		}
+		seqs = next
+	}
+	return seqs, true
+}
+
+func cloneNode(n *ast.Node) *ast.Node {
+	c := ast.NewNode(n.Kind, n.Value)
+	for _, child := range n.Children {
+		ast.Insert(c, cloneNode(child))
+	}
+	return c
+}
+
+// costLength is the length of strings matcherCost assumes.
+const costLength = 16
+
+// matcherCost estimates the number of steps it takes to match a string by
+// the matcher. Each matcher counts as a step, except for BTree with the left
+// branch of unbounded length, which could match its branches at every
+// position of the string.
+func matcherCost(m match.Matcher) int {
+	switch v := m.(type) {
+	case match.BTree:
+		var branches int
+		if v.Left != nil {
+			branches += matcherCost(v.Left)
+		}
+		if v.Right != nil {
+			branches += matcherCost(v.Right)
+		}
+		if v.Left != nil && v.LeftLengthRunes == -1 {
+			branches *= costLength
+		}
+		return matcherCost(v.Value) + branches
+
+	case match.Container:
+		cost := 1
+		for _, c := range v.Children() {
+			cost += matcherCost(c)
+		}
+		return cost
+	}
+	return 1
+}
+
 func compileTreeChildren(tree *ast.Node, sep []rune) ([]match.Matcher, error) {
 	var matchers []match.Matcher
 	for _, desc := range tree.Children {
@@ -472,17 +1123,17 @@ func compile(tree *ast.Node, sep []rune) (m match.Matcher, err error) {
 		return match.NewAnyOf(matchers...), nil
 
 	case ast.KindPattern:
-		if len(tree.Children) == 0 {
-			return match.NewNothing(), nil
-		}
 		matchers, err := compileTreeChildren(tree, sep)
 		if err != nil {
 			return nil, err
 		}
-		m, err = compileMatchers(minimizeMatchers(matchers))
+		m, err = compileMatchers(minimizeMatchers(foldTexts(matchers)))
 		if err != nil {
 			return nil, err
 		}
+		if x, ok := compileExpanded(tree, sep); ok && matcherCost(x) < matcherCost(optimizeMatcher(m)) {
+			return x, nil
+		}
 
 	case ast.KindAny:
 		m = match.NewAny(sep)
@@ -504,10 +1155,21 @@ func compile(tree *ast.Node, sep []rune) (m match.Matcher, err error) {
 		r := tree.Value.(ast.Range)
 		m = match.NewRange(r.Lo, r.Hi, r.Not)
 
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		m = match.NewCharClass(c.Set(), c.Not)
+
 	case ast.KindText:
 		t := tree.Value.(ast.Text)
 		m = match.NewText(t.Text)
 
+	case ast.KindPlaceholder:
+		p := tree.Value.(*ast.Placeholder)
+		var ok bool
+		if m, ok = p.Matcher.(match.Matcher); !ok {
+			return nil, fmt.Errorf("could not compile placeholder %q: %T is not a matcher", p.Name, p.Matcher)
+		}
+
 	default:
 		return nil, fmt.Errorf("could not compile tree: unknown node type")
 	}
@@ -4,6 +4,7 @@ import (
 	"github.com/gobwas/glob/match"
 	"github.com/gobwas/glob/match/debug"
 	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
 	"reflect"
 	"testing"
 )
@@ -154,7 +155,7 @@ func TestGlueMatchers(t *testing.T) {
 			},
 			match.EveryOf{match.Matchers{
 				match.NewMin(1),
-				match.NewContains(string(separators), true),
+				match.NewAny(separators),
 			}},
 		},
 		{
@@ -163,10 +164,7 @@ func TestGlueMatchers(t *testing.T) {
 				match.NewSingle(nil),
 				match.NewSingle(nil),
 			},
-			match.EveryOf{match.Matchers{
-				match.NewMin(3),
-				match.NewMax(3),
-			}},
+			match.NewBetween(3, 3),
 		},
 		{
 			[]match.Matcher{
@@ -175,7 +173,7 @@ func TestGlueMatchers(t *testing.T) {
 			},
 			match.EveryOf{match.Matchers{
 				match.NewMin(1),
-				match.NewContains("a", true),
+				match.NewAny([]rune{'a'}),
 			}},
 		},
 	} {
@@ -192,6 +190,212 @@ func TestGlueMatchers(t *testing.T) {
 	}
 }
 
+func TestOptimizeNested(t *testing.T) {
+	for id, test := range []struct {
+		in  match.Matcher
+		exp match.Matcher
+	}{
+		{
+			match.NewAnyOf(
+				match.NewPrefix("a"),
+				match.NewAnyOf(match.NewPrefix("b"), match.NewPrefix("a")),
+				match.NewPrefix("c"),
+			),
+			match.NewAnyOf(match.NewPrefix("a"), match.NewPrefix("b"), match.NewPrefix("c")),
+		},
+		{
+			match.NewAnyOf(
+				match.NewText("a"),
+				match.NewAnyOf(match.NewText("b"), match.NewText("a")),
+			),
+			match.NewSet("a", "b"),
+		},
+		{
+			match.NewAnyOf(match.NewFoldText("ab"), match.NewText("1"), match.NewFoldText("AB")),
+			match.NewFoldSet("ab", "1"),
+		},
+		{
+			match.NewAnyOf(match.NewFoldText("ab"), match.NewText("b")),
+			match.NewAnyOf(match.NewFoldText("ab"), match.NewText("b")),
+		},
+		{
+			match.NewAnyOf(match.NewText("a"), match.NewText("a")),
+			match.NewText("a"),
+		},
+		{
+			match.NewAnyOf(match.NewNothing(), match.NewText("a"), match.NewText("bc")),
+			match.NewOptional(match.NewSet("a", "bc")),
+		},
+		{
+			match.NewAnyOf(match.NewOptional(match.NewText("a")), match.NewPrefix("b")),
+			match.NewOptional(match.NewAnyOf(match.NewText("a"), match.NewPrefix("b"))),
+		},
+		{
+			match.NewAnyOf(match.NewNothing(), match.NewAny([]rune{'/'})),
+			match.NewAny([]rune{'/'}),
+		},
+		{
+			match.NewEveryOf(
+				match.NewMin(1),
+				match.NewEveryOf(match.NewMin(3), match.NewMax(5), match.NewContains("/", true)),
+				match.NewMax(4),
+				match.NewContains("/", true),
+			),
+			match.NewEveryOf(match.NewBetween(3, 4), match.NewContains("/", true)),
+		},
+		{
+			match.NewEveryOf(match.NewBetween(1, 3), match.NewMin(4)),
+			match.NewEmpty(),
+		},
+		{
+			match.NewEveryOf(match.NewMin(1), match.NewMin(2)),
+			match.NewMin(2),
+		},
+		{
+			match.NewAnyOf(
+				match.NewRange('A', 'Z', false),
+				match.NewRange('a', 'z', false),
+				match.NewText("ſ"),
+				match.NewList([]rune("\u212a"), false),
+			),
+			match.NewCharClass(runes.NewSet(
+				runes.Range{Lo: 'A', Hi: 'Z'},
+				runes.Range{Lo: 'a', Hi: 'z'},
+				runes.Range{Lo: 'ſ', Hi: 'ſ'},
+				runes.Range{Lo: '\u212a', Hi: '\u212a'},
+			), false),
+		},
+		{
+			match.NewAnyOf(match.NewText("a"), match.NewEmpty(), match.NewPrefix("b")),
+			match.NewAnyOf(match.NewText("a"), match.NewPrefix("b")),
+		},
+		{
+			match.NewAnyOf(match.NewEmpty(), match.NewEmpty()),
+			match.NewEmpty(),
+		},
+		{
+			match.NewEveryOf(match.NewMin(1), match.NewEmpty()),
+			match.NewEmpty(),
+		},
+		{
+			match.NewList(nil, false),
+			match.NewEmpty(),
+		},
+		{
+			match.NewRepeat(match.NewSingle([]rune{'/'}), 2, 5),
+			match.NewEveryOf(match.NewBetween(2, 5), match.NewAny([]rune{'/'})),
+		},
+		{
+			match.NewRepeat(match.NewList([]rune("/."), true), 1, -1),
+			match.NewEveryOf(match.NewMin(1), match.NewAny([]rune("/."))),
+		},
+		{
+			match.NewRepeat(match.NewSingle(nil), 0, 3),
+			match.NewMax(3),
+		},
+		{
+			match.NewRepeat(match.NewSingle(nil), 0, -1),
+			match.NewSuper(),
+		},
+		{
+			match.NewRepeat(match.NewAny([]rune{'/'}), 1, -1),
+			match.NewAny([]rune{'/'}),
+		},
+		{
+			match.NewRepeat(match.NewText("ab"), 0, 1),
+			match.NewOptional(match.NewText("ab")),
+		},
+		{
+			match.NewRepeat(match.NewText("ab"), 1, 1),
+			match.NewText("ab"),
+		},
+		{
+			match.NewRepeat(match.NewText("ab"), 0, 0),
+			match.NewNothing(),
+		},
+		{
+			match.NewRepeat(match.NewText("ab"), 1, -1),
+			match.NewRepeat(match.NewText("ab"), 1, -1),
+		},
+		{
+			match.NewBTree(match.NewFoldText("ab"), match.NewSuper(), match.NewSuper()),
+			match.NewFoldContains("ab"),
+		},
+		{
+			match.NewBTree(match.NewFoldText("ab"), match.NewSuper(), nil),
+			match.NewFoldSuffix("ab"),
+		},
+		{
+			match.NewBTree(match.NewList([]rune("aA"), false), nil, match.NewSuper()),
+			match.NewFoldPrefix("a"),
+		},
+		{
+			match.NewBTree(match.NewFoldText("ab"), nil, match.NewBTree(match.NewFoldText("c"), match.NewSuper(), nil)),
+			match.NewFoldPrefixSuffix("ab", "c"),
+		},
+		{
+			match.NewBTree(match.NewFoldText("ab"), match.NewSingle(nil), match.NewSuper()),
+			match.NewBTree(match.NewFoldText("ab"), match.NewSingle(nil), match.NewSuper()),
+		},
+	} {
+		if act := optimizeMatcher(test.in); !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("#%d unexpected optimized matcher:\nact: %#v;\nexp: %#v", id, act, test.exp)
+		}
+	}
+}
+
+func TestFoldTexts(t *testing.T) {
+	for id, test := range []struct {
+		in  []match.Matcher
+		exp []match.Matcher
+	}{
+		{
+			[]match.Matcher{
+				match.NewList([]rune("Ff"), false),
+				match.NewList([]rune("Ii"), false),
+				match.NewText("-1"),
+				match.NewList([]rune("Kk\u212a"), false),
+				match.NewSuper(),
+				match.NewText("."),
+				match.NewList([]rune("Tt"), false),
+			},
+			[]match.Matcher{
+				match.NewFoldText("fi-1k"),
+				match.NewSuper(),
+				match.NewFoldText(".t"),
+			},
+		},
+		{
+			[]match.Matcher{
+				match.NewList([]rune("Ff"), false),
+				match.NewText("oo"),
+				match.NewList([]rune("ab"), false),
+				match.NewText("-"),
+			},
+			[]match.Matcher{
+				match.NewList([]rune("Ff"), false),
+				match.NewText("oo"),
+				match.NewList([]rune("ab"), false),
+				match.NewText("-"),
+			},
+		},
+		{
+			[]match.Matcher{
+				match.NewText("\xff"),
+				match.NewList([]rune("Ff"), false),
+			},
+			[]match.Matcher{
+				match.NewText("\xff"),
+				match.NewList([]rune("Ff"), false),
+			},
+		},
+	} {
+		if act := foldTexts(test.in); !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("#%d unexpected matchers:\nact: %#v;\nexp: %#v", id, act, test.exp)
+		}
+	}
+}
+
 func TestCompileMatchers(t *testing.T) {
 	for id, test := range []struct {
 		in  []match.Matcher
@@ -242,6 +446,31 @@ func TestCompileMatchers(t *testing.T) {
 				}...,
 			),
 		},
+		{
+			[]match.Matcher{
+				match.NewSuper(),
+				match.NewText("ab"),
+				match.NewSuper(),
+				match.NewRow(3, match.NewSingle(nil), match.NewSingle(nil), match.NewSingle(nil)),
+			},
+			match.NewBTree(
+				match.NewText("ab"),
+				match.NewSuper(),
+				match.NewBTree(
+					match.NewRow(3, match.NewSingle(nil), match.NewSingle(nil), match.NewSingle(nil)),
+					match.NewSuper(),
+					nil,
+				),
+			),
+		},
+		{
+			nil,
+			match.NewNothing(),
+		},
+		{
+			[]match.Matcher{match.NewSuper(), match.NewEmpty(), match.NewText("a")},
+			match.NewEmpty(),
+		},
 	} {
 		act, err := compileMatchers(test.in)
 		if err != nil {
@@ -313,12 +542,88 @@ func TestConvertMatchers(t *testing.T) {
 	}
 }
 
+func TestConvertMatchersLong(t *testing.T) {
+	const n = 10000
+	for id, test := range []struct {
+		block, exp []match.Matcher
+	}{
+		{
+			// the runs are equal, so the single goes to the former
+			[]match.Matcher{
+				match.NewText("a"),
+				match.NewSingle(nil),
+				match.NewAny(nil),
+			},
+			[]match.Matcher{
+				match.NewRow(2, match.NewText("a"), match.NewSingle(nil)),
+				match.NewAny(nil),
+			},
+		},
+		{
+			// the wildcards are glued, as the following row ends with the
+			// text of the next block
+			[]match.Matcher{
+				match.NewText("a"),
+				match.NewAny(nil),
+				match.NewSingle(nil),
+				match.NewSingle(nil),
+			},
+			[]match.Matcher{
+				match.NewText("a"),
+				match.NewMin(2),
+			},
+		},
+		{
+			[]match.Matcher{
+				match.NewText("a"),
+				match.NewAny([]rune{'/'}),
+				match.NewSuper(),
+			},
+			[]match.Matcher{
+				match.NewText("a"),
+				match.NewAny([]rune{'/'}),
+				match.NewSuper(),
+			},
+		},
+	} {
+		var in, exp []match.Matcher
+		for i := 0; i < n; i++ {
+			in = append(in, test.block...)
+			exp = append(exp, test.exp...)
+		}
+		act := minimizeMatchers(in)
+		if !reflect.DeepEqual(act, exp) {
+			t.Errorf("#%d unexpected convert matchers result: %d matchers, exp %d", id, len(act), len(exp))
+		}
+	}
+}
+
 func TestCompiler(t *testing.T) {
 	for id, test := range []struct {
 		ast    *ast.Node
 		result match.Matcher
 		sep    []rune
 	}{
+		{
+			// {a,b}*{c,d}
+			ast: ast.NewNode(ast.KindPattern, nil,
+				ast.NewNode(ast.KindAnyOf, nil,
+					ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindText, ast.Text{"a"})),
+					ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindText, ast.Text{"b"})),
+				),
+				ast.NewNode(ast.KindAny, nil),
+				ast.NewNode(ast.KindAnyOf, nil,
+					ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindText, ast.Text{"c"})),
+					ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindText, ast.Text{"d"})),
+				),
+			),
+			result: match.NewAnyOf(
+				match.NewPrefixSuffix("a", "c"),
+				match.NewPrefixSuffix("a", "d"),
+				match.NewPrefixSuffix("b", "c"),
+				match.NewPrefixSuffix("b", "d"),
+			),
+		},
 		{
 			ast: ast.NewNode(ast.KindPattern, nil,
 				ast.NewNode(ast.KindText, ast.Text{"abc"}),
@@ -380,7 +685,7 @@ func TestCompiler(t *testing.T) {
 			sep: separators,
 			result: match.EveryOf{Matchers: match.Matchers{
 				match.NewMin(3),
-				match.NewContains(string(separators), true),
+				match.NewAny(separators),
 			}},
 		},
 		{
@@ -425,7 +730,7 @@ func TestCompiler(t *testing.T) {
 				match.NewText("/"),
 				nil,
 				match.NewBTree(
-					match.NewAnyOf(match.NewText("z"), match.NewText("ab")),
+					match.NewSet("z", "ab"),
 					nil,
 					match.NewSuper(),
 				),
@@ -557,11 +862,10 @@ func TestCompiler(t *testing.T) {
 			result: match.NewBTree(
 				match.NewText("abc"),
 				nil,
-				match.AnyOf{Matchers: match.Matchers{
+				match.NewOptional(match.AnyOf{Matchers: match.Matchers{
 					match.NewSingle(nil),
 					match.NewList([]rune{'d', 'e', 'f'}, false),
-					match.NewNothing(),
-				}},
+				}}),
 			),
 		},
 		{
@@ -0,0 +1,86 @@
+package glob
+
+import (
+	"math"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Complexity describes how expensive matching of a glob could be in the
+// worst case.
+type Complexity struct {
+	// Alternatives is the total number of branches of all pattern
+	// alternatives.
+	Alternatives int
+
+	// Unbounded is the number of wildcards that match strings of any
+	// length, that is `*` and `**`.
+	Unbounded int
+
+	// Depth is the maximum number of nested backtracking points of the
+	// compiled matcher. Each of them could try every position of the input.
+	Depth int
+}
+
+// Cost returns estimated worst-case number of steps needed to match a string
+// of n bytes. It saturates at math.MaxInt32.
+func (c Complexity) Cost(n int) int {
+	if n < 1 {
+		n = 1
+	}
+	cost := float64(c.Alternatives+1) * math.Pow(float64(n), float64(c.Depth+1))
+	if cost > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(cost)
+}
+
+// add returns the complexity of matching both globs, each of which has
+// own backtracking points.
+func (c Complexity) add(d Complexity) Complexity {
+	c.Alternatives += d.Alternatives
+	c.Unbounded += d.Unbounded
+	if d.Depth > c.Depth {
+		c.Depth = d.Depth
+	}
+	return c
+}
+
+func complexity(tree *ast.Node, m match.Matcher) (c Complexity) {
+	syntax.Inspect(tree, func(n *ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		switch n.Kind {
+		case ast.KindAnyOf:
+			c.Alternatives += len(n.Children)
+		case ast.KindAny, ast.KindSuper:
+			c.Unbounded++
+		case ast.KindPlaceholder:
+			if m, ok := n.Value.(*ast.Placeholder).Matcher.(match.Matcher); ok && m.Len() == -1 {
+				c.Unbounded++
+			}
+		}
+		return true
+	})
+	c.Depth = backtrackDepth(m)
+	return c
+}
+
+// backtrackDepth returns the maximum number of nested BTree matchers, which
+// are the only ones that try multiple positions of the input.
+func backtrackDepth(m match.Matcher) (depth int) {
+	if c, ok := m.(match.Container); ok {
+		for _, child := range c.Children() {
+			if d := backtrackDepth(child); d > depth {
+				depth = d
+			}
+		}
+	}
+	if _, ok := m.(match.BTree); ok {
+		depth++
+	}
+	return depth
+}
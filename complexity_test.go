@@ -0,0 +1,36 @@
+package glob
+
+import (
+	"math"
+	"testing"
+)
+
+func TestComplexity(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		exp     Complexity
+	}{
+		{"abc", Complexity{}},
+		{"abc*", Complexity{Unbounded: 1}},
+		{"*abc*", Complexity{Unbounded: 2}},
+		{"{a,b,c}", Complexity{Alternatives: 3}},
+		{"*a*b*", Complexity{Unbounded: 3, Depth: 1}},
+		{pattern_all, Complexity{Unbounded: 4, Depth: 3}},
+	} {
+		g := MustCompile(test.pattern).(Analyzer)
+		if act := g.Complexity(); act != test.exp {
+			t.Errorf("#%d MustCompile(%q).Complexity() = %+v; want %+v\n%s", id, test.pattern, act, test.exp, g)
+		}
+	}
+}
+
+func TestComplexityCost(t *testing.T) {
+	cheap := MustCompile("abc*").(Analyzer).Complexity()
+	expensive := MustCompile("*a*b*c*d*").(Analyzer).Complexity()
+	if c, e := cheap.Cost(100), expensive.Cost(100); c >= e {
+		t.Errorf("cost of cheap pattern is %d; want less than %d", c, e)
+	}
+	if act := (Complexity{Depth: 100}).Cost(1000); act != math.MaxInt32 {
+		t.Errorf("Cost() = %d; want saturated %d", act, math.MaxInt32)
+	}
+}
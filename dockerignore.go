@@ -0,0 +1,113 @@
+package glob
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// dockerignoreTree returns syntax tree matching the same paths as the single
+// .dockerignore pattern. See DialectDockerignore for details.
+func dockerignoreTree(pattern string) (*ast.Node, error) {
+	p := strings.TrimPrefix(path.Clean(pattern), "/")
+	if p == "" {
+		return nil, fmt.Errorf("dockerignore pattern %q is empty", pattern)
+	}
+
+	tree := ast.NewNode(ast.KindPattern, nil)
+	sh := shell{caretOnly: true, doubleStar: true}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		last := i == len(segments)-1
+		if s == "**" {
+			if last {
+				ast.Insert(tree, ast.NewNode(ast.KindSuper, nil))
+				return tree, nil
+			}
+			ast.Insert(tree, anyDirs())
+			continue
+		}
+		nodes, err := sh.nodes(s)
+		if err != nil {
+			return nil, err
+		}
+		ast.Insert(tree, nodes...)
+		if !last {
+			ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: "/"}))
+		}
+	}
+	ast.Insert(tree, anyInside())
+	return tree, nil
+}
+
+// Dockerignore is an ordered list of .dockerignore patterns.
+type Dockerignore struct {
+	// Dockerfile is the name of the Dockerfile relative to the root of the
+	// build context. It is never excluded, as well as the .dockerignore
+	// file itself, because Docker always needs them to build an image.
+	Dockerfile string
+
+	rules []ignoreRule
+}
+
+// LoadDockerignore reads .dockerignore patterns from r. Lines starting with
+// `#` and blank lines are skipped, leading and trailing spaces are removed,
+// and patterns starting with `!` are exceptions, which re-include paths
+// excluded by the previous patterns. The Dockerfile field of the result is
+// set to "Dockerfile".
+func LoadDockerignore(r io.Reader) (*Dockerignore, error) {
+	d := Dockerignore{
+		Dockerfile: "Dockerfile",
+	}
+	var n int
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		n++
+		line := s.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var rule ignoreRule
+		if line[0] == '!' {
+			rule.negate = true
+			line = strings.TrimSpace(line[1:])
+		}
+		g, err := CompileWith(line, WithDialect(DialectDockerignore))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		rule.glob = g
+		d.rules = append(d.rules, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Match reports whether the path is excluded from the build context. The
+// path is relative to the root of the context and uses '/' as the
+// separator. If several patterns match the path, the last one wins.
+func (d *Dockerignore) Match(p string) bool {
+	p = strings.TrimPrefix(path.Clean(p), "/")
+	if p == ".dockerignore" || p == path.Clean(d.Dockerfile) {
+		return false
+	}
+	for i := len(d.rules) - 1; i >= 0; i-- {
+		if r := d.rules[i]; r.glob.Match(p) {
+			return !r.negate
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDialectDockerignore(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: `*.md`,
+			match:   []string{"README.md", "README.md/x"},
+			miss:    []string{"docs/README.md"},
+		},
+		{
+			pattern: `/docs/./*.md`,
+			match:   []string{"docs/a.md"},
+			miss:    []string{"docs/a/b.md", "a.md"},
+		},
+		{
+			pattern: `**/*.go`,
+			match:   []string{"main.go", "a/b/main.go"},
+			miss:    []string{"main.goo"},
+		},
+		{
+			pattern: `a/**`,
+			match:   []string{"a/", "a/b", "a/b/c"},
+			miss:    []string{"ab"},
+		},
+		{
+			pattern: `a**b`,
+			match:   []string{"ab", "axb", "a/x/b"},
+			miss:    []string{"a/x/c"},
+		},
+		{
+			pattern: `temp?`,
+			match:   []string{"temp1", "temp1/x"},
+			miss:    []string{"temp", "temp/"},
+		},
+		{
+			pattern: `x[^a]y`,
+			match:   []string{"xby", "x/y"},
+			miss:    []string{"xay"},
+		},
+		{
+			pattern: `x[!a]y`,
+			match:   []string{"x!y", "xay"},
+			miss:    []string{"xby"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithDialect(DialectDockerignore))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+}
+
+func TestLoadDockerignore(t *testing.T) {
+	d, err := LoadDockerignore(strings.NewReader(strings.Join([]string{
+		"\uFEFF# comment",
+		"  *  ",
+		"! README.md",
+		"!docs",
+		"docs/private",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, test := range []struct {
+		path string
+		exp  bool
+	}{
+		{"main.go", true},
+		{"src/main.go", true},
+		{"README.md", false},
+		{"./README.md", false},
+		{"docs/a.md", false},
+		{"docs/private/key", true},
+		{"Dockerfile", false},
+		{".dockerignore", false},
+	} {
+		if act := d.Match(test.path); act != test.exp {
+			t.Errorf("#%d Match(%q) = %t; want %t", id, test.path, act, test.exp)
+		}
+	}
+	d.Dockerfile = "build/Dockerfile"
+	if !d.Match("Dockerfile") || d.Match("build/Dockerfile") {
+		t.Errorf("Match() does not respect Dockerfile field")
+	}
+}
@@ -0,0 +1,138 @@
+package glob
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// maxBraceExpansion limits the number of patterns braces are expanded to.
+const maxBraceExpansion = 1 << 12
+
+// doublestarTree returns syntax tree matching the same paths as the pattern
+// does with doublestar.Match. See DialectDoublestar for details.
+func doublestarTree(pattern string) (*ast.Node, error) {
+	patterns, err := expandBraces(pattern)
+	if err != nil {
+		return nil, err
+	}
+	tree := ast.NewNode(ast.KindAnyOf, nil)
+	for _, p := range patterns {
+		t, err := doublestarPattern(p)
+		if err != nil {
+			return nil, err
+		}
+		ast.Insert(tree, t)
+	}
+	if len(tree.Children) == 1 {
+		return tree.Children[0], nil
+	}
+	return ast.NewNode(ast.KindPattern, nil, tree), nil
+}
+
+// doublestarPattern returns syntax tree for the pattern without braces.
+func doublestarPattern(pattern string) (*ast.Node, error) {
+	var segments []string
+	for _, s := range strings.Split(pattern, "/") {
+		// Consecutive `**` segments are the same as one.
+		if s == "**" && len(segments) > 0 && segments[len(segments)-1] == "**" {
+			continue
+		}
+		segments = append(segments, s)
+	}
+
+	tree := ast.NewNode(ast.KindPattern, nil)
+	sh := shell{seps: []rune{'/'}, strict: true}
+	var dirs bool // previous segment was `**`
+	for i, s := range segments {
+		if s == "**" {
+			switch {
+			case len(segments) == 1:
+				ast.Insert(tree, ast.NewNode(ast.KindSuper, nil))
+			case i == len(segments)-1:
+				// Trailing `/**` matches the directory itself and everything
+				// inside of it.
+				ast.Insert(tree, anyInside())
+			case i == 0:
+				ast.Insert(tree, anyDirs())
+			default:
+				ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: "/"}), anyDirs())
+			}
+			dirs = true
+			continue
+		}
+		if i > 0 && !dirs {
+			ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: "/"}))
+		}
+		nodes, err := sh.nodes(s)
+		if err != nil {
+			return nil, err
+		}
+		ast.Insert(tree, nodes...)
+		dirs = false
+	}
+	return tree, nil
+}
+
+// expandBraces returns patterns the braces of the pattern expand to. For
+// instance, `a{b,c{d,e}}` expands to `ab`, `acd` and `ace`. Unterminated
+// braces are reported with path.ErrBadPattern.
+func expandBraces(pattern string) ([]string, error) {
+	open := -1
+	for i := 0; i < len(pattern); i++ {
+		if c := pattern[i]; c == '\\' {
+			i++
+		} else if c == '{' {
+			open = i
+			break
+		}
+	}
+	if open == -1 {
+		return []string{pattern}, nil
+	}
+
+	var (
+		alts  []string
+		depth int
+		start = open + 1
+		end   = -1
+	)
+	for i := start; i < len(pattern) && end == -1; i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+				break
+			}
+			alts = append(alts, pattern[start:i])
+			end = i
+		case ',':
+			if depth == 0 {
+				alts = append(alts, pattern[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if end == -1 {
+		return nil, path.ErrBadPattern
+	}
+
+	var out []string
+	for _, alt := range alts {
+		ps, err := expandBraces(pattern[:open] + alt + pattern[end+1:])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ps...)
+		if len(out) > maxBraceExpansion {
+			return nil, fmt.Errorf("pattern %q expands to more than %d patterns", pattern, maxBraceExpansion)
+		}
+	}
+	return out, nil
+}
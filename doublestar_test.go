@@ -0,0 +1,89 @@
+package glob
+
+import (
+	"path"
+	"testing"
+)
+
+func TestDialectDoublestar(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: `**/*.go`,
+			match:   []string{"a.go", "x/y/a.go"},
+			miss:    []string{"a.c", "x/a.go/b"},
+		},
+		{
+			pattern: `a/**`,
+			match:   []string{"a", "a/", "a/b", "a/b/c"},
+			miss:    []string{"ab", "b/a"},
+		},
+		{
+			pattern: `a/**/b`,
+			match:   []string{"a/b", "a/x/b", "a/x/y/b"},
+			miss:    []string{"a/xb", "ab"},
+		},
+		{
+			pattern: `a/**/**/b`,
+			match:   []string{"a/b", "a/x/y/b"},
+		},
+		{
+			pattern: `a**b`,
+			match:   []string{"ab", "axb"},
+			miss:    []string{"a/b"},
+		},
+		{
+			pattern: `**`,
+			match:   []string{"", "a", "a/b"},
+		},
+		{
+			pattern: `{a,b/c}/*.go`,
+			match:   []string{"a/x.go", "b/c/x.go"},
+			miss:    []string{"b/x.go", "c/x.go"},
+		},
+		{
+			pattern: `x{/**,}`,
+			match:   []string{"x", "x/y/z"},
+			miss:    []string{"xy"},
+		},
+		{
+			pattern: `{a,{b,c}d}`,
+			match:   []string{"a", "bd", "cd"},
+			miss:    []string{"b", "ad"},
+		},
+		{
+			pattern: `[!a]?[^b]`,
+			match:   []string{"bac", "xyz"},
+			miss:    []string{"abc", "xyb", "/ab", "x/a"},
+		},
+		{
+			pattern: `\{x\}`,
+			match:   []string{"{x}"},
+			miss:    []string{"x"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithDialect(DialectDoublestar))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+	for _, pattern := range []string{`[a`, `{a,b`, `a{b{c}`, `a\`} {
+		if _, err := CompileWith(pattern, WithDialect(DialectDoublestar)); err != path.ErrBadPattern {
+			t.Errorf("CompileWith(%q) error = %v; want %v", pattern, err, path.ErrBadPattern)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package glob
+
+import (
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// edgeRunes returns the sets of runes the non-empty strings matched by tree
+// could start and end with, and whether tree matches the empty string.
+// Runes matched by placeholders are not known, so they could be any.
+func edgeRunes(tree *ast.Node, seps runes.Set) (first, last runes.Set, empty bool) {
+	switch tree.Kind {
+	case ast.KindText:
+		s := tree.Value.(ast.Text).Text
+		if s == "" {
+			return nil, nil, true
+		}
+		f, _ := utf8.DecodeRuneInString(s)
+		l, _ := utf8.DecodeLastRuneInString(s)
+		return runes.Of(f), runes.Of(l), false
+
+	case ast.KindAny:
+		set := runes.All.Subtract(seps)
+		return set, set, true
+
+	case ast.KindSuper, ast.KindPlaceholder:
+		return runes.All, runes.All, true
+
+	case ast.KindSingle:
+		set := runes.All.Subtract(seps)
+		return set, set, false
+
+	case ast.KindList, ast.KindRange, ast.KindClass:
+		set := nodeSet(tree)
+		return set, set, false
+
+	case ast.KindPattern:
+		type edges struct {
+			first, last runes.Set
+			empty       bool
+		}
+		es := make([]edges, len(tree.Children))
+		for i, c := range tree.Children {
+			es[i].first, es[i].last, es[i].empty = edgeRunes(c, seps)
+		}
+		empty = true
+		for _, e := range es {
+			first = first.Union(e.first)
+			if !e.empty {
+				empty = false
+				break
+			}
+		}
+		for i := len(es) - 1; i >= 0; i-- {
+			last = last.Union(es[i].last)
+			if !es[i].empty {
+				break
+			}
+		}
+		return first, last, empty
+
+	case ast.KindAnyOf:
+		for _, c := range tree.Children {
+			f, l, e := edgeRunes(c, seps)
+			first = first.Union(f)
+			last = last.Union(l)
+			empty = empty || e
+		}
+		return first, last, empty
+
+	default:
+		return nil, nil, true
+	}
+}
+
+// edgeFilter returns the set of runes matched strings start or end with,
+// or nil if the set tells nothing about strings of the glob: it has all
+// the runes, or no non-empty string is matched.
+func edgeFilter(set runes.Set) runes.Set {
+	if set.Empty() || set.Equal(runes.All) {
+		return nil
+	}
+	return set
+}
@@ -0,0 +1,44 @@
+package glob
+
+import (
+	"testing"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+func TestEdgeRunes(t *testing.T) {
+	notSep := runes.All.Subtract(runes.Of('/'))
+	for _, test := range []struct {
+		pattern     string
+		first, last runes.Set
+	}{
+		{"{foo,bar}*baz", runes.Of('b', 'f'), runes.Of('z')},
+		{"a*", runes.Of('a'), notSep},
+		{"*.go", notSep, runes.Of('o')},
+		{"?x{,y}", notSep, runes.Of('x', 'y')},
+		{"[a-c]**[!x]", runes.Of('a', 'b', 'c'), runes.All.Subtract(runes.Of('x'))},
+		{"**", nil, nil},
+		{"", nil, nil},
+	} {
+		c := MustCompile(test.pattern, '/').(*compiled)
+		if !c.first.Equal(test.first) || !c.last.Equal(test.last) {
+			t.Errorf("%q: edge runes %v, %v; want %v, %v", test.pattern, c.first, c.last, test.first, test.last)
+		}
+	}
+
+	g := MustCompile("{foo,bar}*baz", '/')
+	for _, test := range []struct {
+		s   string
+		exp bool
+	}{
+		{"foobaz", true},
+		{"bar-baz", true},
+		{"qux-baz", false},
+		{"foo-bax", false},
+		{"foo/baz", false},
+	} {
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("Match(%q) = %t; want %t", test.s, act, test.exp)
+		}
+	}
+}
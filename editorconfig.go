@@ -0,0 +1,196 @@
+package glob
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// editorconfigTree returns syntax tree matching the same paths as the
+// editorconfig section name. See DialectEditorconfig for details.
+func editorconfigTree(pattern string) (*ast.Node, error) {
+	anchored := strings.Contains(pattern, "/")
+	nodes, err := editorconfigNodes(strings.TrimPrefix(pattern, "/"), true)
+	if err != nil {
+		return nil, err
+	}
+	tree := ast.NewNode(ast.KindPattern, nil)
+	if !anchored {
+		ast.Insert(tree, anyDirs())
+	}
+	ast.Insert(tree, nodes...)
+	return tree, nil
+}
+
+// editorconfigRange matches the contents of `{num1..num2}` braces.
+var editorconfigRange = regexp.MustCompile(`^([+-]?[0-9]+)\.\.([+-]?[0-9]+)$`)
+
+// editorconfigNodes returns syntax nodes for the section name s, which is
+// the whole name relative to the directory if top is true, or alternative
+// of braces otherwise. Special characters which do not form a valid
+// construct, like unterminated brackets or braces with a single
+// alternative, are matched literally.
+func editorconfigNodes(s string, top bool) ([]*ast.Node, error) {
+	var (
+		nodes []*ast.Node
+		text  []byte
+	)
+	flush := func() {
+		if len(text) > 0 {
+			nodes = append(nodes, ast.NewNode(ast.KindText, ast.Text{Text: string(text)}))
+			text = text[:0]
+		}
+	}
+	sh := shell{seps: []rune{'/'}}
+	for i := 0; i < len(s); {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 == len(s) {
+				text = append(text, c)
+				i++
+				break
+			}
+			_, w := utf8.DecodeRuneInString(s[i+1:])
+			text = append(text, s[i+1:i+1+w]...)
+			i += 1 + w
+
+		case '*':
+			flush()
+			j := i
+			for i < len(s) && s[i] == '*' {
+				i++
+			}
+			switch {
+			case i-j == 1:
+				nodes = append(nodes, ast.NewNode(ast.KindAny, nil))
+			case (j == 0 && top || j > 0 && s[j-1] == '/') && i < len(s) && s[i] == '/':
+				// `/**/` matches a single '/' as well. The name is
+				// preceded by '/' of the directory path.
+				nodes = append(nodes, anyDirs())
+				i++
+			default:
+				nodes = append(nodes, ast.NewNode(ast.KindSuper, nil))
+			}
+
+		case '?':
+			flush()
+			nodes = append(nodes, ast.NewNode(ast.KindSingle, nil))
+			i++
+
+		case '[':
+			set, n, err := sh.class(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 || strings.Contains(s[i:i+n], "/") {
+				// Brackets containing '/' are matched literally.
+				text = append(text, c)
+				i++
+				break
+			}
+			flush()
+			nodes = append(nodes, classOrNothing(set))
+			i += n
+
+		case '{':
+			alts, n := splitBraces(s[i:])
+			if n == 0 {
+				text = append(text, c)
+				i++
+				break
+			}
+			if m := editorconfigRange.FindStringSubmatch(s[i+1 : i+n-1]); m != nil {
+				node, err := numberRange(m[1], m[2])
+				if err != nil {
+					return nil, err
+				}
+				flush()
+				nodes = append(nodes, node)
+				i += n
+				break
+			}
+			if len(alts) == 1 {
+				text = append(text, c)
+				i++
+				break
+			}
+			flush()
+			node := ast.NewNode(ast.KindAnyOf, nil)
+			for _, alt := range alts {
+				ns, err := editorconfigNodes(alt, false)
+				if err != nil {
+					return nil, err
+				}
+				ast.Insert(node, ast.NewNode(ast.KindPattern, nil, ns...))
+			}
+			nodes = append(nodes, node)
+			i += n
+
+		default:
+			text = append(text, c)
+			i++
+		}
+	}
+	flush()
+	return nodes, nil
+}
+
+// splitBraces returns top level alternatives of the braces at the beginning
+// of s, and the length of the braces, which is zero if they are not
+// terminated.
+func splitBraces(s string) (alts []string, n int) {
+	var (
+		depth int
+		start = 1
+	)
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			if depth > 0 {
+				depth--
+				break
+			}
+			return append(alts, s[start:i]), i + 1
+		case ',':
+			if depth == 0 {
+				alts = append(alts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return nil, 0
+}
+
+// numberRange returns node matching decimal integers between lo and hi,
+// which are written without leading zeros and with `-` sign if negative.
+func numberRange(lo, hi string) (*ast.Node, error) {
+	a, err := strconv.Atoi(lo)
+	if err != nil {
+		return nil, err
+	}
+	b, err := strconv.Atoi(hi)
+	if err != nil {
+		return nil, err
+	}
+	if a > b {
+		a, b = b, a
+	}
+	if b-a < 0 || b-a >= maxBraceExpansion {
+		return nil, fmt.Errorf("range {%s..%s} has more than %d numbers", lo, hi, maxBraceExpansion)
+	}
+	node := ast.NewNode(ast.KindAnyOf, nil)
+	for x := a; x <= b; x++ {
+		ast.Insert(node, ast.NewNode(ast.KindPattern, nil,
+			ast.NewNode(ast.KindText, ast.Text{Text: strconv.Itoa(x)}),
+		))
+	}
+	return node, nil
+}
@@ -0,0 +1,108 @@
+package glob
+
+import "testing"
+
+func TestDialectEditorconfig(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: `*.go`,
+			match:   []string{"a.go", "x/y/a.go", ".go"},
+			miss:    []string{"a.c", "x/a.go/b"},
+		},
+		{
+			pattern: `lib/*.js`,
+			match:   []string{"lib/a.js"},
+			miss:    []string{"x/lib/a.js", "lib/x/a.js"},
+		},
+		{
+			pattern: `/Makefile`,
+			match:   []string{"Makefile"},
+			miss:    []string{"x/Makefile"},
+		},
+		{
+			pattern: `a**.c`,
+			match:   []string{"a.c", "ab/c.c", "x/a/b.c"},
+			miss:    []string{"b.c"},
+		},
+		{
+			pattern: `a/**/z.c`,
+			match:   []string{"a/z.c", "a/b/z.c", "a/b/c/z.c"},
+			miss:    []string{"az.c", "x/a/z.c"},
+		},
+		{
+			pattern: `**/z.c`,
+			match:   []string{"z.c", "a/z.c"},
+			miss:    []string{"az.c"},
+		},
+		{
+			pattern: `*.{js,py}`,
+			match:   []string{"a.js", "x/a.py"},
+			miss:    []string{"a.go", "a.{js,py}"},
+		},
+		{
+			pattern: `{a,{b,c}d}`,
+			match:   []string{"a", "bd", "x/cd"},
+			miss:    []string{"b", "ad"},
+		},
+		{
+			pattern: `{single}.b`,
+			match:   []string{"{single}.b"},
+			miss:    []string{"single.b"},
+		},
+		{
+			pattern: `{a,b`,
+			match:   []string{"{a,b"},
+			miss:    []string{"a", "b"},
+		},
+		{
+			pattern: `{word,{also},this}.g`,
+			match:   []string{"word.g", "{also}.g", "this.g"},
+			miss:    []string{"also.g"},
+		},
+		{
+			pattern: `{3..120}`,
+			match:   []string{"3", "45", "120"},
+			miss:    []string{"2", "121", "045", "a"},
+		},
+		{
+			pattern: `f{-3..-1}`,
+			match:   []string{"f-3", "f-1"},
+			miss:    []string{"f0", "f1", "f-4"},
+		},
+		{
+			pattern: `[!a-c]x`,
+			match:   []string{"dx", "Ax"},
+			miss:    []string{"ax", "a/x"},
+		},
+		{
+			pattern: `ab[e/]cd.i`,
+			match:   []string{"ab[e/]cd.i"},
+			miss:    []string{"abecd.i", "ab/cd.i"},
+		},
+		{
+			pattern: `a\*b?`,
+			match:   []string{"a*bc"},
+			miss:    []string{"axbc", "a*b/"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithDialect(DialectEditorconfig))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+}
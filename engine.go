@@ -0,0 +1,147 @@
+package glob
+
+import (
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/nfa"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Engine is the way a glob matches strings.
+type Engine int
+
+const (
+	// EngineDefault matches strings with a tree of matchers, which split
+	// the string around literal parts of the pattern. It is the fastest for
+	// most patterns, but could backtrack on patterns with many wildcards,
+	// like `*a*b*c*`.
+	EngineDefault Engine = iota
+
+	// EngineDFA matches strings with a deterministic automaton, reading
+	// every rune of the string once and never backtracking. Transitions on
+	// ASCII runes are looked up in a table. The automaton could grow
+	// exponentially for some patterns, like `*a?????????`; if it exceeds
+	// 1000 states, or if the pattern has placeholders, EngineDefault is
+	// used instead.
+	EngineDFA
+
+	// EnginePikeVM matches strings by simulating a nondeterministic
+	// automaton, tracking all the states it could be in, as RE2 does. It
+	// takes time linear in the length of the string for any pattern, which
+	// bounds the worst case for untrusted patterns and inputs, at the cost
+	// of slower matching of ordinary ones. If the pattern has placeholders,
+	// EngineDefault is used instead.
+	EnginePikeVM
+)
+
+// WithEngine sets the way the glob matches strings.
+func WithEngine(e Engine) Option {
+	return func(o *options) {
+		o.engine = e
+	}
+}
+
+// dfaEngineStates is the limit of states of automata built for EngineDFA.
+const dfaEngineStates = 1000
+
+// engineMatcher returns the matcher of the glob for the engine, or false
+// if the default matcher should be used.
+func engineMatcher(e Engine, tree *ast.Node, separators []rune) (match.Matcher, bool) {
+	if countKind(tree, ast.KindPlaceholder) > 0 {
+		return nil, false
+	}
+	switch e {
+	case EngineDFA:
+		d, ok := nfa.NewDFA([]*nfa.NFA{nfa.New(tree, separators)}, dfaEngineStates)
+		if ok {
+			return dfaMatcher{d}, true
+		}
+	case EnginePikeVM:
+		return pikeMatcher{nfa.New(tree, separators)}, true
+	}
+	return nil, false
+}
+
+// dfaMatcher matches strings with a deterministic automaton.
+type dfaMatcher struct {
+	DFA *nfa.DFA
+}
+
+func (m dfaMatcher) Match(s string) bool {
+	return m.DFA.Match(s) != nil
+}
+
+func (m dfaMatcher) Index(s string) (int, []int) {
+	return m.AppendIndex(nil, s)
+}
+
+func (m dfaMatcher) AppendIndex(buf []int, s string) (int, []int) {
+	if len(m.DFA.States) == 0 {
+		return -1, buf
+	}
+	start := len(buf)
+	for i := 0; ; {
+		segments := buf[:start]
+		if len(m.DFA.States[0].Accept) > 0 {
+			segments = append(segments, 0)
+		}
+		var st int32
+		for j := i; j < len(s); {
+			var w int
+			if st, w = m.DFA.Step(st, s[j:]); st == -1 {
+				break
+			}
+			j += w
+			if len(m.DFA.States[st].Accept) > 0 {
+				segments = append(segments, j-i)
+			}
+		}
+		if len(segments) > start {
+			return i, segments
+		}
+		if i == len(s) {
+			return -1, buf
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+}
+
+func (m dfaMatcher) Len() int {
+	return -1
+}
+
+func (m dfaMatcher) String() string {
+	return "<dfa>"
+}
+
+// pikeMatcher matches strings by simulating a nondeterministic automaton.
+type pikeMatcher struct {
+	NFA *nfa.NFA
+}
+
+func (m pikeMatcher) Match(s string) bool {
+	return m.NFA.Match(s)
+}
+
+func (m pikeMatcher) Index(s string) (int, []int) {
+	for i := 0; ; {
+		if segments := m.NFA.Prefixes(s[i:]); len(segments) > 0 {
+			return i, segments
+		}
+		if i == len(s) {
+			return -1, nil
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+}
+
+func (m pikeMatcher) Len() int {
+	return -1
+}
+
+func (m pikeMatcher) String() string {
+	return "<pikevm>"
+}
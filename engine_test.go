@@ -0,0 +1,63 @@
+package glob
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEngine(t *testing.T) {
+	patterns := []string{
+		"*a*b*c*", "*.go", "{src,cmd}/**/*.go", "[a-c]?[!x]", "", "**", "ü*ß", "a\\*b", "*/*",
+		"*\ufffd", "\ufffd*", "[\ufffd]?", "?[!a]",
+	}
+	subjects := []string{
+		"abc", "xaybzc", "cba", "a.go", "src/a/b.go", "cmd/x.go", "doc/x.go", "abx", "bcy",
+		"", "üxß", "a*b", "axb", "a/b", "a/b/c", strings.Repeat("a", 64),
+		// invalid bytes are told apart from U+FFFD by every engine
+		"\xe2\x82\xe2\x82\xe2\x82", "\xffab\xff", "\ufffd", "\ufffdx", "\xff\xfe", "\xffa",
+	}
+	for _, e := range []Engine{EngineDFA, EnginePikeVM} {
+		for _, p := range patterns {
+			g := MustCompileWith(p, WithSeparators('/'), WithEngine(e))
+			want := MustCompile(p, '/')
+			for _, s := range subjects {
+				if act := g.Match(s); act != want.Match(s) {
+					t.Errorf("engine %d: %q Match(%q) = %t; want %t", e, p, s, act, !act)
+				}
+			}
+		}
+	}
+
+	g := MustCompileWith("*a*b*c*", WithEngine(EngineDFA))
+	if _, ok := g.(*compiled).Matcher.(dfaMatcher); !ok {
+		t.Errorf("EngineDFA does not use DFA")
+	}
+	if g.Match(strings.Repeat("ab", 1<<16)) {
+		t.Errorf("unexpected match")
+	}
+	g = MustCompileWith("*a?????????", WithEngine(EngineDFA))
+	if _, ok := g.(*compiled).Matcher.(dfaMatcher); ok {
+		t.Errorf("EngineDFA uses DFA beyond the limit")
+	}
+
+	m := dfaMatcher{MustCompileWith("a*", WithEngine(EngineDFA)).(*compiled).Matcher.(dfaMatcher).DFA}
+	if i, segments := m.Index("xxab"); i != 2 || !reflect.DeepEqual(segments, []int{1, 2}) {
+		t.Errorf("Index() = %d, %v; want 2, [1 2]", i, segments)
+	}
+	if i, _ := m.Index("xyz"); i != -1 {
+		t.Errorf("Index() = %d; want -1", i)
+	}
+
+	if MustCompileWith("*a*a*a*a*a*a*b", WithEngine(EnginePikeVM)).Match(strings.Repeat("a", 1<<14)) {
+		t.Errorf("unexpected match")
+	}
+	g = MustCompileWith("a*c", WithEngine(EnginePikeVM))
+	p, ok := g.(*compiled).Matcher.(pikeMatcher)
+	if !ok {
+		t.Fatalf("EnginePikeVM does not use NFA")
+	}
+	if i, segments := p.Index("xxabc"); i != 2 || !reflect.DeepEqual(segments, []int{3}) {
+		t.Errorf("Index() = %d, %v; want 2, [3]", i, segments)
+	}
+}
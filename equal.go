@@ -0,0 +1,121 @@
+package glob
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gobwas/glob/match"
+)
+
+// Equal reports whether a and b are compiled into structurally equal
+// matchers, and normalize strings the same way, if they do. Differently
+// written patterns could be equal after optimization: for example, `{abc}`
+// and `abc` are, as well as `**` and `***`.
+func Equal(a, b Glob) bool {
+	ca, oka := canonical(a)
+	cb, okb := canonical(b)
+	if !oka || !okb {
+		return reflect.DeepEqual(a, b)
+	}
+	return bytes.Equal(ca, cb)
+}
+
+// canonical returns a deterministic encoding of the matcher tree g is
+// compiled to, which is the same for structurally equal trees, along with
+// the normalization of strings, if any. It returns false if g is not
+// compiled to a matcher.
+func canonical(g Glob) ([]byte, bool) {
+	switch g.(type) {
+	case *compiled, *combined, match.Matcher:
+	default:
+		return nil, false
+	}
+	var buf bytes.Buffer
+	encodeValue(&buf, reflect.ValueOf(g))
+	return buf.Bytes(), true
+}
+
+var (
+	compiledType = reflect.TypeOf((*compiled)(nil))
+	combinedType = reflect.TypeOf((*combined)(nil))
+)
+
+func encodeValue(buf *bytes.Buffer, v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		buf.WriteString("nil")
+
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			buf.WriteString("nil")
+			return
+		}
+		if t := v.Type(); t == compiledType || t == combinedType {
+			// Globs are encoded as their matchers and the normalization,
+			// as other fields, like syntax trees, have cycles.
+			encodeValue(buf, v.Elem().FieldByName("Matcher"))
+			if t == compiledType {
+				if n := v.Elem().FieldByName("normalization").String(); n != "" {
+					buf.WriteByte('|')
+					buf.WriteString(strconv.Quote(n))
+				}
+			}
+			return
+		}
+		encodeValue(buf, v.Elem())
+
+	case reflect.Struct:
+		buf.WriteString(v.Type().String())
+		buf.WriteByte('{')
+		for i := 0; i < v.NumField(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			encodeValue(buf, v.Field(i))
+		}
+		buf.WriteByte('}')
+
+	case reflect.Slice, reflect.Array:
+		buf.WriteByte('[')
+		buf.WriteString(strconv.Itoa(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			buf.WriteByte(',')
+			encodeValue(buf, v.Index(i))
+		}
+		buf.WriteByte(']')
+
+	case reflect.Map:
+		// Entries are sorted by their encoding, as the order of iteration
+		// is random.
+		entries := make([]string, 0, v.Len())
+		for _, k := range v.MapKeys() {
+			var e bytes.Buffer
+			encodeValue(&e, k)
+			e.WriteByte(':')
+			encodeValue(&e, v.MapIndex(k))
+			entries = append(entries, e.String())
+		}
+		sort.Strings(entries)
+		buf.WriteByte('{')
+		buf.WriteString(strings.Join(entries, ","))
+		buf.WriteByte('}')
+
+	case reflect.String:
+		buf.WriteString(strconv.Quote(v.String()))
+
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+
+	default:
+		buf.WriteString(v.Type().String())
+	}
+}
@@ -0,0 +1,68 @@
+package glob
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	for id, test := range []struct {
+		a, b       string
+		separators []rune
+		exp        bool
+	}{
+		{"abc", "abc", nil, true},
+		{"abc", "{abc}", nil, true},
+		{"abc", "{abc,abc}", nil, true},
+		{"a[b]c", "abc", nil, false},
+		{"**", "***", nil, true},
+		{"*", "**", nil, true},
+		{"*", "**", []rune{'/'}, false},
+		{"abc", "abd", nil, false},
+		{"a*", "a?", nil, false},
+		{"{a,b}", "{b,a}", nil, true},
+		{"{a,b}", "{a,c}", nil, false},
+		{"{a*,b?}", "{b?,a*}", nil, false},
+	} {
+		a := MustCompile(test.a, test.separators...)
+		b := MustCompile(test.b, test.separators...)
+		if act := Equal(a, b); act != test.exp {
+			t.Errorf("#%d Equal(%q, %q) = %t; want %t\n%s\n%s", id, test.a, test.b, act, test.exp, a, b)
+		}
+	}
+}
+
+func TestEqualNormalization(t *testing.T) {
+	for id, test := range []struct {
+		a, b []Option
+		exp  bool
+	}{
+		{nil, nil, true},
+		{nil, []Option{WithURLPath(true)}, false},
+		{[]Option{WithURLPath(true)}, []Option{WithURLPath(true)}, true},
+		{[]Option{WithURLPath(true)}, []Option{WithURLPath(false)}, false},
+		{nil, []Option{WithNormalization(composer{})}, false},
+		{[]Option{WithNormalization(composer{})}, []Option{WithNormalization(composer{})}, true},
+		{nil, []Option{WithInvalidUTF8(InvalidUTF8Bytes)}, true},
+		{nil, []Option{WithInvalidUTF8(InvalidUTF8Reject)}, false},
+		{[]Option{WithInvalidUTF8(InvalidUTF8Reject)}, []Option{WithInvalidUTF8(InvalidUTF8Replace)}, false},
+		{
+			[]Option{WithURLPath(true), WithInvalidUTF8(InvalidUTF8Reject)},
+			[]Option{WithURLPath(true), WithInvalidUTF8(InvalidUTF8Reject)},
+			true,
+		},
+		{
+			[]Option{WithURLPath(true), WithInvalidUTF8(InvalidUTF8Reject)},
+			[]Option{WithURLPath(true), WithInvalidUTF8(InvalidUTF8Replace)},
+			false,
+		},
+	} {
+		a, b := MustCompileWith("/a", test.a...), MustCompileWith("/a", test.b...)
+		if act := Equal(a, b); act != test.exp {
+			t.Errorf("#%d Equal() = %t; want %t", id, act, test.exp)
+		}
+		if act := Hash(a) == Hash(b); act != test.exp {
+			t.Errorf("#%d Hash() == Hash() is %t; want %t", id, act, test.exp)
+		}
+		if act := Equal(Not(a), Not(b)); act != test.exp {
+			t.Errorf("#%d Equal() of Not() = %t; want %t", id, act, test.exp)
+		}
+	}
+}
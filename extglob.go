@@ -0,0 +1,95 @@
+package glob
+
+import (
+	"github.com/gobwas/glob/compiler"
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// extglob stands for extended pattern `*(...)`, `+(...)` or `!(...)` in
+// syntax trees until they are compiled, as there are no nodes for
+// repetitions and complements. Options transforming trees, like
+// WithCaseFold, transform its alternatives as well.
+type extglob struct {
+	op   byte      // '*', '+' or '!'
+	alts *ast.Node // KindAnyOf node of the alternatives
+
+	// more marks repetitions following the first one at the beginning of a
+	// file name.
+	more bool
+
+	// At the beginning of a file name, the complement is split in two:
+	// one matching only the empty string, after which the name begins
+	// still, and one matching other strings, which do not start with
+	// period.
+	empty    bool
+	noPeriod bool
+}
+
+// extglobNode returns the node of the extended pattern, named as it is
+// written.
+func extglobNode(name string, e extglob) *ast.Node {
+	return ast.NewNode(ast.KindPlaceholder, &ast.Placeholder{
+		Name:    name,
+		Matcher: e,
+	})
+}
+
+// with returns the node of the extended pattern of the same kind as n, but
+// with the given alternatives.
+func (e extglob) with(n *ast.Node, alts *ast.Node) *ast.Node {
+	e.alts = alts
+	return extglobNode(n.Value.(*ast.Placeholder).Name, e)
+}
+
+// extglobTree returns a copy of the tree, in which extended patterns are
+// replaced with placeholders of their matchers, which match repetitions of
+// the alternatives with match.Repeat, or their complement.
+func extglobTree(tree *ast.Node, seps []rune) (*ast.Node, error) {
+	if tree.Kind == ast.KindPlaceholder {
+		p := tree.Value.(*ast.Placeholder)
+		e, ok := p.Matcher.(extglob)
+		if !ok {
+			return tree, nil
+		}
+		alts, err := extglobTree(e.alts, seps)
+		if err != nil {
+			return nil, err
+		}
+		child, err := compiler.Compile(alts, seps)
+		if err != nil {
+			return nil, err
+		}
+		min := 0
+		if e.op == '+' {
+			min = 1
+		}
+		var m match.Matcher = match.NewRepeat(child, min, -1)
+		if e.op == '!' {
+			m = e.complement(child, seps)
+		}
+		return ast.NewNode(ast.KindPlaceholder, &ast.Placeholder{Name: p.Name, Matcher: m}), nil
+	}
+	n := ast.NewNode(tree.Kind, tree.Value)
+	for _, c := range tree.Children {
+		c, err := extglobTree(c, seps)
+		if err != nil {
+			return nil, err
+		}
+		ast.Insert(n, c)
+	}
+	return n, nil
+}
+
+// complement returns matcher of strings without separators, which the
+// alternatives do not match, restricted as set by empty and noPeriod.
+func (e extglob) complement(alts match.Matcher, seps []rune) match.Matcher {
+	all := []match.Matcher{match.NewNot(alts), match.NewAny(seps)}
+	switch {
+	case e.empty:
+		all = append(all, match.NewNothing())
+	case e.noPeriod:
+		all = append(all, match.NewNot(match.NewNothing()), match.NewNot(match.NewPrefix(".")))
+	}
+	return match.NewEveryOf(all...)
+}
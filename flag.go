@@ -0,0 +1,142 @@
+package glob
+
+import (
+	"strings"
+)
+
+// GlobFlag is a flag.Value holding the glob compiled from the flag value,
+// so that command line interfaces could accept patterns like
+// `--include '*.go'`. Invalid pattern is reported as the flag parse error.
+// It also implements the Value interface of github.com/spf13/pflag.
+type GlobFlag struct {
+	// Glob is the compiled pattern, which is nil until the flag is set.
+	Glob Glob
+
+	// Options are used to compile the pattern.
+	Options []Option
+
+	pattern string
+}
+
+// NewGlobFlag returns flag compiling the pattern with given options.
+func NewGlobFlag(opts ...Option) *GlobFlag {
+	return &GlobFlag{Options: opts}
+}
+
+// String returns the pattern the flag was set to.
+func (f *GlobFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.pattern
+}
+
+// Set compiles the pattern.
+func (f *GlobFlag) Set(pattern string) error {
+	g, err := CompileWith(pattern, f.Options...)
+	if err != nil {
+		return err
+	}
+	f.Glob, f.pattern = g, pattern
+	return nil
+}
+
+// Type returns the name of the flag type.
+func (f *GlobFlag) Type() string {
+	return "glob"
+}
+
+// Get returns the compiled glob. It implements the flag.Getter interface.
+func (f *GlobFlag) Get() interface{} {
+	return f.Glob
+}
+
+// Match reports whether s matches the glob. It is false if the flag is not
+// set.
+func (f *GlobFlag) Match(s string) bool {
+	return f.Glob != nil && f.Glob.Match(s)
+}
+
+// GlobSliceFlag is a flag.Value holding globs compiled from all values of
+// the repeated flag, like `--include '*.go' --include '*.md'`. Unlike
+// string slices of pflag, values are not split on commas, which are used in
+// patterns. It also implements the SliceValue interface of pflag.
+type GlobSliceFlag struct {
+	// Globs are the compiled patterns in order of the flags.
+	Globs []Glob
+
+	// Options are used to compile the patterns.
+	Options []Option
+
+	patterns []string
+}
+
+// NewGlobSliceFlag returns flag compiling the patterns with given options.
+func NewGlobSliceFlag(opts ...Option) *GlobSliceFlag {
+	return &GlobSliceFlag{Options: opts}
+}
+
+// String returns the patterns joined with commas.
+func (f *GlobSliceFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return strings.Join(f.patterns, ",")
+}
+
+// Set compiles the pattern and appends it to the list.
+func (f *GlobSliceFlag) Set(pattern string) error {
+	return f.Append(pattern)
+}
+
+// Type returns the name of the flag type.
+func (f *GlobSliceFlag) Type() string {
+	return "globs"
+}
+
+// Get returns the compiled globs. It implements the flag.Getter interface.
+func (f *GlobSliceFlag) Get() interface{} {
+	return f.Globs
+}
+
+// Append compiles the pattern and appends it to the list.
+func (f *GlobSliceFlag) Append(pattern string) error {
+	g, err := CompileWith(pattern, f.Options...)
+	if err != nil {
+		return err
+	}
+	f.Globs = append(f.Globs, g)
+	f.patterns = append(f.patterns, pattern)
+	return nil
+}
+
+// Replace compiles the patterns and replaces the list with them. The list
+// is left unchanged if any of the patterns is invalid.
+func (f *GlobSliceFlag) Replace(patterns []string) error {
+	globs := make([]Glob, len(patterns))
+	for i, p := range patterns {
+		g, err := CompileWith(p, f.Options...)
+		if err != nil {
+			return err
+		}
+		globs[i] = g
+	}
+	f.Globs = globs
+	f.patterns = append([]string(nil), patterns...)
+	return nil
+}
+
+// GetSlice returns the patterns.
+func (f *GlobSliceFlag) GetSlice() []string {
+	return append([]string(nil), f.patterns...)
+}
+
+// Match reports whether s matches any of the globs.
+func (f *GlobSliceFlag) Match(s string) bool {
+	for _, g := range f.Globs {
+		if g.Match(s) {
+			return true
+		}
+	}
+	return false
+}
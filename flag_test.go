@@ -0,0 +1,58 @@
+package glob
+
+import (
+	"flag"
+	"io/ioutil"
+	"testing"
+)
+
+func TestGlobFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	var (
+		include = NewGlobSliceFlag(WithSeparators('/'))
+		exclude = NewGlobFlag(WithSeparators('/'))
+	)
+	fs.Var(include, "include", "")
+	fs.Var(exclude, "exclude", "")
+	err := fs.Parse([]string{"-include", "*.{go,md}", "-include", "cmd/**", "-exclude", "*_test.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := include.String(), "*.{go,md},cmd/**"; act != exp {
+		t.Errorf("String() = %q; want %q", act, exp)
+	}
+	for _, test := range []struct {
+		path    string
+		include bool
+		exclude bool
+	}{
+		{"glob.go", true, false},
+		{"glob_test.go", true, true},
+		{"cmd/a/b.c", true, false},
+		{"a/b.go", false, false},
+	} {
+		if act := include.Match(test.path); act != test.include {
+			t.Errorf("include.Match(%q) = %t; want %t", test.path, act, test.include)
+		}
+		if act := exclude.Match(test.path); act != test.exclude {
+			t.Errorf("exclude.Match(%q) = %t; want %t", test.path, act, test.exclude)
+		}
+	}
+
+	if err := fs.Parse([]string{"-exclude", "[a"}); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+	if exclude.String() != "*_test.go" {
+		t.Errorf("invalid pattern changed the flag value to %q", exclude.String())
+	}
+	if err := include.Replace([]string{"a", "[b"}); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+	if act := include.GetSlice(); len(act) != 2 {
+		t.Errorf("invalid pattern changed the flag value to %q", act)
+	}
+	if NewGlobFlag().Match("") {
+		t.Errorf("unset flag should not match")
+	}
+}
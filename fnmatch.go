@@ -0,0 +1,293 @@
+package glob
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// Flags of Fnmatch, which have the same values as in glibc.
+const (
+	FNM_PATHNAME = 1 << 0 // separator '/' is matched only by '/'
+	FNM_NOESCAPE = 1 << 1 // backslash is an ordinary character
+	FNM_PERIOD   = 1 << 2 // leading period is matched only by period
+	FNM_CASEFOLD = 1 << 4 // case-insensitive matching
+	FNM_EXTMATCH = 1 << 5 // extended patterns of ksh, like `*(a|b)`
+)
+
+// FnmatchOptions returns compile options with DialectFnmatch corresponding
+// to fnmatch(3) flags. If FNM_PATHNAME is set, '/' is the separator, and if
+// FNM_PERIOD is set along with it, period is treated as leading right after
+// '/' as well.
+func FnmatchOptions(flags int) []Option {
+	opts := []Option{WithDialect(DialectFnmatch)}
+	if flags&FNM_PATHNAME != 0 {
+		opts = append(opts, WithSeparators('/'))
+	}
+	if flags&FNM_NOESCAPE != 0 {
+		opts = append(opts, WithNoEscape())
+	}
+	if flags&FNM_PERIOD != 0 {
+		opts = append(opts, WithLeadingPeriod())
+	}
+	if flags&FNM_CASEFOLD != 0 {
+		opts = append(opts, WithCaseFold())
+	}
+	if flags&FNM_EXTMATCH != 0 {
+		opts = append(opts, func(o *options) {
+			o.extglob = true
+		})
+	}
+	return opts
+}
+
+// Fnmatch reports whether name matches the shell pattern, as fnmatch(3)
+// does with given flags. The only possible error is ill-formed pattern.
+// Fnmatch compiles the pattern on every call; use CompileWith with
+// FnmatchOptions to match many names.
+func Fnmatch(pattern, name string, flags int) (bool, error) {
+	g, err := CompileWith(pattern, FnmatchOptions(flags)...)
+	if err != nil {
+		return false, err
+	}
+	return g.Match(name), nil
+}
+
+// shell converts shell style patterns, as understood by fnmatch(3), into
+// syntax nodes. Unlike native syntax, braces are not special there, and
+// character classes use `!` or `^` for negation and support POSIX classes
+// like `[:alpha:]`.
+type shell struct {
+	// seps are runes that are never matched by character classes.
+	seps []rune
+
+	// noEscape disables escaping with backslash.
+	noEscape bool
+
+	// caretOnly makes only `^` negate character classes. Otherwise `!`
+	// negates them too.
+	caretOnly bool
+
+	// doubleStar makes sequences of two or more `*` match separators too.
+	doubleStar bool
+
+	// strict makes unterminated classes and trailing backslash to be
+	// reported with path.ErrBadPattern.
+	strict bool
+
+	// extglob enables extended patterns of bash, like `@(a|b)`.
+	extglob bool
+}
+
+// nodes returns syntax nodes for the shell pattern s. Sequences of `*` are
+// converted into a single KindAny node, or KindSuper node if doubleStar is
+// set and there are several of them.
+func (sh shell) nodes(s string) ([]*ast.Node, error) {
+	var (
+		nodes []*ast.Node
+		text  []byte
+	)
+	flush := func() {
+		if len(text) > 0 {
+			nodes = append(nodes, ast.NewNode(ast.KindText, ast.Text{Text: string(text)}))
+			text = text[:0]
+		}
+	}
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case sh.isExtglob(s[i:]):
+			node, n, err := sh.extpattern(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				// Unterminated pattern is matched literally.
+				text = append(text, c)
+				i++
+				break
+			}
+			flush()
+			nodes = append(nodes, node)
+			i += n
+
+		case c == '*':
+			flush()
+			j := i
+			for i < len(s) && s[i] == '*' && !sh.isExtglob(s[i:]) {
+				i++
+			}
+			if sh.doubleStar && i-j > 1 {
+				nodes = append(nodes, ast.NewNode(ast.KindSuper, nil))
+			} else {
+				nodes = append(nodes, ast.NewNode(ast.KindAny, nil))
+			}
+
+		case c == '?':
+			flush()
+			nodes = append(nodes, ast.NewNode(ast.KindSingle, nil))
+			i++
+
+		case c == '[':
+			set, n, err := sh.class(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 && sh.strict {
+				return nil, path.ErrBadPattern
+			}
+			if n == 0 {
+				// Unterminated class is matched literally.
+				text = append(text, c)
+				i++
+				break
+			}
+			flush()
+			nodes = append(nodes, classOrNothing(set))
+			i += n
+
+		case c == '\\' && !sh.noEscape:
+			if i+1 == len(s) && sh.strict {
+				return nil, path.ErrBadPattern
+			}
+			if i+1 == len(s) {
+				return nil, fmt.Errorf("pattern %q ends with unescaped backslash", s)
+			}
+			_, w := utf8.DecodeRuneInString(s[i+1:])
+			text = append(text, s[i+1:i+1+w]...)
+			i += 1 + w
+
+		default:
+			text = append(text, c)
+			i++
+		}
+	}
+	flush()
+	return nodes, nil
+}
+
+// isExtglob reports whether s starts with extended pattern.
+func (sh shell) isExtglob(s string) bool {
+	return sh.extglob && len(s) > 1 && s[1] == '(' && strings.IndexByte("?*+@!", s[0]) != -1
+}
+
+// extpattern parses extended pattern at the beginning of s. It returns node
+// of the pattern and its length, which is zero if the pattern is not
+// terminated. Patterns `@(...)` and `?(...)` are alternatives, while others
+// are left to extglobTree, as there are no nodes for them.
+func (sh shell) extpattern(s string) (*ast.Node, int, error) {
+	var (
+		alts  []string
+		depth int
+		start = 2
+		n     int
+	)
+	for i := start; i < len(s) && n == 0; i++ {
+		switch s[i] {
+		case '\\':
+			if !sh.noEscape {
+				i++
+			}
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+				break
+			}
+			alts = append(alts, s[start:i])
+			n = i + 1
+		case '|':
+			if depth == 0 {
+				alts = append(alts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	if n == 0 {
+		return nil, 0, nil
+	}
+
+	node := ast.NewNode(ast.KindAnyOf, nil)
+	if s[0] == '?' {
+		ast.Insert(node, ast.NewNode(ast.KindPattern, nil))
+	}
+	for _, alt := range alts {
+		nodes, err := sh.nodes(alt)
+		if err != nil {
+			return nil, 0, err
+		}
+		ast.Insert(node, ast.NewNode(ast.KindPattern, nil, nodes...))
+	}
+	switch s[0] {
+	case '*', '+', '!':
+		return extglobNode(s[:n], extglob{op: s[0], alts: node}), n, nil
+	}
+	return node, n, nil
+}
+
+// class parses character class at the beginning of s. It returns set of
+// runes the class matches, which could be empty, and the length of the
+// class, which is zero if the class is not terminated.
+func (sh shell) class(s string) (set runes.Set, n int, err error) {
+	i := 1
+	not := i < len(s) && (s[i] == '^' || s[i] == '!' && !sh.caretOnly)
+	if not {
+		i++
+	}
+	for first := true; ; first = false {
+		if i >= len(s) {
+			return nil, 0, nil
+		}
+		if s[i] == ']' && !first {
+			i++
+			break
+		}
+		if strings.HasPrefix(s[i:], "[:") {
+			if j := strings.Index(s[i+2:], ":]"); j != -1 {
+				name := s[i+2 : i+2+j]
+				c, ok := runes.Class(name)
+				if !ok {
+					return nil, 0, fmt.Errorf("unknown character class %q", name)
+				}
+				set = set.Union(c)
+				i += 2 + j + 2
+				continue
+			}
+		}
+		lo, w := sh.classRune(s[i:])
+		if w == 0 {
+			return nil, 0, nil
+		}
+		i += w
+		hi := lo
+		if i+1 < len(s) && s[i] == '-' && s[i+1] != ']' {
+			if hi, w = sh.classRune(s[i+1:]); w == 0 {
+				return nil, 0, nil
+			}
+			i += 1 + w
+		}
+		if hi < lo {
+			return nil, 0, fmt.Errorf("invalid character range %q-%q", lo, hi)
+		}
+		set = set.Union(runes.NewSet(runes.Range{Lo: lo, Hi: hi}))
+	}
+	if not {
+		set = set.Complement()
+	}
+	return set.Subtract(runes.Of(sh.seps...)), i, nil
+}
+
+func (sh shell) classRune(s string) (r rune, n int) {
+	if s[0] == '\\' && !sh.noEscape {
+		if len(s) == 1 {
+			return 0, 0
+		}
+		r, n = utf8.DecodeRuneInString(s[1:])
+		return r, n + 1
+	}
+	return utf8.DecodeRuneInString(s)
+}
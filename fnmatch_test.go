@@ -0,0 +1,100 @@
+package glob
+
+import "testing"
+
+func TestFnmatch(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		name    string
+		flags   int
+		exp     bool
+	}{
+		{"*.c", "main.c", 0, true},
+		{"*.c", "src/main.c", 0, true},
+		{"*.c", "src/main.c", FNM_PATHNAME, false},
+		{"*/*.c", "src/main.c", FNM_PATHNAME, true},
+		{"src?main.c", "src/main.c", FNM_PATHNAME, false},
+		{"src[/]main.c", "src/main.c", FNM_PATHNAME, false},
+		{"src[/]main.c", "src/main.c", 0, true},
+		{"{a,b}", "{a,b}", 0, true},
+		{"{a,b}", "a", 0, false},
+		{"[!a]", "b", 0, true},
+		{"[^a]", "b", 0, true},
+		{"[[:digit:]x]", "7", 0, true},
+		{`\*`, "*", 0, true},
+		{`\*`, "x", 0, false},
+		{`\*`, `\x`, FNM_NOESCAPE, true},
+		{"*", ".profile", 0, true},
+		{"*", ".profile", FNM_PERIOD, false},
+		{".*", ".profile", FNM_PERIOD, true},
+		{"?profile", ".profile", FNM_PERIOD, false},
+		{"[.]profile", ".profile", FNM_PERIOD, false},
+		{"a/*", "a/.b", FNM_PERIOD, true},
+		{"a/*", "a/.b", FNM_PERIOD | FNM_PATHNAME, false},
+		{"a/.*", "a/.b", FNM_PERIOD | FNM_PATHNAME, true},
+		{"*/b", ".a/b", FNM_PERIOD | FNM_PATHNAME, false},
+		{"*x", "yx", FNM_PERIOD, true},
+		{"*.a", ".a", FNM_PERIOD, false},
+		{"*.a", "x.a", FNM_PERIOD, true},
+		{"**.a", ".a", FNM_PERIOD, false},
+		{"*[.]a", ".a", FNM_PERIOD, false},
+		{"x/*.a", "x/.a", FNM_PERIOD | FNM_PATHNAME, false},
+		{"*/.a", "x/.a", FNM_PERIOD | FNM_PATHNAME, true},
+		{"*.TXT", "Notes.txt", 0, false},
+		{"*.TXT", "Notes.txt", FNM_CASEFOLD, true},
+		{"[a-c]X", "BX", FNM_CASEFOLD, true},
+		{"[!a-c]x", "Bx", FNM_CASEFOLD, false},
+		{"straße", "STRASSE", FNM_CASEFOLD, false},
+		{"ǅ", "ǆ", FNM_CASEFOLD, true},
+		{"[!a]", "a", FNM_PATHNAME | FNM_PERIOD | FNM_CASEFOLD, false},
+		{"[!a]", "A", FNM_PATHNAME | FNM_PERIOD | FNM_CASEFOLD, false},
+		{"[!a]", "b", FNM_PATHNAME | FNM_PERIOD | FNM_CASEFOLD, true},
+		{"[!a]", ".", FNM_PATHNAME | FNM_PERIOD | FNM_CASEFOLD, false},
+		{"x/[!a]", "x/A", FNM_PATHNAME | FNM_PERIOD | FNM_CASEFOLD, false},
+		{"[!A]*", "a.txt", FNM_PERIOD | FNM_CASEFOLD, false},
+		{"[!A]*", "B.txt", FNM_PERIOD | FNM_CASEFOLD, true},
+		{"[!a-c]?", "Bx", FNM_PERIOD | FNM_CASEFOLD, false},
+		{"[!a-c]x", "Bx", FNM_PATHNAME | FNM_CASEFOLD, false},
+		{"[!a-c]x", "dX", FNM_PATHNAME | FNM_CASEFOLD, true},
+		{"[!a-cx]", "X", FNM_PATHNAME | FNM_CASEFOLD, false},
+		{"*(a|b)", "abba", FNM_EXTMATCH, true},
+		{"*(a|b)", "", FNM_EXTMATCH, true},
+		{"*(a|b)", "abc", FNM_EXTMATCH, false},
+		{"+(a|b)", "", FNM_EXTMATCH, false},
+		{"+(a|b)", "ba", FNM_EXTMATCH, true},
+		{"x*(a|b)y", "xaby", FNM_EXTMATCH, true},
+		{"*(*(a)b)", "aabab", FNM_EXTMATCH, true},
+		{"*(*(a)b)", "aaba", FNM_EXTMATCH, false},
+		{"+(ab|a)c", "aabc", FNM_EXTMATCH, true},
+		{"?(a)*(b)", "abb", FNM_EXTMATCH, true},
+		{"*(A)", "aa", FNM_EXTMATCH, false},
+		{"*(A)", "aa", FNM_EXTMATCH | FNM_CASEFOLD, true},
+		{"+([!a])", "b", FNM_EXTMATCH | FNM_CASEFOLD | FNM_PERIOD | FNM_PATHNAME, true},
+		{"+([!a])", "A", FNM_EXTMATCH | FNM_CASEFOLD | FNM_PERIOD | FNM_PATHNAME, false},
+		{"+(a/b)", "a/ba/b", FNM_EXTMATCH | FNM_PATHNAME, true},
+		{"*([!/])", "a/b", FNM_EXTMATCH | FNM_PATHNAME, false},
+		{"!(*.c)", "x.h", FNM_EXTMATCH, true},
+		{"!(*.c)", "x.c", FNM_EXTMATCH, false},
+		{"!(a)", "", FNM_EXTMATCH, true},
+		{"!(a)", "A", FNM_EXTMATCH | FNM_CASEFOLD, false},
+		{"!(a)", "b", FNM_EXTMATCH | FNM_CASEFOLD, true},
+		{"@(a|!(b))", "c", FNM_EXTMATCH, true},
+		{"x!(y)z", "xz", FNM_EXTMATCH, true},
+		{"x!(y)z", "xyz", FNM_EXTMATCH, false},
+		{"!(a|b)c", "abc", FNM_EXTMATCH, true},
+		{"*(a)", "*(a)", 0, true},
+		{"*(a)", "aa", 0, false},
+	} {
+		act, err := Fnmatch(test.pattern, test.name, test.flags)
+		if err != nil {
+			t.Errorf("#%d Fnmatch(%q, %q, %d) unexpected error: %s", id, test.pattern, test.name, test.flags, err)
+			continue
+		}
+		if act != test.exp {
+			t.Errorf("#%d Fnmatch(%q, %q, %d) = %t; want %t", id, test.pattern, test.name, test.flags, act, test.exp)
+		}
+	}
+	if _, err := Fnmatch(`a\`, "a", 0); err == nil {
+		t.Errorf("Fnmatch() expected error for trailing backslash")
+	}
+}
@@ -0,0 +1,72 @@
+package glob
+
+import (
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// foldTree returns a copy of the tree matching strings case-insensitively,
+// under Unicode simple case folding. Runes of texts which have other cases
+// become character classes, and classes are extended with other cases of
+// their runes.
+func foldTree(tree *ast.Node) *ast.Node {
+	switch tree.Kind {
+	case ast.KindText:
+		n := ast.NewNode(ast.KindPattern, nil)
+		s := tree.Value.(ast.Text).Text
+		var start int
+		flush := func(end int) {
+			if start < end {
+				ast.Insert(n, ast.NewNode(ast.KindText, ast.Text{Text: s[start:end]}))
+			}
+		}
+		for i, r := range s {
+			set := runes.Of(r).Fold()
+			if _, ok := set.Single(); ok || r == utf8.RuneError {
+				continue
+			}
+			flush(i)
+			ast.Insert(n, setNode(set))
+			start = i + utf8.RuneLen(r)
+		}
+		flush(len(s))
+		return n
+
+	case ast.KindList:
+		l := tree.Value.(ast.List)
+		return foldClass(runes.Of([]rune(l.Chars)...), l.Not)
+
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		return foldClass(runes.NewSet(runes.Range{Lo: r.Lo, Hi: r.Hi}), r.Not)
+
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		return foldClass(c.Set(), c.Not)
+
+	case ast.KindPlaceholder:
+		if e, ok := tree.Value.(*ast.Placeholder).Matcher.(extglob); ok {
+			return e.with(tree, foldTree(e.alts))
+		}
+		return ast.NewNode(tree.Kind, tree.Value)
+
+	default:
+		n := ast.NewNode(tree.Kind, tree.Value)
+		for _, c := range tree.Children {
+			ast.Insert(n, foldTree(c))
+		}
+		return n
+	}
+}
+
+// foldClass returns node for case-insensitive character class. A rune
+// matches the negated class if none of its cases is in the set.
+func foldClass(set runes.Set, not bool) *ast.Node {
+	set = set.Fold()
+	if not {
+		set = set.Complement()
+	}
+	return classOrNothing(set)
+}
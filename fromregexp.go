@@ -0,0 +1,218 @@
+package glob
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"unicode"
+
+	globsyntax "github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// FromRegexp creates Glob matching the same strings as the given regular
+// expression does with regexp.MatchString. Only the subset of RE2 syntax
+// that could be expressed by glob is accepted: literals, `.`, character
+// classes, alternations, groups, `^` and `$` at the ends of the expression,
+// bounded repetitions like `a?` or `a{2,3}`, and unbounded repetitions of
+// `.`, like `.*`, `.+` or `(?s:.*)`. Any other expression, like `a*`, is
+// reported as error.
+//
+// The glob is compiled with '\n' as the only separator, so `.` becomes `?`,
+// `.*` becomes `*` and `(?s:.*)` becomes `**`.
+func FromRegexp(re string) (Glob, error) {
+	r, err := syntax.Parse(re, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := fromRegexp(r.Simplify())
+	if err != nil {
+		return nil, fmt.Errorf("could not convert regexp %q: %s", re, err)
+	}
+	return CompileAST(globsyntax.New(tree), '\n')
+}
+
+var (
+	allRunes   = runes.All
+	notNewline = runes.Of('\n').Complement()
+)
+
+func fromRegexp(r *syntax.Regexp) (*ast.Node, error) {
+	if r.Op == syntax.OpAlternate {
+		// Each alternative could be anchored on its own.
+		n := ast.NewNode(ast.KindAnyOf, nil)
+		for _, s := range r.Sub {
+			c, err := fromRegexp(s)
+			if err != nil {
+				return nil, err
+			}
+			ast.Insert(n, c)
+		}
+		return ast.NewNode(ast.KindPattern, nil, n), nil
+	}
+
+	subs := []*syntax.Regexp{r}
+	if r.Op == syntax.OpConcat {
+		subs = r.Sub
+	}
+	// Regexp matches if any substring matches, unless it is anchored.
+	var head, tail *ast.Node
+	if len(subs) > 0 && subs[0].Op == syntax.OpBeginText {
+		subs = subs[1:]
+	} else {
+		head = ast.NewNode(ast.KindSuper, nil)
+	}
+	if len(subs) > 0 && subs[len(subs)-1].Op == syntax.OpEndText {
+		subs = subs[:len(subs)-1]
+	} else {
+		tail = ast.NewNode(ast.KindSuper, nil)
+	}
+
+	tree := ast.NewNode(ast.KindPattern, nil)
+	if head != nil {
+		ast.Insert(tree, head)
+	}
+	for _, s := range subs {
+		n, err := fromRegexpNode(s)
+		if err != nil {
+			return nil, err
+		}
+		ast.Insert(tree, n)
+	}
+	if tail != nil {
+		ast.Insert(tree, tail)
+	}
+	return tree, nil
+}
+
+func fromRegexpNode(r *syntax.Regexp) (*ast.Node, error) {
+	switch r.Op {
+	case syntax.OpEmptyMatch:
+		return ast.NewNode(ast.KindNothing, nil), nil
+
+	case syntax.OpLiteral:
+		if r.Flags&syntax.FoldCase == 0 {
+			return ast.NewNode(ast.KindText, ast.Text{Text: string(r.Rune)}), nil
+		}
+		n := ast.NewNode(ast.KindPattern, nil)
+		for _, c := range r.Rune {
+			f, err := fromRegexpClass(foldRune(c))
+			if err != nil {
+				return nil, err
+			}
+			ast.Insert(n, f)
+		}
+		return n, nil
+
+	case syntax.OpAnyCharNotNL:
+		return ast.NewNode(ast.KindSingle, nil), nil
+
+	case syntax.OpAnyChar:
+		return fromRegexpClass(allRunes)
+
+	case syntax.OpCharClass:
+		return fromRegexpClass(classSet(r.Rune))
+
+	case syntax.OpCapture:
+		return fromRegexpNode(r.Sub[0])
+
+	case syntax.OpConcat:
+		n := ast.NewNode(ast.KindPattern, nil)
+		for _, s := range r.Sub {
+			c, err := fromRegexpNode(s)
+			if err != nil {
+				return nil, err
+			}
+			ast.Insert(n, c)
+		}
+		return n, nil
+
+	case syntax.OpAlternate:
+		n := ast.NewNode(ast.KindAnyOf, nil)
+		for _, s := range r.Sub {
+			c, err := fromRegexpNode(s)
+			if err != nil {
+				return nil, err
+			}
+			ast.Insert(n, ast.NewNode(ast.KindPattern, nil, c))
+		}
+		return n, nil
+
+	case syntax.OpQuest:
+		c, err := fromRegexpNode(r.Sub[0])
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindPattern, nil, c),
+			ast.NewNode(ast.KindPattern, nil),
+		), nil
+
+	case syntax.OpStar:
+		return fromRegexpStar(r.Sub[0])
+
+	case syntax.OpPlus:
+		c, err := fromRegexpNode(r.Sub[0])
+		if err != nil {
+			return nil, err
+		}
+		star, err := fromRegexpStar(r.Sub[0])
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewNode(ast.KindPattern, nil, c, star), nil
+	}
+
+	return nil, fmt.Errorf("%s is not supported", r)
+}
+
+// fromRegexpStar converts repetition of r. Glob could express only
+// repetitions of any character.
+func fromRegexpStar(r *syntax.Regexp) (*ast.Node, error) {
+	var set runes.Set
+	switch r.Op {
+	case syntax.OpAnyChar:
+		set = allRunes
+	case syntax.OpAnyCharNotNL:
+		set = notNewline
+	case syntax.OpCharClass:
+		set = classSet(r.Rune)
+	}
+	switch {
+	case set.Equal(allRunes):
+		return ast.NewNode(ast.KindSuper, nil), nil
+	case set.Equal(notNewline):
+		return ast.NewNode(ast.KindAny, nil), nil
+	}
+	return nil, fmt.Errorf("%s* is not supported", r)
+}
+
+// fromRegexpClass converts set of runes to a single character node.
+func fromRegexpClass(set runes.Set) (*ast.Node, error) {
+	switch {
+	case set.Empty():
+		return nil, fmt.Errorf("empty character class is not supported")
+	case set.Equal(allRunes):
+		// Any rune, including the separator.
+		return ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindSingle, nil)),
+			ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindText, ast.Text{Text: "\n"})),
+		), nil
+	case set.Equal(notNewline):
+		return ast.NewNode(ast.KindSingle, nil), nil
+	}
+	return setNode(set), nil
+}
+
+func classSet(pairs []rune) runes.Set {
+	return runes.OfPairs(pairs...)
+}
+
+// foldRune returns set of runes equal to r under simple case folding.
+func foldRune(r rune) runes.Set {
+	set := runes.Of(r)
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		set = set.Union(runes.Of(f))
+	}
+	return set
+}
@@ -0,0 +1,46 @@
+package glob
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestFromRegexp(t *testing.T) {
+	for id, test := range []struct {
+		re      string
+		samples []string
+	}{
+		{`^abc$`, []string{"abc", "xabc", "abcx", "ab"}},
+		{`abc`, []string{"abc", "xabcx", "ab"}},
+		{`^a.c$`, []string{"abc", "a\nc", "ac", "abbc"}},
+		{`^a.*c$`, []string{"ac", "abbc", "ab\nc", "abd"}},
+		{`^a(?s:.*)c$`, []string{"ac", "abbc", "ab\nc"}},
+		{`^a(?s:.)c$`, []string{"abc", "a\nc", "ac"}},
+		{`^a.+c$`, []string{"ac", "abc", "abbc"}},
+		{`^[a-c]x[^a-c]$`, []string{"axd", "dxa", "bx\n"}},
+		{`^[a-cx-z]$`, []string{"a", "y", "d", "w"}},
+		{`^[^\n]*$`, []string{"", "ab", "a\nb"}},
+		{`^(foo|ba[rz])\.go$`, []string{"foo.go", "bar.go", "baz.go", "bax.go"}},
+		{`^ab?c{2,3}$`, []string{"acc", "abcc", "abccc", "abcccc", "ac"}},
+		{`^(?i)go$`, []string{"go", "GO", "gO", "ga"}},
+		{`^a|b$`, []string{"ax", "xb", "xa", "bx"}},
+		{`^$`, []string{"", "a"}},
+	} {
+		g, err := FromRegexp(test.re)
+		if err != nil {
+			t.Errorf("#%d FromRegexp(%q) unexpected error: %s", id, test.re, err)
+			continue
+		}
+		re := regexp.MustCompile(test.re)
+		for _, s := range test.samples {
+			if act, exp := g.Match(s), re.MatchString(s); act != exp {
+				t.Errorf("#%d FromRegexp(%q).Match(%q) = %t; want %t", id, test.re, s, act, exp)
+			}
+		}
+	}
+	for _, re := range []string{`a*`, `^[a-z]+$`, `\bfoo`, `^a$b`, `(`} {
+		if _, err := FromRegexp(re); err == nil {
+			t.Errorf("FromRegexp(%q) expected error", re)
+		}
+	}
+}
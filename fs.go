@@ -0,0 +1,101 @@
+//go:build go1.16
+// +build go1.16
+
+package glob
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// GlobFS returns the names of all files of fsys matching the pattern, in
+// lexical order. The pattern is compiled by CompileWith with '/' as the
+// separator and given options, and could use `**` to match files at any
+// depth. Only the directories under the literal prefix of the pattern are
+// walked, and directories which could not contain matching files are
+// skipped.
+//
+// As fs.Glob does, GlobFS ignores file system errors such as I/O errors
+// reading directories. The only possible returned error is the pattern
+// compilation error.
+func GlobFS(fsys fs.FS, pattern string, opts ...Option) ([]string, error) {
+	g, err := CompileWith(pattern, append([]Option{WithSeparators('/')}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return walkFS(fsys, g), nil
+}
+
+func walkFS(fsys fs.FS, g Glob) []string {
+	var matches []string
+	a := analyzerOf(g)
+	fs.WalkDir(fsys, walkRoot(g), func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if name != "." && g.Match(name) {
+			matches = append(matches, name)
+		}
+		if d.IsDir() && name != "." && !a.CouldMatchPrefix(name+"/") {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	return matches
+}
+
+// walkRoot returns the deepest directory containing all paths matched by
+// the glob, which is the directory part of its literal prefix.
+func walkRoot(g Glob) string {
+	prefix := analyzerOf(g).Prefix()
+	i := strings.LastIndexByte(prefix, '/')
+	if i <= 0 {
+		return "."
+	}
+	return prefix[:i]
+}
+
+// NewGlobFS returns fs.FS which implements fs.GlobFS using this package, so
+// that fs.Glob called with it supports all the syntax of CompileWith, like
+// `**` and braces. Patterns are compiled with '/' as the separator and given
+// options. Compilation errors wrap path.ErrBadPattern, as required by
+// fs.GlobFS. The result also implements fs.ReadDirFS, fs.ReadFileFS,
+// fs.StatFS and fs.SubFS using the wrapped fsys.
+func NewGlobFS(fsys fs.FS, opts ...Option) fs.GlobFS {
+	return globFS{fsys, opts}
+}
+
+type globFS struct {
+	fs.FS
+	opts []Option
+}
+
+func (f globFS) Glob(pattern string) ([]string, error) {
+	g, err := CompileWith(pattern, append([]Option{WithSeparators('/')}, f.opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", path.ErrBadPattern, err)
+	}
+	return walkFS(f.FS, g), nil
+}
+
+func (f globFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(f.FS, name)
+}
+
+func (f globFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(f.FS, name)
+}
+
+func (f globFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(f.FS, name)
+}
+
+func (f globFS) Sub(dir string) (fs.FS, error) {
+	sub, err := fs.Sub(f.FS, dir)
+	if err != nil {
+		return nil, err
+	}
+	return globFS{sub, f.opts}, nil
+}
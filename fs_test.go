@@ -0,0 +1,189 @@
+//go:build go1.16
+// +build go1.16
+
+package glob
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// countFS counts opened directories.
+type countFS struct {
+	fstest.MapFS
+	opened []string
+}
+
+func (c *countFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.opened = append(c.opened, name)
+	return c.MapFS.ReadDir(name)
+}
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"go.mod":              {},
+		"glob.go":             {},
+		"readme.md":           {},
+		"cmd/tool/main.go":    {},
+		"match/any.go":        {},
+		"match/any_test.go":   {},
+		"match/debug/d.go":    {},
+		"syntax/ast/ast.go":   {},
+		"syntax/lexer/lex.go": {},
+	}
+}
+
+func TestGlobFS(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		exp     []string
+		opened  []string
+	}{
+		{
+			pattern: "*.go",
+			exp:     []string{"glob.go"},
+			opened:  []string{"."},
+		},
+		{
+			pattern: "**.go",
+			exp: []string{
+				"cmd/tool/main.go", "glob.go", "match/any.go", "match/any_test.go",
+				"match/debug/d.go", "syntax/ast/ast.go", "syntax/lexer/lex.go",
+			},
+		},
+		{
+			pattern: "match/*_test.go",
+			exp:     []string{"match/any_test.go"},
+			opened:  []string{"match"},
+		},
+		{
+			pattern: "{cmd,syntax}/*/*.go",
+			exp:     []string{"cmd/tool/main.go", "syntax/ast/ast.go", "syntax/lexer/lex.go"},
+			opened:  []string{".", "cmd", "cmd/tool", "syntax", "syntax/ast", "syntax/lexer"},
+		},
+		{
+			pattern: "syntax/ast",
+			exp:     []string{"syntax/ast"},
+			opened:  []string{"syntax"},
+		},
+		{
+			pattern: "nothing/*",
+		},
+	} {
+		fsys := &countFS{MapFS: testFS()}
+		act, err := GlobFS(fsys, test.pattern)
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		if !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("#%d GlobFS(%q) = %q; want %q", id, test.pattern, act, test.exp)
+		}
+		if test.opened != nil && !reflect.DeepEqual(fsys.opened, test.opened) {
+			t.Errorf("#%d GlobFS(%q) opened %q; want %q", id, test.pattern, fsys.opened, test.opened)
+		}
+	}
+	if _, err := GlobFS(testFS(), "[a"); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
+
+func TestNewGlobFS(t *testing.T) {
+	fsys := NewGlobFS(testFS())
+	for id, test := range []struct {
+		pattern string
+		exp     []string
+	}{
+		{"*.go", []string{"glob.go"}},
+		{"{glob,readme}.*", []string{"glob.go", "readme.md"}},
+		{"syntax/**.go", []string{"syntax/ast/ast.go", "syntax/lexer/lex.go"}},
+		{"*.c", nil},
+	} {
+		act, err := fs.Glob(fsys, test.pattern)
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		if !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("#%d fs.Glob(%q) = %q; want %q", id, test.pattern, act, test.exp)
+		}
+	}
+	if _, err := fs.Glob(fsys, "[a"); !errors.Is(err, path.ErrBadPattern) {
+		t.Errorf("fs.Glob() error = %v; want %v", err, path.ErrBadPattern)
+	}
+
+	sub, err := fs.Sub(fsys, "match")
+	if err != nil {
+		t.Fatal(err)
+	}
+	act, err := fs.Glob(sub, "**_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"any_test.go"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("fs.Glob() in sub = %q; want %q", act, exp)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	root := t.TempDir()
+	for name := range testFS() {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, test := range []struct {
+		pattern string
+		exp     []string
+	}{
+		{"*.go", []string{"glob.go"}},
+		{"**_test.go", []string{"match/any_test.go"}},
+		{"syntax/*", []string{"syntax/ast", "syntax/lexer"}},
+		{"{cmd,match}/*/*.go", []string{"cmd/tool/main.go", "match/debug/d.go"}},
+		{"nothing/**", nil},
+	} {
+		var act []string
+		err := Walk(root, test.pattern, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, p)
+			act = append(act, filepath.ToSlash(rel))
+			return nil
+		})
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		if !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("#%d Walk(%q) = %q; want %q", id, test.pattern, act, test.exp)
+		}
+	}
+
+	// Matched directory could be skipped.
+	var act []string
+	err := Walk(root, "{match,match/**}", func(p string, d fs.DirEntry, err error) error {
+		rel, _ := filepath.Rel(root, p)
+		act = append(act, filepath.ToSlash(rel))
+		if d.IsDir() && d.Name() == "debug" {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"match", "match/any.go", "match/any_test.go", "match/debug"}; !reflect.DeepEqual(act, exp) {
+		t.Errorf("Walk() = %q; want %q", act, exp)
+	}
+}
@@ -0,0 +1,199 @@
+package glob
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// gitignoreTree returns syntax tree matching the same paths as the single
+// gitignore pattern. See DialectGitignore for details.
+func gitignoreTree(pattern string) (*ast.Node, error) {
+	tree, dirOnly, err := gitignoreRule(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if dirOnly {
+		// `dir/` matches "dir/" and everything inside.
+		ast.Insert(tree,
+			ast.NewNode(ast.KindText, ast.Text{Text: "/"}),
+			ast.NewNode(ast.KindSuper, nil),
+		)
+	} else {
+		// `name` matches "name", "name/" and everything inside.
+		ast.Insert(tree, anyInside())
+	}
+	return tree, nil
+}
+
+// gitignoreRule returns syntax tree matching the paths which the single
+// gitignore pattern matches by itself, with directories named without
+// trailing '/', and whether the pattern matches only directories.
+func gitignoreRule(pattern string) (tree *ast.Node, dirOnly bool, err error) {
+	p := trimTrailingSpaces(pattern)
+	switch {
+	case p == "" || p[0] == '#':
+		return nil, false, fmt.Errorf("gitignore pattern %q is empty or comment", pattern)
+	case p[0] == '!':
+		return nil, false, fmt.Errorf("gitignore pattern %q is negated", pattern)
+	}
+
+	dirOnly = strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	tree = ast.NewNode(ast.KindPattern, nil)
+	if !anchored {
+		ast.Insert(tree, anyDirs())
+	}
+
+	sh := shell{seps: []rune{'/'}}
+	segments := strings.Split(p, "/")
+	for i, s := range segments {
+		last := i == len(segments)-1
+		if last && strings.Trim(s, "*") == "" {
+			// Names are never empty, so that `dir/*` does not match
+			// "dir/", which stands for the directory itself.
+			ast.Insert(tree, ast.NewNode(ast.KindSingle, nil))
+		}
+		if s == "**" {
+			if last {
+				// Trailing `/**` matches everything inside.
+				ast.Insert(tree, ast.NewNode(ast.KindSuper, nil))
+				break
+			}
+			ast.Insert(tree, anyDirs())
+			continue
+		}
+		nodes, err := sh.nodes(s)
+		if err != nil {
+			return nil, false, err
+		}
+		ast.Insert(tree, nodes...)
+		if !last {
+			ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: "/"}))
+		}
+	}
+	return tree, dirOnly, nil
+}
+
+// anyInside returns `{,/**}` node, which matches any path inside of the
+// directory matched before.
+func anyInside() *ast.Node {
+	return ast.NewNode(ast.KindAnyOf, nil,
+		ast.NewNode(ast.KindPattern, nil),
+		ast.NewNode(ast.KindPattern, nil,
+			ast.NewNode(ast.KindText, ast.Text{Text: "/"}),
+			ast.NewNode(ast.KindSuper, nil),
+		),
+	)
+}
+
+// anyDirs returns `{,**/}` node, which matches any number of leading
+// directories.
+func anyDirs() *ast.Node {
+	return ast.NewNode(ast.KindAnyOf, nil,
+		ast.NewNode(ast.KindPattern, nil),
+		ast.NewNode(ast.KindPattern, nil,
+			ast.NewNode(ast.KindSuper, nil),
+			ast.NewNode(ast.KindText, ast.Text{Text: "/"}),
+		),
+	)
+}
+
+// trimTrailingSpaces removes trailing spaces from s, unless they are escaped
+// with backslash.
+func trimTrailingSpaces(s string) string {
+	for strings.HasSuffix(s, " ") {
+		n := len(s) - 1
+		var k int
+		for n-k-1 >= 0 && s[n-k-1] == '\\' {
+			k++
+		}
+		if k%2 == 1 {
+			break
+		}
+		s = s[:n]
+	}
+	return s
+}
+
+// Gitignore is an ordered list of gitignore patterns.
+type Gitignore struct {
+	rules []ignoreRule
+}
+
+// ignoreRule is a pattern of an ignore list. Negated rules re-include
+// matched paths, and directory rules match only directories.
+type ignoreRule struct {
+	glob    Glob
+	negate  bool
+	dirOnly bool
+}
+
+// LoadGitignore reads patterns in gitignore(5) format from r. Blank lines
+// and lines starting with `#` are skipped, and patterns starting with `!`
+// re-include paths excluded by the previous patterns.
+func LoadGitignore(r io.Reader) (*Gitignore, error) {
+	var (
+		ig Gitignore
+		n  int
+	)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		n++
+		line := trimTrailingSpaces(strings.TrimSuffix(s.Text(), "\r"))
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		var rule ignoreRule
+		if line[0] == '!' {
+			rule.negate = true
+			line = line[1:]
+		}
+		tree, dirOnly, err := gitignoreRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		g, err := CompileAST(&syntax.Pattern{Source: line, Tree: tree}, '/')
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		rule.glob, rule.dirOnly = g, dirOnly
+		ig.rules = append(ig.rules, rule)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return &ig, nil
+}
+
+// Match reports whether the path is ignored. The path is relative to the
+// directory of the ignore file and uses '/' as the separator. As in git, the
+// path is ignored if any of its parent directories is, and otherwise the last
+// pattern matching the path itself wins, so that negated patterns re-include
+// only the paths they match and never the contents of excluded directories.
+func (ig *Gitignore) Match(path string, isDir bool) bool {
+	path = strings.TrimSuffix(path, "/")
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && ig.match(path[:i], true) {
+			return true
+		}
+	}
+	return ig.match(path, isDir)
+}
+
+func (ig *Gitignore) match(path string, isDir bool) bool {
+	for i := len(ig.rules) - 1; i >= 0; i-- {
+		r := ig.rules[i]
+		if (isDir || !r.dirOnly) && r.glob.Match(path) {
+			return !r.negate
+		}
+	}
+	return false
+}
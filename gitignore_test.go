@@ -0,0 +1,230 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDialectGitignore(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: `foo`,
+			match:   []string{"foo", "foo/", "a/foo", "a/b/foo/", "foo/bar", "a/foo/bar/baz"},
+			miss:    []string{"foobar", "afoo", "a/foobar"},
+		},
+		{
+			pattern: `*.log`,
+			match:   []string{"x.log", "a/b/x.log", ".log", "logs.log/x"},
+			miss:    []string{"x.logs", "a.log.txt"},
+		},
+		{
+			pattern: `build/`,
+			match:   []string{"build/", "a/build/", "build/x", "a/build/x/y"},
+			miss:    []string{"build", "a/build", "builds/"},
+		},
+		{
+			pattern: `/todo`,
+			match:   []string{"todo", "todo/x"},
+			miss:    []string{"a/todo"},
+		},
+		{
+			pattern: `doc/*.txt`,
+			match:   []string{"doc/a.txt"},
+			miss:    []string{"doc/a/b.txt", "x/doc/a.txt"},
+		},
+		{
+			pattern: `**/foo`,
+			match:   []string{"foo", "a/foo", "a/b/foo"},
+			miss:    []string{"afoo"},
+		},
+		{
+			pattern: `**/foo/bar`,
+			match:   []string{"foo/bar", "a/b/foo/bar"},
+			miss:    []string{"foo/x/bar"},
+		},
+		{
+			pattern: `abc/**`,
+			match:   []string{"abc/x", "abc/x/y"},
+			miss:    []string{"abc", "abc/", "x/abc/y"},
+		},
+		{
+			pattern: `doc/*`,
+			match:   []string{"doc/a", "doc/a/", "doc/a/b"},
+			miss:    []string{"doc", "doc/"},
+		},
+		{
+			pattern: `**/`,
+			match:   []string{"a/", "a/b", "a/b/"},
+			miss:    []string{"a"},
+		},
+		{
+			pattern: `x/**/`,
+			match:   []string{"x/a/", "x/a/b"},
+			miss:    []string{"x/", "x/a"},
+		},
+		{
+			pattern: `a/**/b`,
+			match:   []string{"a/b", "a/x/b", "a/x/y/b"},
+			miss:    []string{"a/xb", "ab"},
+		},
+		{
+			pattern: `a**b`,
+			match:   []string{"ab", "axxb", "c/axb"},
+			miss:    []string{"a/b"},
+		},
+		{
+			pattern: `**`,
+			match:   []string{"a", "a/b"},
+		},
+		{
+			pattern: `[!a-c]?[[:digit:]]`,
+			match:   []string{"dx1", "x/dx1"},
+			miss:    []string{"ax1", "dxa", "/x1"},
+		},
+		{
+			pattern: `{a,b}`,
+			match:   []string{"{a,b}"},
+			miss:    []string{"a"},
+		},
+		{
+			pattern: `\#x\ \ `,
+			match:   []string{"#x  "},
+			miss:    []string{"#x"},
+		},
+		{
+			pattern: `x  `,
+			match:   []string{"x"},
+			miss:    []string{"x  "},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithDialect(DialectGitignore))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+	for _, pattern := range []string{``, `# comment`, `!foo`, `foo\`, `[[:nope:]]`} {
+		if _, err := CompileWith(pattern, WithDialect(DialectGitignore)); err == nil {
+			t.Errorf("CompileWith(%q) expected error", pattern)
+		}
+	}
+}
+
+func TestLoadGitignore(t *testing.T) {
+	ig, err := LoadGitignore(strings.NewReader(strings.Join([]string{
+		"# comment",
+		"",
+		"*.log",
+		"!important.log",
+		"build/",
+		"!build/keep",
+		"/vendor\r",
+		"\\!bang",
+		"\\#hash",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, test := range []struct {
+		path  string
+		isDir bool
+		exp   bool
+	}{
+		{"a.log", false, true},
+		{"x/a.log", false, true},
+		{"important.log", false, false},
+		{"x/important.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"x/build/", true, true},
+		{"build/keep", false, true},
+		{"vendor", true, true},
+		{"x/vendor", true, false},
+		{"!bang", false, true},
+		{"#hash", false, true},
+		{"comment", false, false},
+		{"main.go", false, false},
+	} {
+		if act := ig.Match(test.path, test.isDir); act != test.exp {
+			t.Errorf("#%d Match(%q, %t) = %t; want %t", id, test.path, test.isDir, act, test.exp)
+		}
+	}
+	if _, err := LoadGitignore(strings.NewReader("ok\n[[:nope:]]\n")); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("LoadGitignore() error = %v; want error at line 2", err)
+	}
+}
+
+// TestGitignoreGit checks the rules against paths as ignored by
+// `git ls-files -o -i --exclude-standard`.
+func TestGitignoreGit(t *testing.T) {
+	for id, test := range []struct {
+		rules []string
+		match []string
+		miss  []string
+	}{
+		{
+			rules: []string{"**/"},
+			match: []string{"b/c"},
+			miss:  []string{"a"},
+		},
+		{
+			rules: []string{"x/**/"},
+			match: []string{"x/b/c"},
+			miss:  []string{"x/a"},
+		},
+		{
+			rules: []string{"?", "!/**/*.log"},
+			match: []string{"c.log/a"},
+			miss:  []string{"b.log"},
+		},
+		{
+			rules: []string{"/*", "!/foo", "/foo/*", "!/foo/bar"},
+			match: []string{"a", "foo/x"},
+			miss:  []string{"foo/bar/baz"},
+		},
+		{
+			rules: []string{"**/", "/**/a", "!b/"},
+			match: []string{"a", "b/a"},
+		},
+		{
+			rules: []string{"foo/*"},
+			match: []string{"foo/x"},
+			miss:  []string{"a/foo/y"},
+		},
+		{
+			rules: []string{"*", "!*/", "!*.go"},
+			match: []string{"a.txt", "d/b.txt"},
+			miss:  []string{"a.go", "d/b.go"},
+		},
+	} {
+		ig, err := LoadGitignore(strings.NewReader(strings.Join(test.rules, "\n")))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !ig.Match(s, false) {
+				t.Errorf("#%d %q should ignore %q", id, test.rules, s)
+			}
+		}
+		for _, s := range test.miss {
+			if ig.Match(s, false) {
+				t.Errorf("#%d %q should not ignore %q", id, test.rules, s)
+			}
+		}
+	}
+}
@@ -1,16 +1,146 @@
 package glob
 
 import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
 	"github.com/gobwas/glob/compiler"
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/nfa"
 	"github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
 )
 
 // Glob represents compiled glob pattern.
 type Glob interface {
+	// Match reports whether the string matches the glob. It does not
+	// allocate, except where options like WithURLPath normalize the string.
 	Match(string) bool
 }
 
-// Compile creates Glob for given pattern and strings (if any present after pattern) as separators.
+// Analyzer is the optional interface of globs which could tell what strings
+// they match without matching them. The globs returned by this package,
+// including the ones made by combinators like Or, implement it, so that
+// g.(Analyzer) gives access to these methods for any of them. Functions of
+// this package, like Overlaps, treat other globs conservatively: as if they
+// had no literal parts and could match strings of any length.
+type Analyzer interface {
+	Glob
+
+	// MatchContext is like Match, but it gives up matching once the context
+	// is done, or once it takes more steps than set with WithMaxSteps, and
+	// returns *MatchError then.
+	MatchContext(ctx context.Context, s string) (bool, error)
+
+	// ReplaceAll returns a copy of s with the template expanded, if s matches
+	// the glob. Otherwise s is returned unchanged. Inside the template $n and
+	// ${n} denote the text matched by the n-th wildcard of the pattern, as in
+	// regexp.Regexp.Expand. Wildcards (`*`, `**`, `?`, character classes and
+	// pattern alternatives) are numbered from 1 in the order they appear in
+	// the pattern. Patterns have no syntax to name wildcards, so $name and
+	// ${name} denote wildcards named with WithCaptureNames, and expand to
+	// the empty string otherwise.
+	ReplaceAll(s, template string) string
+
+	// Prefix returns the longest literal string that every string matched
+	// by the glob starts with. It is empty if the pattern starts with a
+	// wildcard.
+	Prefix() string
+
+	// Suffix returns the longest literal string that every string matched
+	// by the glob ends with. It is empty if the pattern ends with a
+	// wildcard.
+	Suffix() string
+
+	// Literal returns the only string matched by the glob and true, if the
+	// pattern has no wildcards. Otherwise it returns false.
+	Literal() (string, bool)
+
+	// IsLiteral reports whether the glob matches exactly one string.
+	IsLiteral() bool
+
+	// CouldMatchPrefix reports whether some string starting with s could be
+	// matched by the glob. That is, if it returns false, no string with
+	// prefix s is ever matched. To check whether anything inside of a
+	// directory can match, s should end with a separator.
+	CouldMatchPrefix(s string) bool
+
+	// MinLen returns the minimum length in bytes of the strings matched by
+	// the glob.
+	MinLen() int
+
+	// MaxLen returns the maximum length in bytes of the strings matched by
+	// the glob, or -1 if there is no such limit.
+	MaxLen() int
+
+	// Complexity returns an estimation of the worst-case cost of matching.
+	// It could be used to reject or sandbox user supplied patterns.
+	Complexity() Complexity
+
+	// Regexp returns an anchored RE2 expression matching the strings the
+	// glob matches, including its separators semantics. It has no capturing
+	// groups. The expression is exact unless the glob has parts matched by
+	// custom code, which it renders as `.*`, so that it matches a superset:
+	// grapheme clusters of WithGraphemes, tokens of WithPlaceholder,
+	// extended patterns of BashExtglob and FNM_EXTMATCH, and the rest of
+	// DialectPath patterns matched by path.Match. Options normalizing
+	// strings before matching, like WithURLPath, WithHostname or
+	// WithNormalization, are not part of it either, so it describes
+	// normalized strings only.
+	Regexp() string
+}
+
+// analyzerOf returns g, or the glob wrapped by it, if it is Analyzer, and
+// g answering conservatively otherwise.
+func analyzerOf(g Glob) Analyzer {
+	if a, ok := unwrap(g).(Analyzer); ok {
+		return a
+	}
+	return opaque{g}
+}
+
+// opaque adapts globs nothing is known about to the Analyzer interface.
+type opaque struct {
+	Glob
+}
+
+func (o opaque) MatchContext(ctx context.Context, s string) (bool, error) {
+	var l match.Limit
+	return matchContext(ctx, globMatcher{o.Glob}, s, &l)
+}
+
+func (o opaque) ReplaceAll(s, template string) string {
+	if !o.Match(s) {
+		return s
+	}
+	return string(wholeMatch.ExpandString(nil, template, s, []int{0, len(s)}))
+}
+
+func (o opaque) Prefix() string                 { return "" }
+func (o opaque) Suffix() string                 { return "" }
+func (o opaque) Literal() (string, bool)        { return "", false }
+func (o opaque) IsLiteral() bool                { return false }
+func (o opaque) CouldMatchPrefix(s string) bool { return true }
+func (o opaque) MinLen() int                    { return 0 }
+func (o opaque) MaxLen() int                    { return -1 }
+
+// Complexity counts the glob as a wildcard matching strings of any length,
+// as its cost is not known.
+func (o opaque) Complexity() Complexity {
+	return Complexity{Unbounded: 1}
+}
+
+// Regexp matches any string, as the strings the glob matches are not known.
+func (o opaque) Regexp() string {
+	return `(?s)^.*$`
+}
+
+// Compile creates Glob for given pattern and runes (if any present after pattern) as separators.
+// Each rune is a separator of its own, as Separators describes.
 // The pattern syntax is:
 //
 //    pattern:
@@ -31,23 +161,50 @@ type Glob interface {
 //        c           matches character c (c != `\\`, `-`, `]`)
 //        `\` c       matches character c
 //        lo `-` hi   matches character c for lo <= c <= hi
+//        `[:` name `:]`
+//                    matches character of POSIX class, like `[:alpha:]`
 //
 //    pattern-list:
 //        pattern { `,` pattern }
 //                    comma-separated (without spaces) patterns
 //
 func Compile(pattern string, separators ...rune) (Glob, error) {
-	ast, err := syntax.Parse(pattern)
+	p, err := syntax.Parse(pattern)
 	if err != nil {
 		return nil, err
 	}
 
-	matcher, err := compiler.Compile(ast, separators)
-	if err != nil {
+	return CompileAST(p, separators...)
+}
+
+// CompileAST creates Glob for given parsed pattern and separators. It makes
+// possible to compile patterns that were analyzed, transformed or built
+// programmatically with the syntax package. Structurally equal parts of the
+// matcher, like the alternatives of `{a,b}/**/{a,b}`, share memory.
+func CompileAST(p *syntax.Pattern, separators ...rune) (Glob, error) {
+	c := new(compiled)
+	if err := compileAST(c, p, separators); err != nil {
 		return nil, err
 	}
+	return c, nil
+}
 
-	return matcher, nil
+// compileAST sets up the zero glob to match the pattern, so that globs could
+// be allocated by callers.
+func compileAST(c *compiled, p *syntax.Pattern, separators []rune) error {
+	matcher, err := compiler.Compile(p.Tree, separators)
+	if err != nil {
+		return err
+	}
+	c.Matcher = newInterner().intern(matcher)
+	c.tree = p.Tree
+	c.separators = separators
+	c.prefix, c.literal = literalPrefix(p.Tree)
+	c.suffix, _ = literalSuffix(p.Tree)
+	c.minLen, c.maxLen = lengthBounds(p.Tree)
+	first, last, _ := edgeRunes(p.Tree, runes.Of(separators...))
+	c.first, c.last = edgeFilter(first), edgeFilter(last)
+	return nil
 }
 
 // MustCompile is the same as Compile, except that if Compile returns error, this will panic
@@ -78,3 +235,191 @@ func QuoteMeta(s string) string {
 
 	return string(b[0:j])
 }
+
+// Simplify returns shorter pattern matching the same strings as the given
+// one, whatever separators both are compiled with. Adjacent wildcards are
+// merged, so `a***b` becomes `a**b`, and alternatives are deduplicated and
+// factored, so `{abc,abd}` becomes `ab{c,d}`. Note that `**/**` is left as
+// is: unlike `**` it requires at least one `/` in the string.
+func Simplify(pattern string) (string, error) {
+	p, err := syntax.Parse(pattern)
+	if err != nil {
+		return "", err
+	}
+	return syntax.Render(syntax.Simplify(p.Tree)), nil
+}
+
+// compiled is the Glob implementation returned by Compile. It embeds the
+// optimized matcher and keeps the syntax tree it was built from for the
+// operations that need to know what each wildcard has matched.
+type compiled struct {
+	match.Matcher
+
+	tree       *ast.Node
+	separators []rune
+	prefix     string
+	suffix     string
+	literal    bool
+	minLen     int
+	maxLen     int
+
+	// first and last, if set, are the runes the matched non-empty strings
+	// start and end with, which are checked before running the matcher.
+	first runes.Set
+	last  runes.Set
+
+	// normalize, if set, transforms strings before matching. Strings for
+	// which it returns false are not matched.
+	normalize func(string) (string, bool)
+
+	// normalization identifies normalize for Equal and Hash, as functions
+	// could not be compared. It is empty if normalize is not set.
+	normalization string
+
+	// maxSteps, if positive, limits the steps of MatchContext.
+	maxSteps int
+
+	// stats, if set, receives the work done by matches, along with the
+	// source pattern.
+	stats  StatsHook
+	source string
+
+	// captureNames are the names of wildcards for ReplaceAll, as set by
+	// WithCaptureNames.
+	captureNames []string
+	captureOnce  sync.Once
+	capture      *regexp.Regexp
+
+	nfaOnce sync.Once
+	nfa     *nfa.NFA
+}
+
+func (g *compiled) Match(s string) bool {
+	if g.stats != nil {
+		return g.matchStats(s)
+	}
+	if g.normalize != nil {
+		var ok bool
+		if s, ok = g.normalize(s); !ok {
+			return false
+		}
+	}
+	return g.match(s)
+}
+
+// fits reports whether s has the literal suffix and the length of the
+// strings the glob matches, which is a quick test for strings it could not
+// match.
+func (g *compiled) fits(s string) bool {
+	return len(s) >= g.minLen && (g.maxLen == -1 || len(s) <= g.maxLen) && strings.HasSuffix(s, g.suffix)
+}
+
+func (g *compiled) match(s string) bool {
+	return g.admits(s) && g.Matcher.Match(s)
+}
+
+// admits reports whether s has the length and the edge runes of the strings
+// the glob matches, which is checked before running the matcher.
+func (g *compiled) admits(s string) bool {
+	if len(s) < g.minLen || g.maxLen != -1 && len(s) > g.maxLen {
+		return false
+	}
+	if len(s) > 0 && g.first != nil {
+		if r, _ := utf8.DecodeRuneInString(s); !g.first.Contains(r) {
+			return false
+		}
+	}
+	if len(s) > 0 && g.last != nil {
+		if r, _ := utf8.DecodeLastRuneInString(s); !g.last.Contains(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *compiled) ReplaceAll(s, template string) string {
+	orig := s
+	if g.normalize != nil {
+		var ok bool
+		if s, ok = g.normalize(s); !ok {
+			return orig
+		}
+	}
+	if !g.match(s) {
+		return orig
+	}
+	re := g.captureRegexp()
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return orig
+	}
+	return string(re.ExpandString(nil, template, s, loc))
+}
+
+// captureRegexp returns the expression with capturing groups ReplaceAll
+// uses, compiling it once.
+func (g *compiled) captureRegexp() *regexp.Regexp {
+	g.captureOnce.Do(func() {
+		g.capture = regexp.MustCompile(regexpString(g.tree, g.separators, &captures{names: g.captureNames}))
+	})
+	return g.capture
+}
+
+func (g *compiled) Prefix() string {
+	return g.prefix
+}
+
+func (g *compiled) Suffix() string {
+	return g.suffix
+}
+
+func (g *compiled) Literal() (string, bool) {
+	if !g.literal {
+		return "", false
+	}
+	return g.prefix, true
+}
+
+func (g *compiled) IsLiteral() bool {
+	return g.literal
+}
+
+func (g *compiled) CouldMatchPrefix(s string) bool {
+	if g.normalize != nil {
+		var ok bool
+		if s, ok = g.normalize(s); !ok {
+			// The prefix could be incomplete, like truncated escape
+			// sequence, so it is not known whether anything matches.
+			return true
+		}
+	}
+	if !strings.HasPrefix(s, g.prefix) && !strings.HasPrefix(g.prefix, s) {
+		return false
+	}
+	return g.automaton().MatchPrefix(s)
+}
+
+// automaton returns the automaton built from the glob, which is used to
+// answer questions about the whole set of matched strings.
+func (g *compiled) automaton() *nfa.NFA {
+	g.nfaOnce.Do(func() {
+		g.nfa = nfa.New(g.tree, g.separators)
+	})
+	return g.nfa
+}
+
+func (g *compiled) MinLen() int {
+	return g.minLen
+}
+
+func (g *compiled) MaxLen() int {
+	return g.maxLen
+}
+
+func (g *compiled) Complexity() Complexity {
+	return complexity(g.tree, g.Matcher)
+}
+
+func (g *compiled) Regexp() string {
+	return regexpString(g.tree, g.separators, nil)
+}
@@ -1,8 +1,13 @@
 package glob
 
 import (
+	"context"
 	"regexp"
+	"strings"
 	"testing"
+
+	"github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
 )
 
 const (
@@ -88,6 +93,25 @@ func TestGlob(t *testing.T) {
 		glob(false, "a.*", "a.b.c", '.'),
 		glob(false, "a.?.c", "a.bb.c", '.'),
 		glob(false, "*", "a.b.c", '.'),
+		glob(false, "??*", "a/b", '/', '.'),
+		glob(true, "??*", "abc", '/', '.'),
+		glob(true, "a???", "abcd"),
+		glob(false, "a???", "abcde"),
+
+		// bounds of lengths in bytes fall inside of multibyte runes
+		glob(false, "*?[!a]", "\u00e9"),
+		glob(true, "*?[!a]", "\u00e9\u00e9"),
+		glob(false, "[!a]*?", "\u00e9", '/'),
+		glob(false, "?**[!a]", "\u00e9"),
+		glob(false, "[!a]**[!a]", "\ufffd"),
+		glob(true, "[!a]**[!a]", "\ufffd\ufffd"),
+		glob(true, "?*[!a]", "\xe9\xbfx"),
+		glob(false, "?*[!a]", "\xe9\xbf\xbf"),
+
+		// the prefix and the suffix of alternatives do not overlap
+		glob(false, "{,x,ab/*}/", "ab/"),
+		glob(true, "{,x,ab/*}/", "ab//"),
+		glob(false, "[ab]{*/**/{,[ab]},*{b,}b[a-c]}x", "ac/x"),
 
 		glob(true, "*test", "this is a test"),
 		glob(true, "this*", "this is a test"),
@@ -99,6 +123,13 @@ func TestGlob(t *testing.T) {
 		glob(false, "*is", "this is a test"),
 		glob(false, "*no*", "this is a test"),
 		glob(true, "[!a]*", "this is a test3"),
+		glob(true, "[a-f0-9_.-]", "c"),
+		glob(true, "[a-f0-9_.-]", "-"),
+		glob(false, "[a-f0-9_.-]", "g"),
+		glob(true, "v[0-9.]*", "v1.2"),
+		glob(true, "[[:upper:]_]*", "_x"),
+		glob(false, "[![:alpha:][:digit:]]", "q"),
+		glob(true, "[![:alpha:][:digit:]]", "+"),
 
 		glob(true, "*abc", "abcabc"),
 		glob(true, "**abc", "abcabc"),
@@ -115,6 +146,9 @@ func TestGlob(t *testing.T) {
 		glob(true, "{a,ab}{bc,f}", "abc"),
 		glob(true, "{*,**}{a,b}", "ab"),
 		glob(false, "{*,**}{a,b}", "ac"),
+		glob(true, "a***b", "ab", '/'),
+		glob(true, "{,**/}*.go", "a/b/c.go", '/'),
+		glob(true, "{,**/}*.go", ".go", '/'),
 
 		glob(true, "/{rate,[a-z][a-z][a-z]}*", "/rate"),
 		glob(true, "/{rate,[0-9][0-9][0-9]}*", "/rate"),
@@ -207,11 +241,399 @@ func TestQuoteMeta(t *testing.T) {
 	}
 }
 
+func TestReplaceAll(t *testing.T) {
+	for id, test := range []struct {
+		pattern    string
+		separators []rune
+		in         string
+		template   string
+		out        string
+	}{
+		{
+			pattern:    "src/*/v1/*.go",
+			separators: []rune{'/'},
+			in:         "src/api/v1/handler.go",
+			template:   "src/$1/v2/$2.go",
+			out:        "src/api/v2/handler.go",
+		},
+		{
+			pattern:    "src/*/v1/*.go",
+			separators: []rune{'/'},
+			in:         "src/api/v3/handler.go",
+			template:   "src/$1/v2/$2.go",
+			out:        "src/api/v3/handler.go",
+		},
+		{
+			pattern:  "?at-*",
+			in:       "cat-food",
+			template: "${2}_${1}og",
+			out:      "food_cog",
+		},
+		{
+			pattern:  "{cat,dog}.[a-z][!0-9]",
+			in:       "dog.ab",
+			template: "$1:$2$3:$$",
+			out:      "dog:ab:$",
+		},
+		{
+			pattern:  "**.txt",
+			in:       "a/b/c.txt",
+			template: "$1.md",
+			out:      "a/b/c.md",
+		},
+		{
+			pattern:  "abc",
+			in:       "abc",
+			template: "[$1]",
+			out:      "[]",
+		},
+	} {
+		g := MustCompile(test.pattern, test.separators...).(Analyzer)
+		if act := g.ReplaceAll(test.in, test.template); act != test.out {
+			t.Errorf("#%d MustCompile(%q).ReplaceAll(%q, %q) = %q; want %q", id, test.pattern, test.in, test.template, act, test.out)
+		}
+	}
+}
+
+func TestReplaceAllNamed(t *testing.T) {
+	for id, test := range []struct {
+		pattern  string
+		names    []string
+		in       string
+		template string
+		out      string
+	}{
+		{
+			pattern:  "src/*/v1/*.go",
+			names:    []string{"pkg", "file"},
+			in:       "src/api/v1/handler.go",
+			template: "src/${pkg}/v2/${file}.go",
+			out:      "src/api/v2/handler.go",
+		},
+		{
+			pattern:  "src/*/v1/*.go",
+			names:    []string{"", "file"},
+			in:       "src/api/v1/handler.go",
+			template: "$1/$file/$pkg",
+			out:      "api/handler/",
+		},
+		{
+			pattern:  "{cat,dog}-*",
+			names:    []string{"pet"},
+			in:       "dog-food",
+			template: "${pet}_$2",
+			out:      "dog_food",
+		},
+		{
+			pattern:  "src/*.go",
+			in:       "src/a.go",
+			template: "${name}.md",
+			out:      ".md",
+		},
+	} {
+		g := MustCompileWith(test.pattern, WithSeparators('/'), WithCaptureNames(test.names...)).(Analyzer)
+		if act := g.ReplaceAll(test.in, test.template); act != test.out {
+			t.Errorf("#%d ReplaceAll(%q, %q) = %q; want %q", id, test.in, test.template, act, test.out)
+		}
+	}
+	for _, names := range [][]string{{"a", "b", "c"}, {"a-b"}, {"1a"}, {"ä"}} {
+		if _, err := CompileWith("*.*", WithCaptureNames(names...)); err == nil {
+			t.Errorf("CompileWith() with capture names %q: expected error", names)
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		prefix  string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"abc*", "abc"},
+		{"*abc", ""},
+		{"ab?d", "ab"},
+		{"a[b]c*", "abc"},
+		{"a[b-b][!c]", "ab"},
+		{"src/{cmd,pkg}/**", "src/"},
+		{"src/{cmd,cmd}/**", "src/cmd/"},
+		{"{abc*,abd}", "ab"},
+		{"{ab,abc}d*", "ab"},
+		{"{,a}*", ""},
+		{`\*x*`, "*x"},
+		{"{ä,å}", ""},
+	} {
+		g := MustCompile(test.pattern).(Analyzer)
+		if act := g.Prefix(); act != test.prefix {
+			t.Errorf("#%d MustCompile(%q).Prefix() = %q; want %q", id, test.pattern, act, test.prefix)
+		}
+	}
+}
+
+func TestSuffix(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		suffix  string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"*abc", "abc"},
+		{"abc*", ""},
+		{"**/*.log", ".log"},
+		{"a?cd", "cd"},
+		{"*b[c]", "bc"},
+		{"*.{tar.gz,gz}", "gz"},
+		{"*.{go,go}", ".go"},
+		{"{x*.go,y.go}", ".go"},
+		{"*{ä,å}", ""},
+		{`*\*`, "*"},
+	} {
+		g := MustCompile(test.pattern).(Analyzer)
+		if act := g.Suffix(); act != test.suffix {
+			t.Errorf("#%d MustCompile(%q).Suffix() = %q; want %q", id, test.pattern, act, test.suffix)
+		}
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		literal string
+		ok      bool
+	}{
+		{"", "", true},
+		{"abc", "abc", true},
+		{`a\*c`, "a*c", true},
+		{"a[b]c", "abc", true},
+		{"a{b,b}c", "abc", true},
+		{"a{b,c}d", "", false},
+		{"abc*", "", false},
+		{"a?c", "", false},
+		{"a[!b]c", "", false},
+	} {
+		g := MustCompile(test.pattern).(Analyzer)
+		literal, ok := g.Literal()
+		if literal != test.literal || ok != test.ok {
+			t.Errorf("#%d MustCompile(%q).Literal() = %q, %t; want %q, %t", id, test.pattern, literal, ok, test.literal, test.ok)
+		}
+		if act := g.IsLiteral(); act != test.ok {
+			t.Errorf("#%d MustCompile(%q).IsLiteral() = %t; want %t", id, test.pattern, act, test.ok)
+		}
+	}
+}
+
+func TestCouldMatchPrefix(t *testing.T) {
+	for id, test := range []struct {
+		pattern    string
+		separators []rune
+		prefix     string
+		exp        bool
+	}{
+		{"src/**/*.go", []rune{'/'}, "", true},
+		{"src/**/*.go", []rune{'/'}, "sr", true},
+		{"src/**/*.go", []rune{'/'}, "src/cmd/", true},
+		{"src/**/*.go", []rune{'/'}, "build/tmp/", false},
+		{"src/*.go", []rune{'/'}, "src/cmd/", false},
+		{"src/*.go", []rune{'/'}, "src/main.go", true},
+		{"src/*.go", []rune{'/'}, "src/main.go/", false},
+		{"{cmd,pkg}/*", []rune{'/'}, "pkg/", true},
+		{"{cmd,pkg}/*", []rune{'/'}, "internal/", false},
+		{"*.txt", nil, "a/b/c", true},
+	} {
+		g := MustCompile(test.pattern, test.separators...).(Analyzer)
+		if act := g.CouldMatchPrefix(test.prefix); act != test.exp {
+			t.Errorf("#%d MustCompile(%q).CouldMatchPrefix(%q) = %t; want %t", id, test.pattern, test.prefix, act, test.exp)
+		}
+	}
+}
+
+func TestLenBounds(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		min     int
+		max     int
+	}{
+		{"", 0, 0},
+		{"abc", 3, 3},
+		{"abc*", 3, -1},
+		{"a**", 1, -1},
+		{"a?c", 3, 6},
+		{"[ab]ä", 3, 3},
+		{"[aä]", 1, 2},
+		{"[!a]", 1, 4},
+		{"[a-ä]", 1, 2},
+		{"{a,bcd,}x", 1, 4},
+		{"{a,b*}x", 2, -1},
+	} {
+		g := MustCompile(test.pattern).(Analyzer)
+		if min, max := g.MinLen(), g.MaxLen(); min != test.min || max != test.max {
+			t.Errorf("#%d MustCompile(%q): MinLen(), MaxLen() = %d, %d; want %d, %d", id, test.pattern, min, max, test.min, test.max)
+		}
+	}
+}
+
+func TestCompileAST(t *testing.T) {
+	p := syntax.New(ast.NewNode(ast.KindPattern, nil,
+		ast.NewNode(ast.KindText, ast.Text{Text: "src/"}),
+		ast.NewNode(ast.KindSuper, nil),
+		ast.NewNode(ast.KindText, ast.Text{Text: ".go"}),
+	))
+	g, err := CompileAST(p, '/')
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		s   string
+		exp bool
+	}{
+		{"src/a/b.go", true},
+		{"src/b.go", true},
+		{"pkg/b.go", false},
+	} {
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("Match(%q) = %t; want %t", test.s, act, test.exp)
+		}
+	}
+	if act, exp := g.(Analyzer).Prefix(), "src/"; act != exp {
+		t.Errorf("Prefix() = %q; want %q", act, exp)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		exp     string
+		samples []string
+	}{
+		{`abc`, `abc`, nil},
+		{`a***b`, `a**b`, []string{"ab", "axb", "a/b", "a.c"}},
+		{`a*b**c`, `a*b**c`, []string{"abc", "ab/c", "a/bc"}},
+		{`*?*`, `*?*`, []string{"", "a", "ab"}},
+		{`**/**`, `**/**`, []string{"a", "a/b"}},
+		{`{abc}`, `abc`, []string{"abc"}},
+		{`{a,a,b}`, `{a,b}`, []string{"a", "b", "c"}},
+		{`{abc,abd}`, `ab{c,d}`, []string{"abc", "abd", "abe"}},
+		{`{ab,abc}`, `ab{,c}`, []string{"ab", "abc", "a"}},
+		{`*.{tar.gz,gz}`, `*.{tar.,}gz`, []string{"a.tar.gz", "a.gz", "a.tgz"}},
+		{`{*.go,*.c}`, `*.{go,c}`, []string{"a.go", "a.c", "a.h"}},
+		{`{a,{b,{c,a}}}`, `{a,b,c}`, []string{"a", "b", "c", "d"}},
+		{`x{a*,a**}y`, `xa{*,**}y`, []string{"xay", "xa/y"}},
+		{`[a]b[c-c]`, `abc`, []string{"abc", "abd"}},
+		{`[!a]`, `[!a]`, []string{"a", "b"}},
+	} {
+		act, err := Simplify(test.pattern)
+		if err != nil {
+			t.Errorf("#%d Simplify(%q) unexpected error: %s", id, test.pattern, err)
+			continue
+		}
+		if act != test.exp {
+			t.Errorf("#%d Simplify(%q) = %q; want %q", id, test.pattern, act, test.exp)
+		}
+		for _, sep := range [][]rune{nil, {'/'}} {
+			g, s := MustCompile(test.pattern, sep...), MustCompile(act, sep...)
+			for _, x := range test.samples {
+				if g.Match(x) != s.Match(x) {
+					t.Errorf("#%d %q and %q disagree on %q with separators %q", id, test.pattern, act, x, sep)
+				}
+			}
+		}
+	}
+	if _, err := Simplify("[a"); err == nil {
+		t.Errorf("Simplify(%q) expected error", "[a")
+	}
+}
+
+// matchFunc is a glob implemented outside of the package, which has no
+// methods of Analyzer.
+type matchFunc func(string) bool
+
+func (f matchFunc) Match(s string) bool { return f(s) }
+
+func TestAnalyzer(t *testing.T) {
+	for _, g := range []Glob{
+		MustCompile("*.go"),
+		MustCompileWith("/a b/*", WithURLPath(true)),
+		Not(MustCompile("*.go")),
+		Or(MustCompile("*.go"), MustCompile("*.md", '/')),
+		Concat(MustCompile("a"), "/", matchFunc(func(string) bool { return true })),
+	} {
+		if _, ok := g.(Analyzer); !ok {
+			t.Errorf("%T should implement Analyzer", g)
+		}
+	}
+
+	gofile := matchFunc(func(s string) bool { return strings.HasSuffix(s, ".go") })
+	if _, ok := Glob(gofile).(Analyzer); ok {
+		t.Fatalf("matchFunc should not implement Analyzer")
+	}
+	a := analyzerOf(gofile)
+	if a.Prefix() != "" || a.Suffix() != "" || a.IsLiteral() || a.MinLen() != 0 || a.MaxLen() != -1 ||
+		!a.CouldMatchPrefix("x") || a.Regexp() != `(?s)^.*$` {
+		t.Errorf("unexpected answers for unknown glob")
+	}
+	if act := a.ReplaceAll("a.go", "[$0]"); act != "[a.go]" {
+		t.Errorf("ReplaceAll() = %q; want %q", act, "[a.go]")
+	}
+	if matched, err := a.MatchContext(context.Background(), "a.go"); !matched || err != nil {
+		t.Errorf("MatchContext() = %t, %v; want true, nil", matched, err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.MatchContext(ctx, "a.go"); err == nil {
+		t.Errorf("MatchContext() should fail with canceled context")
+	}
+
+	md := MustCompile("*.md", '/')
+	if g := Or(gofile, md); !g.Match("a.go") || !g.Match("b.md") || g.Match("c.txt") {
+		t.Errorf("unexpected Or() results for unknown glob")
+	}
+	if g := And(gofile, Not(MustCompile("*_test.go"))); !g.Match("a.go") || g.Match("a_test.go") {
+		t.Errorf("unexpected And() results for unknown glob")
+	}
+	if !Overlaps(gofile, md) || Subsumes(gofile, MustCompile("a.go")) {
+		t.Errorf("unexpected conservative Overlaps() or Subsumes() results")
+	}
+	if Compare(MustCompile("a.go"), gofile) != -1 || Compare(gofile, MustCompile("*.go")) != 1 {
+		t.Errorf("unexpected Compare() results for unknown glob")
+	}
+}
+
+func TestMatchAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	patterns := []string{
+		"abc", "*.go", "a*", "*a*b*c*", "{src,cmd}/**/*.go", "[a-c]?[!x]", "*[0-9]",
+		"{*.google.*,yandex.*}", "{,**/}*.go", "a*b?c*d", "{abc*[a-c]def,abc?[d-g]def}",
+		"*a?*b", "ü*ß", "**",
+	}
+	subjects := []string{
+		"abc", "xaybzc", "a.go", "src/a/b.go", "www.google.com", "a/b/c.go", "axbycxd",
+		"abcxadef", "xxaxxbxxbb", "üxß", strings.Repeat("a", 200),
+	}
+	for _, e := range []Engine{EngineDefault, EngineDFA, EnginePikeVM} {
+		for _, p := range patterns {
+			g := MustCompileWith(p, WithSeparators('/', '.'), WithEngine(e))
+			for _, s := range subjects {
+				allocs := testing.AllocsPerRun(10, func() {
+					g.Match(s)
+				})
+				if allocs != 0 {
+					t.Errorf("engine %d: %q Match(%q) allocates %v times", e, p, s, allocs)
+				}
+			}
+		}
+	}
+}
+
+//go:generate go run cmd/globgen/main.go -pkg glob -s / -o gogen_generated_test.go genLiteral=readme.md genPrefix=/static/** genSuffix=**.go genContains=**needle** genPrefixSuffix=a**z genSegment=/users/*/profile genClass=[a-z]*[0-9] genNotClass=[!a-c]? genAlternatives=*.{png,jpg} genUnicode=ж?[а-я] genBacktrack=*a*ab genNothing=
+
 func BenchmarkParseGlob(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		Compile(pattern_all)
 	}
 }
+
 func BenchmarkParseRegexp(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		regexp.MustCompile(regexp_all)
@@ -225,6 +647,7 @@ func BenchmarkAllGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_all_match)
 	}
 }
+
 func BenchmarkAllGlobMatchParallel(b *testing.B) {
 	m, _ := Compile(pattern_all)
 
@@ -243,6 +666,7 @@ func BenchmarkAllRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkAllGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_all)
 
@@ -250,6 +674,7 @@ func BenchmarkAllGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_all_mismatch)
 	}
 }
+
 func BenchmarkAllGlobMismatchParallel(b *testing.B) {
 	m, _ := Compile(pattern_all)
 
@@ -259,6 +684,7 @@ func BenchmarkAllGlobMismatchParallel(b *testing.B) {
 		}
 	})
 }
+
 func BenchmarkAllRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_all)
 	f := []byte(fixture_all_mismatch)
@@ -275,6 +701,7 @@ func BenchmarkMultipleGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_multiple_match)
 	}
 }
+
 func BenchmarkMultipleRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_multiple)
 	f := []byte(fixture_multiple_match)
@@ -283,6 +710,7 @@ func BenchmarkMultipleRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkMultipleGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_multiple)
 
@@ -290,6 +718,7 @@ func BenchmarkMultipleGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_multiple_mismatch)
 	}
 }
+
 func BenchmarkMultipleRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_multiple)
 	f := []byte(fixture_multiple_mismatch)
@@ -306,6 +735,7 @@ func BenchmarkAlternativesGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_alternatives_match)
 	}
 }
+
 func BenchmarkAlternativesGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_alternatives)
 
@@ -313,6 +743,7 @@ func BenchmarkAlternativesGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_alternatives_mismatch)
 	}
 }
+
 func BenchmarkAlternativesRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_alternatives)
 	f := []byte(fixture_alternatives_match)
@@ -321,6 +752,7 @@ func BenchmarkAlternativesRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkAlternativesRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_alternatives)
 	f := []byte(fixture_alternatives_mismatch)
@@ -337,6 +769,7 @@ func BenchmarkAlternativesSuffixFirstGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_alternatives_suffix_first_match)
 	}
 }
+
 func BenchmarkAlternativesSuffixFirstGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_alternatives_suffix)
 
@@ -344,6 +777,7 @@ func BenchmarkAlternativesSuffixFirstGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_alternatives_suffix_first_mismatch)
 	}
 }
+
 func BenchmarkAlternativesSuffixSecondGlobMatch(b *testing.B) {
 	m, _ := Compile(pattern_alternatives_suffix)
 
@@ -351,6 +785,7 @@ func BenchmarkAlternativesSuffixSecondGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_alternatives_suffix_second)
 	}
 }
+
 func BenchmarkAlternativesCombineLiteGlobMatch(b *testing.B) {
 	m, _ := Compile(pattern_alternatives_combine_lite)
 
@@ -358,6 +793,7 @@ func BenchmarkAlternativesCombineLiteGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_alternatives_combine_lite)
 	}
 }
+
 func BenchmarkAlternativesCombineHardGlobMatch(b *testing.B) {
 	m, _ := Compile(pattern_alternatives_combine_hard)
 
@@ -365,6 +801,7 @@ func BenchmarkAlternativesCombineHardGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_alternatives_combine_hard)
 	}
 }
+
 func BenchmarkAlternativesSuffixFirstRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_alternatives_suffix)
 	f := []byte(fixture_alternatives_suffix_first_match)
@@ -373,6 +810,7 @@ func BenchmarkAlternativesSuffixFirstRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkAlternativesSuffixFirstRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_alternatives_suffix)
 	f := []byte(fixture_alternatives_suffix_first_mismatch)
@@ -381,6 +819,7 @@ func BenchmarkAlternativesSuffixFirstRegexpMismatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkAlternativesSuffixSecondRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_alternatives_suffix)
 	f := []byte(fixture_alternatives_suffix_second)
@@ -389,6 +828,7 @@ func BenchmarkAlternativesSuffixSecondRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkAlternativesCombineLiteRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_alternatives_combine_lite)
 	f := []byte(fixture_alternatives_combine_lite)
@@ -397,6 +837,7 @@ func BenchmarkAlternativesCombineLiteRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkAlternativesCombineHardRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_alternatives_combine_hard)
 	f := []byte(fixture_alternatives_combine_hard)
@@ -413,6 +854,7 @@ func BenchmarkPlainGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_plain_match)
 	}
 }
+
 func BenchmarkPlainRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_plain)
 	f := []byte(fixture_plain_match)
@@ -421,6 +863,7 @@ func BenchmarkPlainRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkPlainGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_plain)
 
@@ -428,6 +871,7 @@ func BenchmarkPlainGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_plain_mismatch)
 	}
 }
+
 func BenchmarkPlainRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_plain)
 	f := []byte(fixture_plain_mismatch)
@@ -444,6 +888,7 @@ func BenchmarkPrefixGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_prefix_suffix_match)
 	}
 }
+
 func BenchmarkPrefixRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_prefix)
 	f := []byte(fixture_prefix_suffix_match)
@@ -452,6 +897,7 @@ func BenchmarkPrefixRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkPrefixGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_prefix)
 
@@ -459,6 +905,7 @@ func BenchmarkPrefixGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_prefix_suffix_mismatch)
 	}
 }
+
 func BenchmarkPrefixRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_prefix)
 	f := []byte(fixture_prefix_suffix_mismatch)
@@ -475,6 +922,7 @@ func BenchmarkSuffixGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_prefix_suffix_match)
 	}
 }
+
 func BenchmarkSuffixRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_suffix)
 	f := []byte(fixture_prefix_suffix_match)
@@ -483,6 +931,7 @@ func BenchmarkSuffixRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkSuffixGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_suffix)
 
@@ -490,6 +939,7 @@ func BenchmarkSuffixGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_prefix_suffix_mismatch)
 	}
 }
+
 func BenchmarkSuffixRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_suffix)
 	f := []byte(fixture_prefix_suffix_mismatch)
@@ -506,6 +956,7 @@ func BenchmarkPrefixSuffixGlobMatch(b *testing.B) {
 		_ = m.Match(fixture_prefix_suffix_match)
 	}
 }
+
 func BenchmarkPrefixSuffixRegexpMatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_prefix_suffix)
 	f := []byte(fixture_prefix_suffix_match)
@@ -514,6 +965,7 @@ func BenchmarkPrefixSuffixRegexpMatch(b *testing.B) {
 		_ = m.Match(f)
 	}
 }
+
 func BenchmarkPrefixSuffixGlobMismatch(b *testing.B) {
 	m, _ := Compile(pattern_prefix_suffix)
 
@@ -521,6 +973,7 @@ func BenchmarkPrefixSuffixGlobMismatch(b *testing.B) {
 		_ = m.Match(fixture_prefix_suffix_mismatch)
 	}
 }
+
 func BenchmarkPrefixSuffixRegexpMismatch(b *testing.B) {
 	m := regexp.MustCompile(regexp_prefix_suffix)
 	f := []byte(fixture_prefix_suffix_mismatch)
@@ -0,0 +1,501 @@
+package glob
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gobwas/glob/nfa"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// GlobSet is a set of globs matched against a string at once. Patterns are
+// merged into a trie by their literal prefixes, so that a single pass over
+// the string finds the patterns it could match, and only their matchers are
+// run, unless the string does not end with their literal suffixes or does
+// not fit their lengths. So for "src/foo.go" patterns like `src/*.go` or
+// `s*` are consulted, but not `doc/**`. Patterns without wildcards need no matching at all.
+// Patterns without a prefix, like `*word*`, are found by the literals they
+// require instead, using the Aho–Corasick automaton.
+//
+// GlobSet is not safe for Add and Remove concurrent with other calls.
+type GlobSet struct {
+	opts     []Option
+	patterns []string
+	globs    []Glob
+	trie     setTrie
+	contains acAutomaton
+
+	// workers is the number of goroutines matching candidates, when there
+	// are at least threshold of them.
+	workers   int
+	threshold int
+
+	// dfa, if set, recognizes all patterns but the rest ones, which are
+	// matched on their own.
+	dfa       *nfa.DFA
+	rest      []int
+	dfaStates int
+}
+
+// defaultDFAStates is the limit of states of the DFA built for a set,
+// unless WithDFA sets another one.
+const defaultDFAStates = 10000
+
+// WithDFA makes GlobSet union all of its patterns into a single
+// deterministic automaton, so that finding the patterns a string matches
+// is a single linear scan of the string, regardless of the number of
+// patterns. Patterns normalizing strings and patterns with placeholders
+// are matched on their own. Since the automaton could grow exponentially
+// with the number of patterns, it is not used if it has more than maxStates
+// states; non-positive maxStates means the default of 10000. Compilation of
+// single patterns ignores this option.
+func WithDFA(maxStates int) Option {
+	return func(o *options) {
+		o.dfa = true
+		o.dfaStates = maxStates
+	}
+}
+
+// defaultParallelThreshold is the number of candidate patterns, starting
+// from which matching is sharded, unless WithParallelism sets another one.
+// Below it the cost of starting goroutines outweighs the gain.
+const defaultParallelThreshold = 4096
+
+// WithParallelism makes GlobSet match candidate patterns of a string in up
+// to the given number of goroutines, when there are at least threshold of
+// them; non-positive threshold means the default of 4096. It pays off for
+// sets with tens of thousands of patterns, and runtime.NumCPU() is a
+// sensible number of workers. Compilation of single patterns ignores this
+// option.
+func WithParallelism(workers, threshold int) Option {
+	return func(o *options) {
+		o.workers = workers
+		o.threshold = threshold
+	}
+}
+
+// setTrie is a node of the trie of pattern prefixes. The path to the node
+// spells the prefix.
+type setTrie struct {
+	children map[byte]*setTrie
+
+	// globs are the indexes of patterns with wildcards having the prefix,
+	// and literals are the indexes of patterns matching just the prefix.
+	// Both are in ascending order.
+	globs    []int
+	literals []int
+}
+
+func (t *setTrie) insert(prefix string, i int, literal bool) {
+	for j := 0; j < len(prefix); j++ {
+		c := t.children[prefix[j]]
+		if c == nil {
+			if t.children == nil {
+				t.children = make(map[byte]*setTrie)
+			}
+			c = &setTrie{}
+			t.children[prefix[j]] = c
+		}
+		t = c
+	}
+	if literal {
+		t.literals = append(t.literals, i)
+	} else {
+		t.globs = append(t.globs, i)
+	}
+}
+
+// remove removes the i-th pattern with the prefix from the trie and
+// decrements indexes of the following patterns.
+func (t *setTrie) remove(prefix string, i int, literal bool) {
+	n := t
+	for j := 0; j < len(prefix); j++ {
+		n = n.children[prefix[j]]
+	}
+	if literal {
+		n.literals = removeIndex(n.literals, i)
+	} else {
+		n.globs = removeIndex(n.globs, i)
+	}
+	t.shift(i)
+}
+
+func (t *setTrie) shift(i int) {
+	shiftIndexes(t.globs, i)
+	shiftIndexes(t.literals, i)
+	for _, c := range t.children {
+		c.shift(i)
+	}
+}
+
+// removeIndex removes i from the ascending indexes.
+func removeIndex(indexes []int, i int) []int {
+	j := sort.SearchInts(indexes, i)
+	if j < len(indexes) && indexes[j] == i {
+		indexes = append(indexes[:j], indexes[j+1:]...)
+	}
+	return indexes
+}
+
+// shiftIndexes decrements the indexes greater than i, which follow the
+// removed one.
+func shiftIndexes(indexes []int, i int) {
+	for j, k := range indexes {
+		if k > i {
+			indexes[j] = k - 1
+		}
+	}
+}
+
+// lookup appends to buf the indexes of patterns with wildcards, the
+// prefixes of which s starts with. It also returns the indexes of literal
+// patterns equal to s.
+func (t *setTrie) lookup(s string, buf []int) (globs, literals []int) {
+	globs = append(buf, t.globs...)
+	for j := 0; j < len(s); j++ {
+		if t = t.children[s[j]]; t == nil {
+			break
+		}
+		globs = append(globs, t.globs...)
+		if j == len(s)-1 {
+			literals = t.literals
+		}
+	}
+	if len(s) == 0 {
+		literals = t.literals
+	}
+	return globs, literals
+}
+
+// NewGlobSet compiles the patterns with given options by CompileWith into a
+// set. The indexes of the patterns are kept, so that Which returns the
+// index of the pattern in the slice.
+func NewGlobSet(patterns []string, opts ...Option) (*GlobSet, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	s := &GlobSet{
+		opts:      opts,
+		patterns:  append([]string(nil), patterns...),
+		globs:     make([]Glob, len(patterns)),
+		workers:   o.workers,
+		threshold: o.threshold,
+	}
+	if s.threshold <= 0 {
+		s.threshold = defaultParallelThreshold
+	}
+	if s.dfaStates = o.dfaStates; s.dfaStates <= 0 {
+		s.dfaStates = defaultDFAStates
+	}
+	for i, p := range patterns {
+		g, err := CompileWith(p, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("pattern #%d %q: %s", i, p, err)
+		}
+		s.globs[i] = g
+		s.place(i, true)
+	}
+	s.contains.build()
+	if o.dfa {
+		s.buildDFA()
+	}
+	return s, nil
+}
+
+// buildDFA builds the DFA of the patterns of the set, or leaves the set
+// without it, if it has too many states.
+func (s *GlobSet) buildDFA() {
+	ns := make([]*nfa.NFA, len(s.globs))
+	var rest []int
+	for i, g := range s.globs {
+		if c := g.(*compiled); c.normalize == nil && countKind(c.tree, ast.KindPlaceholder) == 0 {
+			ns[i] = nfa.New(c.tree, c.separators)
+		} else {
+			rest = append(rest, i)
+		}
+	}
+	if d, ok := nfa.NewDFA(ns, s.dfaStates); ok {
+		s.dfa, s.rest = d, rest
+	} else {
+		s.dfa, s.rest = nil, nil
+	}
+}
+
+// place inserts the i-th pattern into the trie or the automaton, or
+// removes it from there if insert is false.
+func (s *GlobSet) place(i int, insert bool) {
+	// Strings are transformed before matching by normalizing globs, so
+	// their prefixes say nothing about the strings.
+	c := s.globs[i].(*compiled)
+	prefix, literal := c.prefix, c.literal
+	switch lit := requiredLiteral(c.tree); {
+	case c.normalize != nil:
+		prefix, literal = "", false
+	case c.prefix == "" && lit != "":
+		if insert {
+			s.contains.add(lit, i)
+		} else {
+			s.contains.remove(lit, i)
+			s.trie.shift(i)
+		}
+		return
+	}
+	if insert {
+		s.trie.insert(prefix, i, literal)
+	} else {
+		s.trie.remove(prefix, i, literal)
+		s.contains.remove("", i)
+	}
+}
+
+// Add compiles the pattern with the options of the set and appends it to
+// the set, so that its index is Len()-1. Other patterns are not compiled
+// again.
+func (s *GlobSet) Add(pattern string) error {
+	g, err := CompileWith(pattern, s.opts...)
+	if err != nil {
+		return err
+	}
+	s.patterns = append(s.patterns, pattern)
+	s.globs = append(s.globs, g)
+	s.place(len(s.globs)-1, true)
+	s.contains.build()
+	if s.dfa != nil {
+		// The automaton is not extended, and the pattern is matched on its
+		// own.
+		s.rest = append(s.rest, len(s.globs)-1)
+	}
+	return nil
+}
+
+// Remove removes the i-th pattern from the set. Indexes of the following
+// patterns are decremented, as if the pattern was removed from the slice
+// passed to NewGlobSet. The automaton of a set built WithDFA is built
+// again.
+func (s *GlobSet) Remove(i int) {
+	s.place(i, false)
+	s.contains.build()
+	s.patterns = append(s.patterns[:i], s.patterns[i+1:]...)
+	s.globs = append(s.globs[:i], s.globs[i+1:]...)
+	if s.dfa != nil {
+		s.buildDFA()
+	}
+}
+
+// MustGlobSet is the same as NewGlobSet, except that if NewGlobSet returns
+// error, this will panic.
+func MustGlobSet(patterns []string, opts ...Option) *GlobSet {
+	s, err := NewGlobSet(patterns, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Len returns the number of patterns in the set.
+func (s *GlobSet) Len() int {
+	return len(s.globs)
+}
+
+// Pattern returns the i-th pattern of the set.
+func (s *GlobSet) Pattern(i int) string {
+	return s.patterns[i]
+}
+
+// Glob returns the glob compiled from the i-th pattern of the set.
+func (s *GlobSet) Glob(i int) Glob {
+	return s.globs[i]
+}
+
+// candidates appends to buf the indexes of patterns with wildcards str
+// could match, in ascending order. It also returns the ascending indexes of
+// patterns known to match str: literal patterns equal to str, or patterns
+// accepted by the DFA.
+func (s *GlobSet) candidates(str string, buf []int) (globs, literals []int) {
+	if s.dfa != nil {
+		// Patterns the automaton accepts are matched already.
+		return append(buf, s.rest...), s.dfa.Match(str)
+	}
+	globs, literals = s.trie.lookup(str, buf)
+	globs = s.contains.find(str, globs)
+	if !sort.IntsAreSorted(globs) {
+		sort.Ints(globs)
+	}
+	// Patterns are found once per occurrence of their literals.
+	k := 0
+	for j, i := range globs {
+		if j == 0 || i != globs[k-1] {
+			globs[k] = i
+			k++
+		}
+	}
+	globs = globs[:k]
+	// The trie partitions patterns by their prefixes only, so candidates
+	// with suffixes or lengths str does not fit are left out as well.
+	n := 0
+	for _, i := range globs {
+		if c := s.globs[i].(*compiled); c.normalize != nil || c.fits(str) {
+			globs[n] = i
+			n++
+		}
+	}
+	return globs[:n], literals
+}
+
+// find returns the position in globs of the first pattern, for which
+// matching str reports want, or -1 if there is none.
+func (s *GlobSet) find(str string, globs []int, want bool) int {
+	if s.workers <= 1 || len(globs) < s.threshold {
+		for j, i := range globs {
+			if s.globs[i].Match(str) == want {
+				return j
+			}
+		}
+		return -1
+	}
+	// Workers skip positions after the best one found so far.
+	best := int64(len(globs))
+	s.shard(len(globs), func(lo, hi int) {
+		for j := lo; j < hi && int64(j) < atomic.LoadInt64(&best); j++ {
+			if s.globs[globs[j]].Match(str) != want {
+				continue
+			}
+			for {
+				b := atomic.LoadInt64(&best)
+				if int64(j) >= b || atomic.CompareAndSwapInt64(&best, b, int64(j)) {
+					return
+				}
+			}
+		}
+	})
+	if best == int64(len(globs)) {
+		return -1
+	}
+	return int(best)
+}
+
+// filter returns the indexes of patterns in globs which str matches, in the
+// order of globs.
+func (s *GlobSet) filter(str string, globs []int) []int {
+	if s.workers <= 1 || len(globs) < s.threshold {
+		var matches []int
+		for _, i := range globs {
+			if s.globs[i].Match(str) {
+				matches = append(matches, i)
+			}
+		}
+		return matches
+	}
+	ok := make([]bool, len(globs))
+	s.shard(len(globs), func(lo, hi int) {
+		for j := lo; j < hi; j++ {
+			ok[j] = s.globs[globs[j]].Match(str)
+		}
+	})
+	var matches []int
+	for j, i := range globs {
+		if ok[j] {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// shard splits n positions into contiguous ranges, calls fn for each of
+// them in a separate goroutine and waits for all of them to return.
+func (s *GlobSet) shard(n int, fn func(lo, hi int)) {
+	workers := s.workers
+	if workers > n {
+		workers = n
+	}
+	size := (n + workers - 1) / workers
+	var wg sync.WaitGroup
+	for lo := 0; lo < n; lo += size {
+		hi := lo + size
+		if hi > n {
+			hi = n
+		}
+		wg.Add(1)
+		go func(lo, hi int) {
+			defer wg.Done()
+			fn(lo, hi)
+		}(lo, hi)
+	}
+	wg.Wait()
+}
+
+// MatchAny reports whether str matches any of the patterns.
+func (s *GlobSet) MatchAny(str string) bool {
+	var buf [16]int
+	globs, literals := s.candidates(str, buf[:0])
+	return len(literals) > 0 || s.find(str, globs, true) != -1
+}
+
+// MatchAll reports whether str matches all of the patterns. It is true for
+// the empty set.
+func (s *GlobSet) MatchAll(str string) bool {
+	var buf [16]int
+	globs, literals := s.candidates(str, buf[:0])
+	if len(globs)+len(literals) != len(s.globs) {
+		return false
+	}
+	return s.find(str, globs, false) == -1
+}
+
+// Which returns the index of the first pattern str matches, or -1 if it
+// matches none of them.
+func (s *GlobSet) Which(str string) int {
+	var buf [16]int
+	globs, literals := s.candidates(str, buf[:0])
+	if len(literals) > 0 {
+		// Patterns after the first literal one need not be matched.
+		globs = globs[:sort.SearchInts(globs, literals[0])]
+	}
+	if j := s.find(str, globs, true); j != -1 {
+		return globs[j]
+	}
+	if len(literals) > 0 {
+		return literals[0]
+	}
+	return -1
+}
+
+// Matching returns the indexes of all patterns str matches, in ascending
+// order, for policy engines evaluating all applicable rules rather than the
+// first one.
+func (s *GlobSet) Matching(str string) []int {
+	var buf [16]int
+	globs, literals := s.candidates(str, buf[:0])
+	matches := s.filter(str, globs)
+	if len(literals) == 0 {
+		return matches
+	}
+	all := make([]int, 0, len(matches)+len(literals))
+	for len(matches) > 0 || len(literals) > 0 {
+		if len(literals) == 0 || len(matches) > 0 && matches[0] < literals[0] {
+			all = append(all, matches[0])
+			matches = matches[1:]
+		} else {
+			all = append(all, literals[0])
+			literals = literals[1:]
+		}
+	}
+	return all
+}
+
+// MatchingPatterns is the same as Matching, but returns the patterns
+// instead of their indexes.
+func (s *GlobSet) MatchingPatterns(str string) []string {
+	matches := s.Matching(str)
+	if matches == nil {
+		return nil
+	}
+	patterns := make([]string, len(matches))
+	for j, i := range matches {
+		patterns[j] = s.patterns[i]
+	}
+	return patterns
+}
@@ -0,0 +1,222 @@
+package glob
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestGlobSet(t *testing.T) {
+	set := MustGlobSet([]string{"*.go", "main.go", "cmd/*", "*_test.go", "readme.md"}, WithSeparators('/'))
+	for _, test := range []struct {
+		s     string
+		which int
+		all   bool
+	}{
+		{"glob.go", 0, false},
+		{"main.go", 0, false},
+		{"readme.md", 4, false},
+		{"cmd/x", 2, false},
+		{"cmd/x/y", -1, false},
+		{"a_test.go", 0, false},
+	} {
+		if act := set.Which(test.s); act != test.which {
+			t.Errorf("Which(%q) = %d; want %d", test.s, act, test.which)
+		}
+		if act := set.MatchAny(test.s); act != (test.which != -1) {
+			t.Errorf("MatchAny(%q) = %t; want %t", test.s, act, test.which != -1)
+		}
+		if act := set.MatchAll(test.s); act != test.all {
+			t.Errorf("MatchAll(%q) = %t; want %t", test.s, act, test.all)
+		}
+	}
+
+	set = MustGlobSet([]string{"a*", "abc", "*c", "abc"})
+	if act := set.Which("abc"); act != 0 {
+		t.Errorf("Which() = %d; want 0", act)
+	}
+	if !set.MatchAll("abc") || set.MatchAll("ac") {
+		t.Errorf("unexpected MatchAll() results")
+	}
+	// patterns found by their literals are matched once, however many
+	// times the literals occur
+	set = MustGlobSet([]string{"*ab*"})
+	if !set.MatchAll("abab") {
+		t.Errorf("MatchAll(%q) = false; want true", "abab")
+	}
+	if act := set.Matching("abab"); !reflect.DeepEqual(act, []int{0}) {
+		t.Errorf("Matching(%q) = %v; want [0]", "abab", act)
+	}
+	set = MustGlobSet([]string{"x*", "abc"})
+	if act := set.Which("abc"); act != 1 {
+		t.Errorf("Which() = %d; want 1", act)
+	}
+	if set.Len() != 2 || set.Pattern(1) != "abc" || !set.Glob(0).Match("xy") {
+		t.Errorf("unexpected set accessors results")
+	}
+	if !MustGlobSet(nil).MatchAll("a") || MustGlobSet(nil).MatchAny("a") {
+		t.Errorf("unexpected empty set results")
+	}
+	if _, err := NewGlobSet([]string{"a", "[b"}); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+
+	set = MustGlobSet([]string{"*.go", "main.go", "cmd/*", "*_test.go", "main.*"})
+	if act := set.Matching("main.go"); !reflect.DeepEqual(act, []int{0, 1, 4}) {
+		t.Errorf("Matching() = %v; want [0 1 4]", act)
+	}
+	if act := set.MatchingPatterns("main_test.go"); !reflect.DeepEqual(act, []string{"*.go", "*_test.go"}) {
+		t.Errorf("MatchingPatterns() = %q; want [*.go *_test.go]", act)
+	}
+	if act := set.MatchingPatterns("x"); act != nil {
+		t.Errorf("MatchingPatterns() = %q; want nil", act)
+	}
+
+	set = MustGlobSet([]string{"/a b/*"}, WithURLPath(true))
+	if act := set.Which("/a%20b/c"); act != 0 {
+		t.Errorf("Which() = %d; want 0", act)
+	}
+
+	// Sets sharing prefixes must give the same results as the globs do.
+	patterns := []string{
+		"src/*.go", "src/main.go", "src", "src/cmd/**", "*", "src/cmd/glob/main.go",
+		"s?c/*", "src/*/*", "", "doc/*.md", "src/cmd", "{src,doc}/*.md", "**.go",
+		"**main**", "**ain**", "**.md", "**{glob,gob}**", "*a*b*", "**/**",
+	}
+	subjects := []string{
+		"", "src", "src/main.go", "src/cmd/glob/main.go", "src/cmd", "doc/readme.md",
+		"src/readme.md", "sxc/a", "src/x/y", "main.go", "doc", "s", "gob/x", "amain",
+		"xmd", "ab", "a/b", "ba",
+	}
+	if set := MustGlobSet(patterns, WithSeparators('/'), WithDFA(0)); set.dfa == nil {
+		t.Errorf("DFA of the set is not built")
+	}
+	if set := MustGlobSet(patterns, WithSeparators('/'), WithDFA(1)); set.dfa != nil {
+		t.Errorf("DFA of the set is built beyond the limit")
+	}
+
+	// Parallel matching and matching by DFA must give the same results as
+	// well.
+	for _, set := range []*GlobSet{
+		MustGlobSet(patterns, WithSeparators('/')),
+		MustGlobSet(patterns, WithSeparators('/'), WithParallelism(4, 1)),
+		MustGlobSet(patterns, WithSeparators('/'), WithDFA(0)),
+	} {
+		for _, subj := range subjects {
+			which, all := -1, true
+			var matches []int
+			for i, p := range patterns {
+				if MustCompile(p, '/').Match(subj) {
+					if which == -1 {
+						which = i
+					}
+					matches = append(matches, i)
+				} else {
+					all = false
+				}
+			}
+			if act := set.Which(subj); act != which {
+				t.Errorf("Which(%q) = %d; want %d", subj, act, which)
+			}
+			if act := set.MatchAny(subj); act != (which != -1) {
+				t.Errorf("MatchAny(%q) = %t; want %t", subj, act, which != -1)
+			}
+			if act := set.MatchAll(subj); act != all {
+				t.Errorf("MatchAll(%q) = %t; want %t", subj, act, all)
+			}
+			if act := set.Matching(subj); !reflect.DeepEqual(act, matches) {
+				t.Errorf("Matching(%q) = %v; want %v", subj, act, matches)
+			}
+		}
+	}
+}
+
+func TestGlobSetCandidates(t *testing.T) {
+	// Patterns are partitioned by literal prefixes, and patterns without
+	// them by the literals they require, so that only patterns which could
+	// match are consulted.
+	set := MustGlobSet([]string{
+		"src/*.go", "src/**", "doc/*.md", "doc/**", "*.go", "*_test.go", "s*", "**", "src/main.go",
+	}, WithSeparators('/'))
+	for _, test := range []struct {
+		s        string
+		globs    []int
+		literals []int
+	}{
+		{"src/foo.go", []int{0, 1, 4, 6, 7}, nil},
+		{"src/main.go", []int{0, 1, 4, 6, 7}, []int{8}},
+		{"doc/a.md", []int{2, 3, 7}, nil},
+		{"doc/a_test.go", []int{3, 4, 5, 7}, nil},
+		{"src", []int{6, 7}, nil},
+		{"x", []int{7}, nil},
+	} {
+		globs, literals := set.candidates(test.s, nil)
+		if !reflect.DeepEqual(globs, test.globs) || len(literals) != len(test.literals) ||
+			len(literals) > 0 && !reflect.DeepEqual(literals, test.literals) {
+			t.Errorf("candidates(%q) = %v, %v; want %v, %v", test.s, globs, literals, test.globs, test.literals)
+		}
+	}
+}
+
+func TestGlobSetUpdate(t *testing.T) {
+	for _, set := range []*GlobSet{
+		MustGlobSet(nil, WithSeparators('/')),
+		MustGlobSet(nil, WithSeparators('/'), WithDFA(0)),
+	} {
+		testGlobSetUpdate(t, set)
+	}
+}
+
+func testGlobSetUpdate(t *testing.T, set *GlobSet) {
+	patterns := []string{"*.go", "main.go", "cmd/*", "*_test.go", "*vendor*", "", "**"}
+	subjects := []string{"glob.go", "main.go", "cmd/x", "a_test.go", "my/vendor/z", "", "x/y"}
+	check := func(step string) {
+		for _, subj := range subjects {
+			var matches []int
+			for i, p := range patterns {
+				if MustCompile(p, '/').Match(subj) {
+					matches = append(matches, i)
+				}
+			}
+			if act := set.Matching(subj); !reflect.DeepEqual(act, matches) {
+				t.Errorf("%s: Matching(%q) = %v; want %v", step, subj, act, matches)
+			}
+		}
+		if set.Len() != len(patterns) {
+			t.Errorf("%s: Len() = %d; want %d", step, set.Len(), len(patterns))
+		}
+	}
+	for _, p := range patterns {
+		if err := set.Add(p); err != nil {
+			t.Fatalf("Add(%q): unexpected error: %s", p, err)
+		}
+	}
+	check("after adding")
+	for _, i := range []int{1, 3, 0} {
+		set.Remove(i)
+		patterns = append(patterns[:i], patterns[i+1:]...)
+		check(fmt.Sprintf("after removing #%d", i))
+	}
+	set.Add("*.go")
+	set.Add("main.go")
+	patterns = append(patterns, "*.go", "main.go")
+	check("after adding again")
+
+	var buf bytes.Buffer
+	if err := set.Save(&buf); err != nil {
+		t.Fatalf("Save(): unexpected error: %s", err)
+	}
+	if set, err := LoadGlobSet(&buf, WithSeparators('/')); err != nil {
+		t.Errorf("LoadGlobSet(): unexpected error: %s", err)
+	} else {
+		set.Remove(0)
+		set.Add("*/*")
+		if act := set.Matching("cmd/x"); !reflect.DeepEqual(act, []int{2, 5}) {
+			t.Errorf("Matching() = %v after updating loaded set; want [2 5]", act)
+		}
+	}
+	if err := set.Add("[a"); err == nil || set.Len() != len(patterns) {
+		t.Errorf("Add(): expected error for invalid pattern")
+	}
+}
@@ -0,0 +1,328 @@
+package glob
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/nfa"
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// GoFunc is a function written by WriteGo, which reports whether the string
+// matches the pattern.
+type GoFunc struct {
+	Name    string
+	Pattern string
+}
+
+// goStates is the limit of states of automata that WriteGo turns into code.
+const goStates = 1000
+
+// WriteGo writes to w the source of the Go package pkg with a function
+// `func Name(s string) bool` for each of funcs, matching strings as the
+// pattern compiled with the options does. Patterns which are literals, or
+// literals followed or preceded by `*`, become calls to the strings package;
+// others become deterministic automata written as straight-line code, with
+// a label for every state. The functions neither use interfaces nor
+// allocate, which suits a handful of hot patterns, like routes, known when
+// the program is built; cmd/globgen calls WriteGo for go:generate.
+//
+// Patterns with placeholders or options normalizing strings, like
+// WithURLPath, could not be written, nor the ones whose automata exceed 1000
+// states.
+func WriteGo(w io.Writer, pkg string, funcs []GoFunc, opts ...Option) error {
+	if !isIdentifier(pkg) {
+		return fmt.Errorf("invalid package name %q", pkg)
+	}
+	var (
+		body    bytes.Buffer
+		imports = make(map[string]bool)
+		names   = make(map[string]bool)
+	)
+	for i, f := range funcs {
+		if !isIdentifier(f.Name) {
+			return fmt.Errorf("pattern #%d %q: invalid function name %q", i, f.Pattern, f.Name)
+		}
+		if names[f.Name] {
+			return fmt.Errorf("pattern #%d %q: duplicate function name %q", i, f.Pattern, f.Name)
+		}
+		names[f.Name] = true
+
+		g, err := CompileWith(f.Pattern, opts...)
+		if err != nil {
+			return fmt.Errorf("pattern #%d %q: %s", i, f.Pattern, err)
+		}
+		c := g.(*compiled)
+		if countKind(c.tree, ast.KindPlaceholder) > 0 {
+			return fmt.Errorf("pattern #%d %q: could not write placeholders", i, f.Pattern)
+		}
+		if c.normalize != nil {
+			return fmt.Errorf("pattern #%d %q: could not write normalization", i, f.Pattern)
+		}
+		fmt.Fprintf(&body, "\n// %s reports whether s matches %q.\n", f.Name, f.Pattern)
+		fmt.Fprintf(&body, "func %s(s string) bool {\n", f.Name)
+		if !writeGoLiteral(&body, c.Matcher, imports) {
+			d, ok := nfa.NewDFA([]*nfa.NFA{c.automaton()}, goStates)
+			if !ok {
+				return fmt.Errorf("pattern #%d %q: automaton exceeds %d states", i, f.Pattern, goStates)
+			}
+			writeGoDFA(&body, d, imports)
+		}
+		body.WriteString("}\n")
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by github.com/gobwas/glob. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", pkg)
+	if len(imports) > 0 {
+		buf.WriteString("\nimport (\n")
+		for _, p := range []string{"strings", "unicode/utf8"} {
+			if imports[p] {
+				fmt.Fprintf(&buf, "%q\n", p)
+			}
+		}
+		buf.WriteString(")\n")
+	}
+	buf.Write(body.Bytes())
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func isIdentifier(s string) bool {
+	if s == "" || s == "_" {
+		return false
+	}
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeGoLiteral writes the body of the function matching strings as the
+// matcher does, if it is one of the literal matchers which the strings
+// package implements.
+func writeGoLiteral(buf *bytes.Buffer, m match.Matcher, imports map[string]bool) bool {
+	switch m := m.(type) {
+	case match.Nothing:
+		buf.WriteString("return s == \"\"\n")
+		return true
+	case match.Super:
+		buf.WriteString("return true\n")
+		return true
+	case match.Text:
+		fmt.Fprintf(buf, "return s == %q\n", m.Str)
+		return true
+	case match.Prefix:
+		fmt.Fprintf(buf, "return strings.HasPrefix(s, %q)\n", m.Prefix)
+	case match.Suffix:
+		fmt.Fprintf(buf, "return strings.HasSuffix(s, %q)\n", m.Suffix)
+	case match.PrefixSuffix:
+		// The prefix and the suffix must not overlap.
+		fmt.Fprintf(buf, "return len(s) >= %d && strings.HasPrefix(s, %q) && strings.HasSuffix(s, %q)\n",
+			len(m.Prefix)+len(m.Suffix), m.Prefix, m.Suffix)
+	case match.Contains:
+		if m.Not {
+			return false
+		}
+		fmt.Fprintf(buf, "return strings.Contains(s, %q)\n", m.Needle)
+	default:
+		return false
+	}
+	imports["strings"] = true
+	return true
+}
+
+// writeGoDFA writes the body of the function matching strings as the
+// automaton does. Each state is a label, which is jumped to on transitions.
+// The state reads a rune of the string, if there are any left, and jumps to
+// the next state, or returns whether the automaton accepts the string.
+// Chains of states with a single transition, like those of literal parts
+// of patterns, are compared at once.
+func writeGoDFA(buf *bytes.Buffer, d *nfa.DFA, imports map[string]bool) {
+	if len(d.States) == 0 {
+		buf.WriteString("return false\n")
+		return
+	}
+	var (
+		groups = make([][]asciiGroup, len(d.States))
+		preds  = make([]int, len(d.States))
+		pred   = make([]int, len(d.States))
+	)
+	for n := range d.States {
+		st := &d.States[n]
+		groups[n] = asciiGroups(st)
+		to := make(map[int32]bool)
+		for _, g := range groups[n] {
+			to[g.to] = true
+		}
+		for _, t := range st.Other {
+			to[t.To] = true
+		}
+		for m := range to {
+			preds[m]++
+			pred[m] = n
+		}
+	}
+	// step returns the byte of the single transition of the state, if it
+	// has one, and the state it leads to.
+	step := func(n int) (byte, int, bool) {
+		if len(d.States[n].Other) > 0 || len(groups[n]) != 1 {
+			return 0, 0, false
+		}
+		g := groups[n][0]
+		if len(g.set) != 1 || g.set[0].Lo != g.set[0].Hi {
+			return 0, 0, false
+		}
+		return byte(g.set[0].Lo), int(g.to), true
+	}
+	// Inner states of chains are the ones with a single transition, which
+	// are only led to by the single transition of the previous state. They
+	// are compared along with it and need no labels.
+	inner := make([]bool, len(d.States))
+	for m := range d.States {
+		if m == 0 || preds[m] != 1 || pred[m] == m || len(d.States[m].Accept) > 0 {
+			continue
+		}
+		if _, _, ok := step(m); !ok {
+			continue
+		}
+		if _, to, ok := step(pred[m]); ok && to == m {
+			inner[m] = true
+		}
+	}
+
+	buf.WriteString("var i int\n")
+	for n, st := range d.States {
+		if inner[n] {
+			continue
+		}
+		// Go does not allow labels which are never jumped to.
+		if preds[n] > 0 {
+			fmt.Fprintf(buf, "s%d:\n", n)
+		}
+		accept := len(st.Accept) > 0
+		if len(groups[n]) == 0 && len(st.Other) == 0 {
+			buf.WriteString("return i == len(s)\n")
+			continue
+		}
+		if c, to, ok := step(n); ok && inner[to] {
+			lit := []byte{c}
+			for inner[to] {
+				c, to, _ = step(to)
+				lit = append(lit, c)
+			}
+			imports["strings"] = true
+			if accept {
+				buf.WriteString("if i == len(s) {\nreturn true\n}\n")
+			}
+			fmt.Fprintf(buf, "if !strings.HasPrefix(s[i:], %q) {\nreturn false\n}\n", lit)
+			fmt.Fprintf(buf, "i += %d\ngoto s%d\n", len(lit), to)
+			continue
+		}
+		fmt.Fprintf(buf, "if i == len(s) {\nreturn %t\n}\n", accept)
+		buf.WriteString("switch c := s[i]; {\n")
+		if len(st.Other) > 0 {
+			imports["unicode/utf8"] = true
+			buf.WriteString("case c >= utf8.RuneSelf:\n")
+			if cond := goRunesCond("r", st.Other[0].Runes, utf8.RuneSelf, nfa.Invalid); cond == "true" {
+				// All of the runes lead to the same state.
+				buf.WriteString("_, w := utf8.DecodeRuneInString(s[i:])\n")
+				fmt.Fprintf(buf, "i += w\ngoto s%d\n", st.Other[0].To)
+			} else {
+				buf.WriteString("r, w := utf8.DecodeRuneInString(s[i:])\n")
+				// Invalid bytes are told apart from U+FFFD, as the
+				// automaton does.
+				fmt.Fprintf(buf, "if r == utf8.RuneError && w == 1 {\nr = %s\n}\n", goRune(nfa.Invalid))
+				buf.WriteString("i += w\n")
+				buf.WriteString("switch {\n")
+				for _, t := range st.Other {
+					fmt.Fprintf(buf, "case %s:\ngoto s%d\n", goRunesCond("r", t.Runes, utf8.RuneSelf, nfa.Invalid), t.To)
+				}
+				buf.WriteString("}\n")
+			}
+		}
+		for _, g := range groups[n] {
+			// Non-ASCII bytes are handled above, if they could be.
+			max := rune(utf8.RuneSelf - 1)
+			if len(st.Other) == 0 {
+				max = 0xff
+			}
+			fmt.Fprintf(buf, "case %s:\ni++\ngoto s%d\n", goRunesCond("c", g.set, 0, max), g.to)
+		}
+		buf.WriteString("}\nreturn false\n")
+	}
+}
+
+type asciiGroup struct {
+	set runes.Set
+	to  int32
+}
+
+// asciiGroups returns the ASCII runes of transitions of the state, grouped
+// by the states they lead to, in the order of the least runes of groups.
+func asciiGroups(st *nfa.DFAState) []asciiGroup {
+	var (
+		groups []asciiGroup
+		index  = make(map[int32]int)
+	)
+	for c, to := range st.ASCII {
+		if to == -1 {
+			continue
+		}
+		i, ok := index[to]
+		if !ok {
+			i = len(groups)
+			index[to] = i
+			groups = append(groups, asciiGroup{to: to})
+		}
+		groups[i].set = append(groups[i].set, runes.Range{Lo: rune(c), Hi: rune(c)})
+	}
+	for i := range groups {
+		groups[i].set = runes.NewSet(groups[i].set...)
+	}
+	return groups
+}
+
+// goRunesCond returns Go expression reporting whether the variable v is in
+// the set, given that it is between min and max.
+func goRunesCond(v string, set runes.Set, min, max rune) string {
+	var buf bytes.Buffer
+	for i, r := range set {
+		if i > 0 {
+			buf.WriteString(" || ")
+		}
+		switch {
+		case r.Lo <= min && r.Hi >= max:
+			return "true"
+		case r.Lo == r.Hi:
+			fmt.Fprintf(&buf, "%s == %s", v, goRune(r.Lo))
+		case r.Lo <= min:
+			fmt.Fprintf(&buf, "%s <= %s", v, goRune(r.Hi))
+		case r.Hi >= max:
+			fmt.Fprintf(&buf, "%s >= %s", v, goRune(r.Lo))
+		default:
+			fmt.Fprintf(&buf, "%s <= %s && %s <= %s", goRune(r.Lo), v, v, goRune(r.Hi))
+		}
+	}
+	return buf.String()
+}
+
+func goRune(r rune) string {
+	if utf8.ValidRune(r) {
+		return fmt.Sprintf("%q", r)
+	}
+	return fmt.Sprintf("%#x", r)
+}
@@ -0,0 +1,82 @@
+package glob
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+)
+
+// goFuncs are the functions of gogen_generated_test.go, which is written by
+// go generate.
+var goFuncs = []struct {
+	name    string
+	pattern string
+	match   func(string) bool
+}{
+	{"genLiteral", "readme.md", genLiteral},
+	{"genPrefix", "/static/**", genPrefix},
+	{"genSuffix", "**.go", genSuffix},
+	{"genContains", "**needle**", genContains},
+	{"genPrefixSuffix", "a**z", genPrefixSuffix},
+	{"genSegment", "/users/*/profile", genSegment},
+	{"genClass", "[a-z]*[0-9]", genClass},
+	{"genNotClass", "[!a-c]?", genNotClass},
+	{"genAlternatives", "*.{png,jpg}", genAlternatives},
+	{"genUnicode", "ж?[а-я]", genUnicode},
+	{"genBacktrack", "*a*ab", genBacktrack},
+	{"genNothing", "", genNothing},
+}
+
+func TestWriteGo(t *testing.T) {
+	var funcs []GoFunc
+	for _, f := range goFuncs {
+		funcs = append(funcs, GoFunc{Name: f.name, Pattern: f.pattern})
+	}
+	var buf bytes.Buffer
+	if err := WriteGo(&buf, "glob", funcs, WithSeparators('/')); err != nil {
+		t.Fatal(err)
+	}
+	exp, err := ioutil.ReadFile("gogen_generated_test.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), exp) {
+		t.Errorf("gogen_generated_test.go is out of date, run go generate")
+	}
+
+	subjects := []string{
+		"", "readme.md", "README.md", "/static/", "/static/a/b.css", "/static",
+		"main.go", "cmd/main.go", "go", "a needle in haystack", "needl",
+		"az", "a/b/z", "aza", "z", "/users/bob/profile", "/users//profile",
+		"/users/a/b/profile", "/users/ж/profile", "ab9", "a9", "9", "a/9",
+		"d/", "dx", "aж", "img.png", "a/img.jpg", "img.gif", "жxя", "ж/я",
+		"жxz", "ab", "aab", "xaxab", "xaxabx", "\xff", "a\xff9", "\xffab",
+	}
+	for _, f := range goFuncs {
+		g := MustCompile(f.pattern, '/')
+		for _, s := range subjects {
+			if act, exp := f.match(s), g.Match(s); act != exp {
+				t.Errorf("%s(%q) = %t; want %t as %q matches", f.name, s, act, exp, f.pattern)
+			}
+		}
+	}
+}
+
+func TestWriteGoErrors(t *testing.T) {
+	for _, test := range []struct {
+		pkg   string
+		funcs []GoFunc
+		opts  []Option
+	}{
+		{"1pkg", nil, nil},
+		{"p", []GoFunc{{"a-b", "x"}}, nil},
+		{"p", []GoFunc{{"a", "x"}, {"a", "y"}}, nil},
+		{"p", []GoFunc{{"a", "[a"}}, nil},
+		{"p", []GoFunc{{"a", "/a/*"}}, []Option{WithURLPath(true)}},
+		{"p", []GoFunc{{"a", "*a??????????"}}, nil},
+	} {
+		if err := WriteGo(ioutil.Discard, test.pkg, test.funcs, test.opts...); err == nil {
+			t.Errorf("WriteGo(%q, %v) returned no error", test.pkg, test.funcs)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package glob
+
+import (
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// WithGraphemes makes `?` match a single grapheme cluster, that is, what is
+// seen as a single character, instead of a single rune. A letter followed by
+// combining marks, an emoji with modifiers or joined with other emoji, and a
+// flag are matched by a single `?` then. Clusters are split by
+// runes.GraphemeLen of the util/runes package, which approximates the rules
+// of Unicode Standard Annex #29. Wildcards `*` and character classes are not
+// affected: the latter match a single rune.
+//
+// Since lengths of clusters are not fixed, Regexp renders `?` as `.*` then,
+// and Overlaps and Subsumes report conservative results, as they do for
+// placeholders.
+func WithGraphemes() Option {
+	return func(o *options) {
+		o.graphemes = true
+	}
+}
+
+// graphemeName is the name of placeholders which `?` matching grapheme
+// clusters is replaced with.
+const graphemeName = "?"
+
+// graphemeTree returns a copy of the tree, in which `?` matches grapheme
+// clusters not starting with the separators.
+func graphemeTree(tree *ast.Node, seps []rune) *ast.Node {
+	if tree.Kind == ast.KindSingle {
+		return graphemeNode(seps)
+	}
+	n := ast.NewNode(tree.Kind, tree.Value)
+	for _, c := range tree.Children {
+		ast.Insert(n, graphemeTree(c, seps))
+	}
+	return n
+}
+
+func graphemeNode(seps []rune) *ast.Node {
+	return ast.NewNode(ast.KindPlaceholder, &ast.Placeholder{
+		Name:    graphemeName,
+		Matcher: match.NewGrapheme(seps),
+	})
+}
@@ -0,0 +1,31 @@
+package glob
+
+import "testing"
+
+func TestWithGraphemes(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		s       string
+		exp     bool
+	}{
+		{"caf?", nil, "cafe\u0301", true},
+		{"caf??", nil, "cafe\u0301", false},
+		{"?", nil, "\U0001f44d\U0001f3fd", true},
+		{"?", nil, "\U0001f468\u200d\U0001f469\u200d\U0001f467", true},
+		{"??", nil, "\U0001f1fa\U0001f1f8\U0001f1e9\U0001f1ea", true},
+		{"*.?", []Option{WithSeparators('/')}, "a.e\u0301", true},
+		{"a?b", []Option{WithSeparators('/')}, "a/b", false},
+		{"?*", []Option{WithLeadingPeriod()}, ".a", false},
+		{"?*", []Option{WithLeadingPeriod()}, "e\u0301a", true},
+		{"[e]?", nil, "e\u0301", false},
+	} {
+		g := MustCompileWith(test.pattern, append(test.opts, WithGraphemes())...)
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("#%d %q Match(%q) = %t; want %t", id, test.pattern, test.s, act, test.exp)
+		}
+	}
+	if MustCompile("caf?").Match("cafe\u0301") {
+		t.Errorf("? should match a single rune without the option")
+	}
+}
@@ -0,0 +1,20 @@
+package glob
+
+import (
+	"hash/fnv"
+	"reflect"
+)
+
+// Hash returns a 64-bit hash of the matcher g is compiled to, and of the
+// normalization of strings, if any. The hash does not depend on the process
+// it is computed in, so it could be used as a cache key or for sharding
+// patterns across workers. Globs that are Equal have the same hash.
+func Hash(g Glob) uint64 {
+	h := fnv.New64a()
+	if c, ok := canonical(g); ok {
+		h.Write(c)
+	} else {
+		h.Write([]byte(reflect.TypeOf(g).String()))
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,31 @@
+package glob
+
+import "testing"
+
+func TestHash(t *testing.T) {
+	for id, test := range []struct {
+		a, b string
+		exp  bool
+	}{
+		{"abc", "abc", true},
+		{"abc", "{abc}", true},
+		{"**", "***", true},
+		{"abc", "abd", false},
+		{"a*", "a?", false},
+		{"{a,b}", "{b,a}", true},
+		{"{a,b}", "{a,c}", false},
+		{"{a*,b?}", "{b?,a*}", false},
+	} {
+		a, b := MustCompile(test.a), MustCompile(test.b)
+		if act := Hash(a) == Hash(b); act != test.exp {
+			t.Errorf("#%d Hash(%q) == Hash(%q) is %t; want %t", id, test.a, test.b, act, test.exp)
+		}
+	}
+}
+
+func TestHashStable(t *testing.T) {
+	// The hash must not change between processes and releases.
+	if act, exp := Hash(MustCompile("*.go")), uint64(0x418cb0ea66f14505); act != exp {
+		t.Errorf("Hash(MustCompile(%q)) = %#x; want %#x", "*.go", act, exp)
+	}
+}
@@ -0,0 +1,120 @@
+package glob
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// WithHostname configures matching of DNS host names, with '.' as the
+// separator. Matching is case-insensitive, and a trailing '.' of fully
+// qualified names is ignored, both in the pattern and in the names. Note
+// that other methods of the glob, like Literal, Suffix, MinLen, MaxLen or
+// Regexp, describe names without the trailing '.'.
+//
+// As in wildcard certificates, `*` as a whole label matches exactly one
+// label, that is, one or more characters other than '.'. Inside of a label,
+// like in `w*.example.com`, it matches any characters of the label. If
+// multiLabel is true, `**` as a whole label matches one or more labels, so
+// `**.example.com` matches "a.b.example.com". Otherwise, as well as inside
+// of a label, `**` is rejected by CompileWith.
+func WithHostname(multiLabel bool) Option {
+	return func(o *options) {
+		o.separators = []rune{'.'}
+		o.caseFold = true
+		o.hostname = true
+		o.multiLabel = multiLabel
+		o.normalize = trimTrailingDot
+	}
+}
+
+func trimTrailingDot(s string) (string, bool) {
+	return strings.TrimSuffix(s, "."), true
+}
+
+// hostnameTree returns a copy of the tree, in which wildcards forming whole
+// labels do not match empty strings. See WithHostname for details.
+func hostnameTree(tree *ast.Node, multiLabel bool) (*ast.Node, error) {
+	tree, err := hostnameNode(tree, true, true, multiLabel)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(tree.Children); tree.Kind == ast.KindPattern && n > 0 {
+		// Ignore the trailing dot of fully qualified name.
+		last := tree.Children[n-1]
+		if t, ok := last.Value.(ast.Text); ok && strings.HasSuffix(t.Text, ".") {
+			last.Value = ast.Text{Text: strings.TrimSuffix(t.Text, ".")}
+		}
+	}
+	return tree, nil
+}
+
+// hostnameNode converts the node. The start and end flags report whether the
+// node is preceded and followed by '.' or by the ends of the pattern.
+func hostnameNode(n *ast.Node, start, end, multiLabel bool) (*ast.Node, error) {
+	label := start && end
+	switch n.Kind {
+	case ast.KindAny:
+		if !label {
+			return ast.NewNode(n.Kind, n.Value), nil
+		}
+		// `*` matches exactly one label.
+		return ast.NewNode(ast.KindPattern, nil,
+			ast.NewNode(ast.KindSingle, nil),
+			ast.NewNode(ast.KindAny, nil),
+		), nil
+
+	case ast.KindSuper:
+		if !multiLabel {
+			return nil, fmt.Errorf("multi-label wildcard `**` is not allowed")
+		}
+		if !label {
+			return nil, fmt.Errorf("`**` must be a whole label")
+		}
+		// `**` matches one or more labels, which could not be empty.
+		return ast.NewNode(ast.KindPattern, nil,
+			ast.NewNode(ast.KindSingle, nil),
+			ast.NewNode(ast.KindAnyOf, nil,
+				ast.NewNode(ast.KindPattern, nil),
+				ast.NewNode(ast.KindPattern, nil,
+					ast.NewNode(ast.KindSuper, nil),
+					ast.NewNode(ast.KindSingle, nil),
+				),
+			),
+		), nil
+
+	case ast.KindPattern:
+		p := ast.NewNode(ast.KindPattern, nil)
+		nodes := n.Children
+		for i, c := range nodes {
+			s, e := start, end
+			if i > 0 {
+				s = nodes[i-1].Kind == ast.KindText && strings.HasSuffix(nodes[i-1].Value.(ast.Text).Text, ".")
+			}
+			if i < len(nodes)-1 {
+				e = nodes[i+1].Kind == ast.KindText && strings.HasPrefix(nodes[i+1].Value.(ast.Text).Text, ".")
+			}
+			c, err := hostnameNode(c, s, e, multiLabel)
+			if err != nil {
+				return nil, err
+			}
+			ast.Insert(p, c)
+		}
+		return p, nil
+
+	case ast.KindAnyOf:
+		a := ast.NewNode(ast.KindAnyOf, nil)
+		for _, c := range n.Children {
+			c, err := hostnameNode(c, start, end, multiLabel)
+			if err != nil {
+				return nil, err
+			}
+			ast.Insert(a, c)
+		}
+		return a, nil
+
+	default:
+		return ast.NewNode(n.Kind, n.Value), nil
+	}
+}
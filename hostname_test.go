@@ -0,0 +1,94 @@
+package glob
+
+import "testing"
+
+func TestWithHostname(t *testing.T) {
+	for id, test := range []struct {
+		pattern    string
+		multiLabel bool
+		match      []string
+		miss       []string
+	}{
+		{
+			pattern: "*.example.com",
+			match:   []string{"a.example.com", "WWW.Example.COM", "a.example.com."},
+			miss:    []string{"example.com", ".example.com", "a.b.example.com", "a.example.org"},
+		},
+		{
+			pattern: "example.com.",
+			match:   []string{"example.com", "example.com."},
+			miss:    []string{"a.example.com"},
+		},
+		{
+			pattern: "w*.example.com",
+			match:   []string{"w.example.com", "www.example.com"},
+			miss:    []string{"a.example.com", "w.x.example.com"},
+		},
+		{
+			pattern: "{*,*.*}.example.com",
+			match:   []string{"a.example.com", "a.b.example.com"},
+			miss:    []string{"a..example.com", "a.b.c.example.com"},
+		},
+		{
+			pattern:    "**.example.com",
+			multiLabel: true,
+			match:      []string{"a.example.com", "a.b.c.example.com"},
+			miss:       []string{"example.com", ".example.com", "a..example.com", "a.example.org"},
+		},
+		{
+			pattern:    "api.**",
+			multiLabel: true,
+			match:      []string{"api.com", "api.example.com"},
+			miss:       []string{"api", "api.", "api..com"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithHostname(test.multiLabel))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+	for _, test := range []struct {
+		pattern    string
+		multiLabel bool
+	}{
+		{"**.example.com", false},
+		{"a**.example.com", true},
+	} {
+		if _, err := CompileWith(test.pattern, WithHostname(test.multiLabel)); err == nil {
+			t.Errorf("CompileWith(%q): expected error", test.pattern)
+		}
+	}
+}
+
+func TestWithHostnameTrailingDot(t *testing.T) {
+	// Case folding leaves only digits and hyphens literal.
+	g := MustCompileWith("*.1-2.", WithHostname(false)).(Analyzer)
+	if !g.Match("x.1-2.") {
+		t.Errorf("%q should match %q", "*.1-2.", "x.1-2.")
+	}
+	// Other methods describe names without the trailing dot.
+	if s := g.Suffix(); s != ".1-2" {
+		t.Errorf("Suffix() = %q; want %q", s, ".1-2")
+	}
+	if n := g.MinLen(); n != len("x.1-2") {
+		t.Errorf("MinLen() = %d; want %d", n, len("x.1-2"))
+	}
+	l := MustCompileWith("10.0.0.1.", WithHostname(false)).(Analyzer)
+	if s, ok := l.Literal(); s != "10.0.0.1" || !ok {
+		t.Errorf("Literal() = %q, %t; want %q, true", s, ok, "10.0.0.1")
+	}
+	if n := l.MaxLen(); n != len("10.0.0.1") {
+		t.Errorf("MaxLen() = %d; want %d", n, len("10.0.0.1"))
+	}
+}
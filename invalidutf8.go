@@ -0,0 +1,86 @@
+package glob
+
+import "unicode/utf8"
+
+// InvalidUTF8 is how strings which are not valid UTF-8 are matched.
+// Patterns must be valid UTF-8 whatever it is, and CompileWith rejects
+// other ones.
+type InvalidUTF8 int
+
+const (
+	// InvalidUTF8Bytes matches invalid bytes as they are. Each of them is
+	// a character of its own, which `?` and `*` match, and which classes
+	// see as U+FFFD, as decoding of UTF-8 makes it, while text of the
+	// pattern never matches it. So the pattern `[!a]` matches "\xff",
+	// while the pattern "\uFFFD" does not.
+	InvalidUTF8Bytes InvalidUTF8 = iota
+
+	// InvalidUTF8Replace replaces each invalid byte of strings with
+	// U+FFFD before matching, so that text of the pattern matches them as
+	// well as wildcards and classes do. Only invalid strings are copied.
+	InvalidUTF8Replace
+
+	// InvalidUTF8Reject makes strings with invalid bytes match no
+	// patterns at all, even `*`.
+	InvalidUTF8Reject
+)
+
+// WithInvalidUTF8 sets how strings with invalid UTF-8 are matched. Strings
+// are checked after other options, like WithURLPath, transform them, so that
+// percent-encoded invalid bytes are handled as well. Globs compiled with
+// this option along with such ones could not be saved.
+func WithInvalidUTF8(p InvalidUTF8) Option {
+	return func(o *options) {
+		o.invalid = p
+	}
+}
+
+// normalize returns the function handling invalid UTF-8 as p says, or nil if
+// strings are matched as they are.
+func (p InvalidUTF8) normalize() func(string) (string, bool) {
+	switch p {
+	case InvalidUTF8Replace:
+		return replaceInvalidUTF8
+	case InvalidUTF8Reject:
+		return checkUTF8
+	}
+	return nil
+}
+
+// checkUTF8 reports whether s is valid UTF-8.
+func checkUTF8(s string) (string, bool) {
+	return s, utf8.ValidString(s)
+}
+
+// replaceInvalidUTF8 replaces each invalid byte of s with U+FFFD.
+func replaceInvalidUTF8(s string) (string, bool) {
+	if utf8.ValidString(s) {
+		return s, true
+	}
+	b := make([]byte, 0, len(s)+len(s)/2)
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && w == 1 {
+			b = append(b, "\uFFFD"...)
+		} else {
+			b = append(b, s[i:i+w]...)
+		}
+		i += w
+	}
+	return string(b), true
+}
+
+// normalizeThen returns the function transforming strings by first, if it
+// is set, and the result by then.
+func normalizeThen(first, then func(string) (string, bool)) func(string) (string, bool) {
+	if first == nil {
+		return then
+	}
+	return func(s string) (string, bool) {
+		s, ok := first(s)
+		if !ok {
+			return s, false
+		}
+		return then(s)
+	}
+}
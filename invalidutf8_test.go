@@ -0,0 +1,44 @@
+package glob
+
+import "testing"
+
+func TestWithInvalidUTF8(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		s       string
+		exp     bool
+	}{
+		{"?", nil, "\xff", true},
+		{"[!a]", nil, "\xff", true},
+		{"[\ufffd]", nil, "\xff", true},
+		{"[\u0800-\uffff]", nil, "\xff", true},
+		{"\ufffd", nil, "\xff", false},
+		{"*.txt", nil, "a\xff.txt", true},
+		{"??", nil, "\xffa", true},
+		{"\ufffd", []Option{WithInvalidUTF8(InvalidUTF8Replace)}, "\xff", true},
+		{"a\ufffd\ufffd*", []Option{WithInvalidUTF8(InvalidUTF8Replace)}, "a\xff\xfeb", true},
+		{"*", []Option{WithInvalidUTF8(InvalidUTF8Reject)}, "\xff", false},
+		{"*", []Option{WithInvalidUTF8(InvalidUTF8Reject)}, "a\u00e9", true},
+		{"/*", []Option{WithInvalidUTF8(InvalidUTF8Reject), WithURLPath(true)}, "/%FF", false},
+		{"/*", []Option{WithInvalidUTF8(InvalidUTF8Reject), WithURLPath(true)}, "/%C3%A9", true},
+		{"*", []Option{WithInvalidUTF8(InvalidUTF8Reject), WithNormalization(composer{})}, "\xff", false},
+	} {
+		g := MustCompileWith(test.pattern, test.opts...)
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("#%d %q Match(%q) = %t; want %t", id, test.pattern, test.s, act, test.exp)
+		}
+	}
+
+	if _, err := Compile("a\xff"); err == nil {
+		t.Errorf("expected error compiling invalid UTF-8")
+	}
+	c := NewCache(2)
+	a, _ := c.Compile("*", WithInvalidUTF8(InvalidUTF8Reject))
+	if b, _ := c.Compile("*"); a == b {
+		t.Errorf("globs compiled with different handling of invalid UTF-8 should not be shared")
+	}
+	if b, _ := c.Compile("*", WithInvalidUTF8(InvalidUTF8Reject)); a != b {
+		t.Errorf("glob compiled with handling of invalid UTF-8 should be cached")
+	}
+}
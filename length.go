@@ -0,0 +1,117 @@
+package glob
+
+import (
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// lengthBounds returns the minimum and the maximum length in bytes of the
+// strings matched by tree. The maximum is -1 if it is unbounded.
+func lengthBounds(tree *ast.Node) (min, max int) {
+	switch tree.Kind {
+	case ast.KindText:
+		n := len(tree.Value.(ast.Text).Text)
+		return n, n
+
+	case ast.KindAny, ast.KindSuper:
+		return 0, -1
+
+	case ast.KindPlaceholder:
+		// Len of matchers is the number of runes.
+		if m, ok := tree.Value.(*ast.Placeholder).Matcher.(match.Matcher); ok {
+			if n := m.Len(); n != -1 {
+				return n, n * utf8.UTFMax
+			}
+		}
+		return 0, -1
+
+	case ast.KindSingle:
+		return 1, utf8.UTFMax
+
+	case ast.KindList:
+		l := tree.Value.(ast.List)
+		if l.Not {
+			return 1, utf8.UTFMax
+		}
+		min = utf8.UTFMax
+		for _, r := range l.Chars {
+			lo, hi := runeLen(r)
+			if lo < min {
+				min = lo
+			}
+			if hi > max {
+				max = hi
+			}
+		}
+		return min, max
+
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		if r.Not {
+			return 1, utf8.UTFMax
+		}
+		min, _ = runeLen(r.Lo)
+		_, max = runeLen(r.Hi)
+		if r.Lo <= utf8.RuneError && utf8.RuneError <= r.Hi {
+			min = 1
+		}
+		return min, max
+
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		set := c.Set()
+		if c.Not || set.Empty() {
+			return 1, utf8.UTFMax
+		}
+		min, _ = runeLen(set[0].Lo)
+		_, max = runeLen(set[len(set)-1].Hi)
+		if set.Contains(utf8.RuneError) {
+			min = 1
+		}
+		return min, max
+
+	case ast.KindPattern:
+		for _, c := range tree.Children {
+			cmin, cmax := lengthBounds(c)
+			min += cmin
+			if max != -1 {
+				if cmax == -1 {
+					max = -1
+				} else {
+					max += cmax
+				}
+			}
+		}
+		return min, max
+
+	case ast.KindAnyOf:
+		for i, c := range tree.Children {
+			cmin, cmax := lengthBounds(c)
+			if i == 0 || cmin < min {
+				min = cmin
+			}
+			if i == 0 || max != -1 && (cmax == -1 || cmax > max) {
+				max = cmax
+			}
+		}
+		return min, max
+
+	default:
+		return 0, 0
+	}
+}
+
+// runeLen returns the bounds of the number of bytes a rune r matches. It
+// differs from utf8.RuneLen for utf8.RuneError, which is also what every
+// invalid byte decodes to, and for surrogate halves.
+func runeLen(r rune) (min, max int) {
+	if r == utf8.RuneError {
+		return 1, utf8.RuneLen(r)
+	}
+	if n := utf8.RuneLen(r); n > 0 {
+		return n, n
+	}
+	return 3, 3
+}
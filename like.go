@@ -0,0 +1,117 @@
+package glob
+
+import (
+	"bytes"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Like translates the glob into SQL LIKE pattern, which could be used with
+// both LIKE and ILIKE operators. The escape rune is used to escape `%` and `_`
+// characters of the pattern text; it should be passed to the database in
+// the ESCAPE clause. If escape is 0, such characters are matched with `_`.
+//
+// Some patterns can not be expressed with LIKE exactly. For instance, `*`
+// with separators and character classes are translated into `%` and `_`
+// respectively, so LIKE matches more strings than the glob does. In that
+// case residual is the glob itself, which should be applied to the rows
+// selected by the database. The residual is nil if LIKE pattern matches
+// exactly the same strings as the glob.
+func Like(g Glob, escape rune) (pattern string, residual Glob) {
+	c, ok := g.(*compiled)
+	if !ok {
+		return "%", g
+	}
+	w := likeWriter{
+		sep:    len(c.separators) > 0,
+		escape: escape,
+		exact:  true,
+	}
+	w.write(c.tree)
+	if w.exact {
+		return w.buf.String(), nil
+	}
+	return w.buf.String(), g
+}
+
+type likeWriter struct {
+	buf    bytes.Buffer
+	sep    bool
+	escape rune
+	exact  bool
+	wild   bool // last written is `%`
+}
+
+func (w *likeWriter) write(tree *ast.Node) {
+	switch tree.Kind {
+	case ast.KindPattern:
+		for _, c := range tree.Children {
+			w.write(c)
+		}
+
+	case ast.KindText:
+		for _, r := range tree.Value.(ast.Text).Text {
+			w.wild = false
+			switch {
+			case r != '%' && r != '_' && (r != w.escape || r == 0):
+				w.buf.WriteRune(r)
+			case w.escape != 0:
+				w.buf.WriteRune(w.escape)
+				w.buf.WriteRune(r)
+			default:
+				w.single(false)
+			}
+		}
+
+	case ast.KindSuper:
+		w.any(true)
+
+	case ast.KindPlaceholder:
+		w.any(false)
+
+	case ast.KindAny:
+		w.any(!w.sep)
+
+	case ast.KindSingle:
+		w.single(!w.sep)
+
+	case ast.KindList, ast.KindRange, ast.KindClass:
+		w.single(false)
+
+	case ast.KindAnyOf:
+		// Alternatives are kept only if all of them translate to the same
+		// LIKE pattern.
+		var (
+			first string
+			same  = true
+		)
+		for i, c := range tree.Children {
+			a := likeWriter{sep: w.sep, escape: w.escape, exact: true}
+			a.write(c)
+			same = same && a.exact && (i == 0 || a.buf.String() == first)
+			if i == 0 {
+				first = a.buf.String()
+			}
+		}
+		if same {
+			w.buf.WriteString(first)
+			w.wild = false
+		} else {
+			w.any(false)
+		}
+	}
+}
+
+func (w *likeWriter) any(exact bool) {
+	w.exact = w.exact && exact
+	if !w.wild {
+		w.buf.WriteByte('%')
+		w.wild = true
+	}
+}
+
+func (w *likeWriter) single(exact bool) {
+	w.exact = w.exact && exact
+	w.buf.WriteByte('_')
+	w.wild = false
+}
@@ -0,0 +1,35 @@
+package glob
+
+import "testing"
+
+func TestLike(t *testing.T) {
+	for id, test := range []struct {
+		pattern  string
+		sep      []rune
+		escape   rune
+		exp      string
+		residual bool
+	}{
+		{`abc`, nil, '\\', `abc`, false},
+		{`abc*`, nil, '\\', `abc%`, false},
+		{`*abc**`, nil, '\\', `%abc%`, false},
+		{`a*?b`, nil, '\\', `a%_b`, false},
+		{`a*b`, []rune{'/'}, '\\', `a%b`, true},
+		{`a**b`, []rune{'/'}, '\\', `a%b`, false},
+		{`a?b`, []rune{'/'}, '\\', `a_b`, true},
+		{`a[xy]b`, nil, '\\', `a_b`, true},
+		{`100%_\\x`, nil, '\\', `100\%\_\\x`, false},
+		{`100%_`, nil, 0, `100__`, true},
+		{`a{x,y}*`, nil, '\\', `a%`, true},
+		{`a{x*,x**}`, nil, '\\', `ax%`, false},
+	} {
+		g := MustCompile(test.pattern, test.sep...)
+		act, residual := Like(g, test.escape)
+		if act != test.exp {
+			t.Errorf("#%d Like(%q) = %q; want %q", id, test.pattern, act, test.exp)
+		}
+		if (residual != nil) != test.residual {
+			t.Errorf("#%d Like(%q) residual is %v; want residual: %t", id, test.pattern, residual, test.residual)
+		}
+	}
+}
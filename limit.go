@@ -0,0 +1,238 @@
+package glob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// ErrMaxSteps is the error of matches that took more steps than the limit
+// set with WithMaxSteps.
+var ErrMaxSteps = errors.New("too many steps")
+
+// MatchError describes the match given up by MatchContext.
+type MatchError struct {
+	// Steps is the number of steps taken before the match was given up.
+	Steps int
+
+	// Err is ErrMaxSteps, or the error of the context which is done.
+	Err error
+}
+
+func (e *MatchError) Error() string {
+	return fmt.Sprintf("match given up after %d steps: %s", e.Steps, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *MatchError) Unwrap() error {
+	return e.Err
+}
+
+// WithMaxSteps limits the steps taken by MatchContext to match a string, so
+// that services matching untrusted strings against untrusted patterns could
+// not be wedged by a single match. A step is a match of a part of the string
+// against a wildcard, or a search of a literal part of the pattern; matches
+// against patterns which backtrack, like `*a*a*a*a*b`, take many steps.
+// MatchContext returns *MatchError with ErrMaxSteps for matches that take
+// more than n steps; Match is not limited. Non-positive n means no limit,
+// which is the default.
+//
+// Globs made by combinators, like Or, are not limited by the steps of the
+// globs they are made of.
+func WithMaxSteps(n int) Option {
+	return func(o *options) {
+		o.maxSteps = n
+	}
+}
+
+func (g *compiled) MatchContext(ctx context.Context, s string) (bool, error) {
+	l := match.Limit{MaxSteps: g.maxSteps}
+	matched, err := matchContext(ctx, g, s, &l)
+	if g.stats != nil {
+		g.stats.MatchStats(g.source, MatchStats(l.Stats()))
+	}
+	return matched, err
+}
+
+func (g *compiled) MatchLimit(s string, l *match.Limit) bool {
+	if g.normalize != nil {
+		var ok bool
+		if s, ok = g.normalize(s); !ok {
+			return false
+		}
+	}
+	return g.admits(s) && match.MatchLimit(g.Matcher, s, l)
+}
+
+func (c *combined) MatchContext(ctx context.Context, s string) (bool, error) {
+	var l match.Limit
+	return matchContext(ctx, c, s, &l)
+}
+
+func (c *combined) MatchLimit(s string, l *match.Limit) bool {
+	if len(s) < c.minLen || c.maxLen != -1 && len(s) > c.maxLen {
+		return false
+	}
+	return match.MatchLimit(c.Matcher, s, l)
+}
+
+func (m globMatcher) MatchLimit(s string, l *match.Limit) bool {
+	if v, ok := unwrap(m.Glob).(match.Limited); ok {
+		return v.MatchLimit(s, l)
+	}
+	return l.Step() && m.Match(s)
+}
+
+// matchContext matches s against the matcher within the limit, which is
+// given up once the context is done as well.
+func matchContext(ctx context.Context, m match.Limited, s string, l *match.Limit) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, &MatchError{Err: err}
+	}
+	l.Done = ctx.Done()
+	matched := m.MatchLimit(s, l)
+	if !l.Aborted() {
+		return matched, nil
+	}
+	err := ctx.Err()
+	if err == nil {
+		err = ErrMaxSteps
+	}
+	return false, &MatchError{Steps: l.Steps(), Err: err}
+}
+
+// ErrTooComplex is the error of patterns exceeding limits set with options
+// like WithMaxAlternatives.
+var ErrTooComplex = errors.New("pattern is too complex")
+
+// TooComplexError describes the limit of complexity a pattern exceeds.
+type TooComplexError struct {
+	// Limit is what is limited, like "pattern length".
+	Limit string
+
+	// Value is the value of the pattern, which saturates at math.MaxInt32,
+	// and Max is the limit.
+	Value int
+	Max   int
+}
+
+func (e *TooComplexError) Error() string {
+	return fmt.Sprintf("%s: %s is %d, over the limit of %d", ErrTooComplex, e.Limit, e.Value, e.Max)
+}
+
+// Unwrap returns ErrTooComplex.
+func (e *TooComplexError) Unwrap() error {
+	return ErrTooComplex
+}
+
+// WithMaxPatternLength makes CompileWith reject patterns longer than n bytes
+// with *TooComplexError, before they are parsed. Non-positive n means no
+// limit, which is the default for this and the options below.
+func WithMaxPatternLength(n int) Option {
+	return func(o *options) {
+		o.maxLength = n
+	}
+}
+
+// WithMaxAlternatives makes CompileWith reject patterns with more than n
+// branches of all pattern alternatives, as counted by Complexity, with
+// *TooComplexError.
+func WithMaxAlternatives(n int) Option {
+	return func(o *options) {
+		o.maxAlternatives = n
+	}
+}
+
+// WithMaxNesting makes CompileWith reject patterns with pattern alternatives
+// nested deeper than n, like `{a,{b,{c,d}}}` for n < 3, with
+// *TooComplexError.
+func WithMaxNesting(n int) Option {
+	return func(o *options) {
+		o.maxNesting = n
+	}
+}
+
+// WithMaxExpansions makes CompileWith reject patterns which expand to more
+// than n patterns without alternatives, as shells expand braces, with
+// *TooComplexError. Adjacent alternatives multiply, so that a few bytes like
+// `{a,b}{a,b}{a,b}{a,b}` expand to 16 patterns.
+//
+// The limits of alternatives, nesting and expansions apply to the syntax
+// tree of the pattern as the dialect parses it, before it is compiled.
+func WithMaxExpansions(n int) Option {
+	return func(o *options) {
+		o.maxExpansions = n
+	}
+}
+
+// checkComplexity returns *TooComplexError if the syntax tree exceeds the
+// limits set by the options.
+func (o *options) checkComplexity(tree *ast.Node) error {
+	if o.maxAlternatives <= 0 && o.maxNesting <= 0 && o.maxExpansions <= 0 {
+		return nil
+	}
+	var c treeComplexity
+	c.expansions = c.node(tree, 0)
+	for _, check := range []struct {
+		limit      string
+		value, max int
+	}{
+		{"alternative count", c.alternatives, o.maxAlternatives},
+		{"nesting depth", c.depth, o.maxNesting},
+		{"expansion count", c.expansions, o.maxExpansions},
+	} {
+		if check.max > 0 && check.value > check.max {
+			return &TooComplexError{Limit: check.limit, Value: check.value, Max: check.max}
+		}
+	}
+	return nil
+}
+
+// treeComplexity is the complexity of a syntax tree, as limited by options.
+type treeComplexity struct {
+	alternatives int
+	depth        int
+	expansions   int
+}
+
+// node adds the alternatives of the node, at the given depth of nesting, to
+// the complexity, and returns the number of patterns it expands to.
+func (c *treeComplexity) node(n *ast.Node, depth int) int {
+	switch n.Kind {
+	case ast.KindAnyOf:
+		depth++
+		if depth > c.depth {
+			c.depth = depth
+		}
+		c.alternatives = saturatedAdd(c.alternatives, len(n.Children))
+		sum := 0
+		for _, child := range n.Children {
+			sum = saturatedAdd(sum, c.node(child, depth))
+		}
+		return sum
+	default:
+		product := 1
+		for _, child := range n.Children {
+			product = saturatedMul(product, c.node(child, depth))
+		}
+		return product
+	}
+}
+
+func saturatedAdd(a, b int) int {
+	if a > math.MaxInt32-b {
+		return math.MaxInt32
+	}
+	return a + b
+}
+
+func saturatedMul(a, b int) int {
+	if b != 0 && a > math.MaxInt32/b {
+		return math.MaxInt32
+	}
+	return a * b
+}
@@ -0,0 +1,104 @@
+package glob
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatchContext(t *testing.T) {
+	ctx := context.Background()
+	for id, test := range []struct {
+		glob Glob
+		str  string
+	}{
+		{MustCompile("abc"), "abc"},
+		{MustCompile("*abc*"), "xabx"},
+		{MustCompile("*b*a*a"), "baxa"},
+		{MustCompileWith("*.TXT", WithCaseFold(), WithMaxSteps(10)), "a.txt"},
+		{MustCompileWith("/a/*", WithURLPath(true)), "/a/%62"},
+		{Not(MustCompile("*a*b")), "ab"},
+		{Or(MustCompile("*a*b"), MustCompileWith("c", WithURLPath(true))), "%63"},
+		{And(MustCompile("*a*"), MustCompile("*b*")), "ba"},
+	} {
+		act, err := test.glob.(Analyzer).MatchContext(ctx, test.str)
+		if err != nil {
+			t.Errorf("#%d unexpected error: %v", id, err)
+		}
+		if exp := test.glob.Match(test.str); act != exp {
+			t.Errorf("#%d MatchContext(%q) = %t; want %t", id, test.str, act, exp)
+		}
+	}
+}
+
+func TestMatchContextAbort(t *testing.T) {
+	// Matching of the pattern backtracks for every `*a`, which takes long
+	// for long strings of a's.
+	const pattern = "*b*a*a*a*a*a"
+	s := strings.Repeat("a", 20000)
+
+	g := MustCompileWith(pattern, WithMaxSteps(1000)).(Analyzer)
+	matched, err := g.MatchContext(context.Background(), s)
+	if e, ok := err.(*MatchError); !ok || e.Err != ErrMaxSteps || e.Steps != 1000 || matched {
+		t.Errorf("MatchContext() = %t, %v; want *MatchError of %v after %d steps", matched, err, ErrMaxSteps, 1000)
+	}
+	if matched, err := g.MatchContext(context.Background(), "b"+s[:100]); !matched || err != nil {
+		t.Errorf("MatchContext() = %t, %v; want match", matched, err)
+	}
+
+	g = MustCompile(pattern).(Analyzer)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.MatchContext(ctx, s); err == nil || err.(*MatchError).Err != context.Canceled {
+		t.Errorf("MatchContext() error = %v; want %v", err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	for _, g := range []Analyzer{g, Not(g).(Analyzer)} {
+		if _, err := g.MatchContext(ctx, s); err == nil || err.(*MatchError).Err != context.DeadlineExceeded {
+			t.Errorf("MatchContext() error = %v; want %v", err, context.DeadlineExceeded)
+		}
+	}
+}
+
+func TestCompileLimits(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		limit   string
+		value   int
+	}{
+		{"abc", []Option{WithMaxPatternLength(3)}, "", 0},
+		{"abcd", []Option{WithMaxPatternLength(3)}, "pattern length", 4},
+		{"{a,b}{c,d}", []Option{WithMaxAlternatives(4)}, "", 0},
+		{"{a,b}{c,d,e}", []Option{WithMaxAlternatives(4)}, "alternative count", 5},
+		{"{a,{b,c}}", []Option{WithMaxNesting(2)}, "", 0},
+		{"{a,{b,{c,d}}}", []Option{WithMaxNesting(2)}, "nesting depth", 3},
+		{"{a,b}{a,b}{a,b}{a,b}", []Option{WithMaxExpansions(16)}, "", 0},
+		{"{a,b}{a,b}{a,b}{a,b}{a,b}", []Option{WithMaxExpansions(16)}, "expansion count", 32},
+		{"x{a,{b,c}}y{d,}", []Option{WithMaxExpansions(5)}, "expansion count", 6},
+		{strings.Repeat("{a,b,c,d}", 20), []Option{WithMaxExpansions(1000)}, "expansion count", math.MaxInt32},
+		// the dialect adds alternatives matching the section at any depth
+		{"{1..100}", []Option{WithDialect(DialectEditorconfig), WithMaxAlternatives(99)}, "alternative count", 102},
+		{"{a,b}", []Option{WithMaxExpansions(-1), WithMaxAlternatives(0)}, "", 0},
+	} {
+		_, err := CompileWith(test.pattern, test.opts...)
+		if test.limit == "" {
+			if err != nil {
+				t.Errorf("#%d unexpected error: %v", id, err)
+			}
+			continue
+		}
+		e, ok := err.(*TooComplexError)
+		if !ok {
+			t.Errorf("#%d unexpected error: exp: *TooComplexError, act: %v", id, err)
+			continue
+		}
+		if e.Limit != test.limit || e.Value != test.value || e.Unwrap() != ErrTooComplex {
+			t.Errorf("#%d unexpected error: exp: %s is %d, act: %v", id, test.limit, test.value, e)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package glob
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// ListPlan describes how to list keys of an object store, like S3 or GCS,
+// to find the keys matching a glob.
+type ListPlan struct {
+	// Prefix is the key prefix to pass to the listing call. Keys without it
+	// never match the glob.
+	Prefix string
+
+	// Delimiter is "/" if keys matching the glob have no '/' after the
+	// prefix, so that deeper keys are not listed, and is empty otherwise.
+	Delimiter string
+
+	// Residual is the glob which listed keys should be filtered with. It is
+	// nil if every listed key matches.
+	Residual Glob
+}
+
+// PlanList returns the plan listing as few keys as possible for the glob,
+// compiled with '/' separator. For instance, the plan for
+// `logs/2024/*/app-*.gz` lists keys with prefix "logs/2024/", and the plan
+// for `logs/2024/01/app-*` lists keys with prefix "logs/2024/01/app-" and
+// "/" delimiter, which need no filtering.
+//
+// To walk the keys hierarchically instead, the listing could be done with
+// "/" delimiter at every level, and common prefixes p for which
+// g.CouldMatchPrefix(p) is false could be skipped.
+func PlanList(g Glob) ListPlan {
+	plan := ListPlan{
+		Prefix:   analyzerOf(g).Prefix(),
+		Residual: g,
+	}
+	c, ok := g.(*compiled)
+	if !ok || c.normalize != nil {
+		return plan
+	}
+	slash := false
+	for _, s := range c.separators {
+		slash = slash || s == '/'
+	}
+	if n := maxSlashes(c.tree, slash); n != -1 && n == strings.Count(plan.Prefix, "/") {
+		plan.Delimiter = "/"
+	}
+
+	// The plan is exact if the glob is the prefix followed by a single
+	// wildcard matching anything the listing returns.
+	nodes := []*ast.Node{c.tree}
+	if c.tree.Kind == ast.KindPattern {
+		nodes = c.tree.Children
+	}
+	if len(nodes) > 0 && nodes[0].Kind == ast.KindText {
+		nodes = nodes[1:]
+	}
+	if len(nodes) == 1 {
+		switch k := nodes[0].Kind; {
+		case k == ast.KindSuper && plan.Delimiter == "":
+			plan.Residual = nil
+		case k == ast.KindAny && plan.Delimiter == "/" && len(c.separators) == 1:
+			plan.Residual = nil
+		}
+	}
+	return plan
+}
+
+// maxSlashes returns the maximum number of '/' in strings matched by the
+// tree, or -1 if there is no limit. The slash flag reports whether '/' is
+// a separator.
+func maxSlashes(tree *ast.Node, slash bool) int {
+	switch tree.Kind {
+	case ast.KindText:
+		return strings.Count(tree.Value.(ast.Text).Text, "/")
+
+	case ast.KindSuper, ast.KindPlaceholder:
+		return -1
+
+	case ast.KindAny:
+		if slash {
+			return 0
+		}
+		return -1
+
+	case ast.KindSingle:
+		if slash {
+			return 0
+		}
+		return 1
+
+	case ast.KindList, ast.KindRange, ast.KindClass:
+		if nodeSet(tree).Contains('/') {
+			return 1
+		}
+		return 0
+
+	case ast.KindAnyOf:
+		var max int
+		for _, c := range tree.Children {
+			n := maxSlashes(c, slash)
+			if n == -1 {
+				return -1
+			}
+			if n > max {
+				max = n
+			}
+		}
+		return max
+
+	default:
+		var sum int
+		for _, c := range tree.Children {
+			n := maxSlashes(c, slash)
+			if n == -1 {
+				return -1
+			}
+			sum += n
+		}
+		return sum
+	}
+}
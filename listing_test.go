@@ -0,0 +1,33 @@
+package glob
+
+import "testing"
+
+func TestPlanList(t *testing.T) {
+	for id, test := range []struct {
+		pattern   string
+		sep       []rune
+		prefix    string
+		delimiter string
+		exact     bool
+	}{
+		{pattern: "logs/2024/*/app-*.gz", sep: []rune{'/'}, prefix: "logs/2024/"},
+		{pattern: "logs/2024/01/app-*", sep: []rune{'/'}, prefix: "logs/2024/01/app-", delimiter: "/", exact: true},
+		{pattern: "logs/2024/01/*.gz", sep: []rune{'/'}, prefix: "logs/2024/01/", delimiter: "/"},
+		{pattern: "logs/**", sep: []rune{'/'}, prefix: "logs/", exact: true},
+		{pattern: "logs/*", prefix: "logs/", exact: false},
+		{pattern: "logs/{a,b}/x", sep: []rune{'/'}, prefix: "logs/", delimiter: ""},
+		{pattern: "logs/{a,b}x", sep: []rune{'/'}, prefix: "logs/", delimiter: "/"},
+		{pattern: "*", sep: []rune{'/'}, delimiter: "/", exact: true},
+		{pattern: "a/b", sep: []rune{'/'}, prefix: "a/b", delimiter: "/"},
+	} {
+		g := MustCompile(test.pattern, test.sep...)
+		plan := PlanList(g)
+		if plan.Prefix != test.prefix || plan.Delimiter != test.delimiter || (plan.Residual == nil) != test.exact {
+			t.Errorf(
+				"#%d PlanList(%q) = {%q, %q, exact: %t}; want {%q, %q, exact: %t}",
+				id, test.pattern, plan.Prefix, plan.Delimiter, plan.Residual == nil,
+				test.prefix, test.delimiter, test.exact,
+			)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package glob
+
+import (
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// literalPrefix returns the longest string every string matched by tree
+// starts with. The exact flag reports whether tree matches only that string.
+func literalPrefix(tree *ast.Node) (prefix string, exact bool) {
+	switch tree.Kind {
+	case ast.KindNothing:
+		return "", true
+
+	case ast.KindText:
+		return tree.Value.(ast.Text).Text, true
+
+	case ast.KindList:
+		if l := tree.Value.(ast.List); !l.Not && utf8.RuneCountInString(l.Chars) == 1 && l.Chars != string(utf8.RuneError) {
+			return l.Chars, true
+		}
+		return "", false
+
+	case ast.KindRange:
+		if r := tree.Value.(ast.Range); !r.Not && r.Lo == r.Hi && r.Lo != utf8.RuneError {
+			return string(r.Lo), true
+		}
+		return "", false
+
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		if r, ok := c.Set().Single(); ok && !c.Not && r != utf8.RuneError {
+			return string(r), true
+		}
+		return "", false
+
+	case ast.KindPattern:
+		var buf []byte
+		for _, c := range tree.Children {
+			p, e := literalPrefix(c)
+			buf = append(buf, p...)
+			if !e {
+				return string(buf), false
+			}
+		}
+		return string(buf), true
+
+	case ast.KindAnyOf:
+		if len(tree.Children) == 0 {
+			return "", true
+		}
+		prefix, exact = literalPrefix(tree.Children[0])
+		for _, c := range tree.Children[1:] {
+			p, e := literalPrefix(c)
+			if p != prefix || !e {
+				exact = false
+			}
+			prefix = commonPrefix(prefix, p)
+		}
+		return prefix, exact
+
+	default:
+		return "", false
+	}
+}
+
+// literalSuffix returns the longest string every string matched by tree
+// ends with. The exact flag reports whether tree matches only that string.
+func literalSuffix(tree *ast.Node) (suffix string, exact bool) {
+	switch tree.Kind {
+	case ast.KindPattern:
+		var buf string
+		for i := len(tree.Children) - 1; i >= 0; i-- {
+			s, e := literalSuffix(tree.Children[i])
+			buf = s + buf
+			if !e {
+				return buf, false
+			}
+		}
+		return buf, true
+
+	case ast.KindAnyOf:
+		if len(tree.Children) == 0 {
+			return "", true
+		}
+		suffix, exact = literalSuffix(tree.Children[0])
+		for _, c := range tree.Children[1:] {
+			s, e := literalSuffix(c)
+			if s != suffix || !e {
+				exact = false
+			}
+			suffix = commonSuffix(suffix, s)
+		}
+		return suffix, exact
+
+	default:
+		// Other kinds are symmetric: they either are exact literals or
+		// have no literal parts at all.
+		return literalPrefix(tree)
+	}
+}
+
+// requiredLiteral returns a literal string every string matched by tree
+// contains. It is the longest text on the top level of tree, or the longest
+// common prefix or suffix of pattern alternatives there.
+func requiredLiteral(tree *ast.Node) string {
+	switch tree.Kind {
+	case ast.KindText:
+		return tree.Value.(ast.Text).Text
+
+	case ast.KindPattern:
+		var lit string
+		for _, c := range tree.Children {
+			if s := requiredLiteral(c); len(s) > len(lit) {
+				lit = s
+			}
+		}
+		return lit
+
+	case ast.KindAnyOf:
+		prefix, _ := literalPrefix(tree)
+		if suffix, _ := literalSuffix(tree); len(suffix) > len(prefix) {
+			return suffix
+		}
+		return prefix
+
+	default:
+		return ""
+	}
+}
+
+// commonPrefix returns the longest common prefix of a and b that does not
+// split a multibyte rune.
+func commonPrefix(a, b string) string {
+	var n int
+	for n < len(a) && n < len(b) {
+		r, w := utf8.DecodeRuneInString(a[n:])
+		if r2, w2 := utf8.DecodeRuneInString(b[n:]); r != r2 || w != w2 {
+			break
+		}
+		n += w
+	}
+	return a[:n]
+}
+
+// commonSuffix returns the longest common suffix of a and b that does not
+// split a multibyte rune.
+func commonSuffix(a, b string) string {
+	var n int
+	for n < len(a) && n < len(b) {
+		r, w := utf8.DecodeLastRuneInString(a[:len(a)-n])
+		if r2, w2 := utf8.DecodeLastRuneInString(b[:len(b)-n]); r != r2 || w != w2 {
+			break
+		}
+		n += w
+	}
+	return a[len(a)-n:]
+}
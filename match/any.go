@@ -18,22 +18,15 @@ func (self Any) Match(s string) bool {
 }
 
 func (self Any) Index(s string) (int, []int) {
-	found := strings.IndexAnyRunes(s, self.Separators)
-	switch found {
-	case -1:
-	case 0:
-		return 0, segments0
-	default:
-		s = s[:found]
-	}
+	return self.AppendIndex(nil, s)
+}
 
-	segments := acquireSegments(len(s))
-	for i := range s {
-		segments = append(segments, i)
+func (self Any) AppendIndex(buf []int, s string) (int, []int) {
+	if found := strings.IndexAnyRunes(s, self.Separators); found != -1 {
+		s = s[:found]
 	}
-	segments = append(segments, len(s))
 
-	return 0, segments
+	return 0, appendBoundaries(growSegments(buf, len(s)+1), s, 0)
 }
 
 func (self Any) Len() int {
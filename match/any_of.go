@@ -25,50 +25,63 @@ func (self AnyOf) Match(s string) bool {
 	return false
 }
 
+func (self AnyOf) MatchLimit(s string, l *Limit) bool {
+	for _, m := range self.Matchers {
+		if MatchLimit(m, s, l) {
+			return true
+		}
+	}
+	return false
+}
+
+// Index returns the earliest index at which any of the matchers matches, and
+// the merged lengths of the substrings all the matchers matching there
+// match, so that AnyOf could be the value of BTree.
 func (self AnyOf) Index(s string) (int, []int) {
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
+
+func (self AnyOf) AppendIndex(buf []int, s string) (int, []int) {
 	index := -1
 
-	segments := acquireSegments(len(s))
+	buf = growSegments(buf, len(s)+1)
+	start := len(buf)
 	for _, m := range self.Matchers {
-		idx, seg := m.Index(s)
-		if idx == -1 {
-			continue
-		}
+		// The segments found so far are buf[start:mid], and the ones of
+		// the matcher are appended past them.
+		mid := len(buf)
+		idx, found := AppendIndex(m, buf, s)
+		switch {
+		case idx == -1 || index != -1 && idx > index:
+			buf = found[:mid]
 
-		if index == -1 || idx < index {
+		case index == -1 || idx < index:
 			index = idx
-			segments = append(segments[:0], seg...)
-			continue
-		}
+			buf = append(found[:start], found[mid:]...)
 
-		if idx > index {
-			continue
+		default:
+			buf = mergeSegments(found, start, mid)
 		}
-
-		// here idx == index
-		segments = appendMerge(segments, seg)
 	}
 
 	if index == -1 {
-		releaseSegments(segments)
-		return -1, nil
+		return -1, buf
 	}
 
-	return index, segments
+	return index, buf
 }
 
 func (self AnyOf) Len() (l int) {
 	l = -1
-	for _, m := range self.Matchers {
+	for i, m := range self.Matchers {
 		ml := m.Len()
 		switch {
-		case l == -1:
-			l = ml
-			continue
-
 		case ml == -1:
 			return -1
 
+		case i == 0:
+			l = ml
+
 		case l != ml:
 			return -1
 		}
@@ -77,6 +90,23 @@ func (self AnyOf) Len() (l int) {
 	return
 }
 
+func (self AnyOf) Bounds() (min, max int) {
+	for i, m := range self.Matchers {
+		mmin, mmax := bounds(m)
+		if i == 0 || mmin < min {
+			min = mmin
+		}
+		if i == 0 || max != -1 && (mmax == -1 || mmax > max) {
+			max = mmax
+		}
+	}
+	return min, max
+}
+
+func (self AnyOf) Children() []Matcher {
+	return self.Matchers
+}
+
 func (self AnyOf) String() string {
 	return fmt.Sprintf("<any_of:[%s]>", self.Matchers)
 }
@@ -2,6 +2,7 @@ package match
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -40,6 +41,34 @@ func TestAnyOfIndex(t *testing.T) {
 			0,
 			[]int{1},
 		},
+		{
+			Matchers{
+				NewText("cd"),
+				NewText("bcd"),
+				NewText("bc"),
+			},
+			"abcdef",
+			1,
+			[]int{2, 3},
+		},
+		{
+			Matchers{
+				NewText("x"),
+				NewText("y"),
+			},
+			"abcdef",
+			-1,
+			nil,
+		},
+		{
+			Matchers{
+				NewSuper(),
+				NewText("a"),
+			},
+			"",
+			0,
+			[]int{0},
+		},
 	} {
 		everyOf := NewAnyOf(test.matchers...)
 		index, segments := everyOf.Index(test.fixture)
@@ -51,3 +80,74 @@ func TestAnyOfIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestAnyOfIndexPooled(t *testing.T) {
+	// Segments of the tie are merged into ones acquired from the pool, which
+	// must not be released while they are returned.
+	fixture := strings.Repeat("a", 2*cacheFrom)
+	index, segments := NewAnyOf(NewText(fixture[:cacheFrom]), NewPrefix("a")).Index(fixture)
+	var exp []int
+	for i := 1; i <= len(fixture); i++ {
+		exp = append(exp, i)
+	}
+	for c := len(fixture); c <= 2*len(fixture); c *= 2 {
+		s := acquireSegments(c)
+		for j := 0; j < cap(s); j++ {
+			s = append(s, -1)
+		}
+		releaseSegments(s)
+	}
+	if index != 0 || !reflect.DeepEqual(segments, exp) {
+		t.Errorf("Index() = %d, %v; want 0, %v", index, segments, exp)
+	}
+}
+
+func TestAnyOfLen(t *testing.T) {
+	for id, test := range []struct {
+		matchers Matchers
+		length   int
+	}{
+		{
+			Matchers{
+				NewText("ab"),
+				NewSingle(nil),
+			},
+			-1,
+		},
+		{
+			Matchers{
+				NewText("ab"),
+				NewText("cd"),
+			},
+			2,
+		},
+		{
+			Matchers{
+				NewPrefix("a"),
+				NewNothing(),
+			},
+			-1,
+		},
+	} {
+		if act := NewAnyOf(test.matchers...).Len(); act != test.length {
+			t.Errorf("#%d unexpected length: exp: %d, act: %d", id, test.length, act)
+		}
+	}
+}
+
+func TestAnyOfBTreeValue(t *testing.T) {
+	// *{ab,cde}*
+	tree := NewBTree(NewAnyOf(NewPrefix("ab"), NewText("cde")), NewSuper(), NewSuper())
+	for _, test := range []struct {
+		fixture string
+		exp     bool
+	}{
+		{"xxcdexx", true},
+		{"xxabx", true},
+		{"xxcdxx", false},
+	} {
+		if act := tree.Match(test.fixture); act != test.exp {
+			t.Errorf("Match(%q) = %t; want %t", test.fixture, act, test.exp)
+		}
+	}
+}
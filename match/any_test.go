@@ -24,6 +24,12 @@ func TestAnyIndex(t *testing.T) {
 			0,
 			[]int{0, 1, 2, 3},
 		},
+		{
+			[]rune{'.', '/'},
+			"a/b.c",
+			0,
+			[]int{0, 1},
+		},
 	} {
 		p := NewAny(test.sep)
 		index, segments := p.Index(test.fixture)
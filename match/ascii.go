@@ -0,0 +1,81 @@
+package match
+
+import "unicode/utf8"
+
+// Where all runes a matcher looks for are ASCII, it scans strings bytewise
+// and does not decode other runes, as they could not be the ones: bytes of
+// multibyte runes are never ASCII. Otherwise it ranges over the runes.
+
+// runeCount returns the number of runes in s, but stops counting once it
+// exceeds limit. Since runes are never more than bytes, strings not longer
+// than limit are counted at once.
+func runeCount(s string, limit int) int {
+	if len(s) <= limit {
+		return utf8.RuneCountInString(s)
+	}
+	var n int
+	for range s {
+		if n++; n > limit {
+			break
+		}
+	}
+	return n
+}
+
+// isASCII reports whether all of the runes are ASCII.
+func isASCII(rs []rune) bool {
+	for _, r := range rs {
+		if r < 0 || r >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// asciiSet is the set of ASCII bytes, which is looked up by a shift and a
+// mask, instead of comparing runes to ranges or searching lists of them.
+type asciiSet [2]uint64
+
+func newASCIISet(rs []rune) (set asciiSet) {
+	for _, r := range rs {
+		if 0 <= r && r < utf8.RuneSelf {
+			set[r>>6] |= 1 << uint(r&63)
+		}
+	}
+	return set
+}
+
+// newASCIIRange returns the set of ASCII runes between lo and hi.
+func newASCIIRange(lo, hi rune) (set asciiSet) {
+	if lo < 0 {
+		lo = 0
+	}
+	for r := lo; r <= hi && r < utf8.RuneSelf; r++ {
+		set[r>>6] |= 1 << uint(r&63)
+	}
+	return set
+}
+
+func (set *asciiSet) contains(c byte) bool {
+	return set[c>>6]&(1<<uint(c&63)) != 0
+}
+
+// indexASCII returns the offset of the first rune of s which is in the
+// set, or is not in it if not is true, and the segments of the rune. Runes
+// which are not ASCII are never in the set.
+func indexASCII(s string, set *asciiSet, not bool) (int, []int) {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			if !not {
+				continue
+			}
+			_, w := utf8.DecodeRuneInString(s[i:])
+			return i, segmentsByRuneLength[w]
+		}
+		if set.contains(c) != not {
+			return i, segments1
+		}
+	}
+	return -1, nil
+}
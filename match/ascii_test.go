@@ -0,0 +1,65 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+var asciiFixtures = []string{
+	"", "abc", "a.b", "ä", "aäb.c", "\xffa\x80", "日本.語", "..", "a\xe6\x97b",
+}
+
+func TestRuneCount(t *testing.T) {
+	for _, s := range asciiFixtures {
+		n := utf8.RuneCountInString(s)
+		for limit := 0; limit <= len(s)+1; limit++ {
+			act := runeCount(s, limit)
+			if n <= limit && act != n || n > limit && act <= limit {
+				t.Errorf("runeCount(%q, %d) = %d; have %d runes", s, limit, act, n)
+			}
+		}
+	}
+}
+
+func TestIndexASCII(t *testing.T) {
+	for _, rs := range [][]rune{{'.'}, {'a', '.'}, {}} {
+		for _, not := range []bool{false, true} {
+			for _, s := range asciiFixtures {
+				exp, expSegments := -1, []int(nil)
+				for i, r := range s {
+					if (runes.IndexRune(rs, r) == -1) == not {
+						exp, expSegments = i, []int{utf8.RuneLen(r)}
+						if r == utf8.RuneError {
+							expSegments = []int{1}
+						}
+						break
+					}
+				}
+				set := newASCIISet(rs)
+				act, segments := indexASCII(s, &set, not)
+				if act != exp || !reflect.DeepEqual(segments, expSegments) {
+					t.Errorf("indexASCII(%q, %q, %t) = %d, %v; want %d, %v", s, string(rs), not, act, segments, exp, expSegments)
+				}
+			}
+		}
+	}
+}
+
+var benchUnicode = "абвгдеёжзийклмнопрстуфхцчшщъыьэюя.def"
+
+func BenchmarkIndexListUnicode(b *testing.B) {
+	m := NewList([]rune("def"), false)
+	for i := 0; i < b.N; i++ {
+		m.Index(benchUnicode)
+	}
+}
+
+func BenchmarkMatchMaxUnicode(b *testing.B) {
+	m := NewMax(len(benchUnicode))
+	for i := 0; i < b.N; i++ {
+		m.Match(benchUnicode)
+	}
+}
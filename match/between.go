@@ -0,0 +1,58 @@
+package match
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Between matches strings of at least Min and at most Max runes, which runs
+// of wildcards like `??` or `{?,??,???}` are compiled to. Both bounds are
+// checked in a single pass over the string, and are exposed by Len and
+// Bounds for trees to skip splits of wrong lengths.
+type Between struct {
+	Min, Max int
+}
+
+func NewBetween(min, max int) Between {
+	return Between{Min: min, Max: max}
+}
+
+func (self Between) Match(s string) bool {
+	if len(s) < self.Min || len(s) > self.Max*utf8.UTFMax {
+		return false
+	}
+	n := runeCount(s, self.Max)
+	return n >= self.Min && n <= self.Max
+}
+
+func (self Between) Index(s string) (int, []int) {
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
+
+func (self Between) AppendIndex(buf []int, s string) (int, []int) {
+	start := runesPrefix(s, self.Min)
+	if start == -1 {
+		return -1, buf
+	}
+	end := start + runesPrefix(s[start:], self.Max-self.Min)
+	if end < start {
+		end = len(s)
+	}
+	return 0, appendBoundaries(growSegments(buf, self.Max-self.Min+1), s[start:end], start)
+}
+
+// Len returns the number of runes if the bounds are the same.
+func (self Between) Len() int {
+	if self.Min == self.Max {
+		return self.Min
+	}
+	return lenNo
+}
+
+func (self Between) Bounds() (min, max int) {
+	return self.Min, self.Max * utf8.UTFMax
+}
+
+func (self Between) String() string {
+	return fmt.Sprintf("<between:%d,%d>", self.Min, self.Max)
+}
@@ -0,0 +1,67 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBetweenMatch(t *testing.T) {
+	for id, test := range []struct {
+		min, max int
+		fixture  string
+		exp      bool
+	}{
+		{2, 3, "a", false},
+		{2, 3, "ab", true},
+		{2, 3, "abc", true},
+		{2, 3, "abcd", false},
+		{2, 3, "жжж", true},
+		{1, 1, "ж", true},
+		{0, 1, "", true},
+	} {
+		m := NewBetween(test.min, test.max)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected match of %q: exp: %v, act: %v", id, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestBetweenIndex(t *testing.T) {
+	for id, test := range []struct {
+		min, max int
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{
+			1,
+			3,
+			"abcdef",
+			0,
+			[]int{1, 2, 3},
+		},
+		{
+			2,
+			4,
+			"жbc",
+			0,
+			[]int{3, 4},
+		},
+		{
+			2,
+			2,
+			"a",
+			-1,
+			nil,
+		},
+	} {
+		m := NewBetween(test.min, test.max)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package match
+
+import "unicode/utf8"
+
+// Bounded is implemented by matchers that know the bounds of the length in
+// bytes of the strings they match, which is tighter than the one following
+// from Len. BTree uses the bounds of its branches to skip positions of the
+// value at which the branches could not match.
+type Bounded interface {
+	Matcher
+
+	// Bounds returns the minimum and the maximum length in bytes of the
+	// strings the matcher matches. The maximum is -1 if it is unbounded.
+	Bounds() (min, max int)
+}
+
+// bounds returns the bounds of the length in bytes of the strings m matches,
+// or of the empty string, if m is nil.
+func bounds(m Matcher) (min, max int) {
+	switch v := m.(type) {
+	case nil:
+		return 0, 0
+	case Bounded:
+		return v.Bounds()
+	}
+	if n := m.Len(); n != -1 {
+		// Invalid bytes are single runes as well.
+		return n, n * utf8.UTFMax
+	}
+	return 0, -1
+}
+
+// addBounds returns the bounds of the concatenation of strings of the given
+// bounds.
+func addBounds(min, max, nmin, nmax int) (int, int) {
+	if max == -1 || nmax == -1 {
+		return min + nmin, -1
+	}
+	return min + nmin, max + nmax
+}
@@ -0,0 +1,31 @@
+package match
+
+import "testing"
+
+func TestBounds(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Matcher
+		min, max int
+	}{
+		{nil, 0, 0},
+		{NewText("abc"), 3, 3},
+		{NewText("ä"), 2, 2},
+		{NewSingle(nil), 1, 4},
+		{NewSuper(), 0, -1},
+		{NewPrefixSuffix("ab", "c"), 3, -1},
+		{NewContains("ab", true), 0, -1},
+		{NewMax(2), 0, 8},
+		{NewSet("a", "abc"), 1, 3},
+		{NewRow(3, NewText("ab"), NewSingle(nil)), 3, 6},
+		{NewAnyOf(NewText("ab"), NewMax(1)), 0, 4},
+		{NewAnyOf(NewText("ab"), NewSuper()), 0, -1},
+		{NewEveryOf(NewMin(2), NewMax(3)), 2, 12},
+		{NewBTree(NewText("a"), NewText("bc"), NewSingle(nil)), 4, 7},
+		{NewBTree(NewText("a"), NewSuper(), nil), 1, -1},
+	} {
+		min, max := bounds(test.matcher)
+		if min != test.min || max != test.max {
+			t.Errorf("#%d unexpected bounds of %s: exp: [%d, %d], act: [%d, %d]", id, test.matcher, test.min, test.max, min, max)
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package match
 
 import (
+	"bytes"
+	"encoding/gob"
 	"fmt"
+	"sync"
 	"unicode/utf8"
 )
 
@@ -13,6 +16,36 @@ type BTree struct {
 	LeftLengthRunes  int
 	RightLengthRunes int
 	LengthRunes      int
+
+	// lengths limit the positions of the value tried by Match. They are
+	// set by NewBTree, and measured on demand for trees made otherwise.
+	lengths btreeLengths
+}
+
+// btreeLengths are the bounds of the lengths in bytes of the strings matched
+// by the branches of a tree, as returned by Bounds. Maximums are -1 if
+// unbounded. The zero value is not known.
+type btreeLengths struct {
+	valueMin, valueMax int
+	leftMin, leftMax   int
+	rightMin, rightMax int
+	known              bool
+}
+
+// measureBTree returns the lengths of the given branches.
+func measureBTree(value, left, right Matcher) (l btreeLengths) {
+	l.valueMin, l.valueMax = bounds(value)
+	l.leftMin, l.leftMax = bounds(left)
+	l.rightMin, l.rightMax = bounds(right)
+	l.known = true
+	return l
+}
+
+// measure sets the lengths of the tree, unless they are known.
+func (self *BTree) measure() {
+	if !self.lengths.known {
+		self.lengths = measureBTree(self.Value, self.Left, self.Right)
+	}
 }
 
 func NewBTree(Value, Left, Right Matcher) (tree BTree) {
@@ -43,6 +76,8 @@ func NewBTree(Value, Left, Right Matcher) (tree BTree) {
 		tree.LengthRunes = -1
 	}
 
+	tree.lengths = measureBTree(Value, Left, Right)
+
 	return tree
 }
 
@@ -50,123 +85,619 @@ func (self BTree) Len() int {
 	return self.LengthRunes
 }
 
-// todo?
-func (self BTree) Index(s string) (index int, segments []int) {
-	//inputLen := len(s)
-	//// try to cut unnecessary parts
-	//// by knowledge of length of right and left part
-	//offset, limit := self.offsetLimit(inputLen)
-	//for offset < limit {
-	//	// search for matching part in substring
-	//	vi, segments := self.Value.Index(s[offset:limit])
-	//	if index == -1 {
-	//		return -1, nil
-	//	}
-	//	if self.Left == nil {
-	//		if index != offset {
-	//			return -1, nil
-	//		}
-	//	} else {
-	//		left := s[:offset+vi]
-	//		i := self.Left.IndexSuffix(left)
-	//		if i == -1 {
-	//			return -1, nil
-	//		}
-	//		index = i
-	//	}
-	//	if self.Right != nil {
-	//		for _, seg := range segments {
-	//			right := s[:offset+vi+seg]
-	//		}
-	//	}
-
-	//	l := s[:offset+index]
-	//	var left bool
-	//	if self.Left != nil {
-	//		left = self.Left.Index(l)
-	//	} else {
-	//		left = l == ""
-	//	}
-	//}
-
-	return -1, nil
+func (self BTree) Bounds() (min, max int) {
+	self.measure()
+	l := &self.lengths
+	min, max = addBounds(l.leftMin, l.leftMax, l.valueMin, l.valueMax)
+	return addBounds(min, max, l.rightMin, l.rightMax)
+}
+
+// Index returns the first index of s at which the tree matches, and lengths
+// of all matching substrings starting there. As the value could be found at
+// different positions within a substring, every substring is checked by
+// Match.
+func (self BTree) Index(s string) (int, []int) {
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
+
+func (self BTree) AppendIndex(buf []int, s string) (int, []int) {
+	buf = growSegments(buf, len(s)+1)
+	start := len(buf)
+	for i := 0; i <= len(s); {
+		for j := i; j <= len(s); {
+			if self.Match(s[i:j]) {
+				buf = append(buf, j-i)
+			}
+			if j == len(s) {
+				break
+			}
+			_, w := utf8.DecodeRuneInString(s[j:])
+			j += w
+		}
+		if len(buf) > start {
+			return i, buf
+		}
+		if i == len(s) {
+			break
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+
+	return -1, buf
 }
 
 func (self BTree) Match(s string) bool {
-	inputLen := len(s)
-	// try to cut unnecessary parts
-	// by knowledge of length of right and left part
-	offset, limit := self.offsetLimit(inputLen)
+	self.measure()
+	if self.leaf() {
+		if sharedSegments(self.Value) {
+			matched, _ := self.matchLeaf(s, nil)
+			return matched
+		}
+		st := btreeStackPool.Get().(*btreeStack)
+		matched, buf := self.matchLeaf(s, st.buffer())
+		st.recycle(buf)
+		btreeStackPool.Put(st)
+		return matched
+	}
+	return self.match(s, nil)
+}
 
-	for offset < limit {
-		// search for matching part in substring
-		index, segments := self.Value.Index(s[offset:limit])
-		if index == -1 {
-			releaseSegments(segments)
+// MatchLimit is like Match, but it takes a step of the limit for every
+// search of the value of a tree and every match of a subtree.
+func (self BTree) MatchLimit(s string, l *Limit) bool {
+	if !l.Step() {
+		return false
+	}
+	self.measure()
+	return self.match(s, l) && !l.Aborted()
+}
+
+// match matches the string against the tree within the limit, if it is not
+// nil.
+func (self *BTree) match(s string, l *Limit) bool {
+	var f btreeFrame
+	if !f.init(self, s, 0, len(s), 0, false) {
+		return false
+	}
+	m := btreeMatch{limit: l}
+	matched := m.run(&f, self, s)
+	if m.stack != nil {
+		m.stack.frames = m.stack.frames[:0]
+		m.stack.trees = m.stack.trees[:0]
+		btreeStackPool.Put(m.stack)
+	}
+	return matched
+}
+
+// btreeMatch matches a string against a tree, which is matched against
+// parts of the string by its subtrees in turn. Instead of recursing into
+// subtrees, which nest as deep as the pattern has wildcards, it keeps the
+// state of matching of every subtree in progress on an explicit stack.
+// Subtrees which have no subtrees of their own are matched at once.
+type btreeMatch struct {
+	stack *btreeStack // frames of subtrees above the root, if any
+
+	// calls is the number of subtrees matched so far, and results caches
+	// results of subtrees after memoAfter calls. Without the cache,
+	// patterns like `*b*a*a*a*a` match strings of a's in time exponential
+	// in the number of wildcards, as every subtree is matched against the
+	// same parts over and over again.
+	calls   int
+	results map[btreeMemoKey]bool
+
+	// limit, if not nil, is taken a step of for every search of a value
+	// and every match of a subtree, and counts backtracks as well. All the
+	// frames are given up once it tells to.
+	limit *Limit
+}
+
+// btreeStack is the stack of frames of subtrees, along with copies of the
+// subtrees, as they could not be referred to in the branches of trees.
+type btreeStack struct {
+	frames []btreeFrame
+	trees  []BTree
+
+	// buffers are segments no frame uses, to be reused in searches of
+	// values. Slices put to a pool are boxed, which allocates, so they
+	// are pooled along with the stack instead.
+	buffers [][]int
+
+	// results is the cache of results of subtrees, which is kept to be
+	// reused as well.
+	results map[btreeMemoKey]bool
+}
+
+// btreeStackPool keeps stacks of frames and buffers of segments, so that
+// matching against trees does not allocate.
+var btreeStackPool = sync.Pool{New: func() interface{} {
+	return new(btreeStack)
+}}
+
+// buffer returns an empty buffer of segments, or nil if there is none, so
+// that the segments are acquired by AppendIndex.
+func (st *btreeStack) buffer() []int {
+	n := len(st.buffers) - 1
+	if n < 0 {
+		return nil
+	}
+	buf := st.buffers[n]
+	st.buffers = st.buffers[:n]
+	return buf[:0]
+}
+
+// recycle keeps the buffer of segments for later searches.
+func (st *btreeStack) recycle(buf []int) {
+	if cap(buf) > 0 {
+		st.buffers = append(st.buffers, buf[:0])
+	}
+}
+
+// memo returns the cache of results of the stack, emptied of results of
+// earlier matches.
+func (m *btreeMatch) memo() map[btreeMemoKey]bool {
+	if m.stack == nil {
+		m.stack = btreeStackPool.Get().(*btreeStack)
+	}
+	st := m.stack
+	if st.results == nil {
+		st.results = make(map[btreeMemoKey]bool)
+	}
+	for k := range st.results {
+		delete(st.results, k)
+	}
+	return st.results
+}
+
+// buffer returns an empty buffer of segments from the stack, which is
+// acquired on demand.
+func (m *btreeMatch) buffer() []int {
+	if m.stack == nil {
+		m.stack = btreeStackPool.Get().(*btreeStack)
+	}
+	return m.stack.buffer()
+}
+
+// btreeMemoKey identifies the subtree by its index in the tree, numbered as
+// in binary heap, and the part of the string by its bounds.
+type btreeMemoKey struct {
+	node   uint64
+	lo, hi int
+}
+
+const (
+	// memoAfter is the number of calls of subtrees after which their
+	// results are cached. Most strings are matched well before that, and
+	// do not pay for the cache.
+	memoAfter = 64
+
+	// memoNodes limits the indexes of cached subtrees, so that indexes
+	// of their children do not overflow. Deeper subtrees all get this
+	// index, and are not cached.
+	memoNodes = 1 << 62
+)
+
+// btreeFrame is the state of matching of s[lo:hi] against the subtree of
+// the given index.
+type btreeFrame struct {
+	lo, hi int
+	node   uint64
+	cache  bool // whether the result goes to the cache
+
+	state         btreeState
+	offset, limit int   // part of s[lo:hi] where the value is searched
+	index         int   // position of the value found, relative to offset
+	segments      []int // lengths of the value found
+	next          int   // index of the segment to try next, descending
+}
+
+type btreeState int
+
+const (
+	btreeSearch  btreeState = iota // the value is to be searched
+	btreeLeft                      // the left branch is matched
+	btreeSegment                   // the next segment is to be tried
+	btreeRight                     // the right branch is matched
+	btreeAdvance                   // the value is to be searched further
+)
+
+// init sets up the frame to match s[lo:hi] against its tree. It returns
+// false if the part is too short or too long for the tree to match it.
+func (f *btreeFrame) init(tree *BTree, s string, lo, hi int, node uint64, cache bool) bool {
+	f.offset, f.limit = tree.offsetLimit(s[lo:hi])
+	if f.offset > f.limit {
+		return false
+	}
+	f.lo, f.hi = lo, hi
+	f.node = node
+	f.cache = cache
+	return true
+}
+
+// run matches the string against the root tree, given the frame of it.
+func (m *btreeMatch) run(rf *btreeFrame, root *BTree, s string) bool {
+	var result bool
+	for {
+		f, t := rf, root
+		if m.stack != nil && len(m.stack.frames) > 0 {
+			n := len(m.stack.frames) - 1
+			f, t = &m.stack.frames[n], &m.stack.trees[n]
+		}
+		done, matched := m.resume(f, t, s, result)
+		if m.limit != nil && m.limit.Aborted() {
 			return false
 		}
+		if !done {
+			// the frame of a subtree was pushed
+			continue
+		}
+		if f == rf {
+			return matched
+		}
+		if f.cache {
+			m.results[btreeMemoKey{f.node, f.lo, f.hi}] = matched
+		}
+		m.stack.pop()
+		result = matched
+	}
+}
+
+// push pushes the frame to match s[lo:hi] against the tree, which is the
+// subtree of the given index. It returns false instead if the subtree could
+// not match it.
+func (m *btreeMatch) push(t *BTree, s string, lo, hi int, node uint64, cache bool) bool {
+	if m.stack == nil {
+		m.stack = btreeStackPool.Get().(*btreeStack)
+	}
+	st := m.stack
+	st.trees = append(st.trees, *t)
+	st.frames = append(st.frames, btreeFrame{})
+	n := len(st.frames) - 1
+	if !st.frames[n].init(&st.trees[n], s, lo, hi, node, cache) {
+		st.pop()
+		return false
+	}
+	return true
+}
+
+// pop pops the frame matched last. Popped frames are not cleared, as they
+// only refer to the trees, which outlive the stack anyway.
+func (st *btreeStack) pop() {
+	n := len(st.frames) - 1
+	st.frames = st.frames[:n]
+	st.trees = st.trees[:n]
+}
+
+// resume continues matching of the frame of the tree, given the result of
+// its branch that was matched last. It returns whether the frame is done,
+// and its result. If it is not done, the frame of a subtree was pushed.
+//
+// The state of the frame is kept in locals while the frame is matched, and
+// saved to the frame before a branch is matched, as it could push a frame
+// and move the stack.
+func (m *btreeMatch) resume(f *btreeFrame, t *BTree, s string, result bool) (done, matched bool) {
+	lo, hi, node := f.lo, f.hi, f.node
+	inputLen := hi - lo
+	state := f.state
+	offset, limit, index := f.offset, f.limit, f.index
+	segments, next := f.segments, f.next
+	for {
+		switch state {
+		case btreeSearch:
+			if offset > limit || m.limit != nil && !m.limit.index() {
+				m.stack.recycle(segments)
+				return true, false
+			}
+			// search for matching part in substring, reusing the
+			// segments of the previous search
+			if segments == nil {
+				segments = m.buffer()
+			}
+			index, segments = AppendIndex(t.Value, segments[:0], s[lo+offset:lo+limit])
+			if index == -1 {
+				m.stack.recycle(segments)
+				return true, false
+			}
+			if t.lengths.leftMax != -1 && offset+index > t.lengths.leftMax {
+				// the left part would be too long for the left branch,
+				// and it only grows further on
+				m.stack.recycle(segments)
+				return true, false
+			}
+			state = btreeLeft
+			if t.Left == nil {
+				result = offset+index == 0
+				break
+			}
+			f.state, f.offset, f.index, f.segments = state, offset, index, segments
+			if !m.branch(t.Left, s, lo, lo+offset+index, childNode(node, 1), &result) {
+				return false, false
+			}
+
+		case btreeLeft:
+			if result {
+				next = len(segments) - 1
+				state = btreeSegment
+			} else {
+				m.backtrack()
+				state = btreeAdvance
+			}
+
+		case btreeSegment:
+			if next < 0 {
+				state = btreeAdvance
+				break
+			}
+			length := segments[next]
+			next--
+
+			// if there is no string for the right branch
+			r := offset + index + length
+			if inputLen < r {
+				r = inputLen
+			}
+			if n := inputLen - r; n < t.lengths.rightMin || t.lengths.rightMax != -1 && n > t.lengths.rightMax {
+				break
+			}
+			state = btreeRight
+			if t.Right == nil {
+				result = r == inputLen
+				break
+			}
+			f.state, f.offset, f.index, f.segments, f.next = state, offset, index, segments, next
+			if !m.branch(t.Right, s, lo+r, hi, childNode(node, 2), &result) {
+				return false, false
+			}
+
+		case btreeRight:
+			if result {
+				m.stack.recycle(segments)
+				return true, true
+			}
+			m.backtrack()
+			state = btreeSegment
+
+		case btreeAdvance:
+			_, step := utf8.DecodeRuneInString(s[lo+offset+index : hi])
+			if step == 0 {
+				// value is already searched at the end of the string
+				m.stack.recycle(segments)
+				return true, false
+			}
+			offset += index + step
+			state = btreeSearch
+		}
+	}
+}
+
+// backtrack counts going on to another part of the string, if there is the
+// limit.
+func (m *btreeMatch) backtrack() {
+	if m.limit != nil {
+		m.limit.stats.Backtracks++
+	}
+}
+
+// branch matches s[lo:hi] against the branch of the tree, which is the
+// subtree of the given index if it is a tree. It returns false if the frame
+// of the subtree was pushed instead, to be matched later; otherwise it sets
+// the result, possibly taken from the cache or from the bounds of lengths
+// of the subtree.
+func (m *btreeMatch) branch(b Matcher, s string, lo, hi int, node uint64, result *bool) bool {
+	t, ok := b.(BTree)
+	if !ok {
+		if m.limit != nil {
+			*result = MatchLimit(b, s[lo:hi], m.limit)
+		} else {
+			*result = b.Match(s[lo:hi])
+		}
+		return true
+	}
+	if m.limit != nil && !m.limit.Step() {
+		*result = false
+		return true
+	}
+	t.measure()
+	cache := false
+	if m.calls++; m.calls > memoAfter && node != memoNodes {
+		if m.results == nil {
+			m.results = m.memo()
+		} else if r, ok := m.results[btreeMemoKey{node, lo, hi}]; ok {
+			*result = r
+			return true
+		}
+		cache = true
+	}
+	if t.leaf() && m.limit == nil {
+		var buf []int
+		if !sharedSegments(t.Value) {
+			buf = m.buffer()
+		}
+		*result, buf = t.matchLeaf(s[lo:hi], buf)
+		m.stack.recycle(buf)
+		if cache {
+			m.results[btreeMemoKey{node, lo, hi}] = *result
+		}
+		return true
+	}
+	if !m.push(&t, s, lo, hi, node, cache) {
+		*result = false
+		return true
+	}
+	return false
+}
+
+// leaf reports whether none of the branches of the tree is a tree.
+func (self *BTree) leaf() bool {
+	if _, ok := self.Left.(BTree); ok {
+		return false
+	}
+	_, ok := self.Right.(BTree)
+	return !ok
+}
+
+// matchLeaf reports whether s matches the tree, which is a leaf, so that
+// its branches are matched at once. Unless the value shares its segments,
+// it is searched with the given buffer of segments, which is returned to be
+// reused.
+func (self *BTree) matchLeaf(s string, buf []int) (bool, []int) {
+	shared := sharedSegments(self.Value)
+	inputLen := len(s)
+	offset, limit := self.offsetLimit(s)
+	for offset <= limit {
+		// search for matching part in substring, reusing the segments of
+		// the previous search
+		var index int
+		var segments []int
+		if shared {
+			index, segments = self.Value.Index(s[offset:limit])
+		} else {
+			index, buf = AppendIndex(self.Value, buf[:0], s[offset:limit])
+			segments = buf
+		}
+		if index == -1 {
+			return false, buf
+		}
+		if self.lengths.leftMax != -1 && offset+index > self.lengths.leftMax {
+			return false, buf
+		}
 
-		l := s[:offset+index]
 		var left bool
 		if self.Left != nil {
-			left = self.Left.Match(l)
+			left = self.Left.Match(s[:offset+index])
 		} else {
-			left = l == ""
+			left = offset+index == 0
 		}
 
 		if left {
 			for i := len(segments) - 1; i >= 0; i-- {
-				length := segments[i]
-
-				var right bool
-				var r string
-				// if there is no string for the right branch
-				if inputLen <= offset+index+length {
-					r = ""
-				} else {
-					r = s[offset+index+length:]
+				r := offset + index + segments[i]
+				if n := inputLen - r; n < self.lengths.rightMin || self.lengths.rightMax != -1 && n > self.lengths.rightMax {
+					continue
 				}
-
+				var right bool
 				if self.Right != nil {
-					right = self.Right.Match(r)
+					right = self.Right.Match(s[r:])
 				} else {
-					right = r == ""
+					right = r == inputLen
 				}
-
 				if right {
-					releaseSegments(segments)
-					return true
+					return true, buf
 				}
 			}
 		}
 
 		_, step := utf8.DecodeRuneInString(s[offset+index:])
+		if step == 0 {
+			// value is already searched at the end of the string
+			break
+		}
 		offset += index + step
+	}
+	return false, buf
+}
 
-		releaseSegments(segments)
+// childNode returns the index of the left (1) or the right (2) child of the
+// subtree of the given index.
+func childNode(node, child uint64) uint64 {
+	if node >= memoNodes/2 {
+		return memoNodes
 	}
+	return 2*node + child
+}
 
-	return false
+// offsetLimit returns the bounds of the part of s where the value is
+// searched. The left part before the value and the right part after it have
+// to fit the branches. The bounds of lengths in bytes could fall inside of
+// runes, so they are moved to the ends of such runes, as the value could
+// not start or end anywhere else.
+func (self BTree) offsetLimit(s string) (offset int, limit int) {
+	offset, limit = self.byteOffsetLimit(len(s))
+	if offset > limit {
+		return offset, limit
+	}
+	return runeEnd(s, offset), runeEnd(s, limit)
 }
 
-func (self BTree) offsetLimit(inputLen int) (offset int, limit int) {
-	// self.Length, self.RLen and self.LLen are values meaning the length of runes for each part
-	// here we manipulating byte length for better optimizations
-	// but these checks still works, cause minLen of 1-rune string is 1 byte.
-	if self.LengthRunes != -1 && self.LengthRunes > inputLen {
-		return 0, 0
+// byteOffsetLimit returns the bounds of offsetLimit for a string of the
+// given length, which may fall inside of runes.
+func (self BTree) byteOffsetLimit(inputLen int) (offset int, limit int) {
+	min, max := self.Bounds()
+	if inputLen < min || max != -1 && inputLen > max {
+		return 0, -1
 	}
-	if self.LeftLengthRunes >= 0 {
-		offset = self.LeftLengthRunes
+	offset = self.lengths.leftMin
+	if self.lengths.rightMax != -1 && self.lengths.valueMax != -1 {
+		if n := inputLen - self.lengths.rightMax - self.lengths.valueMax; n > offset {
+			offset = n
+		}
+		if self.lengths.leftMax != -1 && offset > self.lengths.leftMax {
+			return 0, -1
+		}
 	}
-	if self.RightLengthRunes >= 0 {
-		limit = inputLen - self.RightLengthRunes
-	} else {
-		limit = inputLen
+	limit = inputLen - self.lengths.rightMin
+	if self.lengths.leftMax != -1 && self.lengths.valueMax != -1 {
+		if n := self.lengths.leftMax + self.lengths.valueMax; n < limit {
+			limit = n
+		}
 	}
 	return offset, limit
 }
 
+// runeEnd returns i if it is a boundary of runes of s, or the end of the
+// rune of s it falls inside of otherwise. Bytes which could not start a
+// rune are runes of their own, unless they continue the valid rune started
+// by one of the three bytes before them.
+func runeEnd(s string, i int) int {
+	for j := i - 1; j >= 0 && j > i-utf8.UTFMax; j-- {
+		if utf8.RuneStart(s[j]) {
+			if _, w := utf8.DecodeRuneInString(s[j:]); j+w > i {
+				return j + w
+			}
+			return i
+		}
+	}
+	return i
+}
+
+// Children returns the left, the value and the right matchers of the tree.
+// Absent branches are omitted.
+func (self BTree) Children() []Matcher {
+	c := make([]Matcher, 0, 3)
+	if self.Left != nil {
+		c = append(c, self.Left)
+	}
+	c = append(c, self.Value)
+	if self.Right != nil {
+		c = append(c, self.Right)
+	}
+	return c
+}
+
+// btreeBranches are the branches of a tree as it is encoded with
+// encoding/gob. Other fields of the tree are derived from them, and are set
+// by NewBTree when the tree is decoded.
+type btreeBranches struct {
+	Value, Left, Right Matcher
+}
+
+// GobEncode encodes the branches of the tree.
+func (self BTree) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(btreeBranches{self.Value, self.Left, self.Right})
+	return buf.Bytes(), err
+}
+
+// GobDecode sets the tree to the one NewBTree makes of the decoded branches.
+func (self *BTree) GobDecode(b []byte) error {
+	var br btreeBranches
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&br); err != nil {
+		return err
+	}
+	*self = NewBTree(br.Value, br.Left, br.Right)
+	return nil
+}
+
 func (self BTree) String() string {
 	const n string = "<nil>"
 	var l, r string
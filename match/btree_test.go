@@ -1,6 +1,8 @@
 package match
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -38,6 +40,30 @@ func TestBTree(t *testing.T) {
 			"abc",
 			true,
 		},
+		// Trees made without NewBTree measure their branches on demand.
+		{
+			BTree{Value: NewText("abc"), Left: NewSuper(), Right: NewSuper()},
+			"xabcx",
+			true,
+		},
+		{
+			BTree{Value: NewText("b"), Left: NewSingle(nil)},
+			"ab",
+			true,
+		},
+		{
+			BTree{Value: NewText("b"), Left: NewSingle(nil)},
+			"bbb",
+			false,
+		},
+		{
+			BTree{
+				Value: NewText("c"),
+				Left:  BTree{Value: NewSingle(nil), Left: NewSuper()},
+			},
+			"abc",
+			true,
+		},
 	} {
 		act := test.tree.Match(test.str)
 		if act != test.exp {
@@ -47,6 +73,183 @@ func TestBTree(t *testing.T) {
 	}
 }
 
+func TestBTreeMemo(t *testing.T) {
+	// `*b*a*a*a*a*a` as the compiler builds it, which is matched against
+	// strings of a's in exponential time without the memo.
+	var tree Matcher = NewContains("b", false)
+	for i := 0; i < 5; i++ {
+		var right Matcher = NewSuper()
+		if i == 4 {
+			right = nil
+		}
+		tree = NewBTree(NewText("a"), tree, right)
+	}
+	if tree.Match(strings.Repeat("a", 200)) {
+		t.Errorf("unexpected match")
+	}
+	if !tree.Match("b" + strings.Repeat("a", 200)) {
+		t.Errorf("unexpected mismatch")
+	}
+}
+
+func TestBTreeDeep(t *testing.T) {
+	// trees nested deeper than matching would comfortably recurse
+	const n = 100000
+	var tree Matcher = NewSuper()
+	for i := 0; i < n; i++ {
+		tree = NewBTree(NewText("a"), nil, tree)
+	}
+	if !tree.Match(strings.Repeat("a", n) + "b") {
+		t.Errorf("unexpected mismatch")
+	}
+	if tree.Match(strings.Repeat("a", n-1) + "b") {
+		t.Errorf("unexpected match")
+	}
+}
+
+func TestBTreeAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	for id, test := range []struct {
+		tree Matcher
+		str  string
+	}{
+		{
+			// a*b*c, with leaves for branches
+			NewBTree(NewText("b"), NewPrefix("a"), NewSuffix("c")),
+			"aaabbbccc",
+		},
+		{
+			// *a*b*, with the value acquiring segments
+			NewBTree(NewPrefixSuffix("a", "b"), NewSuper(), NewSuper()),
+			"xxaxxbxxbxx",
+		},
+		{
+			// *a*b*c*
+			NewBTree(NewText("a"), NewSuper(), NewBTree(NewText("b"), NewSuper(), NewBTree(NewText("c"), NewSuper(), NewSuper()))),
+			"xxaxxbxxcxx",
+		},
+		{
+			// *a?*b
+			NewBTree(NewText("a"), NewSuper(), NewBTree(NewText("b"), NewEveryOf(NewSuper(), NewMin(1)), nil)),
+			"xxaxxbxxbb",
+		},
+	} {
+		allocs := testing.AllocsPerRun(100, func() {
+			if !test.tree.Match(test.str) {
+				t.Errorf("#%d unexpected mismatch", id)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("#%d unexpected allocations: %v", id, allocs)
+		}
+	}
+}
+
+// countMatcher counts the calls of Match of the matcher.
+type countMatcher struct {
+	Matcher
+	calls *int
+}
+
+func (c countMatcher) Match(s string) bool {
+	*c.calls++
+	return c.Matcher.Match(s)
+}
+
+func (c countMatcher) Bounds() (min, max int) {
+	return bounds(c.Matcher)
+}
+
+func TestBTreePrune(t *testing.T) {
+	for id, test := range []struct {
+		value, left, right Matcher
+		str                string
+		exp                bool
+		calls              int
+	}{
+		{
+			// the right branch is only tried at the last x
+			NewText("x"), NewSuper(), NewText("yz"),
+			"xxxxxxyz",
+			true,
+			1,
+		},
+		{
+			NewText("x"), NewSuper(), NewText("yz"),
+			"xxxxxxzz",
+			false,
+			1,
+		},
+		{
+			// the value is not searched past the end of the left branch
+			NewText("x"), NewText("ab"), NewSuper(),
+			"abcxxxxx",
+			false,
+			0,
+		},
+		{
+			NewText("x"), NewMax(2), NewSet("y", "yy"),
+			"axxyy",
+			true,
+			1,
+		},
+		{
+			// too short for the branches to be tried at all
+			NewText("x"), NewMin(3), NewSet("yy", "yyy"),
+			"abcxy",
+			false,
+			0,
+		},
+	} {
+		var calls int
+		tree := NewBTree(test.value, test.left, countMatcher{test.right, &calls})
+		if act := tree.Match(test.str); act != test.exp {
+			t.Errorf("#%d match %q error: act: %t; exp: %t", id, test.str, act, test.exp)
+		}
+		if calls != test.calls {
+			t.Errorf("#%d unexpected calls of right branch: exp: %d, act: %d", id, test.calls, calls)
+		}
+	}
+}
+
+func TestBTreeIndex(t *testing.T) {
+	for id, test := range []struct {
+		tree     BTree
+		str      string
+		index    int
+		segments []int
+	}{
+		{
+			NewBTree(NewText("b"), NewSingle(nil), NewAny(nil)),
+			"xabcd",
+			1,
+			[]int{2, 3, 4},
+		},
+		{
+			NewBTree(NewText("b"), nil, NewSingle([]rune{'/'})),
+			"abcb/",
+			1,
+			[]int{2},
+		},
+		{
+			NewBTree(NewText("c"), NewSingle(nil), nil),
+			"abab",
+			-1,
+			nil,
+		},
+	} {
+		index, segments := test.tree.Index(test.str)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
+
 type fakeMatcher struct {
 	len  int
 	name string
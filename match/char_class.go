@@ -0,0 +1,88 @@
+package match
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// CharClass matches a single rune of the set, or not of it if Not is true.
+// Unlike List and Range, it could hold any set, like a class made of several
+// ranges and named classes, as `[a-f0-9[:space:]]`, or a class extended with
+// other cases of its runes. ASCII runes of the set are put to a table when
+// the class is made, so that they are looked up at once.
+type CharClass struct {
+	Set   runes.Set
+	Not   bool
+	ASCII asciiSet
+}
+
+func NewCharClass(set runes.Set, not bool) CharClass {
+	c := CharClass{Set: set, Not: not}
+	for _, r := range set {
+		for x := r.Lo; x <= r.Hi && x < utf8.RuneSelf; x++ {
+			c.ASCII[x>>6] |= 1 << uint(x&63)
+		}
+	}
+	return c
+}
+
+func (self CharClass) contains(r rune) bool {
+	if 0 <= r && r < utf8.RuneSelf {
+		return self.ASCII.contains(byte(r)) != self.Not
+	}
+	return self.Set.Contains(r) != self.Not
+}
+
+func (self CharClass) Match(s string) bool {
+	r, w := utf8.DecodeRuneInString(s)
+	if w == 0 || len(s) > w {
+		return false
+	}
+	return self.contains(r)
+}
+
+func (self CharClass) Len() int {
+	return lenOne
+}
+
+func (self CharClass) Index(s string) (int, []int) {
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if self.ASCII.contains(c) != self.Not {
+				return i, segments1
+			}
+			i++
+			continue
+		}
+		r, w := utf8.DecodeRuneInString(s[i:])
+		if self.Set.Contains(r) != self.Not {
+			return i, segmentsByRuneLength[w]
+		}
+		i += w
+	}
+	return -1, nil
+}
+
+func (self CharClass) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
+func (self CharClass) String() string {
+	var not string
+	if self.Not {
+		not = "!"
+	}
+	var buf bytes.Buffer
+	for _, r := range self.Set {
+		buf.WriteRune(r.Lo)
+		if r.Lo != r.Hi {
+			buf.WriteByte('-')
+			buf.WriteRune(r.Hi)
+		}
+	}
+	return fmt.Sprintf("<char_class:%s[%s]>", not, buf.String())
+}
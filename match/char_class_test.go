@@ -0,0 +1,83 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+func TestCharClassMatch(t *testing.T) {
+	set := runes.NewSet(runes.Range{Lo: 'a', Hi: 'c'}, runes.Range{Lo: '\u212a', Hi: '\u212a'})
+	for id, test := range []struct {
+		not     bool
+		fixture string
+		exp     bool
+	}{
+		{false, "b", true},
+		{false, "\u212a", true},
+		{false, "d", false},
+		{false, "bb", false},
+		{false, "", false},
+		{true, "d", true},
+		{true, "\u212a", false},
+		{true, "ж", true},
+	} {
+		m := NewCharClass(set, test.not)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected match of %q: exp: %v, act: %v", id, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestCharClassIndex(t *testing.T) {
+	set := runes.NewSet(runes.Range{Lo: 'x', Hi: 'z'}, runes.Range{Lo: '\u212a', Hi: '\u212a'})
+	for id, test := range []struct {
+		not      bool
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{
+			false,
+			"abyc",
+			2,
+			[]int{1},
+		},
+		{
+			false,
+			"ab\u212ac",
+			2,
+			[]int{3},
+		},
+		{
+			true,
+			"xyжa",
+			2,
+			[]int{2},
+		},
+		{
+			false,
+			"abc",
+			-1,
+			nil,
+		},
+	} {
+		m := NewCharClass(set, test.not)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
+
+func BenchmarkIndexCharClass(b *testing.B) {
+	m := NewCharClass(runes.NewSet(runes.Range{Lo: 'x', Hi: 'z'}), false)
+
+	for i := 0; i < b.N; i++ {
+		m.Index(bench_pattern)
+	}
+}
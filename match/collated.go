@@ -0,0 +1,111 @@
+package match
+
+import (
+	"bytes"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// Collator compares strings by the collation order of some locale, as
+// collate.Collator of golang.org/x/text/collate does.
+type Collator interface {
+	CompareString(a, b string) int
+}
+
+// Collated matches a single rune of the character class, the ranges of which
+// follow the collation order of the Collator instead of the order of code
+// points, so that `[a-z]` could match "é" for some locales. Ranges are the
+// pairs of the lowest and the highest runes of the ranges, and Set holds the
+// other runes of the class. Runes of Except are never matched, and if Fold is
+// set, the rune matches if any of its cases is in the class.
+type Collated struct {
+	Collator Collator
+	Ranges   []rune
+	Set      runes.Set
+	Except   []rune
+	Not      bool
+	Fold     bool
+}
+
+func NewCollated(c Collator, ranges []rune, set runes.Set, not bool) Collated {
+	return Collated{Collator: c, Ranges: ranges, Set: set, Not: not}
+}
+
+func (self Collated) Match(s string) bool {
+	r, w := utf8.DecodeRuneInString(s)
+	if w == 0 || len(s) > w {
+		return false
+	}
+	return self.contains(r)
+}
+
+func (self Collated) contains(r rune) bool {
+	if runes.IndexRune(self.Except, r) != -1 {
+		return false
+	}
+	in := self.in(r)
+	if self.Fold {
+		for f := unicode.SimpleFold(r); !in && f != r; f = unicode.SimpleFold(f) {
+			in = self.in(f)
+		}
+	}
+	return in != self.Not
+}
+
+func (self Collated) in(r rune) bool {
+	if self.Set.Contains(r) {
+		return true
+	}
+	s := string(r)
+	for i := 0; i+1 < len(self.Ranges); i += 2 {
+		if self.Collator.CompareString(string(self.Ranges[i]), s) <= 0 &&
+			self.Collator.CompareString(s, string(self.Ranges[i+1])) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (self Collated) Len() int {
+	return lenOne
+}
+
+func (self Collated) Index(s string) (int, []int) {
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
+		if self.contains(r) {
+			return i, singleSegment(w)
+		}
+		i += w
+	}
+	return -1, nil
+}
+
+func (self Collated) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
+func (self Collated) String() string {
+	var not string
+	if self.Not {
+		not = "!"
+	}
+	var buf bytes.Buffer
+	for i := 0; i+1 < len(self.Ranges); i += 2 {
+		buf.WriteRune(self.Ranges[i])
+		buf.WriteByte('-')
+		buf.WriteRune(self.Ranges[i+1])
+	}
+	for _, r := range self.Set {
+		buf.WriteRune(r.Lo)
+		if r.Lo != r.Hi {
+			buf.WriteByte('-')
+			buf.WriteRune(r.Hi)
+		}
+	}
+	return fmt.Sprintf("<collated:%s[%s]>", not, buf.String())
+}
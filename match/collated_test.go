@@ -0,0 +1,65 @@
+package match
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// lowerFirst orders strings by their lower cases, and the ones of the same
+// lower case by code points.
+type lowerFirst struct{}
+
+func (lowerFirst) CompareString(a, b string) int {
+	if c := strings.Compare(strings.ToLower(a), strings.ToLower(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+func TestCollatedMatch(t *testing.T) {
+	for id, test := range []struct {
+		matcher Collated
+		fixture string
+		exp     bool
+	}{
+		{NewCollated(lowerFirst{}, []rune("ac"), nil, false), "b", true},
+		{NewCollated(lowerFirst{}, []rune("ac"), nil, false), "B", true},
+		{NewCollated(lowerFirst{}, []rune("ac"), nil, false), "c", true},
+		{NewCollated(lowerFirst{}, []rune("ac"), nil, false), "d", false},
+		{NewCollated(lowerFirst{}, []rune("ac"), nil, false), "bb", false},
+		{NewCollated(lowerFirst{}, []rune("ac"), nil, true), "B", false},
+		{NewCollated(lowerFirst{}, []rune("ac"), runes.Of('x'), false), "x", true},
+		{Collated{Collator: lowerFirst{}, Ranges: []rune("ab"), Fold: true}, "K", false},
+		{Collated{Collator: lowerFirst{}, Ranges: []rune("ak"), Fold: true}, "K", true},
+		{Collated{Collator: lowerFirst{}, Ranges: []rune("ak"), Except: []rune("b")}, "b", false},
+	} {
+		if act := test.matcher.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result of %s on %q: exp: %t, act: %t", id, test.matcher, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestCollatedIndex(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Collated
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{NewCollated(lowerFirst{}, []rune("ac"), nil, false), "xBc", 1, []int{1}},
+		{NewCollated(lowerFirst{}, []rune("bc"), nil, true), "bc\u0434", 2, []int{2}},
+		{NewCollated(lowerFirst{}, []rune("bc"), nil, false), "xyz", -1, nil},
+		{NewCollated(lowerFirst{}, []rune("bc"), nil, true), "b\xffc", 1, []int{1}},
+	} {
+		index, segments := test.matcher.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
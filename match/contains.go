@@ -2,53 +2,62 @@ package match
 
 import (
 	"fmt"
-	"strings"
 )
 
 type Contains struct {
 	Needle string
 	Not    bool
+
+	// Skip is the table to search long needles with, or nil.
+	Skip []int
 }
 
 func NewContains(needle string, not bool) Contains {
-	return Contains{needle, not}
+	return Contains{
+		Needle: needle,
+		Not:    not,
+		Skip:   horspoolSkip(needle),
+	}
 }
 
 func (self Contains) Match(s string) bool {
-	return strings.Contains(s, self.Needle) != self.Not
+	return (indexSkip(s, self.Needle, self.Skip) != -1) != self.Not
 }
 
 func (self Contains) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self Contains) AppendIndex(buf []int, s string) (int, []int) {
 	var offset int
 
-	idx := strings.Index(s, self.Needle)
+	idx := indexSkip(s, self.Needle, self.Skip)
 
 	if !self.Not {
 		if idx == -1 {
-			return -1, nil
+			return -1, buf
 		}
 
 		offset = idx + len(self.Needle)
-		if len(s) <= offset {
-			return 0, []int{offset}
-		}
 		s = s[offset:]
 	} else if idx != -1 {
 		s = s[:idx]
 	}
 
-	segments := acquireSegments(len(s) + 1)
-	for i := range s {
-		segments = append(segments, offset+i)
-	}
-
-	return 0, append(segments, offset+len(s))
+	return 0, appendBoundaries(growSegments(buf, len(s)+1), s, offset)
 }
 
 func (self Contains) Len() int {
 	return lenNo
 }
 
+func (self Contains) Bounds() (min, max int) {
+	if self.Not {
+		return 0, -1
+	}
+	return len(self.Needle), -1
+}
+
 func (self Contains) String() string {
 	var not string
 	if self.Not {
@@ -27,23 +27,15 @@ func graphviz_internal(m match.Matcher, id string) string {
 			default:
 				sub := fmt.Sprintf("%x", rand.Int63())
 				fmt.Fprintf(buf, `"%s"->"%s";`, id, sub)
-				fmt.Fprintf(buf, graphviz_internal(n, sub))
+				buf.WriteString(graphviz_internal(n, sub))
 			}
 		}
 
-	case match.AnyOf:
-		fmt.Fprintf(buf, `"%s"[label="AnyOf"];`, id)
-		for _, m := range matcher.Matchers {
-			rnd := rand.Int63()
-			fmt.Fprintf(buf, graphviz_internal(m, fmt.Sprintf("%x", rnd)))
-			fmt.Fprintf(buf, `"%s"->"%x";`, id, rnd)
-		}
-
-	case match.EveryOf:
-		fmt.Fprintf(buf, `"%s"[label="EveryOf"];`, id)
-		for _, m := range matcher.Matchers {
+	case match.Container:
+		fmt.Fprintf(buf, `"%s"[label="%s"];`, id, label(m))
+		for _, m := range matcher.Children() {
 			rnd := rand.Int63()
-			fmt.Fprintf(buf, graphviz_internal(m, fmt.Sprintf("%x", rnd)))
+			buf.WriteString(graphviz_internal(m, fmt.Sprintf("%x", rnd)))
 			fmt.Fprintf(buf, `"%s"->"%x";`, id, rnd)
 		}
 
@@ -53,3 +45,20 @@ func graphviz_internal(m match.Matcher, id string) string {
 
 	return buf.String()
 }
+
+func label(m match.Matcher) string {
+	switch v := m.(type) {
+	case match.AnyOf:
+		return "AnyOf"
+	case match.EveryOf:
+		return "EveryOf"
+	case match.Row:
+		return "Row"
+	case match.Optional:
+		return "Optional"
+	case match.Repeat:
+		return fmt.Sprintf("Repeat{%d,%d}", v.Min, v.Max)
+	default:
+		return fmt.Sprintf("%T", m)
+	}
+}
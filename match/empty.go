@@ -0,0 +1,35 @@
+package match
+
+// Empty matches no strings at all, not even the empty one, which is what
+// Nothing matches. It is what the empty pattern is compiled to when it is
+// chosen to match nothing, and what alternatives of no patterns match.
+type Empty struct{}
+
+func NewEmpty() Empty {
+	return Empty{}
+}
+
+func (self Empty) Match(s string) bool {
+	return false
+}
+
+func (self Empty) Index(s string) (int, []int) {
+	return -1, nil
+}
+
+func (self Empty) AppendIndex(buf []int, s string) (int, []int) {
+	return -1, buf
+}
+
+// Len returns -1, so that Empty is never searched for as the value of BTree.
+func (self Empty) Len() int {
+	return lenNo
+}
+
+func (self Empty) Bounds() (min, max int) {
+	return 0, 0
+}
+
+func (self Empty) String() string {
+	return "<empty>"
+}
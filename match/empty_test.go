@@ -0,0 +1,15 @@
+package match
+
+import "testing"
+
+func TestEmpty(t *testing.T) {
+	m := NewEmpty()
+	for id, fixture := range []string{"", "a", "abc"} {
+		if m.Match(fixture) {
+			t.Errorf("#%d unexpected match of %q", id, fixture)
+		}
+		if index, segments := m.Index(fixture); index != -1 || segments != nil {
+			t.Errorf("#%d unexpected index: exp: -1 [], act: %d %v", id, index, segments)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package match
 
 import (
 	"fmt"
+	"unicode/utf8"
 )
 
 type EveryOf struct {
@@ -17,71 +18,95 @@ func (self *EveryOf) Add(m Matcher) error {
 	return nil
 }
 
-func (self EveryOf) Len() (l int) {
+// Len returns the length of the strings all the matchers match, which is
+// known if any of them matches strings of some length, or the Min, Max and
+// Between constraints bound the length to the same number of runes.
+func (self EveryOf) Len() int {
+	min, max := -1, -1
 	for _, m := range self.Matchers {
-		if ml := m.Len(); l > 0 {
-			l += ml
-		} else {
-			return -1
+		if l := m.Len(); l != -1 {
+			return l
+		}
+		switch v := m.(type) {
+		case Min:
+			if v.Limit > min {
+				min = v.Limit
+			}
+		case Max:
+			if max == -1 || v.Limit < max {
+				max = v.Limit
+			}
+		case Between:
+			if v.Min > min {
+				min = v.Min
+			}
+			if max == -1 || v.Max < max {
+				max = v.Max
+			}
 		}
 	}
+	if min != -1 && min == max {
+		return min
+	}
+	return -1
+}
 
-	return
+func (self EveryOf) Bounds() (min, max int) {
+	max = -1
+	for _, m := range self.Matchers {
+		mmin, mmax := bounds(m)
+		if mmin > min {
+			min = mmin
+		}
+		if mmax != -1 && (max == -1 || mmax < max) {
+			max = mmax
+		}
+	}
+	return min, max
 }
 
+// Index returns the earliest index at which all the matchers match some
+// substring, and the lengths of such substrings. Substrings the first
+// matcher finds are checked by the others, so that EveryOf of constraints
+// like `*` glued with separators could be the value of BTree.
 func (self EveryOf) Index(s string) (int, []int) {
-	var index int
-	var offset int
-
-	// make `in` with cap as len(s),
-	// cause it is the maximum size of output segments values
-	next := acquireSegments(len(s))
-	current := acquireSegments(len(s))
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
 
-	sub := s
-	for i, m := range self.Matchers {
-		idx, seg := m.Index(sub)
+func (self EveryOf) AppendIndex(buf []int, s string) (int, []int) {
+	if len(self.Matchers) == 0 {
+		return -1, buf
+	}
+	buf = growSegments(buf, len(s)+1)
+	first := len(buf)
+	for offset := 0; offset <= len(s); {
+		// The segments of the first matcher are appended to buf and
+		// filtered in place.
+		idx, found := AppendIndex(self.Matchers[0], buf, s[offset:])
 		if idx == -1 {
-			releaseSegments(next)
-			releaseSegments(current)
-			return -1, nil
+			return -1, buf
 		}
-
-		if i == 0 {
-			// we use copy here instead of `current = seg`
-			// cause seg is a slice from reusable buffer `in`
-			// and it could be overwritten in next iteration
-			current = append(current, seg...)
-		} else {
-			// clear the next
-			next = next[:0]
-
-			delta := index - (idx + offset)
-			for _, ex := range current {
-				for _, n := range seg {
-					if ex+delta == n {
-						next = append(next, n)
-					}
+		start := offset + idx
+		buf = found[:first]
+	next:
+		for _, n := range found[first:] {
+			for _, m := range self.Matchers[1:] {
+				if !m.Match(s[start : start+n]) {
+					continue next
 				}
 			}
-
-			if len(next) == 0 {
-				releaseSegments(next)
-				releaseSegments(current)
-				return -1, nil
-			}
-
-			current = append(current[:0], next...)
+			buf = append(buf, n)
 		}
-
-		index = idx + offset
-		sub = s[index:]
-		offset += idx
+		if len(buf) > first {
+			return start, buf
+		}
+		if start == len(s) {
+			break
+		}
+		_, w := utf8.DecodeRuneInString(s[start:])
+		offset = start + w
 	}
-
-	releaseSegments(next)
-
-	return index, current
+	return -1, buf
 }
 
 func (self EveryOf) Match(s string) bool {
@@ -94,6 +119,19 @@ func (self EveryOf) Match(s string) bool {
 	return true
 }
 
+func (self EveryOf) MatchLimit(s string, l *Limit) bool {
+	for _, m := range self.Matchers {
+		if !MatchLimit(m, s, l) {
+			return false
+		}
+	}
+	return true
+}
+
+func (self EveryOf) Children() []Matcher {
+	return self.Matchers
+}
+
 func (self EveryOf) String() string {
 	return fmt.Sprintf("<every_of:[%s]>", self.Matchers)
 }
@@ -32,6 +32,26 @@ func TestEveryOfIndex(t *testing.T) {
 			1,
 			[]int{2},
 		},
+		{
+			// ?? with separator /
+			Matchers{
+				NewMin(2),
+				NewMax(2),
+				NewContains("/", true),
+			},
+			"a/bc/",
+			2,
+			[]int{2},
+		},
+		{
+			Matchers{
+				NewMin(1),
+				NewContains("/", true),
+			},
+			"/ab",
+			1,
+			[]int{1, 2},
+		},
 	} {
 		everyOf := NewEveryOf(test.matchers...)
 		index, segments := everyOf.Index(test.fixture)
@@ -43,3 +63,19 @@ func TestEveryOfIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestEveryOfLen(t *testing.T) {
+	for id, test := range []struct {
+		matchers Matchers
+		length   int
+	}{
+		{Matchers{NewMin(2), NewMax(2), NewContains("/", true)}, 2},
+		{Matchers{NewMin(1), NewMax(2)}, -1},
+		{Matchers{NewMin(1), NewContains("/", true)}, -1},
+		{Matchers{NewText("abc"), NewContains("/", true)}, 3},
+	} {
+		if act := NewEveryOf(test.matchers...).Len(); act != test.length {
+			t.Errorf("#%d unexpected length: exp: %d, act: %d", id, test.length, act)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package match
+
+import (
+	"fmt"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// FoldContains matches strings containing the needle case-insensitively, as
+// FoldText matches the whole string.
+type FoldContains struct {
+	Needle   string // folded by runes.FoldString
+	MinBytes int
+}
+
+func NewFoldContains(needle string) FoldContains {
+	f := runes.FoldString(needle)
+	_, min, _ := foldLength(f)
+	return FoldContains{Needle: f, MinBytes: min}
+}
+
+func (self FoldContains) Match(s string) bool {
+	idx, _ := runes.IndexFold(s, self.Needle)
+	return idx != -1
+}
+
+func (self FoldContains) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self FoldContains) AppendIndex(buf []int, s string) (int, []int) {
+	idx, n := runes.IndexFold(s, self.Needle)
+	if idx == -1 {
+		return -1, buf
+	}
+	offset := idx + n
+	s = s[offset:]
+	return 0, appendBoundaries(growSegments(buf, len(s)+1), s, offset)
+}
+
+func (self FoldContains) Len() int {
+	return lenNo
+}
+
+func (self FoldContains) Bounds() (min, max int) {
+	return self.MinBytes, -1
+}
+
+func (self FoldContains) String() string {
+	return fmt.Sprintf("<fold_contains:[%s]>", self.Needle)
+}
@@ -0,0 +1,46 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldContainsIndex(t *testing.T) {
+	for id, test := range []struct {
+		needle   string
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{
+			"bc",
+			"aBCd",
+			0,
+			[]int{3, 4},
+		},
+		{
+			"k",
+			"a\u212a",
+			0,
+			[]int{4},
+		},
+		{
+			"f",
+			"abcd",
+			-1,
+			nil,
+		},
+	} {
+		m := NewFoldContains(test.needle)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+		if act, exp := m.Match(test.fixture), test.index != -1; act != exp {
+			t.Errorf("#%d unexpected match: exp: %v, act: %v", id, exp, act)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package match
+
+import (
+	"fmt"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// FoldPrefix matches strings starting with the prefix case-insensitively, as
+// FoldText matches the whole string.
+type FoldPrefix struct {
+	Prefix   string // folded by runes.FoldString
+	MinBytes int
+}
+
+func NewFoldPrefix(p string) FoldPrefix {
+	f := runes.FoldString(p)
+	_, min, _ := foldLength(f)
+	return FoldPrefix{Prefix: f, MinBytes: min}
+}
+
+func (self FoldPrefix) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self FoldPrefix) AppendIndex(buf []int, s string) (int, []int) {
+	idx, n := runes.IndexFold(s, self.Prefix)
+	if idx == -1 {
+		return -1, buf
+	}
+	sub := s[idx+n:]
+	return idx, appendBoundaries(growSegments(buf, len(sub)+1), sub, n)
+}
+
+func (self FoldPrefix) Len() int {
+	return lenNo
+}
+
+func (self FoldPrefix) Bounds() (min, max int) {
+	return self.MinBytes, -1
+}
+
+func (self FoldPrefix) Match(s string) bool {
+	return runes.PrefixFold(s, self.Prefix) != -1
+}
+
+func (self FoldPrefix) String() string {
+	return fmt.Sprintf("<fold_prefix:%s>", self.Prefix)
+}
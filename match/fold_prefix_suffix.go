@@ -0,0 +1,73 @@
+package match
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// FoldPrefixSuffix matches strings starting with the prefix and ending with
+// the suffix case-insensitively, as FoldText matches the whole string. Unlike
+// the ones of PrefixSuffix, the prefix and the suffix do not overlap.
+type FoldPrefixSuffix struct {
+	Prefix, Suffix string // folded by runes.FoldString
+	MinBytes       int
+}
+
+func NewFoldPrefixSuffix(p, s string) FoldPrefixSuffix {
+	m := FoldPrefixSuffix{Prefix: runes.FoldString(p), Suffix: runes.FoldString(s)}
+	_, pmin, _ := foldLength(m.Prefix)
+	_, smin, _ := foldLength(m.Suffix)
+	m.MinBytes = pmin + smin
+	return m
+}
+
+func (self FoldPrefixSuffix) Index(s string) (int, []int) {
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
+
+func (self FoldPrefixSuffix) AppendIndex(buf []int, s string) (int, []int) {
+	prefixIdx, n := runes.IndexFold(s, self.Prefix)
+	if prefixIdx == -1 {
+		return -1, buf
+	}
+	sub := s[prefixIdx+n:]
+
+	idx, m := runes.IndexFold(sub, self.Suffix)
+	if idx == -1 {
+		return -1, buf
+	}
+	buf = growSegments(buf, len(sub)+1)
+	for {
+		buf = append(buf, n+idx+m)
+		if idx == len(sub) {
+			break
+		}
+		_, w := utf8.DecodeRuneInString(sub[idx:])
+		next, k := runes.IndexFold(sub[idx+w:], self.Suffix)
+		if next == -1 {
+			break
+		}
+		idx, m = idx+w+next, k
+	}
+
+	return prefixIdx, buf
+}
+
+func (self FoldPrefixSuffix) Len() int {
+	return lenNo
+}
+
+func (self FoldPrefixSuffix) Bounds() (min, max int) {
+	return self.MinBytes, -1
+}
+
+func (self FoldPrefixSuffix) Match(s string) bool {
+	n := runes.PrefixFold(s, self.Prefix)
+	return n != -1 && runes.SuffixFold(s[n:], self.Suffix) != -1
+}
+
+func (self FoldPrefixSuffix) String() string {
+	return fmt.Sprintf("<fold_prefix_suffix:[%s,%s]>", self.Prefix, self.Suffix)
+}
@@ -0,0 +1,64 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldPrefixSuffixMatch(t *testing.T) {
+	for id, test := range []struct {
+		prefix, suffix string
+		fixture        string
+		exp            bool
+	}{
+		{"a", "c", "AbC", true},
+		{"ab", "bc", "abc", false},
+		{"ab", "bc", "ABbc", true},
+		{"k", "k", "\u212ak", true},
+	} {
+		m := NewFoldPrefixSuffix(test.prefix, test.suffix)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected match of %q: exp: %v, act: %v", id, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestFoldPrefixSuffixIndex(t *testing.T) {
+	for id, test := range []struct {
+		prefix, suffix string
+		fixture        string
+		index          int
+		segments       []int
+	}{
+		{
+			"a",
+			"c",
+			"xAbc",
+			1,
+			[]int{3},
+		},
+		{
+			"f",
+			"f",
+			"fFfabfff",
+			0,
+			[]int{2, 3, 6, 7, 8},
+		},
+		{
+			"ab",
+			"bc",
+			"abc",
+			-1,
+			nil,
+		},
+	} {
+		m := NewFoldPrefixSuffix(test.prefix, test.suffix)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldPrefixMatch(t *testing.T) {
+	for id, test := range []struct {
+		prefix  string
+		fixture string
+		exp     bool
+	}{
+		{"ab", "ABc", true},
+		{"ab", "xab", false},
+		{"k", "\u212ax", true},
+		{"", "", true},
+	} {
+		m := NewFoldPrefix(test.prefix)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected match of %q: exp: %v, act: %v", id, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestFoldPrefixIndex(t *testing.T) {
+	for id, test := range []struct {
+		prefix   string
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{
+			"ab",
+			"aBcd",
+			0,
+			[]int{2, 3, 4},
+		},
+		{
+			"k",
+			"x\u212ay",
+			1,
+			[]int{3, 4},
+		},
+		{
+			"f",
+			"abcd",
+			-1,
+			nil,
+		},
+	} {
+		m := NewFoldPrefix(test.prefix)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package match
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// FoldSet matches any of the strings of the set case-insensitively, as
+// FoldText matches a single string. The strings are folded once, by
+// NewFoldSet, and matched strings are folded as they are looked up.
+type FoldSet struct {
+	Strings map[string]bool // folded by runes.FoldString
+
+	// Lengths are the ascending distinct lengths in bytes of the folded
+	// strings, and First tells which bytes the non-empty ones start with.
+	Lengths []int
+	First   [256]bool
+
+	// RunesLength is the length in runes of every string, or -1 if the
+	// lengths differ. MinBytes and MaxBytes bound the lengths in bytes
+	// of the strings matched, as they do for FoldText.
+	RunesLength        int
+	MinBytes, MaxBytes int
+}
+
+// foldSetBuffer is the size of the buffer strings are folded into, which
+// is allocated on the stack.
+const foldSetBuffer = 64
+
+func NewFoldSet(strs ...string) FoldSet {
+	set := FoldSet{
+		Strings:     make(map[string]bool, len(strs)),
+		RunesLength: -1,
+	}
+	for i, s := range strs {
+		f := runes.FoldString(s)
+		if set.Strings[f] {
+			continue
+		}
+		set.Strings[f] = true
+		set.Lengths = append(set.Lengths, len(f))
+		if f != "" {
+			set.First[f[0]] = true
+		}
+		n, min, max := foldLength(f)
+		if i == 0 || min < set.MinBytes {
+			set.MinBytes = min
+		}
+		if max > set.MaxBytes {
+			set.MaxBytes = max
+		}
+		switch {
+		case i == 0:
+			set.RunesLength = n
+		case set.RunesLength != n:
+			set.RunesLength = -1
+		}
+	}
+	sort.Ints(set.Lengths)
+	set.Lengths = uniqueInts(set.Lengths)
+	return set
+}
+
+func (self FoldSet) Match(s string) bool {
+	if len(s) < self.MinBytes || len(s) > self.MaxBytes {
+		return false
+	}
+	var buf [foldSetBuffer]byte
+	return self.Strings[string(runes.AppendFold(buf[:0], s))]
+}
+
+func (self FoldSet) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self FoldSet) AppendIndex(buf []int, s string) (int, []int) {
+	var folded [foldSetBuffer]byte
+	start := len(buf)
+	for i := 0; i <= len(s); {
+		if buf = self.appendPrefixes(buf, s[i:], folded[:0]); len(buf) > start {
+			return i, buf
+		}
+		if i == len(s) {
+			break
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+	return -1, buf
+}
+
+// appendPrefixes appends to buf the ascending lengths of the prefixes of s
+// which are in the set, folding the runes of s into folded one by one.
+// Segments are acquired for nil buf only if there are some.
+func (self FoldSet) appendPrefixes(buf []int, s string, folded []byte) []int {
+	if len(self.Lengths) == 0 {
+		return buf
+	}
+	max := self.Lengths[len(self.Lengths)-1]
+	for i := 0; ; {
+		if self.Strings[string(folded)] {
+			buf = append(growSegments(buf, len(self.Lengths)), i)
+		}
+		if i == len(s) || len(folded) >= max {
+			return buf
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		folded = runes.AppendFold(folded, s[i:i+w])
+		if i == 0 && !self.First[folded[0]] {
+			return buf
+		}
+		i += w
+	}
+}
+
+func (self FoldSet) Len() int {
+	return self.RunesLength
+}
+
+func (self FoldSet) Bounds() (min, max int) {
+	return self.MinBytes, self.MaxBytes
+}
+
+func (self FoldSet) String() string {
+	strs := make([]string, 0, len(self.Strings))
+	for s := range self.Strings {
+		strs = append(strs, s)
+	}
+	sort.Strings(strs)
+	return fmt.Sprintf("<fold_set:[%s]>", strings.Join(strs, ","))
+}
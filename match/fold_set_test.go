@@ -0,0 +1,58 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldSetMatch(t *testing.T) {
+	for id, test := range []struct {
+		strs    []string
+		fixture string
+		exp     bool
+	}{
+		{[]string{"abc", "def"}, "ABC", true},
+		{[]string{"abc", "def"}, "dEf", true},
+		{[]string{"abc", "def"}, "abcdef", false},
+		{[]string{"key", ""}, "\u212aEY", true},
+		{[]string{"key", ""}, "", true},
+		{[]string{"abc"}, "", false},
+	} {
+		if act := NewFoldSet(test.strs...).Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result: exp: %t, act: %t", id, test.exp, act)
+		}
+	}
+}
+
+func TestFoldSetIndex(t *testing.T) {
+	for id, test := range []struct {
+		strs     []string
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{[]string{"abc", "def"}, "xxDEFabc", 2, []int{3}},
+		{[]string{"ab", "a", "abc"}, "xAbCd", 1, []int{1, 2, 3}},
+		{[]string{"k", "b"}, "a\u212ab", 1, []int{3}},
+		{[]string{"abc", "def"}, "abdeg", -1, nil},
+		{[]string{"", "a"}, "Ab", 0, []int{0, 1}},
+	} {
+		index, segments := NewFoldSet(test.strs...).Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
+
+func TestFoldSetBounds(t *testing.T) {
+	m := NewFoldSet("ab", "key")
+	if min, max := m.Bounds(); min != 2 || max != 5 {
+		t.Errorf("Bounds() = %d, %d; want 2, 5", min, max)
+	}
+	if n := m.Len(); n != -1 {
+		t.Errorf("Len() = %d; want -1", n)
+	}
+}
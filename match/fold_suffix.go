@@ -0,0 +1,66 @@
+package match
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// FoldSuffix matches strings ending with the suffix case-insensitively, as
+// FoldText matches the whole string.
+type FoldSuffix struct {
+	Suffix   string // folded by runes.FoldString
+	MinBytes int
+}
+
+func NewFoldSuffix(s string) FoldSuffix {
+	f := runes.FoldString(s)
+	_, min, _ := foldLength(f)
+	return FoldSuffix{Suffix: f, MinBytes: min}
+}
+
+func (self FoldSuffix) Len() int {
+	return lenNo
+}
+
+func (self FoldSuffix) Bounds() (min, max int) {
+	return self.MinBytes, -1
+}
+
+func (self FoldSuffix) Match(s string) bool {
+	return runes.SuffixFold(s, self.Suffix) != -1
+}
+
+func (self FoldSuffix) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self FoldSuffix) AppendIndex(buf []int, s string) (int, []int) {
+	idx, n := runes.IndexFold(s, self.Suffix)
+	if idx == -1 {
+		return -1, buf
+	}
+
+	// Every occurrence of the suffix could be the end of the match. As the
+	// occurrences are of the same number of runes, their ends are ascending.
+	buf = growSegments(buf, len(s)+1)
+	for {
+		buf = append(buf, idx+n)
+		if idx == len(s) {
+			break
+		}
+		_, w := utf8.DecodeRuneInString(s[idx:])
+		next, m := runes.IndexFold(s[idx+w:], self.Suffix)
+		if next == -1 {
+			break
+		}
+		idx, n = idx+w+next, m
+	}
+
+	return 0, buf
+}
+
+func (self FoldSuffix) String() string {
+	return fmt.Sprintf("<fold_suffix:%s>", self.Suffix)
+}
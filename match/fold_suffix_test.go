@@ -0,0 +1,61 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldSuffixMatch(t *testing.T) {
+	for id, test := range []struct {
+		suffix  string
+		fixture string
+		exp     bool
+	}{
+		{"ab", "cAB", true},
+		{"ab", "abx", false},
+		{"k", "x\u212a", true},
+		{"ss", "s", false},
+	} {
+		m := NewFoldSuffix(test.suffix)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected match of %q: exp: %v, act: %v", id, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestFoldSuffixIndex(t *testing.T) {
+	for id, test := range []struct {
+		suffix   string
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{
+			"ab",
+			"abfABab",
+			0,
+			[]int{2, 5, 7},
+		},
+		{
+			"kk",
+			"\u212akK",
+			0,
+			[]int{4, 5},
+		},
+		{
+			"f",
+			"abcd",
+			-1,
+			nil,
+		},
+	} {
+		m := NewFoldSuffix(test.suffix)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
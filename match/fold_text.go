@@ -0,0 +1,81 @@
+package match
+
+import (
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// FoldText matches the string case-insensitively, under Unicode simple case
+// folding. The string is folded once, by NewFoldText, and runes of matched
+// strings are folded as they are compared to it.
+type FoldText struct {
+	Str         string // folded by runes.FoldString
+	RunesLength int
+
+	// Cases of a rune could be encoded in different numbers of bytes,
+	// like `k` and the Kelvin sign, so strings matched are of lengths
+	// in bytes between MinBytes and MaxBytes.
+	MinBytes, MaxBytes int
+}
+
+func NewFoldText(s string) FoldText {
+	t := FoldText{Str: runes.FoldString(s)}
+	t.RunesLength, t.MinBytes, t.MaxBytes = foldLength(t.Str)
+	return t
+}
+
+// foldLength returns the number of runes of the folded string, and the
+// bounds of the lengths in bytes of the strings equal to it under folding.
+func foldLength(folded string) (n, min, max int) {
+	for i := 0; i < len(folded); {
+		r, w := utf8.DecodeRuneInString(folded[i:])
+		i += w
+		// Bytes which are not valid UTF-8 only match themselves.
+		rmin, rmax := w, w
+		if r != utf8.RuneError || w != 1 {
+			for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+				if n := utf8.RuneLen(f); n < rmin {
+					rmin = n
+				} else if n > rmax {
+					rmax = n
+				}
+			}
+		}
+		n++
+		min += rmin
+		max += rmax
+	}
+	return n, min, max
+}
+
+func (self FoldText) Match(s string) bool {
+	return len(s) >= self.MinBytes && len(s) <= self.MaxBytes && runes.EqualFold(s, self.Str)
+}
+
+func (self FoldText) Len() int {
+	return self.RunesLength
+}
+
+func (self FoldText) Bounds() (min, max int) {
+	return self.MinBytes, self.MaxBytes
+}
+
+func (self FoldText) Index(s string) (int, []int) {
+	index, n := runes.IndexFold(s, self.Str)
+	if index == -1 {
+		return -1, nil
+	}
+	return index, singleSegment(n)
+}
+
+func (self FoldText) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
+func (self FoldText) String() string {
+	return fmt.Sprintf("<fold_text:`%v`>", self.Str)
+}
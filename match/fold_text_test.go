@@ -0,0 +1,90 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFoldTextMatch(t *testing.T) {
+	for id, test := range []struct {
+		text    string
+		fixture string
+		exp     bool
+	}{
+		{"abc", "abc", true},
+		{"abc", "AbC", true},
+		{"abc", "abd", false},
+		{"abc", "abcd", false},
+		{"key", "Key", true},
+		{"ss", "ſS", true},
+		{"a\xff", "A\xff", true},
+		{"a\xff", "A�", false},
+	} {
+		m := NewFoldText(test.text)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected match of %q: exp: %v, act: %v", id, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestFoldTextBounds(t *testing.T) {
+	for id, test := range []struct {
+		text     string
+		min, max int
+	}{
+		{"abc", 3, 3},
+		{"key", 3, 5},
+		{"ſ", 1, 2},
+		{"ж\xff", 3, 3},
+	} {
+		m := NewFoldText(test.text)
+		if min, max := m.Bounds(); min != test.min || max != test.max {
+			t.Errorf("#%d unexpected bounds: exp: %d, %d, act: %d, %d", id, test.min, test.max, min, max)
+		}
+	}
+}
+
+func TestFoldTextIndex(t *testing.T) {
+	for id, test := range []struct {
+		text     string
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{
+			"b",
+			"aBc",
+			1,
+			[]int{1},
+		},
+		{
+			"ok",
+			"xO\u212a",
+			1,
+			[]int{4},
+		},
+		{
+			"f",
+			"abcd",
+			-1,
+			nil,
+		},
+	} {
+		m := NewFoldText(test.text)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
+
+func BenchmarkIndexFoldText(b *testing.B) {
+	m := NewFoldText("XYZ")
+
+	for i := 0; i < b.N; i++ {
+		m.Index(bench_pattern)
+	}
+}
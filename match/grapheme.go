@@ -0,0 +1,64 @@
+package match
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// Grapheme matches a single grapheme cluster, as runes.GraphemeLen splits
+// strings into them, which does not start with a separator. It is what `?`
+// is compiled to for matching of text seen by users, where a single rune
+// could be a part of what is seen as a single character, like a letter
+// followed by a combining accent, or an emoji with a skin tone modifier.
+// Since the matcher does not see the string its argument is split from, it
+// never matches clusters starting with combining marks, which are parts of
+// the preceding clusters, if they are not at the beginning of the string.
+type Grapheme struct {
+	Separators []rune
+}
+
+func NewGrapheme(s []rune) Grapheme {
+	return Grapheme{s}
+}
+
+func (self Grapheme) Match(s string) bool {
+	return len(s) > 0 && runes.GraphemeLen(s) == len(s) && self.allowed(s)
+}
+
+// allowed reports whether the cluster s starts with is neither a separator,
+// nor a part of the cluster the string is split from, which starts with a
+// rune extending the preceding one.
+func (self Grapheme) allowed(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return runes.IndexRune(self.Separators, r) == -1 && !runes.Extends(r)
+}
+
+func (self Grapheme) Len() int {
+	return lenNo
+}
+
+func (self Grapheme) Bounds() (min, max int) {
+	return 1, -1
+}
+
+func (self Grapheme) Index(s string) (int, []int) {
+	for i := 0; i < len(s); {
+		n := runes.GraphemeLen(s[i:])
+		if self.allowed(s[i:]) {
+			return i, singleSegment(n)
+		}
+		i += n
+	}
+	return -1, nil
+}
+
+func (self Grapheme) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
+func (self Grapheme) String() string {
+	return fmt.Sprintf("<grapheme:![%s]>", string(self.Separators))
+}
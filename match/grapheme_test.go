@@ -0,0 +1,61 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGraphemeMatch(t *testing.T) {
+	for id, test := range []struct {
+		separators []rune
+		fixture    string
+		exp        bool
+	}{
+		{[]rune{'.'}, "a", true},
+		{[]rune{'.'}, "e\u0301", true},
+		{[]rune{'.'}, "\U0001f44d\U0001f3fd", true},
+		{[]rune{'.'}, ".\u0301", false},
+		{[]rune{'.'}, "ab", false},
+		{[]rune{'.'}, "", false},
+	} {
+		if act := NewGrapheme(test.separators).Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result: exp: %t, act: %t", id, test.exp, act)
+		}
+	}
+}
+
+func TestGraphemeIndex(t *testing.T) {
+	for id, test := range []struct {
+		separators []rune
+		fixture    string
+		index      int
+		segments   []int
+	}{
+		{
+			[]rune{'.'},
+			".e\u0301",
+			1,
+			[]int{3},
+		},
+		{
+			[]rune{'.'},
+			".\u0301",
+			-1,
+			nil,
+		},
+		{
+			nil,
+			"\U0001f1fa\U0001f1f8a",
+			0,
+			[]int{8},
+		},
+	} {
+		index, segments := NewGrapheme(test.separators).Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package match
+
+import "strings"
+
+// horspoolMinLen is the length of strings, starting from which they are
+// searched by the Boyer–Moore–Horspool algorithm. Shorter strings are
+// searched faster by strings.Index, which is vectorized on most platforms.
+const horspoolMinLen = 32
+
+// horspoolSkip returns the shift table of the Boyer–Moore–Horspool
+// algorithm for the needle, or nil if the needle is too short for it to pay
+// off. The table holds for every byte the distance from its last occurrence
+// in the needle, except for the last byte, to the end of the needle.
+func horspoolSkip(needle string) []int {
+	if len(needle) < horspoolMinLen {
+		return nil
+	}
+	skip := make([]int, 256)
+	for i := range skip {
+		skip[i] = len(needle)
+	}
+	for i := 0; i < len(needle)-1; i++ {
+		skip[needle[i]] = len(needle) - 1 - i
+	}
+	return skip
+}
+
+// indexSkip returns the index of the first occurrence of the needle in s,
+// or -1. If skip is the table returned by horspoolSkip, windows of s are
+// compared from the end and shifted by the table, so that long needles are
+// found looking at a fraction of s.
+func indexSkip(s, needle string, skip []int) int {
+	if skip == nil {
+		return strings.Index(s, needle)
+	}
+	last := len(needle) - 1
+	for i := 0; i+last < len(s); i += skip[s[i+last]] {
+		if s[i+last] == needle[last] && s[i:i+last] == needle[:last] {
+			return i
+		}
+	}
+	return -1
+}
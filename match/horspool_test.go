@@ -0,0 +1,62 @@
+package match
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestIndexSkip(t *testing.T) {
+	needle := "needle-with-32-bytes-of-text-ok!"
+	for id, test := range []struct {
+		s     string
+		index int
+	}{
+		{"", -1},
+		{needle, 0},
+		{needle[1:], -1},
+		{"xx" + needle, 2},
+		{strings.Repeat("needle-with-", 10) + needle + "tail", 120},
+		{strings.Repeat("a", 1000), -1},
+		{needle[:31] + "?" + needle, 32},
+	} {
+		if act := indexSkip(test.s, needle, horspoolSkip(needle)); act != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, act)
+		}
+	}
+
+	// Random texts over a small alphabet have many partial matches.
+	r := rand.New(rand.NewSource(1))
+	random := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "ab"[r.Intn(2)]
+		}
+		return string(b)
+	}
+	for i := 0; i < 1000; i++ {
+		needle, s := random(horspoolMinLen+r.Intn(8)), random(r.Intn(4096))
+		if act, exp := indexSkip(s, needle, horspoolSkip(needle)), strings.Index(s, needle); act != exp {
+			t.Fatalf("indexSkip(%q, %q) = %d; want %d", s, needle, act, exp)
+		}
+	}
+}
+
+func BenchmarkIndexSkip(b *testing.B) {
+	needle := "needle-with-32-bytes-of-text-ok!"
+	s := strings.Repeat("haystack without the needle, ", 1<<15)
+	skip := horspoolSkip(needle)
+	b.SetBytes(int64(len(s)))
+	for i := 0; i < b.N; i++ {
+		indexSkip(s, needle, skip)
+	}
+}
+
+func BenchmarkIndexSkipStrings(b *testing.B) {
+	needle := "needle-with-32-bytes-of-text-ok!"
+	s := strings.Repeat("haystack without the needle, ", 1<<15)
+	b.SetBytes(int64(len(s)))
+	for i := 0; i < b.N; i++ {
+		strings.Index(s, needle)
+	}
+}
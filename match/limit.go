@@ -0,0 +1,111 @@
+package match
+
+// Limit bounds the work of matching, so that matching of strings against
+// patterns which backtrack a lot, like `*a*a*a*a*b`, could be given up. The
+// work is counted in steps: a step is a match of a matcher which does not
+// backtrack, or a search of the value of a tree, or a match of its subtree.
+// The zero Limit has no bounds.
+type Limit struct {
+	// MaxSteps, if positive, is the number of steps after which matching
+	// is given up.
+	MaxSteps int
+
+	// Done, if not nil, gives matching up once it is closed, as the
+	// channel of context.Context does.
+	Done <-chan struct{}
+
+	stats   Stats
+	aborted bool
+}
+
+// Stats counts the work of matching within a limit.
+type Stats struct {
+	// Evaluations is the number of matches of matchers, and of subtrees
+	// of trees, against parts of the string.
+	Evaluations int
+
+	// IndexCalls is the number of searches of the values of trees.
+	IndexCalls int
+
+	// Backtracks is the number of times trees went on to other parts of
+	// the string, after the ones their values were found at did not match.
+	Backtracks int
+}
+
+// pollSteps is the number of steps between checks of the Done channel,
+// which take longer than most steps.
+const pollSteps = 64
+
+// Step takes a step of matching, which is an evaluation of a matcher. It
+// returns false if matching should be given up instead.
+func (l *Limit) Step() bool {
+	if !l.step() {
+		return false
+	}
+	l.stats.Evaluations++
+	return true
+}
+
+// index takes a step of matching, which is a search of the value of a tree.
+func (l *Limit) index() bool {
+	if !l.step() {
+		return false
+	}
+	l.stats.IndexCalls++
+	return true
+}
+
+// step reports whether matching could take another step.
+func (l *Limit) step() bool {
+	if l.aborted {
+		return false
+	}
+	steps := l.Steps()
+	if l.MaxSteps > 0 && steps >= l.MaxSteps {
+		l.aborted = true
+		return false
+	}
+	if l.Done != nil && (steps+1)%pollSteps == 0 {
+		select {
+		case <-l.Done:
+			l.aborted = true
+			return false
+		default:
+		}
+	}
+	return true
+}
+
+// Steps returns the number of steps taken so far.
+func (l *Limit) Steps() int {
+	return l.stats.Evaluations + l.stats.IndexCalls
+}
+
+// Stats returns the work of matching done so far.
+func (l *Limit) Stats() Stats {
+	return l.stats
+}
+
+// Aborted reports whether matching was given up.
+func (l *Limit) Aborted() bool {
+	return l.aborted
+}
+
+// Limited is implemented by matchers which could backtrack, or which are
+// composed of ones that could, so that they take steps as they match.
+type Limited interface {
+	Matcher
+
+	// MatchLimit is like Match, but it takes steps of the limit, and
+	// returns false once the limit tells to give up.
+	MatchLimit(s string, l *Limit) bool
+}
+
+// MatchLimit reports whether s matches m, taking steps of the limit. If
+// matching is given up, it returns false and l.Aborted reports true.
+func MatchLimit(m Matcher, s string, l *Limit) bool {
+	if v, ok := m.(Limited); ok {
+		return v.MatchLimit(s, l)
+	}
+	return l.Step() && m.Match(s)
+}
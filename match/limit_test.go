@@ -0,0 +1,109 @@
+package match
+
+import (
+	"strings"
+	"testing"
+)
+
+// backtracking returns `*b*a*a*a*a*a` as the compiler builds it.
+func backtracking() Matcher {
+	var tree Matcher = NewContains("b", false)
+	for i := 0; i < 5; i++ {
+		var right Matcher = NewSuper()
+		if i == 4 {
+			right = nil
+		}
+		tree = NewBTree(NewText("a"), tree, right)
+	}
+	return tree
+}
+
+func TestMatchLimit(t *testing.T) {
+	for id, test := range []struct {
+		matcher Matcher
+		str     string
+	}{
+		{NewText("abc"), "abc"},
+		{NewText("abc"), "abd"},
+		{NewBTree(NewText("abc"), NewSuper(), NewSuper()), "xabcx"},
+		{NewBTree(NewText("abc"), NewSuper(), NewSuper()), "xabx"},
+		{NewAnyOf(NewText("a"), NewBTree(NewText("b"), NewSuper(), nil)), "xb"},
+		{NewEveryOf(NewPrefix("a"), NewBTree(NewText("b"), NewSuper(), nil)), "ab"},
+		{NewNot(NewBTree(NewText("b"), NewSuper(), nil)), "ab"},
+		{NewNot(NewBTree(NewText("b"), NewSuper(), nil)), "ba"},
+		{backtracking(), "b" + strings.Repeat("a", 50)},
+		{backtracking(), strings.Repeat("a", 50)},
+	} {
+		var l Limit
+		exp := test.matcher.Match(test.str)
+		if act := MatchLimit(test.matcher, test.str, &l); act != exp {
+			t.Errorf("#%d MatchLimit(%s, %q) = %t; want %t", id, test.matcher, test.str, act, exp)
+		}
+		if l.Aborted() || l.Steps() == 0 {
+			t.Errorf("#%d unexpected limit: aborted %t after %d steps", id, l.Aborted(), l.Steps())
+		}
+
+		// The steps taken are enough to match again.
+		l = Limit{MaxSteps: l.Steps()}
+		if act := MatchLimit(test.matcher, test.str, &l); act != exp || l.Aborted() {
+			t.Errorf("#%d MatchLimit(%s, %q) within %d steps = %t, aborted %t; want %t", id, test.matcher, test.str, l.MaxSteps, act, l.Aborted(), exp)
+		}
+	}
+}
+
+func TestMatchLimitAbort(t *testing.T) {
+	tree := backtracking()
+	s := strings.Repeat("a", 2000)
+
+	l := Limit{MaxSteps: 1000}
+	if MatchLimit(tree, s, &l) || !l.Aborted() {
+		t.Errorf("expected match to be given up")
+	}
+	if l.Steps() != 1000 {
+		t.Errorf("unexpected steps: exp: %d, act: %d", 1000, l.Steps())
+	}
+
+	done := make(chan struct{})
+	close(done)
+	l = Limit{Done: done}
+	if MatchLimit(tree, s, &l) || !l.Aborted() {
+		t.Errorf("expected match to be given up")
+	}
+	if l.Steps() > pollSteps {
+		t.Errorf("unexpected steps after done: %d", l.Steps())
+	}
+
+	// Not of the given up match is not matched either.
+	l = Limit{MaxSteps: 1000}
+	if MatchLimit(NewNot(tree), s, &l) || !l.Aborted() {
+		t.Errorf("expected match of complement to be given up")
+	}
+}
+
+func TestLimitStats(t *testing.T) {
+	for id, test := range []struct {
+		matcher Matcher
+		str     string
+		exp     Stats
+	}{
+		{NewText("a"), "a", Stats{Evaluations: 1}},
+		{NewBTree(NewText("abc"), NewSuper(), NewSuper()), "xabcx", Stats{Evaluations: 3, IndexCalls: 1}},
+		{NewBTree(NewText("a"), NewSingle(nil), NewText("b")), "aaab", Stats{Evaluations: 2, IndexCalls: 2, Backtracks: 1}},
+		{backtracking(), "baaaaa", Stats{Evaluations: 10, IndexCalls: 5}},
+	} {
+		var l Limit
+		MatchLimit(test.matcher, test.str, &l)
+		if act := l.Stats(); act != test.exp {
+			t.Errorf("#%d unexpected stats of %s on %q: exp: %+v, act: %+v", id, test.matcher, test.str, test.exp, act)
+		}
+		if act, exp := l.Steps(), test.exp.Evaluations+test.exp.IndexCalls; act != exp {
+			t.Errorf("#%d unexpected steps: exp: %d, act: %d", id, exp, act)
+		}
+	}
+
+	var l Limit
+	MatchLimit(backtracking(), strings.Repeat("a", 50), &l)
+	if l.Stats().Backtracks == 0 {
+		t.Errorf("expected backtracks of %s", backtracking())
+	}
+}
@@ -9,20 +9,40 @@ import (
 type List struct {
 	List []rune
 	Not  bool
+
+	// ASCII holds the ASCII runes of the list, and Other tells whether
+	// there are other ones. They are set by NewList. Lists with the zero
+	// ASCII, such as ones made without NewList, look runes up in List.
+	ASCII asciiSet
+	Other bool
 }
 
 func NewList(list []rune, not bool) List {
-	return List{list, not}
+	return List{
+		List:  list,
+		Not:   not,
+		ASCII: newASCIISet(list),
+		Other: !isASCII(list),
+	}
+}
+
+func (self List) contains(r rune) bool {
+	if self.ASCII == (asciiSet{}) {
+		return runes.IndexRune(self.List, r) != -1
+	}
+	if 0 <= r && r < utf8.RuneSelf {
+		return self.ASCII.contains(byte(r))
+	}
+	return self.Other && runes.IndexRune(self.List, r) != -1
 }
 
 func (self List) Match(s string) bool {
 	r, w := utf8.DecodeRuneInString(s)
-	if len(s) > w {
+	if w == 0 || len(s) > w {
 		return false
 	}
 
-	inList := runes.IndexRune(self.List, r) != -1
-	return inList == !self.Not
+	return self.contains(r) == !self.Not
 }
 
 func (self List) Len() int {
@@ -30,15 +50,25 @@ func (self List) Len() int {
 }
 
 func (self List) Index(s string) (int, []int) {
-	for i, r := range s {
-		if self.Not == (runes.IndexRune(self.List, r) == -1) {
-			return i, segmentsByRuneLength[utf8.RuneLen(r)]
+	if !self.Other && self.ASCII != (asciiSet{}) {
+		return indexASCII(s, &self.ASCII, self.Not)
+	}
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
+		if self.Not != self.contains(r) {
+			return i, segmentsByRuneLength[w]
 		}
+		i += w
 	}
 
 	return -1, nil
 }
 
+func (self List) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
 func (self List) String() string {
 	var not string
 	if self.Not {
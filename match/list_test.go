@@ -27,14 +27,63 @@ func TestListIndex(t *testing.T) {
 			0,
 			[]int{1},
 		},
+		{
+			[]rune("aж"),
+			false,
+			"bcжa",
+			2,
+			[]int{2},
+		},
+		{
+			[]rune("ab"),
+			true,
+			"abжa",
+			2,
+			[]int{2},
+		},
+		{
+			[]rune("aж"),
+			true,
+			"a\xffж",
+			1,
+			[]int{1},
+		},
 	} {
-		p := NewList(test.list, test.not)
-		index, segments := p.Index(test.fixture)
-		if index != test.index {
-			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		// Lists made without NewList have no tables, but match the same.
+		for _, p := range []List{NewList(test.list, test.not), {List: test.list, Not: test.not}} {
+			index, segments := p.Index(test.fixture)
+			if index != test.index {
+				t.Errorf("#%d unexpected index of %s: exp: %d, act: %d", id, p, test.index, index)
+			}
+			if !reflect.DeepEqual(segments, test.segments) {
+				t.Errorf("#%d unexpected segments of %s: exp: %v, act: %v", id, p, test.segments, segments)
+			}
 		}
-		if !reflect.DeepEqual(segments, test.segments) {
-			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+	}
+}
+
+func TestListMatch(t *testing.T) {
+	for id, test := range []struct {
+		list    []rune
+		not     bool
+		fixture string
+		exp     bool
+	}{
+		{[]rune("ab"), false, "a", true},
+		{[]rune("ab"), false, "c", false},
+		{[]rune("ab"), false, "ab", false},
+		{[]rune("ab"), true, "ж", true},
+		{[]rune("aж"), false, "ж", true},
+		{[]rune("aж"), false, "a", true},
+		{[]rune("aж"), true, "я", true},
+		{nil, false, "a", false},
+		{[]rune("aж"), true, "", false},
+		{[]rune("aж"), true, "\xff", true},
+	} {
+		for _, m := range []List{NewList(test.list, test.not), {List: test.list, Not: test.not}} {
+			if act := m.Match(test.fixture); act != test.exp {
+				t.Errorf("#%d unexpected match of %q by %s: exp: %v, act: %v", id, test.fixture, m, test.exp, act)
+			}
 		}
 	}
 }
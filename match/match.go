@@ -18,6 +18,35 @@ type Matcher interface {
 	String() string
 }
 
+// Appender is implemented by matchers which could append the segments found
+// by Index to a buffer provided by the caller, so that loops searching many
+// strings reuse a single buffer instead of acquiring segments every time.
+type Appender interface {
+	Matcher
+
+	// AppendIndex is like Index, but appends the segments to buf and
+	// returns the extended buffer, or buf if there is no match. If buf is
+	// nil, the segments are acquired as Index does. Unlike Index, it never
+	// returns segments shared with the matcher, so the buffer could be
+	// truncated and reused.
+	AppendIndex(buf []int, s string) (int, []int)
+}
+
+// AppendIndex calls AppendIndex of m, if it is an Appender. Otherwise, it
+// appends the segments returned by Index of m to buf, and releases them.
+func AppendIndex(m Matcher, buf []int, s string) (int, []int) {
+	if a, ok := m.(Appender); ok {
+		return a.AppendIndex(buf, s)
+	}
+	index, segments := m.Index(s)
+	if index == -1 {
+		return -1, buf
+	}
+	buf = append(growSegments(buf, len(segments)), segments...)
+	releaseSegments(segments)
+	return index, buf
+}
+
 type Matchers []Matcher
 
 func (m Matchers) String() string {
@@ -29,46 +58,29 @@ func (m Matchers) String() string {
 	return fmt.Sprintf("%s", strings.Join(s, ","))
 }
 
-// appendMerge merges and sorts given already SORTED and UNIQUE segments.
-func appendMerge(target, sub []int) []int {
-	lt, ls := len(target), len(sub)
-	out := make([]int, 0, lt+ls)
-
-	for x, y := 0, 0; x < lt || y < ls; {
-		if x >= lt {
-			out = append(out, sub[y:]...)
-			break
-		}
-
-		if y >= ls {
-			out = append(out, target[x:]...)
-			break
-		}
-
-		xValue := target[x]
-		yValue := sub[y]
-
+// mergeSegments merges the SORTED and UNIQUE segments buf[lo:mid] and
+// buf[mid:] in place. The merged segments are appended past them, using
+// the capacity of buf, and then moved to lo.
+func mergeSegments(buf []int, lo, mid int) []int {
+	hi := len(buf)
+	for x, y := lo, mid; x < mid || y < hi; {
 		switch {
-
-		case xValue == yValue:
-			out = append(out, xValue)
+		case y == hi || x < mid && buf[x] < buf[y]:
+			buf = append(buf, buf[x])
 			x++
+
+		case x == mid || buf[y] < buf[x]:
+			buf = append(buf, buf[y])
 			y++
 
-		case xValue < yValue:
-			out = append(out, xValue)
+		default:
+			buf = append(buf, buf[x])
 			x++
-
-		case yValue < xValue:
-			out = append(out, yValue)
 			y++
-
 		}
 	}
 
-	target = append(target[:0], out...)
-
-	return target
+	return append(buf[:lo], buf[hi:]...)
 }
 
 func reverseSegments(input []int) {
@@ -2,15 +2,18 @@ package match
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
 )
 
 var bench_separators = []rune{'.'}
 
 const bench_pattern = "abcdefghijklmnopqrstuvwxyz0123456789"
 
-func TestAppendMerge(t *testing.T) {
+func TestMergeSegments(t *testing.T) {
 	for id, test := range []struct {
 		segments [2][]int
 		exp      []int
@@ -30,7 +33,8 @@ func TestAppendMerge(t *testing.T) {
 			[]int{0, 1, 3, 6, 7, 10},
 		},
 	} {
-		act := appendMerge(test.segments[0], test.segments[1])
+		buf := append(append([]int{-1}, test.segments[0]...), test.segments[1]...)
+		act := mergeSegments(buf, 1, 1+len(test.segments[0]))[1:]
 		if !reflect.DeepEqual(act, test.exp) {
 			t.Errorf("#%d merge sort segments unexpected:\nact: %v\nexp:%v", id, act, test.exp)
 			continue
@@ -38,22 +42,176 @@ func TestAppendMerge(t *testing.T) {
 	}
 }
 
-func BenchmarkAppendMerge(b *testing.B) {
+func TestAppendIndex(t *testing.T) {
+	for id, test := range []struct {
+		matcher Matcher
+		fixture string
+	}{
+		{NewText("c"), "abcdc"},
+		{NewSingle([]rune{'.'}), ".a.b"},
+		{NewGrapheme([]rune{'.'}), ".e\u0301.b"},
+		{NewList([]rune("bc"), false), "abc"},
+		{NewRange('b', 'c', true), "bcd"},
+		{NewRow(2, NewText("b"), NewSingle(nil)), "abcd"},
+		{NewNothing(), "abc"},
+		{NewAny([]rune{'.'}), "ab.c"},
+		{NewSuper(), "abc"},
+		{NewPrefix("b"), "abcb"},
+		{NewSuffix("b"), "abcb"},
+		{NewPrefixAny("b", []rune{'.'}), "abc.b"},
+		{NewSuffixAny("b", []rune{'.'}), "a.cb"},
+		{NewPrefixSuffix("a", "c"), "abcac"},
+		{NewContains("b", false), "abcb"},
+		{NewContains("b", true), "acbc"},
+		{NewMax(2), "abc"},
+		{NewMin(2), "abc"},
+		{NewBetween(1, 2), "abc"},
+		{NewSet("b", "bc", "d"), "abcd"},
+		{NewFoldSet("b", "bc", "d"), "aBcd"},
+		{NewFoldText("bC"), "aBcd"},
+		{NewFoldPrefix("b"), "aBcb"},
+		{NewFoldSuffix("b"), "aBcb"},
+		{NewFoldContains("b"), "aBcb"},
+		{NewFoldPrefixSuffix("a", "c"), "AbcaC"},
+		{NewCharClass(runes.Of('c', '\u212a'), false), "ab\u212ac"},
+		{NewCollated(lowerFirst{}, []rune("bc"), nil, false), "aBc"},
+		{NewNot(NewText("a")), "aab"},
+		{NewOptional(NewText("ab")), "abab"},
+		{NewRepeat(NewSet("a", "ab", "b"), 1, 2), "xabb"},
+		{NewAnyOf(NewText("ab"), NewPrefix("a"), NewText("c")), "cab"},
+		{NewEveryOf(NewSuper(), NewMax(2)), "abc"},
+		{NewBTree(NewText("b"), NewSuper(), NewSuper()), "abcb"},
+		{NewText("x"), "abc"},
+		{NewMin(4), "abc"},
+		{NewPrefixSuffix("a", "x"), "abc"},
+	} {
+		expIndex, expSegments := test.matcher.Index(test.fixture)
+		exp := append([]int{-1}, expSegments...)
+
+		index, buf := AppendIndex(test.matcher, []int{-1}, test.fixture)
+		if index != expIndex {
+			t.Errorf("#%d %s unexpected index: exp: %d, act: %d", id, test.matcher, expIndex, index)
+		}
+		if !reflect.DeepEqual(buf, exp) {
+			t.Errorf("#%d %s unexpected segments: exp: %v, act: %v", id, test.matcher, exp, buf)
+		}
+
+		index, buf = AppendIndex(test.matcher, nil, test.fixture)
+		if index != expIndex || len(buf) != len(expSegments) {
+			t.Errorf("#%d %s unexpected segments for nil buffer: exp: %d %v, act: %d %v", id, test.matcher, expIndex, expSegments, index, buf)
+		}
+	}
+}
+
+func TestMatchAllocs(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	// `*b*a*a*a*a*a`, which caches results of subtrees
+	var memo Matcher = NewContains("b", false)
+	for i := 0; i < 5; i++ {
+		memo = NewBTree(NewText("a"), memo, NewSuper())
+	}
+	for id, test := range []struct {
+		matcher Matcher
+		fixture string
+	}{
+		{NewText("abc"), "abc"},
+		{NewSingle([]rune{'.'}), "a"},
+		{NewGrapheme([]rune{'.'}), "e\u0301"},
+		{NewList([]rune("bc"), false), "b"},
+		{NewRange('b', 'c', true), "d"},
+		{NewRow(2, NewText("b"), NewSingle(nil)), "bü"},
+		{NewNothing(), ""},
+		{NewAny([]rune{'.'}), "abc"},
+		{NewSuper(), "abc"},
+		{NewPrefix("b"), "bcd"},
+		{NewSuffix("b"), "abcb"},
+		{NewPrefixAny("b", []rune{'.'}), "bcd"},
+		{NewSuffixAny("b", []rune{'.'}), "acb"},
+		{NewPrefixSuffix("a", "c"), "abc"},
+		{NewContains("b", false), "abc"},
+		{NewMax(2), "ab"},
+		{NewMin(2), "abc"},
+		{NewBetween(1, 2), "жb"},
+		{NewSet("b", "bc", "d"), "bc"},
+		{NewFoldSet("b", "bc", "d"), "BC"},
+		{NewFoldText("bC"), "Bc"},
+		{NewFoldPrefix("b"), "Bcd"},
+		{NewFoldSuffix("b"), "acB"},
+		{NewFoldContains("\u212a"), "ak"},
+		{NewFoldPrefixSuffix("a", "c"), "AbC"},
+		{NewCharClass(runes.Of('c', '\u212a'), false), "\u212a"},
+		{NewNot(NewText("a")), "b"},
+		{NewOptional(NewPrefix("a")), "abc"},
+		{NewRepeat(NewText("ab"), 1, -1), "abab"},
+		{NewRepeat(NewSet("a", "ab", "b"), 1, -1), "abba"},
+		{NewAnyOf(NewText("ab"), NewPrefix("a")), "abc"},
+		{NewEveryOf(NewSuper(), NewMax(2)), "ab"},
+		{NewBTree(NewAnyOf(NewSuffixAny(".b.", []rune{'.'}), NewText("c.")), nil, NewAny([]rune{'.'})), "a.b.c"},
+		{NewBTree(NewText("a"), NewSuper(), NewBTree(NewText("b"), NewSuper(), NewSuper())), "xaxbx"},
+		{memo, strings.Repeat("a", 200)},
+	} {
+		allocs := testing.AllocsPerRun(100, func() {
+			test.matcher.Match(test.fixture)
+		})
+		if allocs != 0 {
+			t.Errorf("#%d %s unexpected allocations: %v", id, test.matcher, allocs)
+		}
+	}
+}
+
+func TestAppendIndexAllocs(t *testing.T) {
+	buf := make([]int, 0, 16)
+	for id, m := range []Matcher{
+		NewText("b"),
+		NewSingle([]rune{'.'}),
+		NewAny([]rune{'.'}),
+		NewSuper(),
+		NewPrefix("b"),
+		NewSuffix("b"),
+		NewPrefixSuffix("a", "b"),
+		NewFoldPrefix("B"),
+		NewFoldSuffix("B"),
+		NewFoldContains("B"),
+		NewFoldPrefixSuffix("a", "b"),
+		NewContains("b", false),
+		NewMax(2),
+		NewMin(2),
+		NewBetween(1, 3),
+		NewSet("b", "bc"),
+		NewFoldSet("B", "bc"),
+		NewEveryOf(NewSuper(), NewMax(2)),
+	} {
+		allocs := testing.AllocsPerRun(100, func() {
+			_, buf = AppendIndex(m, buf[:0], "abcb")
+		})
+		if allocs != 0 {
+			t.Errorf("#%d %s unexpected allocations: %v", id, m, allocs)
+		}
+	}
+}
+
+func BenchmarkMergeSegments(b *testing.B) {
 	s1 := []int{0, 1, 3, 6, 7}
 	s2 := []int{0, 1, 3}
+	buf := make([]int, 0, 16)
 
 	for i := 0; i < b.N; i++ {
-		appendMerge(s1, s2)
+		buf = append(append(buf[:0], s1...), s2...)
+		mergeSegments(buf, 0, len(s1))
 	}
 }
 
-func BenchmarkAppendMergeParallel(b *testing.B) {
+func BenchmarkMergeSegmentsParallel(b *testing.B) {
 	s1 := []int{0, 1, 3, 6, 7}
 	s2 := []int{0, 1, 3}
 
 	b.RunParallel(func(pb *testing.PB) {
+		buf := make([]int, 0, 16)
 		for pb.Next() {
-			appendMerge(s1, s2)
+			buf = append(append(buf[:0], s1...), s2...)
+			mergeSegments(buf, 0, len(s1))
 		}
 	})
 }
@@ -14,36 +14,28 @@ func NewMax(l int) Max {
 }
 
 func (self Max) Match(s string) bool {
-	var l int
-	for range s {
-		l += 1
-		if l > self.Limit {
-			return false
-		}
-	}
-
-	return true
+	return len(s) <= self.Limit || runeCount(s, self.Limit) <= self.Limit
 }
 
 func (self Max) Index(s string) (int, []int) {
-	segments := acquireSegments(self.Limit + 1)
-	segments = append(segments, 0)
-	var count int
-	for i, r := range s {
-		count++
-		if count > self.Limit {
-			break
-		}
-		segments = append(segments, i+utf8.RuneLen(r))
-	}
+	return self.AppendIndex(nil, s)
+}
 
-	return 0, segments
+func (self Max) AppendIndex(buf []int, s string) (int, []int) {
+	if n := runesPrefix(s, self.Limit); n != -1 {
+		s = s[:n]
+	}
+	return 0, appendBoundaries(growSegments(buf, self.Limit+1), s, 0)
 }
 
 func (self Max) Len() int {
 	return lenNo
 }
 
+func (self Max) Bounds() (min, max int) {
+	return 0, self.Limit * utf8.UTFMax
+}
+
 func (self Max) String() string {
 	return fmt.Sprintf("<max:%d>", self.Limit)
 }
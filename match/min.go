@@ -2,7 +2,6 @@ package match
 
 import (
 	"fmt"
-	"unicode/utf8"
 )
 
 type Min struct {
@@ -14,44 +13,40 @@ func NewMin(l int) Min {
 }
 
 func (self Min) Match(s string) bool {
-	var l int
-	for range s {
-		l += 1
-		if l >= self.Limit {
-			return true
-		}
-	}
-
-	return false
+	return len(s) >= self.Limit && len(s) > 0 && runeCount(s, self.Limit) >= self.Limit
 }
 
 func (self Min) Index(s string) (int, []int) {
-	var count int
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
 
+func (self Min) AppendIndex(buf []int, s string) (int, []int) {
 	c := len(s) - self.Limit + 1
 	if c <= 0 {
-		return -1, nil
+		return -1, buf
 	}
 
-	segments := acquireSegments(c)
-	for i, r := range s {
-		count++
-		if count >= self.Limit {
-			segments = append(segments, i+utf8.RuneLen(r))
-		}
+	// Strings are matched after at least one rune, even if the limit is 0.
+	limit := self.Limit
+	if limit == 0 {
+		limit = 1
 	}
-
-	if len(segments) == 0 {
-		return -1, nil
+	n := runesPrefix(s, limit)
+	if n == -1 {
+		return -1, buf
 	}
 
-	return 0, segments
+	return 0, appendBoundaries(growSegments(buf, c), s[n:], n)
 }
 
 func (self Min) Len() int {
 	return lenNo
 }
 
+func (self Min) Bounds() (min, max int) {
+	return self.Limit, -1
+}
+
 func (self Min) String() string {
 	return fmt.Sprintf("<min:%d>", self.Limit)
 }
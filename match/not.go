@@ -0,0 +1,67 @@
+package match
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// Not matches strings which are not matched by the wrapped matcher.
+type Not struct {
+	Matcher Matcher
+}
+
+func NewNot(m Matcher) Not {
+	return Not{m}
+}
+
+func (self Not) Match(s string) bool {
+	return !self.Matcher.Match(s)
+}
+
+func (self Not) MatchLimit(s string, l *Limit) bool {
+	return !MatchLimit(self.Matcher, s, l) && !l.Aborted()
+}
+
+func (self Not) Len() int {
+	return lenNo
+}
+
+func (self Not) Index(s string) (int, []int) {
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
+
+func (self Not) AppendIndex(buf []int, s string) (int, []int) {
+	// The complement of a matcher has no structure to search by, so every
+	// substring is tried.
+	buf = growSegments(buf, len(s)+1)
+	start := len(buf)
+	for i := 0; i <= len(s); {
+		for j := i; ; {
+			if self.Match(s[i:j]) {
+				buf = append(buf, j-i)
+			}
+			if j == len(s) {
+				break
+			}
+			_, w := utf8.DecodeRuneInString(s[j:])
+			j += w
+		}
+		if len(buf) > start {
+			return i, buf
+		}
+		if i == len(s) {
+			break
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+	return -1, buf
+}
+
+func (self Not) Children() []Matcher {
+	return []Matcher{self.Matcher}
+}
+
+func (self Not) String() string {
+	return fmt.Sprintf("<not:%s>", self.Matcher)
+}
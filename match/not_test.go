@@ -0,0 +1,84 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNotMatch(t *testing.T) {
+	for id, test := range []struct {
+		matcher Matcher
+		fixture string
+		exp     bool
+	}{
+		{NewText("abc"), "abc", false},
+		{NewText("abc"), "ab", true},
+		{NewNothing(), "", false},
+		{NewNothing(), "a", true},
+		{NewSuper(), "", false},
+	} {
+		if act := NewNot(test.matcher).Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result: exp: %t, act: %t", id, test.exp, act)
+		}
+	}
+}
+
+func TestNotIndex(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Matcher
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{
+			NewText("abc"),
+			"abc",
+			0,
+			[]int{0, 1, 2},
+		},
+		{
+			NewNothing(),
+			"ab",
+			0,
+			[]int{1, 2},
+		},
+		{
+			NewNothing(),
+			"",
+			-1,
+			nil,
+		},
+		{
+			NewSuper(),
+			"abc",
+			-1,
+			nil,
+		},
+		{
+			NewAny([]rune{'.'}),
+			"ab.c",
+			0,
+			[]int{3, 4},
+		},
+		{
+			NewAny([]rune{'.'}),
+			"ab.",
+			0,
+			[]int{3},
+		},
+		{
+			NewSingle(nil),
+			"ä",
+			0,
+			[]int{0},
+		},
+	} {
+		index, segments := NewNot(test.matcher).Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
@@ -18,10 +18,18 @@ func (self Nothing) Index(s string) (int, []int) {
 	return 0, segments0
 }
 
+func (self Nothing) AppendIndex(buf []int, s string) (int, []int) {
+	return 0, append(growSegments(buf, 1), 0)
+}
+
 func (self Nothing) Len() int {
 	return lenZero
 }
 
+func (self Nothing) Bounds() (min, max int) {
+	return 0, 0
+}
+
 func (self Nothing) String() string {
 	return fmt.Sprintf("<nothing>")
 }
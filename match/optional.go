@@ -0,0 +1,65 @@
+package match
+
+import "fmt"
+
+// Optional matches the empty string, along with the strings the wrapped
+// matcher matches, as extended patterns `?(...)` of bash and braces like
+// `{,.bak}` do.
+type Optional struct {
+	Matcher Matcher
+}
+
+func NewOptional(m Matcher) Optional {
+	return Optional{m}
+}
+
+func (self Optional) Match(s string) bool {
+	return len(s) == 0 || self.Matcher.Match(s)
+}
+
+func (self Optional) MatchLimit(s string, l *Limit) bool {
+	return len(s) == 0 || MatchLimit(self.Matcher, s, l)
+}
+
+// Len returns zero if the wrapped matcher matches the empty string only.
+// Otherwise, the length is not fixed.
+func (self Optional) Len() int {
+	if self.Matcher.Len() == lenZero {
+		return lenZero
+	}
+	return lenNo
+}
+
+func (self Optional) Bounds() (min, max int) {
+	_, max = bounds(self.Matcher)
+	return 0, max
+}
+
+// Index always returns 0, as the empty string is matched at the beginning of
+// any string, along with the lengths of the strings the wrapped matcher
+// matches there.
+func (self Optional) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self Optional) AppendIndex(buf []int, s string) (int, []int) {
+	buf = append(growSegments(buf, len(s)+1), 0)
+	start := len(buf)
+	index, found := AppendIndex(self.Matcher, buf, s)
+	if index != 0 {
+		return 0, found[:start]
+	}
+	if len(found) > start && found[start] == 0 {
+		// The empty string is matched by both.
+		found = append(found[:start], found[start+1:]...)
+	}
+	return 0, found
+}
+
+func (self Optional) Children() []Matcher {
+	return []Matcher{self.Matcher}
+}
+
+func (self Optional) String() string {
+	return fmt.Sprintf("<optional:%s>", self.Matcher)
+}
@@ -0,0 +1,70 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptionalMatch(t *testing.T) {
+	for id, test := range []struct {
+		matcher Matcher
+		fixture string
+		exp     bool
+	}{
+		{NewText(".bak"), "", true},
+		{NewText(".bak"), ".bak", true},
+		{NewText(".bak"), ".ba", false},
+		{NewPrefix("a"), "abc", true},
+		{NewPrefix("a"), "bc", false},
+	} {
+		m := NewOptional(test.matcher)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result: exp: %v, act: %v", id, test.exp, act)
+		}
+	}
+}
+
+func TestOptionalIndex(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Matcher
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{NewText("ab"), "abab", 0, []int{0, 2}},
+		{NewText("ab"), "xab", 0, []int{0}},
+		{NewText("ab"), "", 0, []int{0}},
+		{NewAny(nil), "abc", 0, []int{0, 1, 2, 3}},
+		{NewBTree(NewText("b"), NewSuper(), NewSuper()), "abcb", 0, []int{0, 2, 3, 4}},
+		{NewBTree(NewText("b"), NewSuper(), NewSuper()), "xyz", 0, []int{0}},
+	} {
+		m := NewOptional(test.matcher)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
+
+func TestOptionalLen(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Matcher
+		len      int
+		min, max int
+	}{
+		{NewText("ab"), -1, 0, 2},
+		{NewNothing(), 0, 0, 0},
+		{NewSuper(), -1, 0, -1},
+	} {
+		m := NewOptional(test.matcher)
+		if act := m.Len(); act != test.len {
+			t.Errorf("#%d unexpected len: exp: %d, act: %d", id, test.len, act)
+		}
+		if min, max := m.Bounds(); min != test.min || max != test.max {
+			t.Errorf("#%d unexpected bounds: exp: %d, %d, act: %d, %d", id, test.min, test.max, min, max)
+		}
+	}
+}
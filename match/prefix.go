@@ -3,7 +3,6 @@ package match
 import (
 	"fmt"
 	"strings"
-	"unicode/utf8"
 )
 
 type Prefix struct {
@@ -15,9 +14,13 @@ func NewPrefix(p string) Prefix {
 }
 
 func (self Prefix) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self Prefix) AppendIndex(buf []int, s string) (int, []int) {
 	idx := strings.Index(s, self.Prefix)
 	if idx == -1 {
-		return -1, nil
+		return -1, buf
 	}
 
 	length := len(self.Prefix)
@@ -28,19 +31,17 @@ func (self Prefix) Index(s string) (int, []int) {
 		sub = ""
 	}
 
-	segments := acquireSegments(len(sub) + 1)
-	segments = append(segments, length)
-	for i, r := range sub {
-		segments = append(segments, length+i+utf8.RuneLen(r))
-	}
-
-	return idx, segments
+	return idx, appendBoundaries(growSegments(buf, len(sub)+1), sub, length)
 }
 
 func (self Prefix) Len() int {
 	return lenNo
 }
 
+func (self Prefix) Bounds() (min, max int) {
+	return len(self.Prefix), -1
+}
+
 func (self Prefix) Match(s string) bool {
 	return strings.HasPrefix(s, self.Prefix)
 }
@@ -3,7 +3,6 @@ package match
 import (
 	"fmt"
 	"strings"
-	"unicode/utf8"
 
 	sutil "github.com/gobwas/glob/util/strings"
 )
@@ -18,9 +17,13 @@ func NewPrefixAny(s string, sep []rune) PrefixAny {
 }
 
 func (self PrefixAny) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self PrefixAny) AppendIndex(buf []int, s string) (int, []int) {
 	idx := strings.Index(s, self.Prefix)
 	if idx == -1 {
-		return -1, nil
+		return -1, buf
 	}
 
 	n := len(self.Prefix)
@@ -30,19 +33,17 @@ func (self PrefixAny) Index(s string) (int, []int) {
 		sub = sub[:i]
 	}
 
-	seg := acquireSegments(len(sub) + 1)
-	seg = append(seg, n)
-	for i, r := range sub {
-		seg = append(seg, n+i+utf8.RuneLen(r))
-	}
-
-	return idx, seg
+	return idx, appendBoundaries(growSegments(buf, len(sub)+1), sub, n)
 }
 
 func (self PrefixAny) Len() int {
 	return lenNo
 }
 
+func (self PrefixAny) Bounds() (min, max int) {
+	return len(self.Prefix), -1
+}
+
 func (self PrefixAny) Match(s string) bool {
 	if !strings.HasPrefix(s, self.Prefix) {
 		return false
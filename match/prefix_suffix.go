@@ -14,47 +14,58 @@ func NewPrefixSuffix(p, s string) PrefixSuffix {
 }
 
 func (self PrefixSuffix) Index(s string) (int, []int) {
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
+
+func (self PrefixSuffix) AppendIndex(buf []int, s string) (int, []int) {
 	prefixIdx := strings.Index(s, self.Prefix)
 	if prefixIdx == -1 {
-		return -1, nil
+		return -1, buf
 	}
 
 	suffixLen := len(self.Suffix)
 	if suffixLen <= 0 {
-		return prefixIdx, []int{len(s) - prefixIdx}
+		return prefixIdx, append(growSegments(buf, 1), len(s)-prefixIdx)
 	}
 
 	if (len(s) - prefixIdx) <= 0 {
-		return -1, nil
+		return -1, buf
 	}
 
-	segments := acquireSegments(len(s) - prefixIdx)
+	buf = growSegments(buf, len(s)-prefixIdx)
+	start := len(buf)
 	for sub := s[prefixIdx:]; ; {
 		suffixIdx := strings.LastIndex(sub, self.Suffix)
 		if suffixIdx == -1 {
 			break
 		}
 
-		segments = append(segments, suffixIdx+suffixLen)
+		buf = append(buf, suffixIdx+suffixLen)
 		sub = sub[:suffixIdx]
 	}
 
-	if len(segments) == 0 {
-		releaseSegments(segments)
-		return -1, nil
+	if len(buf) == start {
+		return -1, buf
 	}
 
-	reverseSegments(segments)
+	reverseSegments(buf[start:])
 
-	return prefixIdx, segments
+	return prefixIdx, buf
 }
 
 func (self PrefixSuffix) Len() int {
 	return lenNo
 }
 
+func (self PrefixSuffix) Bounds() (min, max int) {
+	return len(self.Prefix) + len(self.Suffix), -1
+}
+
+// Match reports whether s starts with the prefix and ends with the suffix,
+// which do not overlap, as `ab*b` does not match "ab".
 func (self PrefixSuffix) Match(s string) bool {
-	return strings.HasPrefix(s, self.Prefix) && strings.HasSuffix(s, self.Suffix)
+	return len(s) >= len(self.Prefix)+len(self.Suffix) &&
+		strings.HasPrefix(s, self.Prefix) && strings.HasSuffix(s, self.Suffix)
 }
 
 func (self PrefixSuffix) String() string {
@@ -46,6 +46,27 @@ func TestPrefixSuffixIndex(t *testing.T) {
 	}
 }
 
+func TestPrefixSuffixMatch(t *testing.T) {
+	for id, test := range []struct {
+		prefix  string
+		suffix  string
+		fixture string
+		exp     bool
+	}{
+		{"a", "c", "abc", true},
+		{"a", "c", "ac", true},
+		{"ab", "b", "ab", false},
+		{"ab", "b", "abb", true},
+		{"ab/", "/", "ab/", false},
+		{"f", "f", "f", false},
+	} {
+		m := NewPrefixSuffix(test.prefix, test.suffix)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected match of %q: exp: %v, act: %v", id, test.fixture, test.exp, act)
+		}
+	}
+}
+
 func BenchmarkIndexPrefixSuffix(b *testing.B) {
 	m := NewPrefixSuffix("qew", "sqw")
 
@@ -0,0 +1,8 @@
+//go:build race
+// +build race
+
+package match
+
+// raceEnabled reports whether tests run with the race detector, which
+// allocates memory counted by testing.AllocsPerRun.
+const raceEnabled = true
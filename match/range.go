@@ -8,10 +8,20 @@ import (
 type Range struct {
 	Lo, Hi rune
 	Not    bool
+
+	// ASCII holds the ASCII runes of the range. It is set by NewRange.
+	// Ranges with the zero ASCII, such as ones made without NewRange,
+	// compare runes with Lo and Hi.
+	ASCII asciiSet
 }
 
 func NewRange(lo, hi rune, not bool) Range {
-	return Range{lo, hi, not}
+	return Range{
+		Lo:    lo,
+		Hi:    hi,
+		Not:   not,
+		ASCII: newASCIIRange(lo, hi),
+	}
 }
 
 func (self Range) Len() int {
@@ -20,25 +30,40 @@ func (self Range) Len() int {
 
 func (self Range) Match(s string) bool {
 	r, w := utf8.DecodeRuneInString(s)
-	if len(s) > w {
+	if w == 0 || len(s) > w {
 		return false
 	}
 
-	inRange := r >= self.Lo && r <= self.Hi
+	var inRange bool
+	if 0 <= r && r < utf8.RuneSelf && self.ASCII != (asciiSet{}) {
+		inRange = self.ASCII.contains(byte(r))
+	} else {
+		inRange = r >= self.Lo && r <= self.Hi
+	}
 
 	return inRange == !self.Not
 }
 
 func (self Range) Index(s string) (int, []int) {
-	for i, r := range s {
+	if self.Hi < utf8.RuneSelf && self.ASCII != (asciiSet{}) {
+		return indexASCII(s, &self.ASCII, self.Not)
+	}
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
 		if self.Not != (r >= self.Lo && r <= self.Hi) {
-			return i, segmentsByRuneLength[utf8.RuneLen(r)]
+			return i, segmentsByRuneLength[w]
 		}
+		i += w
 	}
 
 	return -1, nil
 }
 
+func (self Range) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
 func (self Range) String() string {
 	var not string
 	if self.Not {
@@ -34,14 +34,62 @@ func TestRangeIndex(t *testing.T) {
 			3,
 			[]int{1},
 		},
+		{
+			'a', 'c',
+			true,
+			"abжd",
+			2,
+			[]int{2},
+		},
+		{
+			'x', 'я',
+			false,
+			"abжd",
+			2,
+			[]int{2},
+		},
+		{
+			'a', 'я',
+			true,
+			"a\xffb",
+			1,
+			[]int{1},
+		},
 	} {
-		m := NewRange(test.lo, test.hi, test.not)
-		index, segments := m.Index(test.fixture)
-		if index != test.index {
-			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		// Ranges made without NewRange have no tables, but match the same.
+		for _, m := range []Range{NewRange(test.lo, test.hi, test.not), {Lo: test.lo, Hi: test.hi, Not: test.not}} {
+			index, segments := m.Index(test.fixture)
+			if index != test.index {
+				t.Errorf("#%d unexpected index of %s: exp: %d, act: %d", id, m, test.index, index)
+			}
+			if !reflect.DeepEqual(segments, test.segments) {
+				t.Errorf("#%d unexpected segments of %s: exp: %v, act: %v", id, m, test.segments, segments)
+			}
 		}
-		if !reflect.DeepEqual(segments, test.segments) {
-			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+	}
+}
+
+func TestRangeMatch(t *testing.T) {
+	for id, test := range []struct {
+		lo, hi  rune
+		not     bool
+		fixture string
+		exp     bool
+	}{
+		{'a', 'z', false, "b", true},
+		{'a', 'z', false, "B", false},
+		{'a', 'z', false, "bc", false},
+		{'a', 'z', true, "ж", true},
+		{'x', 'я', false, "z", true},
+		{'x', 'я', false, "ж", true},
+		{'x', 'я', false, "a", false},
+		{'x', 'я', true, "a", true},
+		{'x', 'я', true, "", false},
+	} {
+		for _, m := range []Range{NewRange(test.lo, test.hi, test.not), {Lo: test.lo, Hi: test.hi, Not: test.not}} {
+			if act := m.Match(test.fixture); act != test.exp {
+				t.Errorf("#%d unexpected match of %q by %s: exp: %v, act: %v", id, test.fixture, m, test.exp, act)
+			}
 		}
 	}
 }
@@ -0,0 +1,229 @@
+package match
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Repeat matches strings made of at least Min and at most Max strings the
+// child matches, one after another, as extended patterns `*(...)` and
+// `+(...)` of bash, or counted repetitions, do. Max is -1 if there is no
+// such limit.
+type Repeat struct {
+	Child    Matcher
+	Min, Max int
+}
+
+func NewRepeat(child Matcher, min, max int) Repeat {
+	return Repeat{Child: child, Min: min, Max: max}
+}
+
+func (self Repeat) Match(s string) bool {
+	if n := self.Child.Len(); n > 0 {
+		return self.matchRunes(s, n)
+	}
+	r := newRepeatRun(len(s))
+	matched := r.run(self, s, true)
+	r.release()
+	return matched
+}
+
+// MatchLimit takes a step for each search of the child.
+func (self Repeat) MatchLimit(s string, l *Limit) bool {
+	r := newRepeatRun(len(s))
+	r.limit = l
+	matched := r.run(self, s, true)
+	r.release()
+	return matched
+}
+
+// matchRunes matches s, given that the child matches strings of n runes, so
+// that s is split into them at once.
+func (self Repeat) matchRunes(s string, n int) bool {
+	var count int
+	for i := 0; i < len(s); count++ {
+		if self.Max != -1 && count == self.Max {
+			return false
+		}
+		w := runesPrefix(s[i:], n)
+		if w == -1 || !self.Child.Match(s[i:i+w]) {
+			return false
+		}
+		i += w
+	}
+	return count >= self.Min
+}
+
+func (self Repeat) Len() int {
+	if self.Max == 0 {
+		return lenZero
+	}
+	if n := self.Child.Len(); n != lenNo && self.Min == self.Max {
+		return n * self.Min
+	}
+	return lenNo
+}
+
+func (self Repeat) Bounds() (min, max int) {
+	cmin, cmax := bounds(self.Child)
+	min = cmin * self.Min
+	switch {
+	case self.Max == 0:
+		return 0, 0
+	case self.Max == -1 || cmax == -1:
+		return min, -1
+	}
+	return min, cmax * self.Max
+}
+
+func (self Repeat) Index(s string) (int, []int) {
+	return acquiredIndex(self.AppendIndex(nil, s))
+}
+
+// AppendIndex returns the earliest index at which the repetition matches,
+// and the lengths of all the strings it matches there. Unless Min is zero,
+// repetitions could only start where the child matches, which is searched
+// for first.
+func (self Repeat) AppendIndex(buf []int, s string) (int, []int) {
+	r := newRepeatRun(len(s))
+	defer r.release()
+	for i := 0; i <= len(s); {
+		if self.Min > 0 {
+			start := len(buf)
+			index, found := AppendIndex(self.Child, buf, s[i:])
+			if index == -1 {
+				return -1, found
+			}
+			buf = found[:start]
+			i += index
+		}
+		r.acc.clear()
+		if r.run(self, s[i:], false) {
+			buf = growSegments(buf, len(s)-i+1)
+			for j := 0; j <= len(s)-i; j++ {
+				if r.acc.has(j) {
+					buf = append(buf, j)
+				}
+			}
+			return i, buf
+		}
+		if i == len(s) {
+			break
+		}
+		i += runesPrefix(s[i:], 1)
+	}
+	return -1, buf
+}
+
+func (self Repeat) Children() []Matcher {
+	return []Matcher{self.Child}
+}
+
+func (self Repeat) String() string {
+	return fmt.Sprintf("<repeat_%d,%d:%s>", self.Min, self.Max, self.Child)
+}
+
+// bitset is the set of positions in a string.
+type bitset []uint64
+
+func (b bitset) set(i int)      { b[i/64] |= 1 << uint(i%64) }
+func (b bitset) has(i int) bool { return b[i/64]&(1<<uint(i%64)) != 0 }
+
+func (b bitset) clear() {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// repeatRun is the state of matching of repetitions: the positions of the
+// string where the ones matched so far end.
+type repeatRun struct {
+	cur, next bitset
+	seen      bitset // ends of at least Min repetitions
+	acc       bitset // ends of the repeated strings
+	buf       []int
+	limit     *Limit
+
+	// mem is the memory of the sets, which is kept to be reused.
+	mem bitset
+}
+
+// repeatRunPool keeps the states of matching, so that matching of
+// repetitions does not allocate.
+var repeatRunPool = sync.Pool{New: func() interface{} {
+	return new(repeatRun)
+}}
+
+// newRepeatRun returns the state of matching of repetitions against strings
+// of at most n bytes.
+func newRepeatRun(n int) *repeatRun {
+	r := repeatRunPool.Get().(*repeatRun)
+	w := n/64 + 1
+	if len(r.mem) < 4*w {
+		r.mem = make(bitset, 4*w)
+	}
+	r.cur, r.next = r.mem[:w:w], r.mem[w:2*w:2*w]
+	r.seen, r.acc = r.mem[2*w:3*w:3*w], r.mem[3*w:4*w:4*w]
+	return r
+}
+
+func (r *repeatRun) release() {
+	r.limit = nil
+	repeatRunPool.Put(r)
+}
+
+// run adds the ends of the prefixes of s that the repetition matches to
+// acc, and reports whether any of them is found. If whole is set, it only
+// reports whether s is matched as a whole.
+func (r *repeatRun) run(rep Repeat, s string, whole bool) (found bool) {
+	r.cur.clear()
+	r.seen.clear()
+	r.cur.set(0)
+	for k := 0; ; k++ {
+		if k >= rep.Min {
+			if whole && r.cur.has(len(s)) {
+				return true
+			}
+			// Repetitions continued from the ends seen before with
+			// fewer of them could not match less.
+			var any bool
+			for i, w := range r.cur {
+				w &^= r.seen[i]
+				r.cur[i] = w
+				r.seen[i] |= w
+				r.acc[i] |= w
+				any = any || w != 0
+			}
+			found = found || any
+			if !any {
+				return found && !whole
+			}
+		}
+		if k == rep.Max {
+			return found && !whole
+		}
+		r.next.clear()
+		var step bool
+		for pos := 0; pos <= len(s); pos++ {
+			if !r.cur.has(pos) {
+				continue
+			}
+			if r.limit != nil && !r.limit.index() {
+				return false
+			}
+			var index int
+			index, r.buf = AppendIndex(rep.Child, r.buf[:0], s[pos:])
+			if index != 0 {
+				continue
+			}
+			for _, n := range r.buf {
+				r.next.set(pos + n)
+				step = true
+			}
+		}
+		if !step {
+			return found && !whole
+		}
+		r.cur, r.next = r.next, r.cur
+	}
+}
@@ -0,0 +1,104 @@
+package match
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRepeatMatch(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Matcher
+		min, max int
+		fixture  string
+		exp      bool
+	}{
+		{NewText("ab"), 0, -1, "", true},
+		{NewText("ab"), 0, -1, "ababab", true},
+		{NewText("ab"), 0, -1, "ababa", false},
+		{NewText("ab"), 1, -1, "", false},
+		{NewText("ab"), 2, 3, "ab", false},
+		{NewText("ab"), 2, 3, "abab", true},
+		{NewText("ab"), 2, 3, "abababab", false},
+		{NewSingle([]rune{'/'}), 1, 2, "яb", true},
+		{NewSingle([]rune{'/'}), 1, 2, "a/", false},
+		{NewSet("a", "ab", "b"), 1, -1, "abba", true},
+		{NewSet("a", "ab", "b"), 1, 2, "abba", false},
+		{NewSet("a", "ab", "b"), 1, 3, "abba", true},
+		{NewSet("a", "ab", "b"), 0, -1, "abc", false},
+		{NewPrefix("a"), 2, -1, "abcabc", true},
+		{NewPrefix("a"), 2, -1, "abc", false},
+		{NewOptional(NewText("a")), 2, -1, "", true},
+		{NewNothing(), 0, 0, "", true},
+		{NewText("a"), 0, 0, "a", false},
+	} {
+		m := NewRepeat(test.matcher, test.min, test.max)
+		if act := m.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result of %s on %q: exp: %v, act: %v", id, m, test.fixture, test.exp, act)
+		}
+		var l Limit
+		if act := m.MatchLimit(test.fixture, &l); act != test.exp || l.Aborted() {
+			t.Errorf("#%d unexpected limited result of %s on %q: exp: %v, act: %v", id, m, test.fixture, test.exp, act)
+		}
+	}
+}
+
+func TestRepeatIndex(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Matcher
+		min, max int
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{NewText("ab"), 0, -1, "xabab", 0, []int{0}},
+		{NewText("ab"), 1, -1, "xababx", 1, []int{2, 4}},
+		{NewText("ab"), 2, 2, "abxabab", 3, []int{4}},
+		{NewText("ab"), 1, -1, "xyz", -1, nil},
+		{NewSet("a", "ab", "b"), 1, 2, "xabb", 1, []int{1, 2, 3}},
+		{NewSingle(nil), 2, 3, "abcd", 0, []int{2, 3}},
+	} {
+		m := NewRepeat(test.matcher, test.min, test.max)
+		index, segments := m.Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
+
+func TestRepeatLen(t *testing.T) {
+	for id, test := range []struct {
+		matcher  Matcher
+		min, max int
+		len      int
+		bmin     int
+		bmax     int
+	}{
+		{NewText("ab"), 2, 2, 4, 4, 4},
+		{NewText("ab"), 1, 3, -1, 2, 6},
+		{NewText("ab"), 0, -1, -1, 0, -1},
+		{NewText("ab"), 0, 0, 0, 0, 0},
+		{NewSuper(), 1, 2, -1, 0, -1},
+	} {
+		m := NewRepeat(test.matcher, test.min, test.max)
+		if act := m.Len(); act != test.len {
+			t.Errorf("#%d unexpected len: exp: %d, act: %d", id, test.len, act)
+		}
+		if min, max := m.Bounds(); min != test.bmin || max != test.bmax {
+			t.Errorf("#%d unexpected bounds: exp: %d, %d, act: %d, %d", id, test.bmin, test.bmax, min, max)
+		}
+	}
+}
+
+func TestRepeatLimit(t *testing.T) {
+	m := NewRepeat(NewSet("a", "aa"), 1, -1)
+	s := strings.Repeat("a", 1000) + "b"
+
+	l := Limit{MaxSteps: 100}
+	if m.MatchLimit(s, &l) || !l.Aborted() {
+		t.Errorf("expected match to be given up")
+	}
+}
@@ -2,6 +2,7 @@ package match
 
 import (
 	"fmt"
+	"unicode/utf8"
 )
 
 type Row struct {
@@ -18,60 +19,79 @@ func NewRow(len int, m ...Matcher) Row {
 	}
 }
 
-func (self Row) matchAll(s string) bool {
+// matchAll reports whether prefix of s matches the row, and the length of
+// the prefix in bytes.
+func (self Row) matchAll(s string) (int, bool) {
 	var idx int
 	for _, m := range self.Matchers {
 		length := m.Len()
 
-		var next, i int
-		for next = range s[idx:] {
-			i++
-			if i == length {
-				break
-			}
+		// find the end of the next length runes
+		var i int
+		end := idx
+		for ; i < length && end < len(s); i++ {
+			_, w := utf8.DecodeRuneInString(s[end:])
+			end += w
 		}
 
-		if i < length || !m.Match(s[idx:idx+next+1]) {
-			return false
+		if i < length || !m.Match(s[idx:end]) {
+			return 0, false
 		}
 
-		idx += next + 1
+		idx = end
 	}
 
-	return true
+	return idx, true
 }
 
 func (self Row) lenOk(s string) bool {
-	var i int
-	for range s {
-		i++
-		if i > self.RunesLength {
-			return false
-		}
-	}
-	return self.RunesLength == i
+	return runeCount(s, self.RunesLength) == self.RunesLength
 }
 
 func (self Row) Match(s string) bool {
-	return self.lenOk(s) && self.matchAll(s)
+	if !self.lenOk(s) {
+		return false
+	}
+	_, ok := self.matchAll(s)
+	return ok
 }
 
 func (self Row) Len() (l int) {
 	return self.RunesLength
 }
 
+func (self Row) Bounds() (min, max int) {
+	for _, m := range self.Matchers {
+		mmin, mmax := bounds(m)
+		min, max = addBounds(min, max, mmin, mmax)
+	}
+	return min, max
+}
+
 func (self Row) Index(s string) (int, []int) {
 	for i := range s {
 		if len(s[i:]) < self.RunesLength {
 			break
 		}
-		if self.matchAll(s[i:]) {
-			return i, self.Segments
+		if n, ok := self.matchAll(s[i:]); ok {
+			if n == self.RunesLength {
+				return i, self.Segments
+			}
+			return i, singleSegment(n)
 		}
 	}
 	return -1, nil
 }
 
+func (self Row) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
+func (self Row) Children() []Matcher {
+	return self.Matchers
+}
+
 func (self Row) String() string {
 	return fmt.Sprintf("<row_%d:[%s]>", self.RunesLength, self.Matchers)
 }
@@ -35,6 +35,16 @@ func TestRowIndex(t *testing.T) {
 			-1,
 			nil,
 		},
+		{
+			Matchers{
+				NewText("аб"),
+				NewSingle(nil),
+			},
+			3,
+			"xабвг",
+			1,
+			[]int{6},
+		},
 	} {
 		p := NewRow(test.length, test.matchers...)
 		index, segments := p.Index(test.fixture)
@@ -47,6 +57,48 @@ func TestRowIndex(t *testing.T) {
 	}
 }
 
+func TestRowMatch(t *testing.T) {
+	for id, test := range []struct {
+		matchers Matchers
+		length   int
+		fixture  string
+		exp      bool
+	}{
+		{
+			Matchers{
+				NewText("аб"),
+				NewSingle(nil),
+			},
+			3,
+			"абв",
+			true,
+		},
+		{
+			Matchers{
+				NewSingle(nil),
+				NewText("b"),
+			},
+			2,
+			"вb",
+			true,
+		},
+		{
+			Matchers{
+				NewNothing(),
+				NewSingle(nil),
+			},
+			1,
+			"a",
+			true,
+		},
+	} {
+		p := NewRow(test.length, test.matchers...)
+		if act := p.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d Match(%q) = %t; want %t", id, test.fixture, act, test.exp)
+		}
+	}
+}
+
 func BenchmarkRowIndex(b *testing.B) {
 	m := NewRow(
 		7,
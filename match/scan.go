@@ -0,0 +1,77 @@
+package match
+
+import "unicode/utf8"
+
+// Literal parts of strings are searched for with the strings package,
+// which is vectorized on most platforms. What is left for matchers is
+// listing the runes around them, which is done here eight bytes at a time
+// while they are ASCII, as the bytes of such runs are runes on their own.
+
+// isASCII8 reports whether the eight bytes of s starting from i are ASCII.
+func isASCII8(s string, i int) bool {
+	b := s[i : i+8]
+	return (b[0]|b[1]|b[2]|b[3]|b[4]|b[5]|b[6]|b[7])&utf8.RuneSelf == 0
+}
+
+// appendBoundaries appends to buf the offsets of the runes of s, followed
+// by len(s), each added to base. Bytes which are not valid UTF-8 are runes
+// of their own, as ranging over s makes them.
+func appendBoundaries(buf []int, s string, base int) []int {
+	n := len(buf)
+	if cap(buf)-n <= len(s) {
+		grown := make([]int, n, n+len(s)+1)
+		copy(grown, buf)
+		buf = grown
+	}
+	out := buf[n : n+len(s)+1]
+	var i, j int
+	for i < len(s) {
+		if i+8 <= len(s) && isASCII8(s, i) {
+			o := out[j : j+8 : j+8]
+			o[0] = base + i
+			o[1] = base + i + 1
+			o[2] = base + i + 2
+			o[3] = base + i + 3
+			o[4] = base + i + 4
+			o[5] = base + i + 5
+			o[6] = base + i + 6
+			o[7] = base + i + 7
+			i += 8
+			j += 8
+			continue
+		}
+		out[j] = base + i
+		j++
+		if s[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+	out[j] = base + len(s)
+	return buf[:n+j+1]
+}
+
+// runesPrefix returns the length in bytes of the first n runes of s, or -1
+// if there are fewer of them.
+func runesPrefix(s string, n int) int {
+	var i int
+	for ; n > 0 && i < len(s); n-- {
+		if i+8 <= len(s) && n >= 8 && isASCII8(s, i) {
+			i += 8
+			n -= 7
+			continue
+		}
+		if s[i] < utf8.RuneSelf {
+			i++
+			continue
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+	if n > 0 {
+		return -1
+	}
+	return i
+}
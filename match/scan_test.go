@@ -0,0 +1,63 @@
+package match
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var scanFixtures = []string{
+	"",
+	"a",
+	"abcdefg",
+	"abcdefgh",
+	"abcdefghijklmnopqrstuvwxyz",
+	"aбвгдеёжзabcdefghij",
+	"abcdefgж",
+	"abc\xffdefghijk\xe2\x82",
+	strings.Repeat("ü", 10) + strings.Repeat("x", 17),
+}
+
+func TestAppendBoundaries(t *testing.T) {
+	for id, s := range scanFixtures {
+		for _, base := range []int{0, 5} {
+			exp := []int{-1}
+			for i := range s {
+				exp = append(exp, base+i)
+			}
+			exp = append(exp, base+len(s))
+
+			for _, buf := range [][]int{{-1}, append(make([]int, 0, 64), -1)} {
+				if act := appendBoundaries(buf, s, base); !reflect.DeepEqual(act, exp) {
+					t.Errorf("#%d unexpected boundaries of %q: exp: %v, act: %v", id, s, exp, act)
+				}
+			}
+		}
+	}
+}
+
+func TestRunesPrefix(t *testing.T) {
+	for id, s := range scanFixtures {
+		var offsets []int
+		for i := range s {
+			offsets = append(offsets, i)
+		}
+		offsets = append(offsets, len(s))
+		for n := 0; n <= len(offsets); n++ {
+			exp := -1
+			if n < len(offsets) {
+				exp = offsets[n]
+			}
+			if act := runesPrefix(s, n); act != exp {
+				t.Errorf("#%d unexpected length of %d runes of %q: exp: %d, act: %d", id, n, s, exp, act)
+			}
+		}
+	}
+}
+
+func BenchmarkAppendBoundaries(b *testing.B) {
+	buf := make([]int, 0, len(bench_pattern)+1)
+	for i := 0; i < b.N; i++ {
+		appendBoundaries(buf, bench_pattern, 0)
+	}
+}
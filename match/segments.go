@@ -46,6 +46,26 @@ var segmentsByRuneLength [5][]int = [5][]int{
 	4: segments4,
 }
 
+// segmentsTable holds small lengths, to be sliced into static segments by
+// singleSegment.
+var segmentsTable = func() []int {
+	t := make([]int, 256)
+	for i := range t {
+		t[i] = i
+	}
+	return t
+}()
+
+// singleSegment returns the segments of the single length n. They are
+// static, with the capacity limited so that appending copies them, unless
+// n is too large for the table.
+func singleSegment(n int) []int {
+	if n < len(segmentsTable) {
+		return segmentsTable[n : n+1 : n+1]
+	}
+	return []int{n}
+}
+
 func init() {
 	for i := cacheToAndHigher; i >= cacheFrom; i >>= 1 {
 		func(i int) {
@@ -89,3 +109,41 @@ func releaseSegments(s []int) {
 
 	segmentsPools[getTableIndex(c)].Put(s)
 }
+
+// growSegments returns buf, or segments acquired for n of them, if buf is
+// nil, as AppendIndex called by Index does.
+func growSegments(buf []int, n int) []int {
+	if buf == nil {
+		return acquireSegments(n)
+	}
+	return buf
+}
+
+// sharedSegments reports whether Index of m returns segments which are not
+// acquired, so that they need neither a buffer nor releasing.
+func sharedSegments(m Matcher) bool {
+	switch m.(type) {
+	case Text, Single, List, Range, Row, Nothing, Empty, SuffixAny, FoldText, CharClass, Grapheme, Collated:
+		return true
+	}
+	return false
+}
+
+// appendSegments returns the result of AppendIndex of a matcher whose Index
+// returns segments shared with it, which are never released.
+func appendSegments(buf []int, index int, segments []int) (int, []int) {
+	if index == -1 {
+		return -1, buf
+	}
+	return index, append(growSegments(buf, len(segments)), segments...)
+}
+
+// acquiredIndex returns the result of AppendIndex called by Index with nil
+// buffer, releasing the segments acquired if nothing is found.
+func acquiredIndex(index int, segments []int) (int, []int) {
+	if index == -1 {
+		releaseSegments(segments)
+		return -1, nil
+	}
+	return index, segments
+}
@@ -0,0 +1,121 @@
+package match
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Set matches any of the strings of the set, which it looks up in a map
+// instead of trying the strings one by one like AnyOf of Text does. A set
+// could stand for a placeholder of a pattern, see WithPlaceholder of the
+// glob package, to match one of many names inside of the pattern.
+type Set struct {
+	Strings map[string]bool
+
+	// Lengths are the ascending distinct lengths in bytes of the strings,
+	// and First tells which bytes the non-empty strings start with.
+	Lengths []int
+	First   [256]bool
+
+	// RunesLength is the length in runes of every string, or -1 if the
+	// lengths differ.
+	RunesLength int
+}
+
+func NewSet(strs ...string) Set {
+	set := Set{
+		Strings:     make(map[string]bool, len(strs)),
+		RunesLength: -1,
+	}
+	for i, s := range strs {
+		if set.Strings[s] {
+			continue
+		}
+		set.Strings[s] = true
+		set.Lengths = append(set.Lengths, len(s))
+		if s != "" {
+			set.First[s[0]] = true
+		}
+		n := utf8.RuneCountInString(s)
+		switch {
+		case i == 0:
+			set.RunesLength = n
+		case set.RunesLength != n:
+			set.RunesLength = -1
+		}
+	}
+	sort.Ints(set.Lengths)
+	set.Lengths = uniqueInts(set.Lengths)
+	return set
+}
+
+func uniqueInts(a []int) []int {
+	out := a[:0]
+	for i, x := range a {
+		if i == 0 || x != a[i-1] {
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+func (self Set) Match(s string) bool {
+	return self.Strings[s]
+}
+
+func (self Set) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self Set) AppendIndex(buf []int, s string) (int, []int) {
+	if self.Strings[""] {
+		return 0, self.appendPrefixes(buf, s)
+	}
+	start := len(buf)
+	for i := 0; i < len(s); i++ {
+		if !self.First[s[i]] {
+			continue
+		}
+		if buf = self.appendPrefixes(buf, s[i:]); len(buf) > start {
+			return i, buf
+		}
+	}
+	return -1, buf
+}
+
+// appendPrefixes appends to buf the ascending lengths of the strings of the
+// set which s starts with. Segments are acquired for nil buf only if there
+// are some.
+func (self Set) appendPrefixes(buf []int, s string) []int {
+	for _, n := range self.Lengths {
+		if n > len(s) {
+			break
+		}
+		if self.Strings[s[:n]] {
+			buf = append(growSegments(buf, len(self.Lengths)), n)
+		}
+	}
+	return buf
+}
+
+func (self Set) Len() int {
+	return self.RunesLength
+}
+
+func (self Set) Bounds() (min, max int) {
+	if len(self.Lengths) == 0 {
+		return 0, 0
+	}
+	return self.Lengths[0], self.Lengths[len(self.Lengths)-1]
+}
+
+func (self Set) String() string {
+	strs := make([]string, 0, len(self.Strings))
+	for s := range self.Strings {
+		strs = append(strs, s)
+	}
+	sort.Strings(strs)
+	return fmt.Sprintf("<set:[%s]>", strings.Join(strs, ","))
+}
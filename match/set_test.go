@@ -0,0 +1,85 @@
+package match
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestSetMatch(t *testing.T) {
+	for id, test := range []struct {
+		strs    []string
+		fixture string
+		exp     bool
+	}{
+		{[]string{"abc", "def"}, "abc", true},
+		{[]string{"abc", "def"}, "def", true},
+		{[]string{"abc", "def"}, "abcdef", false},
+		{[]string{"abc", ""}, "", true},
+		{[]string{"abc"}, "", false},
+	} {
+		if act := NewSet(test.strs...).Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result: exp: %t, act: %t", id, test.exp, act)
+		}
+	}
+}
+
+func TestSetIndex(t *testing.T) {
+	for id, test := range []struct {
+		strs     []string
+		fixture  string
+		index    int
+		segments []int
+	}{
+		{[]string{"abc", "def"}, "xxdefabc", 2, []int{3}},
+		{[]string{"ab", "a", "abc"}, "xabcd", 1, []int{1, 2, 3}},
+		{[]string{"ä", "b"}, "aäb", 1, []int{2}},
+		{[]string{"abc", "def"}, "abdeg", -1, nil},
+		{[]string{"", "a"}, "bab", 0, []int{0}},
+		{[]string{"", "a"}, "ab", 0, []int{0, 1}},
+	} {
+		index, segments := NewSet(test.strs...).Index(test.fixture)
+		if index != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, index)
+		}
+		if !reflect.DeepEqual(segments, test.segments) {
+			t.Errorf("#%d unexpected segments: exp: %v, act: %v", id, test.segments, segments)
+		}
+	}
+}
+
+func TestSetLen(t *testing.T) {
+	if n := NewSet("ab", "cd").Len(); n != 2 {
+		t.Errorf("Len() = %d; want 2", n)
+	}
+	if n := NewSet("ab", "c").Len(); n != -1 {
+		t.Errorf("Len() = %d; want -1", n)
+	}
+}
+
+func benchSetStrings(n int) []string {
+	strs := make([]string, n)
+	for i := range strs {
+		strs[i] = fmt.Sprintf("name%03d", i)
+	}
+	return strs
+}
+
+func BenchmarkIndexSet(b *testing.B) {
+	m := NewSet(benchSetStrings(100)...)
+	for i := 0; i < b.N; i++ {
+		_, s := m.Index(bench_pattern)
+		releaseSegments(s)
+	}
+}
+
+func BenchmarkIndexSetAnyOf(b *testing.B) {
+	var m AnyOf
+	for _, s := range benchSetStrings(100) {
+		m.Add(NewText(s))
+	}
+	for i := 0; i < b.N; i++ {
+		_, s := m.Index(bench_pattern)
+		releaseSegments(s)
+	}
+}
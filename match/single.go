@@ -17,7 +17,7 @@ func NewSingle(s []rune) Single {
 
 func (self Single) Match(s string) bool {
 	r, w := utf8.DecodeRuneInString(s)
-	if len(s) > w {
+	if w == 0 || len(s) > w {
 		return false
 	}
 
@@ -29,15 +29,42 @@ func (self Single) Len() int {
 }
 
 func (self Single) Index(s string) (int, []int) {
-	for i, r := range s {
+	switch {
+	case len(self.Separators) == 1 && 0 <= self.Separators[0] && self.Separators[0] < utf8.RuneSelf:
+		// Skip the separator bytewise, decoding just the rune found.
+		sep := byte(self.Separators[0])
+		for i := 0; i < len(s); i++ {
+			if s[i] == sep {
+				continue
+			}
+			if s[i] < utf8.RuneSelf {
+				return i, segmentsByRuneLength[1]
+			}
+			_, w := utf8.DecodeRuneInString(s[i:])
+			return i, segmentsByRuneLength[w]
+		}
+		return -1, nil
+
+	case isASCII(self.Separators):
+		set := newASCIISet(self.Separators)
+		return indexASCII(s, &set, true)
+	}
+	for i := 0; i < len(s); {
+		r, w := utf8.DecodeRuneInString(s[i:])
 		if runes.IndexRune(self.Separators, r) == -1 {
-			return i, segmentsByRuneLength[utf8.RuneLen(r)]
+			return i, segmentsByRuneLength[w]
 		}
+		i += w
 	}
 
 	return -1, nil
 }
 
+func (self Single) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
 func (self Single) String() string {
 	return fmt.Sprintf("<single:![%s]>", string(self.Separators))
 }
@@ -5,6 +5,24 @@ import (
 	"testing"
 )
 
+func TestSingleMatch(t *testing.T) {
+	for id, test := range []struct {
+		separators []rune
+		fixture    string
+		exp        bool
+	}{
+		{[]rune{'.'}, "a", true},
+		{[]rune{'.'}, "ä", true},
+		{[]rune{'.'}, ".", false},
+		{[]rune{'.'}, "ab", false},
+		{[]rune{'.'}, "", false},
+	} {
+		if act := NewSingle(test.separators).Match(test.fixture); act != test.exp {
+			t.Errorf("#%d unexpected result: exp: %t, act: %t", id, test.exp, act)
+		}
+	}
+}
+
 func TestSingleIndex(t *testing.T) {
 	for id, test := range []struct {
 		separators []rune
@@ -24,6 +42,24 @@ func TestSingleIndex(t *testing.T) {
 			-1,
 			nil,
 		},
+		{
+			[]rune{'.'},
+			"..ä",
+			2,
+			[]int{2},
+		},
+		{
+			[]rune{'.', '/'},
+			"/.b",
+			2,
+			[]int{1},
+		},
+		{
+			[]rune{'.', '\u00e4'},
+			".\xffb",
+			1,
+			[]int{1},
+		},
 	} {
 		p := NewSingle(test.separators)
 		index, segments := p.Index(test.fixture)
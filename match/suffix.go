@@ -17,17 +17,39 @@ func (self Suffix) Len() int {
 	return lenNo
 }
 
+func (self Suffix) Bounds() (min, max int) {
+	return len(self.Suffix), -1
+}
+
 func (self Suffix) Match(s string) bool {
 	return strings.HasSuffix(s, self.Suffix)
 }
 
 func (self Suffix) Index(s string) (int, []int) {
+	return self.AppendIndex(nil, s)
+}
+
+func (self Suffix) AppendIndex(buf []int, s string) (int, []int) {
 	idx := strings.Index(s, self.Suffix)
 	if idx == -1 {
-		return -1, nil
+		return -1, buf
+	}
+
+	// every occurrence of the suffix could be the end of the match
+	buf = growSegments(buf, len(s)+1)
+	for idx != -1 {
+		buf = append(buf, idx+len(self.Suffix))
+		if idx == len(s) {
+			break
+		}
+		next := strings.Index(s[idx+1:], self.Suffix)
+		if next == -1 {
+			break
+		}
+		idx += 1 + next
 	}
 
-	return 0, []int{idx + len(self.Suffix)}
+	return 0, buf
 }
 
 func (self Suffix) String() string {
@@ -24,13 +24,22 @@ func (self SuffixAny) Index(s string) (int, []int) {
 
 	i := sutil.LastIndexAnyRunes(s[:idx], self.Separators) + 1
 
-	return i, []int{idx + len(self.Suffix) - i}
+	return i, singleSegment(idx + len(self.Suffix) - i)
+}
+
+func (self SuffixAny) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
 }
 
 func (self SuffixAny) Len() int {
 	return lenNo
 }
 
+func (self SuffixAny) Bounds() (min, max int) {
+	return len(self.Suffix), -1
+}
+
 func (self SuffixAny) Match(s string) bool {
 	if !strings.HasSuffix(s, self.Suffix) {
 		return false
@@ -24,6 +24,18 @@ func TestSuffixIndex(t *testing.T) {
 			0,
 			[]int{5},
 		},
+		{
+			"ab",
+			"abfabab",
+			0,
+			[]int{2, 5, 7},
+		},
+		{
+			"aa",
+			"aaa",
+			0,
+			[]int{2, 3},
+		},
 	} {
 		p := NewSuffix(test.prefix)
 		index, segments := p.Index(test.fixture)
@@ -19,13 +19,11 @@ func (self Super) Len() int {
 }
 
 func (self Super) Index(s string) (int, []int) {
-	segments := acquireSegments(len(s) + 1)
-	for i := range s {
-		segments = append(segments, i)
-	}
-	segments = append(segments, len(s))
+	return self.AppendIndex(nil, s)
+}
 
-	return 0, segments
+func (self Super) AppendIndex(buf []int, s string) (int, []int) {
+	return 0, appendBoundaries(growSegments(buf, len(s)+1), s, 0)
 }
 
 func (self Super) String() string {
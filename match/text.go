@@ -2,7 +2,6 @@ package match
 
 import (
 	"fmt"
-	"strings"
 	"unicode/utf8"
 )
 
@@ -12,6 +11,9 @@ type Text struct {
 	RunesLength int
 	BytesLength int
 	Segments    []int
+
+	// Skip is the table to search long strings with, or nil.
+	Skip []int
 }
 
 func NewText(s string) Text {
@@ -20,6 +22,7 @@ func NewText(s string) Text {
 		RunesLength: utf8.RuneCountInString(s),
 		BytesLength: len(s),
 		Segments:    []int{len(s)},
+		Skip:        horspoolSkip(s),
 	}
 }
 
@@ -31,8 +34,12 @@ func (self Text) Len() int {
 	return self.RunesLength
 }
 
+func (self Text) Bounds() (min, max int) {
+	return self.BytesLength, self.BytesLength
+}
+
 func (self Text) Index(s string) (int, []int) {
-	index := strings.Index(s, self.Str)
+	index := indexSkip(s, self.Str, self.Skip)
 	if index == -1 {
 		return -1, nil
 	}
@@ -40,6 +47,11 @@ func (self Text) Index(s string) (int, []int) {
 	return index, self.Segments
 }
 
+func (self Text) AppendIndex(buf []int, s string) (int, []int) {
+	index, segments := self.Index(s)
+	return appendSegments(buf, index, segments)
+}
+
 func (self Text) String() string {
 	return fmt.Sprintf("<text:`%v`>", self.Str)
 }
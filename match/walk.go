@@ -0,0 +1,22 @@
+package match
+
+// Container is implemented by matchers that are composed of other matchers.
+type Container interface {
+	Matcher
+
+	// Children returns matchers this one is composed of.
+	Children() []Matcher
+}
+
+// Walk traverses matcher tree in depth-first order. It calls fn for m and, if
+// fn returns true, walks recursively the children of m, if it is a Container.
+func Walk(m Matcher, fn func(Matcher) bool) {
+	if m == nil || !fn(m) {
+		return
+	}
+	if c, ok := m.(Container); ok {
+		for _, child := range c.Children() {
+			Walk(child, fn)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package match
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	tree := NewBTree(
+		NewText("abc"),
+		NewAnyOf(NewText("x"), NewRow(2, NewSingle(nil), NewText("y"))),
+		NewEveryOf(NewMin(1), NewMax(3)),
+	)
+
+	var act []string
+	Walk(tree, func(m Matcher) bool {
+		act = append(act, m.String())
+		_, isAnyOf := m.(AnyOf)
+		return !isAnyOf
+	})
+	exp := []string{
+		tree.String(),
+		"<any_of:[<text:`x`>,<row_2:[<single:![]>,<text:`y`>]>]>",
+		"<text:`abc`>",
+		"<every_of:[<min:1>,<max:3>]>",
+		"<min:1>",
+		"<max:3>",
+	}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("unexpected walk order:\nact: %v\nexp: %v", act, exp)
+	}
+}
@@ -0,0 +1,145 @@
+package glob
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// WithMIMEType configures matching of media types, as in the Accept header
+// of HTTP. The type and the subtype are separated by '/', and a structured
+// syntax suffix of the subtype by '+'. Matching is case-insensitive, and
+// parameters of media types, like `; charset=utf-8`, are ignored. Strings
+// which are not media types are never matched.
+//
+// A `*` forming the whole type or subtype matches any of them, so
+// `application/*` matches "application/ld+json". Inside of a subtype it
+// does not match '+', so `application/vnd.*+json` matches
+// "application/vnd.api+json", but `application/vnd.*` does not.
+func WithMIMEType() Option {
+	return func(o *options) {
+		o.separators = []rune{'/', '+'}
+		o.caseFold = true
+		o.mimeType = true
+		o.normalize = mediaType
+	}
+}
+
+// mediaType returns the media type without parameters and surrounding
+// spaces. It returns false if s is not `type/subtype`.
+func mediaType(s string) (string, bool) {
+	if i := strings.IndexByte(s, ';'); i != -1 {
+		s = s[:i]
+	}
+	s = strings.TrimSpace(s)
+	i := strings.IndexByte(s, '/')
+	if i <= 0 || i == len(s)-1 || strings.IndexByte(s[i+1:], '/') != -1 {
+		return "", false
+	}
+	return s, true
+}
+
+// mimeTree returns the pattern tree, in which `*` forming the whole type or
+// subtype matches '+' too.
+func mimeTree(tree *ast.Node) *ast.Node {
+	nodes := []*ast.Node{tree}
+	if tree.Kind == ast.KindPattern {
+		nodes = tree.Children
+	}
+	n := ast.NewNode(ast.KindPattern, nil)
+	for i, c := range nodes {
+		if c.Kind == ast.KindAny {
+			start := i == 0 || nodes[i-1].Kind == ast.KindText && strings.HasSuffix(nodes[i-1].Value.(ast.Text).Text, "/")
+			end := i == len(nodes)-1 || nodes[i+1].Kind == ast.KindText && strings.HasPrefix(nodes[i+1].Value.(ast.Text).Text, "/")
+			if start && end {
+				c = ast.NewNode(ast.KindSuper, nil)
+			}
+		}
+		ast.Insert(n, c)
+	}
+	return n
+}
+
+// Accept is a list of media ranges of the Accept header of HTTP, along with
+// their quality values.
+type Accept struct {
+	ranges []mediaRange
+}
+
+type mediaRange struct {
+	glob        Glob
+	quality     float64
+	specificity int
+}
+
+// ParseAccept parses the value of the Accept header, like
+// `text/html, application/*;q=0.8, */*;q=0.1`. Media ranges are compiled
+// with WithMIMEType, so that they could contain wildcards anywhere, not
+// only as the whole type or subtype.
+func ParseAccept(header string) (*Accept, error) {
+	var a Accept
+	for _, s := range strings.Split(header, ",") {
+		params := strings.Split(s, ";")
+		pattern := strings.TrimSpace(params[0])
+		if pattern == "" {
+			continue
+		}
+		r := mediaRange{
+			quality:     1,
+			specificity: len(pattern) - 2*strings.Count(pattern, "*"),
+		}
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") && !strings.HasPrefix(p, "Q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(p[2:], 64)
+			if err != nil || q < 0 || q > 1 {
+				return nil, fmt.Errorf("invalid quality value %q", p)
+			}
+			r.quality = q
+		}
+		g, err := CompileWith(pattern, WithMIMEType())
+		if err != nil {
+			return nil, err
+		}
+		r.glob = g
+		a.ranges = append(a.ranges, r)
+	}
+	return &a, nil
+}
+
+// Quality returns the quality value of the media type typ, which is the
+// value of the most specific range matching it, or zero if none does. Range
+// with more literal characters is more specific, so `text/html` overrides
+// `text/*`, which overrides `*/*`.
+func (a *Accept) Quality(typ string) float64 {
+	var (
+		q    float64
+		spec = -1 << 31
+	)
+	for _, r := range a.ranges {
+		if r.specificity > spec && r.glob.Match(typ) {
+			q, spec = r.quality, r.specificity
+		}
+	}
+	return q
+}
+
+// Negotiate returns the offered media type with the highest non-zero
+// quality value. If several offers have the same quality, the first one
+// wins. It returns empty string if none of the offers is acceptable.
+func (a *Accept) Negotiate(offers ...string) string {
+	var (
+		best string
+		max  float64
+	)
+	for _, o := range offers {
+		if q := a.Quality(o); q > max {
+			best, max = o, q
+		}
+	}
+	return best
+}
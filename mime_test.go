@@ -0,0 +1,91 @@
+package glob
+
+import "testing"
+
+func TestWithMIMEType(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: "text/*",
+			match:   []string{"text/html", "Text/HTML", "text/plain; charset=utf-8", " text/x+y "},
+			miss:    []string{"text", "text/", "image/png", "text/html/x"},
+		},
+		{
+			pattern: "*/*",
+			match:   []string{"text/html", "application/ld+json"},
+			miss:    []string{"text", "/html", "a/b/c"},
+		},
+		{
+			pattern: "application/vnd.*+json",
+			match:   []string{"application/vnd.api+json", "application/VND.X+JSON;v=1"},
+			miss:    []string{"application/json", "application/vnd.a+b+json", "application/vnd.api+xml"},
+		},
+		{
+			pattern: "application/vnd.*",
+			match:   []string{"application/vnd.ms-excel"},
+			miss:    []string{"application/vnd.api+json"},
+		},
+		{
+			pattern: "*/*+json",
+			match:   []string{"application/ld+json"},
+			miss:    []string{"application/json"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithMIMEType())
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+}
+
+func TestParseAccept(t *testing.T) {
+	a, err := ParseAccept("text/html, application/*;q=0.8, application/vnd.*+json;q=0.9, */*;q=0.1, image/png;q=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		mediaType string
+		quality   float64
+	}{
+		{"text/html", 1},
+		{"text/html; level=1", 1},
+		{"application/xml", 0.8},
+		{"application/vnd.api+json", 0.9},
+		{"video/mp4", 0.1},
+		{"image/png", 0},
+	} {
+		if act := a.Quality(test.mediaType); act != test.quality {
+			t.Errorf("Quality(%q) = %v; want %v", test.mediaType, act, test.quality)
+		}
+	}
+	for _, test := range []struct {
+		offers []string
+		exp    string
+	}{
+		{[]string{"application/json", "text/html"}, "text/html"},
+		{[]string{"application/json", "application/vnd.api+json"}, "application/vnd.api+json"},
+		{[]string{"image/png"}, ""},
+		{[]string{"image/png", "image/gif"}, "image/gif"},
+	} {
+		if act := a.Negotiate(test.offers...); act != test.exp {
+			t.Errorf("Negotiate(%q) = %q; want %q", test.offers, act, test.exp)
+		}
+	}
+	if _, err := ParseAccept("text/html;q=2"); err == nil {
+		t.Errorf("ParseAccept(): expected error for invalid quality value")
+	}
+}
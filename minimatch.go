@@ -0,0 +1,191 @@
+package glob
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// Minimatch translates the glob into pattern of the minimatch and picomatch
+// JavaScript libraries, which matches the same paths when used with the
+// `{dot: true}` option. Paths are expected to be clean, that is, relative,
+// with '/' as the separator and without empty segments or trailing '/'.
+//
+// The glob must use '/' as the only separator if it contains `*`, `?` or
+// character classes, because in minimatch they never match '/'. For the
+// same reason classes must not match '/', and `**` must be a whole path
+// segment, like in `a/**/b`. Error is returned for globs which could not be
+// translated exactly.
+func Minimatch(g Glob) (string, error) {
+	c, ok := g.(*compiled)
+	if !ok {
+		return "", fmt.Errorf("could not translate %T into minimatch pattern", g)
+	}
+	w := minimatchWriter{
+		slash: len(c.separators) == 1 && c.separators[0] == '/',
+	}
+	w.node(c.tree, true, true)
+	if w.err != nil {
+		return "", fmt.Errorf("could not translate glob into minimatch pattern: %s", w.err)
+	}
+	return w.buf.String(), nil
+}
+
+type minimatchWriter struct {
+	buf   bytes.Buffer
+	slash bool // '/' is the only separator
+	err   error
+}
+
+// nodes writes sequence of nodes. The before and after flags report whether
+// the sequence starts and ends at the boundary of a path segment.
+func (w *minimatchWriter) nodes(nodes []*ast.Node, before, after bool) {
+	for i, n := range nodes {
+		b, a := before, after
+		if i > 0 {
+			b = nodes[i-1].Kind == ast.KindText && strings.HasSuffix(nodes[i-1].Value.(ast.Text).Text, "/")
+		}
+		if i < len(nodes)-1 {
+			a = nodes[i+1].Kind == ast.KindText && strings.HasPrefix(nodes[i+1].Value.(ast.Text).Text, "/")
+		}
+		w.node(n, b, a)
+	}
+}
+
+func (w *minimatchWriter) node(n *ast.Node, before, after bool) {
+	if w.err != nil {
+		return
+	}
+	switch n.Kind {
+	case ast.KindPattern:
+		w.nodes(n.Children, before, after)
+
+	case ast.KindNothing:
+
+	case ast.KindText:
+		for _, r := range n.Value.(ast.Text).Text {
+			if strings.ContainsRune(`\*?[]{},!#()+@`, r) {
+				w.buf.WriteByte('\\')
+			}
+			w.buf.WriteRune(r)
+		}
+
+	case ast.KindSuper:
+		if !before || !after {
+			w.err = fmt.Errorf("`**` is not a whole path segment")
+			return
+		}
+		// In clean paths `**` matches one or more segments, while in
+		// minimatch it matches zero or more.
+		w.buf.WriteString("**/*")
+
+	case ast.KindAny:
+		w.wildcard('*')
+
+	case ast.KindSingle:
+		w.wildcard('?')
+
+	case ast.KindList, ast.KindRange, ast.KindClass:
+		w.class(nodeSet(n))
+
+	case ast.KindAnyOf:
+		if set, ok := anyOfSet(n); ok {
+			w.class(set)
+			return
+		}
+		var alts []string
+		for _, c := range n.Children {
+			a := minimatchWriter{slash: w.slash}
+			a.node(c, before, after)
+			if a.err != nil {
+				w.err = a.err
+				return
+			}
+			alts = appendString(alts, a.buf.String())
+		}
+		switch len(alts) {
+		case 0:
+			w.err = fmt.Errorf("empty alternation never matches")
+		case 1:
+			w.buf.WriteString(alts[0])
+		default:
+			w.buf.WriteString("{" + strings.Join(alts, ",") + "}")
+		}
+
+	default:
+		w.err = fmt.Errorf("unexpected node kind %d", n.Kind)
+	}
+}
+
+func (w *minimatchWriter) wildcard(c byte) {
+	if !w.slash {
+		w.err = fmt.Errorf("`%c` requires '/' to be the only separator", c)
+		return
+	}
+	w.buf.WriteByte(c)
+}
+
+// class writes character class matching the set, which must not contain
+// '/'. Negated class is used if it is shorter.
+func (w *minimatchWriter) class(set runes.Set) {
+	if set.Contains('/') {
+		w.err = fmt.Errorf("character class matches '/'")
+		return
+	}
+	if set.Empty() {
+		w.err = fmt.Errorf("empty character class never matches")
+		return
+	}
+	not := set.Complement().Subtract(runes.Of('/'))
+	if len(not) == 0 {
+		w.buf.WriteByte('?')
+		return
+	}
+	w.buf.WriteByte('[')
+	if len(not) < len(set) {
+		w.buf.WriteByte('!')
+		set = not
+	}
+	for _, r := range set {
+		w.classRune(r.Lo)
+		if r.Hi != r.Lo {
+			w.buf.WriteByte('-')
+			w.classRune(r.Hi)
+		}
+	}
+	w.buf.WriteByte(']')
+}
+
+func (w *minimatchWriter) classRune(r rune) {
+	if strings.ContainsRune(`\[]-^!`, r) {
+		w.buf.WriteByte('\\')
+	}
+	w.buf.WriteRune(r)
+}
+
+// anyOfSet returns union of sets matched by the alternatives, if all of them
+// are character classes.
+func anyOfSet(n *ast.Node) (set runes.Set, ok bool) {
+	for _, c := range n.Children {
+		for c.Kind == ast.KindPattern && len(c.Children) == 1 {
+			c = c.Children[0]
+		}
+		if c.Kind != ast.KindList && c.Kind != ast.KindRange && c.Kind != ast.KindClass {
+			return nil, false
+		}
+		set = set.Union(nodeSet(c))
+	}
+	return set, len(n.Children) > 0
+}
+
+func appendString(list []string, s string) []string {
+	for _, x := range list {
+		if x == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
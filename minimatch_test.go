@@ -0,0 +1,64 @@
+package glob
+
+import "testing"
+
+func TestMinimatch(t *testing.T) {
+	paths := []string{
+		"a", "b", "ab", "a.go", ".go", "a/b", "a/b.go", "a/.b", "a/b/c",
+		"a/b/c.go", "x/a/b", "x/y/a", "{a}", "a,b", "!a", "#a", "a*b",
+	}
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		exp     string
+		err     bool
+	}{
+		{pattern: "*.go", opts: []Option{WithSeparators('/')}, exp: "*.go"},
+		{pattern: "a/**", exp: "a/**/*"},
+		{pattern: "**/a/**/b", exp: "**/*/a/**/*/b"},
+		{pattern: "**", exp: "**/*"},
+		{pattern: "{,**/}a", exp: "{,**/*/}a"},
+		{pattern: "a{,/**}", exp: "a{,/**/*}"},
+		{pattern: "a/?/[!a-c]", opts: FnmatchOptions(FNM_PATHNAME), exp: "a/?/[!a-c]"},
+		{pattern: "[a-c]/[xyz]", exp: "[a-c]/[x-z]"},
+		{pattern: "{a,b}{,.go}", exp: "{a,b}{,.go}"},
+		{pattern: `\{a\}`, exp: `\{a\}`},
+		{pattern: "{a,b},b", exp: `{a,b}\,b`},
+		{pattern: "!a", exp: `\!a`},
+		{pattern: `a\*b`, exp: `a\*b`},
+		{pattern: "a/**b", err: true},
+		{pattern: "*.go", err: true},
+		{pattern: "a?", opts: []Option{WithSeparators('.')}, err: true},
+		{pattern: "a[!b]", err: true},
+	} {
+		g, err := CompileWith(test.pattern, test.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		act, err := Minimatch(g)
+		if test.err {
+			if err == nil {
+				t.Errorf("#%d %q: expected error; got %q", id, test.pattern, act)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d %q: unexpected error: %s", id, test.pattern, err)
+			continue
+		}
+		if act != test.exp {
+			t.Errorf("#%d %q: Minimatch() = %q; want %q", id, test.pattern, act, test.exp)
+		}
+		// Minimatch patterns have the same semantics as doublestar ones.
+		mm, err := CompileWith(act, WithDialect(DialectDoublestar))
+		if err != nil {
+			t.Errorf("#%d %q: could not compile %q: %s", id, test.pattern, act, err)
+			continue
+		}
+		for _, p := range paths {
+			if a, b := g.Match(p), mm.Match(p); a != b {
+				t.Errorf("#%d %q: Match(%q) = %t, but %q matches %t", id, test.pattern, p, a, act, b)
+			}
+		}
+	}
+}
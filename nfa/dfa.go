@@ -0,0 +1,170 @@
+package nfa
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/util/runes"
+)
+
+// DFA is a deterministic automaton recognizing the union of a list of
+// automata, which reports the indexes of the automata accepting a string.
+// Transitions on ASCII runes are looked up in a table, so that matching is
+// a single linear scan of the string.
+//
+// Fields are exported so that the automaton could be encoded, and must not
+// be changed.
+type DFA struct {
+	// States are the states of the automaton, which starts in the state 0.
+	// There are no states if no automaton accepts any string.
+	States []DFAState
+}
+
+// DFAState is a single state of DFA.
+type DFAState struct {
+	// Accept are the ascending indexes of the automata accepting strings
+	// leading to the state.
+	Accept []int
+
+	// ASCII are the states to go to on ASCII runes, or -1 if no automaton
+	// could accept the string anymore. Other lists transitions on other
+	// runes; runes not listed there lead nowhere as well.
+	ASCII [utf8.RuneSelf]int32
+	Other []Transition
+}
+
+// Transition is a transition of DFA to the state To on the Runes.
+type Transition struct {
+	Runes runes.Set
+	To    int32
+}
+
+// NewDFA builds DFA of the union of the automata by the subset
+// construction, ignoring nil automata. Since the number of states could be
+// exponential in the size of the automata, it gives up and returns false as
+// soon as there are more than limit states.
+func NewDFA(ns []*NFA, limit int) (*DFA, bool) {
+	var (
+		b     builder
+		owner = make(map[int]int)
+		roots []int
+	)
+	for i, n := range ns {
+		if n == nil {
+			continue
+		}
+		s, e := b.embed(n)
+		owner[e] = i
+		roots = append(roots, s)
+	}
+	u := b.nfa(b.state(), b.state())
+	for e := range owner {
+		u.States[e].Epsilon = append(u.States[e].Epsilon, u.Final)
+	}
+	u.live = u.reaching(u.Final)
+
+	var (
+		d     DFA
+		sets  [][]int
+		index = make(map[string]int)
+		buf   []byte
+	)
+	// state returns the state of d corresponding to the set of live states
+	// of u, or -1 if there are none of them.
+	state := func(set []int) int {
+		live := set[:0]
+		for _, i := range set {
+			if u.live[i] {
+				live = append(live, i)
+			}
+		}
+		if len(live) == 0 {
+			return -1
+		}
+		buf = appendKey(buf[:0], -1, live)
+		if i, ok := index[string(buf)]; ok {
+			return i
+		}
+		index[string(buf)] = len(d.States)
+		st := DFAState{}
+		for _, i := range live {
+			if j, ok := owner[i]; ok {
+				st.Accept = append(st.Accept, j)
+			}
+		}
+		sort.Ints(st.Accept)
+		d.States = append(d.States, st)
+		sets = append(sets, live)
+		return len(d.States) - 1
+	}
+	cur := newSparseSet(len(u.States))
+	for _, s := range roots {
+		u.closure(cur, s)
+	}
+	if state(u.sorted(cur)) == -1 {
+		return &d, true
+	}
+	for i := 0; i < len(d.States); i++ {
+		if len(d.States) > limit {
+			return nil, false
+		}
+		st := &d.States[i]
+		for c := range st.ASCII {
+			st.ASCII[c] = -1
+		}
+		for _, a := range u.atoms(all, sets[i]) {
+			to := state(u.step(cur, sets[i], a))
+			// The states could be reallocated by the call above.
+			st = &d.States[i]
+			if to == -1 {
+				continue
+			}
+			for _, r := range a {
+				for c := r.Lo; c <= r.Hi && c < utf8.RuneSelf; c++ {
+					st.ASCII[c] = int32(to)
+				}
+			}
+			if other := a.Subtract(runes.NewSet(runes.Range{Lo: 0, Hi: utf8.RuneSelf - 1})); !other.Empty() {
+				st.Other = append(st.Other, Transition{other, int32(to)})
+			}
+		}
+	}
+	return &d, true
+}
+
+// Match returns the ascending indexes of the automata accepting s. The
+// returned slice must not be changed.
+func (d *DFA) Match(s string) []int {
+	if len(d.States) == 0 {
+		return nil
+	}
+	var st int32
+	for i := 0; i < len(s); {
+		var w int
+		if st, w = d.Step(st, s[i:]); st == -1 {
+			return nil
+		}
+		i += w
+	}
+	return d.States[st].Accept
+}
+
+// Step returns the state the automaton goes to from the state st on the
+// first rune of s, which must not be empty, along with the width of the
+// rune. The state is -1 if no automaton could accept the string anymore.
+func (d *DFA) Step(st int32, s string) (int32, int) {
+	if c := s[0]; c < utf8.RuneSelf {
+		return d.States[st].ASCII[c], 1
+	}
+	r, w := decode(s)
+	return d.States[st].next(r), w
+}
+
+func (s *DFAState) next(r rune) int32 {
+	for _, t := range s.Other {
+		if t.Runes.Contains(r) {
+			return t.To
+		}
+	}
+	return -1
+}
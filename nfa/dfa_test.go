@@ -0,0 +1,48 @@
+package nfa
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gobwas/glob/syntax"
+)
+
+func TestDFA(t *testing.T) {
+	patterns := []string{"*.go", "main.*", "**/node_modules/**", "[a-c]?", "", "ü*", "{x,y}z"}
+	ns := make([]*NFA, len(patterns)+1)
+	for i, p := range patterns {
+		tree, err := syntax.Parse(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ns[i] = New(tree.Tree, []rune{'/'})
+	}
+	d, ok := NewDFA(ns, 1000)
+	if !ok {
+		t.Fatalf("NewDFA() gave up")
+	}
+	for _, s := range []string{
+		"main.go", "a.go", "x/a.go", "a/node_modules/b", "ab", "a/", "", "über", "ü.go",
+		"xz", "yz", "zz", "\xff", "main.ü",
+	} {
+		var want []int
+		for i, n := range ns {
+			if n != nil && n.Match(s) {
+				want = append(want, i)
+			}
+		}
+		if act := d.Match(s); !reflect.DeepEqual(act, want) {
+			t.Errorf("Match(%q) = %v; want %v", s, act, want)
+		}
+	}
+
+	if d, ok := NewDFA(nil, 10); !ok || d.Match("") != nil {
+		t.Errorf("unexpected DFA of no automata")
+	}
+	// The DFA of `*a?????????` has a state for every combination of the
+	// last ten runes being 'a'.
+	tree, _ := syntax.Parse("*a?????????")
+	if _, ok := NewDFA([]*NFA{New(tree.Tree, nil)}, 100); ok {
+		t.Errorf("NewDFA() should give up")
+	}
+}
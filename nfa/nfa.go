@@ -0,0 +1,939 @@
+// Package nfa implements nondeterministic finite automata built from glob
+// syntax trees. Automata are not used for matching itself, but for
+// answering questions about the whole set of strings a pattern matches.
+package nfa
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// State is a single state of an automaton. It has either a transition to the
+// Next state labeled with the set of Runes, or any number of Epsilon
+// transitions. Next is -1 if there is no labeled transition.
+type State struct {
+	Runes   runes.Set
+	Next    int
+	Epsilon []int
+}
+
+// Invalid is what automata read for a byte which is not valid UTF-8. It is
+// not a rune of Unicode, so that texts of patterns never accept it, while
+// wildcards and classes, which see such bytes as utf8.RuneError, accept it
+// along with the rune.
+const Invalid = utf8.MaxRune + 1
+
+// all is the set of everything automata read.
+var all = runes.NewSet(runes.Range{Lo: 0, Hi: Invalid})
+
+// decode returns what automata read at the start of s, along with its width
+// in bytes.
+func decode(s string) (rune, int) {
+	r, w := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError && w == 1 {
+		return Invalid, 1
+	}
+	return r, w
+}
+
+// class returns the set of a wildcard or a class, which accepts Invalid if
+// it holds utf8.RuneError.
+func class(set runes.Set) runes.Set {
+	if set.Contains(utf8.RuneError) {
+		return set.Union(runes.Of(Invalid))
+	}
+	return set
+}
+
+// NFA is a Thompson automaton with a single Start and a single Final state.
+type NFA struct {
+	States []State
+	Start  int
+	Final  int
+
+	// live marks states from which the Final state is reachable.
+	live []bool
+}
+
+// wireNFA is the part of NFA encoded by GobEncode, from which the rest is
+// derived.
+type wireNFA struct {
+	States []State
+	Start  int
+	Final  int
+}
+
+// GobEncode encodes the automaton for encoding/gob.
+func (n *NFA) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(wireNFA{n.States, n.Start, n.Final})
+	return buf.Bytes(), err
+}
+
+// GobDecode decodes the automaton encoded by GobEncode.
+func (n *NFA) GobDecode(data []byte) error {
+	var w wireNFA
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&w); err != nil {
+		return err
+	}
+	if w.Start < 0 || w.Start >= len(w.States) || w.Final < 0 || w.Final >= len(w.States) {
+		return errors.New("malformed automaton")
+	}
+	n.States, n.Start, n.Final = w.States, w.Start, w.Final
+	n.live = n.reaching(n.Final)
+	return nil
+}
+
+// New builds automaton recognizing the same strings as the glob described by
+// tree and compiled with given separators.
+func New(tree *ast.Node, separators []rune) *NFA {
+	b := builder{
+		sep: class(runes.Of(separators...).Complement()),
+	}
+	return b.nfa(b.build(tree))
+}
+
+// Union returns automaton accepting the strings accepted by any of the
+// automata.
+func Union(ns ...*NFA) *NFA {
+	var b builder
+	start, end := b.state(), b.state()
+	for _, n := range ns {
+		s, e := b.embed(n)
+		b.epsilon(start, s)
+		b.epsilon(e, end)
+	}
+	return b.nfa(start, end)
+}
+
+// Concat returns automaton accepting the concatenations of strings accepted
+// by each of the automata in order.
+func Concat(ns ...*NFA) *NFA {
+	var b builder
+	start := b.state()
+	end := start
+	for _, n := range ns {
+		s, e := b.embed(n)
+		b.epsilon(end, s)
+		end = e
+	}
+	return b.nfa(start, end)
+}
+
+// Intersect returns automaton accepting the strings accepted by all of the
+// automata. It is built as the product of the automata, the states of which
+// are tuples of their states.
+func Intersect(ns ...*NFA) *NFA {
+	if len(ns) == 0 {
+		var b builder
+		return b.nfa(b.loop(all))
+	}
+	n := ns[0]
+	for _, m := range ns[1:] {
+		n = n.intersect(m)
+	}
+	return n
+}
+
+func (n *NFA) intersect(m *NFA) *NFA {
+	type pair struct{ i, j int }
+	var (
+		b     builder
+		index = make(map[pair]int)
+		queue []pair
+	)
+	state := func(p pair) int {
+		if k, ok := index[p]; ok {
+			return k
+		}
+		k := b.state()
+		index[p] = k
+		queue = append(queue, p)
+		return k
+	}
+	start := state(pair{n.Start, m.Start})
+	end := state(pair{n.Final, m.Final})
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		k := index[p]
+		if !n.live[p.i] || !m.live[p.j] {
+			continue
+		}
+		x, y := &n.States[p.i], &m.States[p.j]
+		for _, e := range x.Epsilon {
+			b.epsilon(k, state(pair{e, p.j}))
+		}
+		for _, e := range y.Epsilon {
+			b.epsilon(k, state(pair{p.i, e}))
+		}
+		if x.Next != -1 && y.Next != -1 {
+			if set := x.Runes.Intersect(y.Runes); !set.Empty() {
+				next := state(pair{x.Next, y.Next})
+				b.states[k].Runes = set
+				b.states[k].Next = next
+			}
+		}
+	}
+	return b.nfa(start, end)
+}
+
+// Match reports whether the automaton accepts s.
+func (n *NFA) Match(s string) bool {
+	sets := acquireRunSets(len(n.States))
+	cur := n.run(s, sets)
+	matched := cur != nil && cur.contains(n.Final)
+	runSetsPool.Put(sets)
+	return matched
+}
+
+// MatchPrefix reports whether the automaton accepts some string starting
+// with s.
+func (n *NFA) MatchPrefix(s string) bool {
+	sets := acquireRunSets(len(n.States))
+	defer runSetsPool.Put(sets)
+	cur := n.run(s, sets)
+	if cur == nil {
+		return false
+	}
+	for _, i := range cur.dense {
+		if n.live[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Prefixes returns the ascending lengths in bytes of the prefixes of s the
+// automaton accepts. It reads every rune of s at most once, tracking all
+// states the automaton could be in, so it takes time linear in the length
+// of s.
+func (n *NFA) Prefixes(s string) []int {
+	var ends []int
+	cur := newSparseSet(len(n.States))
+	next := newSparseSet(len(n.States))
+	n.closure(cur, n.Start)
+	if cur.contains(n.Final) {
+		ends = append(ends, 0)
+	}
+	for i := 0; i < len(s); {
+		r, w := decode(s[i:])
+		i += w
+		next.clear()
+		for _, j := range cur.dense {
+			st := &n.States[j]
+			if st.Next != -1 && n.live[st.Next] && st.Runes.Contains(r) {
+				n.closure(next, st.Next)
+			}
+		}
+		if len(next.dense) == 0 {
+			break
+		}
+		cur, next = next, cur
+		if cur.contains(n.Final) {
+			ends = append(ends, i)
+		}
+	}
+	return ends
+}
+
+// run returns the set of states the automaton is in after reading s, or nil
+// if no states are left. It tracks the states with the given sets, one of
+// which is returned.
+func (n *NFA) run(s string, sets *runSets) *sparseSet {
+	cur, next := &sets[0], &sets[1]
+	n.closure(cur, n.Start)
+	for i := 0; i < len(s); {
+		r, w := decode(s[i:])
+		i += w
+		next.clear()
+		for _, i := range cur.dense {
+			st := &n.States[i]
+			if st.Next != -1 && st.Runes.Contains(r) {
+				n.closure(next, st.Next)
+			}
+		}
+		if len(next.dense) == 0 {
+			return nil
+		}
+		cur, next = next, cur
+	}
+	return cur
+}
+
+// Intersects reports whether some string is accepted by both automata. It
+// walks the product automaton, the states of which are pairs of states of n
+// and m.
+func (n *NFA) Intersects(m *NFA) bool {
+	type pair struct{ i, j int }
+	var (
+		seen  = make(map[pair]bool)
+		queue []pair
+	)
+	push := func(p pair) {
+		if !seen[p] && n.live[p.i] && m.live[p.j] {
+			seen[p] = true
+			queue = append(queue, p)
+		}
+	}
+	push(pair{n.Start, m.Start})
+	for len(queue) > 0 {
+		p := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if p.i == n.Final && p.j == m.Final {
+			return true
+		}
+		a, b := &n.States[p.i], &m.States[p.j]
+		for _, e := range a.Epsilon {
+			push(pair{e, p.j})
+		}
+		for _, e := range b.Epsilon {
+			push(pair{p.i, e})
+		}
+		if a.Next != -1 && b.Next != -1 && !a.Runes.Intersect(b.Runes).Empty() {
+			push(pair{a.Next, b.Next})
+		}
+	}
+	return false
+}
+
+// Includes reports whether every string accepted by m is accepted by n as
+// well. It walks the product of m and the deterministic automaton built
+// from n by the subset construction, looking for a string accepted by m
+// only.
+func (n *NFA) Includes(m *NFA) bool {
+	type item struct {
+		j   int
+		set []int
+	}
+	var (
+		seen  = make(map[string]bool)
+		queue []item
+		buf   []byte
+	)
+	push := func(j int, set []int) {
+		buf = appendKey(buf[:0], j, set)
+		if !seen[string(buf)] {
+			seen[string(buf)] = true
+			queue = append(queue, item{j, set})
+		}
+	}
+	cur := newSparseSet(len(n.States))
+	n.closure(cur, n.Start)
+	push(m.Start, n.sorted(cur))
+	for len(queue) > 0 {
+		it := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if !m.live[it.j] {
+			continue
+		}
+		if it.j == m.Final && !contains(it.set, n.Final) {
+			return false
+		}
+		b := &m.States[it.j]
+		for _, e := range b.Epsilon {
+			push(e, it.set)
+		}
+		if b.Next == -1 {
+			continue
+		}
+		for _, a := range n.atoms(b.Runes, it.set) {
+			push(b.Next, n.step(cur, it.set, a))
+		}
+	}
+	return true
+}
+
+// Complement returns automaton accepting exactly the strings n does not
+// accept. It is built from the minimal deterministic automaton, which is
+// made by the subset construction, so its size could be exponential in the
+// size of n.
+func (n *NFA) Complement() *NFA {
+	d := n.determinize().minimize()
+	var b builder
+	for range d {
+		b.state()
+	}
+	end := b.state()
+	for i, st := range d {
+		if !st.final {
+			b.epsilon(i, end)
+		}
+		for _, e := range st.edges {
+			s, x := b.runes(e.runes)
+			b.epsilon(i, s)
+			b.epsilon(x, e.to)
+		}
+	}
+	return b.nfa(0, end)
+}
+
+// dfa is a deterministic automaton, which starts in the state 0. Every
+// state has transitions on all runes.
+type dfa []dfaState
+
+type dfaState struct {
+	final bool
+	edges []dfaEdge
+}
+
+type dfaEdge struct {
+	runes runes.Set
+	to    int
+}
+
+// determinize builds the deterministic automaton accepting the same strings
+// as n by the subset construction.
+func (n *NFA) determinize() dfa {
+	var (
+		d     dfa
+		sets  [][]int
+		index = make(map[string]int)
+		buf   []byte
+	)
+	// state returns the state of d corresponding to the set of states of n.
+	state := func(set []int) int {
+		buf = appendKey(buf[:0], -1, set)
+		if i, ok := index[string(buf)]; ok {
+			return i
+		}
+		index[string(buf)] = len(d)
+		d = append(d, dfaState{final: contains(set, n.Final)})
+		sets = append(sets, set)
+		return len(d) - 1
+	}
+	cur := newSparseSet(len(n.States))
+	n.closure(cur, n.Start)
+	state(n.sorted(cur))
+	for i := 0; i < len(d); i++ {
+		var edges []dfaEdge
+		for _, a := range n.atoms(all, sets[i]) {
+			edges = addEdge(edges, a, state(n.step(cur, sets[i], a)))
+		}
+		d[i].edges = edges
+	}
+	return d
+}
+
+// addEdge adds the transition on the runes to the edges, merging it with
+// the transition to the same state, if any.
+func addEdge(edges []dfaEdge, set runes.Set, to int) []dfaEdge {
+	for i := range edges {
+		if edges[i].to == to {
+			edges[i].runes = edges[i].runes.Union(set)
+			return edges
+		}
+	}
+	return append(edges, dfaEdge{set, to})
+}
+
+// minimize returns the minimal automaton accepting the same strings as d.
+// Equivalent states are found by the Moore algorithm, which splits blocks
+// of states until all states of a block have transitions on the same runes
+// to the same blocks.
+func (d dfa) minimize() dfa {
+	block := make([]int, len(d))
+	count := 1
+	for i, st := range d {
+		if st.final != d[0].final {
+			block[i] = 1
+			count = 2
+		}
+	}
+	var buf []byte
+	for {
+		index := make(map[string]int)
+		next := make([]int, len(d))
+		for i, st := range d {
+			var edges []dfaEdge
+			for _, e := range st.edges {
+				edges = addEdge(edges, e.runes, block[e.to])
+			}
+			sort.Sort(byTarget(edges))
+			buf = strconv.AppendInt(buf[:0], int64(block[i]), 10)
+			for _, e := range edges {
+				buf = append(buf, ';')
+				buf = strconv.AppendInt(buf, int64(e.to), 10)
+				for _, r := range e.runes {
+					buf = append(buf, ',')
+					buf = strconv.AppendInt(buf, int64(r.Lo), 10)
+					buf = append(buf, '-')
+					buf = strconv.AppendInt(buf, int64(r.Hi), 10)
+				}
+			}
+			j, ok := index[string(buf)]
+			if !ok {
+				j = len(index)
+				index[string(buf)] = j
+			}
+			next[i] = j
+		}
+		block = next
+		if len(index) == count {
+			break
+		}
+		count = len(index)
+	}
+	m := make(dfa, count)
+	done := make([]bool, count)
+	for i, st := range d {
+		j := block[i]
+		if done[j] {
+			continue
+		}
+		done[j] = true
+		m[j].final = st.final
+		for _, e := range st.edges {
+			m[j].edges = addEdge(m[j].edges, e.runes, block[e.to])
+		}
+	}
+	return m
+}
+
+type byTarget []dfaEdge
+
+func (s byTarget) Len() int           { return len(s) }
+func (s byTarget) Less(i, j int) bool { return s[i].to < s[j].to }
+func (s byTarget) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// atoms splits the base set of runes into atoms, each of which is either
+// inside or outside of the runes of every transition from the set of
+// states, so that all the runes of an atom lead to the same states.
+func (n *NFA) atoms(base runes.Set, set []int) []runes.Set {
+	atoms := []runes.Set{base}
+	for _, i := range set {
+		if n.States[i].Next == -1 {
+			continue
+		}
+		t := n.States[i].Runes
+		var split []runes.Set
+		for _, a := range atoms {
+			if in := a.Intersect(t); !in.Empty() {
+				split = append(split, in)
+			}
+			if out := a.Subtract(t); !out.Empty() {
+				split = append(split, out)
+			}
+		}
+		atoms = split
+	}
+	return atoms
+}
+
+// step returns the sorted set of states the automaton is in after reading a
+// rune of the atom in the set of states. The cur set is used as a buffer.
+func (n *NFA) step(cur *sparseSet, set []int, atom runes.Set) []int {
+	cur.clear()
+	for _, i := range set {
+		st := &n.States[i]
+		if st.Next != -1 && !atom.Intersect(st.Runes).Empty() {
+			n.closure(cur, st.Next)
+		}
+	}
+	return n.sorted(cur)
+}
+
+// minRuneLen returns the length in bytes of the shortest rune of the set.
+// The sets holding Invalid accept single bytes.
+func minRuneLen(set runes.Set) int {
+	if set.Contains(Invalid) {
+		return 1
+	}
+	return utf8.RuneLen(set[0].Lo)
+}
+
+// maxRuneLen returns the length in bytes of the longest rune of the set.
+func maxRuneLen(set runes.Set) int {
+	r := set[len(set)-1]
+	switch {
+	case r.Hi < Invalid:
+		return utf8.RuneLen(r.Hi)
+	case r.Lo < Invalid:
+		return utf8.RuneLen(Invalid - 1)
+	case len(set) > 1:
+		return utf8.RuneLen(set[len(set)-2].Hi)
+	}
+	return 1
+}
+
+// LenBounds returns the minimum and the maximum length in bytes of accepted
+// strings. The maximum is -1 if there is no limit. Both are -1 if the
+// automaton accepts nothing.
+func (n *NFA) LenBounds() (min, max int) {
+	if !n.live[n.Start] {
+		return -1, -1
+	}
+	// Only states on the paths from the Start to the Final state matter.
+	reach := n.reachable()
+	useful := func(i int) bool { return reach[i] && n.live[i] }
+
+	// Minimum is found by the Dijkstra algorithm, weighting transitions
+	// by the shortest encoding of their runes.
+	dist := make([]int, len(n.States))
+	for i := range dist {
+		dist[i] = -1
+	}
+	done := make([]bool, len(n.States))
+	dist[n.Start] = 0
+	for {
+		u := -1
+		for i, d := range dist {
+			if d != -1 && !done[i] && (u == -1 || d < dist[u]) {
+				u = i
+			}
+		}
+		if u == -1 || u == n.Final {
+			break
+		}
+		done[u] = true
+		relax := func(v, w int) {
+			if useful(v) && (dist[v] == -1 || dist[u]+w < dist[v]) {
+				dist[v] = dist[u] + w
+			}
+		}
+		st := &n.States[u]
+		for _, e := range st.Epsilon {
+			relax(e, 0)
+		}
+		if st.Next != -1 && !st.Runes.Empty() {
+			relax(st.Next, minRuneLen(st.Runes))
+		}
+	}
+	min = dist[n.Final]
+
+	// Maximum is the longest path, which exists only if the useful part
+	// of the automaton has no cycles.
+	const (
+		white = iota
+		grey
+		black
+	)
+	color := make([]int, len(n.States))
+	longest := make([]int, len(n.States))
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch color[i] {
+		case grey:
+			return false
+		case black:
+			return true
+		}
+		color[i] = grey
+		st := &n.States[i]
+		for _, e := range st.Epsilon {
+			if !useful(e) {
+				continue
+			}
+			if !visit(e) {
+				return false
+			}
+			if longest[e] > longest[i] {
+				longest[i] = longest[e]
+			}
+		}
+		if st.Next != -1 && !st.Runes.Empty() && useful(st.Next) {
+			if !visit(st.Next) {
+				return false
+			}
+			w := maxRuneLen(st.Runes)
+			if longest[st.Next]+w > longest[i] {
+				longest[i] = longest[st.Next] + w
+			}
+		}
+		color[i] = black
+		return true
+	}
+	if !visit(n.Start) {
+		return min, -1
+	}
+	return min, longest[n.Start]
+}
+
+// reachable returns states reachable from the Start state.
+func (n *NFA) reachable() []bool {
+	seen := make([]bool, len(n.States))
+	seen[n.Start] = true
+	stack := []int{n.Start}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		st := &n.States[i]
+		next := st.Epsilon
+		if st.Next != -1 && !st.Runes.Empty() {
+			next = append(next[:len(next):len(next)], st.Next)
+		}
+		for _, j := range next {
+			if !seen[j] {
+				seen[j] = true
+				stack = append(stack, j)
+			}
+		}
+	}
+	return seen
+}
+
+// appendKey appends to buf the key identifying the state j along with the
+// sorted set of states.
+func appendKey(buf []byte, j int, set []int) []byte {
+	buf = strconv.AppendInt(buf, int64(j), 10)
+	for _, i := range set {
+		buf = append(buf, ',')
+		buf = strconv.AppendInt(buf, int64(i), 10)
+	}
+	return buf
+}
+
+// sorted returns the states of the set in ascending order.
+func (n *NFA) sorted(set *sparseSet) []int {
+	s := append([]int(nil), set.dense...)
+	sort.Ints(s)
+	return s
+}
+
+func contains(set []int, i int) bool {
+	for _, j := range set {
+		if j == i {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *NFA) closure(set *sparseSet, i int) {
+	if !set.add(i) {
+		return
+	}
+	for _, e := range n.States[i].Epsilon {
+		n.closure(set, e)
+	}
+}
+
+// reaching returns states from which the target state is reachable.
+func (n *NFA) reaching(target int) []bool {
+	preds := make([][]int, len(n.States))
+	for i, st := range n.States {
+		if st.Next != -1 && !st.Runes.Empty() {
+			preds[st.Next] = append(preds[st.Next], i)
+		}
+		for _, e := range st.Epsilon {
+			preds[e] = append(preds[e], i)
+		}
+	}
+	seen := make([]bool, len(n.States))
+	seen[target] = true
+	queue := []int{target}
+	for len(queue) > 0 {
+		i := queue[0]
+		queue = queue[1:]
+		for _, p := range preds[i] {
+			if !seen[p] {
+				seen[p] = true
+				queue = append(queue, p)
+			}
+		}
+	}
+	return seen
+}
+
+type builder struct {
+	states []State
+	sep    runes.Set // set of non-separator runes
+}
+
+// nfa returns the automaton of the built states.
+func (b *builder) nfa(start, final int) *NFA {
+	n := &NFA{
+		States: b.states,
+		Start:  start,
+		Final:  final,
+	}
+	n.live = n.reaching(final)
+	return n
+}
+
+// embed copies the states of the automaton and returns the copies of its
+// Start and Final states.
+func (b *builder) embed(n *NFA) (start, end int) {
+	offset := len(b.states)
+	for _, st := range n.States {
+		if st.Next != -1 {
+			st.Next += offset
+		}
+		eps := make([]int, len(st.Epsilon))
+		for i, e := range st.Epsilon {
+			eps[i] = e + offset
+		}
+		st.Epsilon = eps
+		b.states = append(b.states, st)
+	}
+	return n.Start + offset, n.Final + offset
+}
+
+func (b *builder) state() int {
+	b.states = append(b.states, State{Next: -1})
+	return len(b.states) - 1
+}
+
+func (b *builder) epsilon(from, to int) {
+	b.states[from].Epsilon = append(b.states[from].Epsilon, to)
+}
+
+func (b *builder) runes(set runes.Set) (start, end int) {
+	start, end = b.state(), b.state()
+	b.states[start].Runes = set
+	b.states[start].Next = end
+	return
+}
+
+func (b *builder) loop(set runes.Set) (start, end int) {
+	start, end = b.state(), b.state()
+	s, e := b.runes(set)
+	b.epsilon(start, s)
+	b.epsilon(start, end)
+	b.epsilon(e, start)
+	return
+}
+
+func (b *builder) build(tree *ast.Node) (start, end int) {
+	switch tree.Kind {
+	case ast.KindPattern:
+		start = b.state()
+		end = start
+		for _, c := range tree.Children {
+			s, e := b.build(c)
+			b.epsilon(end, s)
+			end = e
+		}
+		return
+
+	case ast.KindAnyOf:
+		start, end = b.state(), b.state()
+		for _, c := range tree.Children {
+			s, e := b.build(c)
+			b.epsilon(start, s)
+			b.epsilon(e, end)
+		}
+		return
+
+	case ast.KindText:
+		start = b.state()
+		end = start
+		for _, r := range tree.Value.(ast.Text).Text {
+			s, e := b.runes(runes.Of(r))
+			b.epsilon(end, s)
+			end = e
+		}
+		return
+
+	case ast.KindAny:
+		return b.loop(b.sep)
+
+	case ast.KindSuper:
+		return b.loop(all)
+
+	case ast.KindPlaceholder:
+		// Nothing is known about strings custom matchers match, so any
+		// string is accepted.
+		return b.loop(all)
+
+	case ast.KindSingle:
+		return b.runes(b.sep)
+
+	case ast.KindList:
+		l := tree.Value.(ast.List)
+		set := runes.Of([]rune(l.Chars)...)
+		if l.Not {
+			set = set.Complement()
+		}
+		return b.runes(class(set))
+
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		set := runes.NewSet(runes.Range{Lo: r.Lo, Hi: r.Hi})
+		if r.Not {
+			set = set.Complement()
+		}
+		return b.runes(class(set))
+
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		set := c.Set()
+		if c.Not {
+			set = set.Complement()
+		}
+		return b.runes(class(set))
+
+	default:
+		// KindNothing matches only the empty string.
+		start = b.state()
+		return start, start
+	}
+}
+
+// sparseSet is a set of states with constant time insertion, lookup and
+// clearing, which preserves the order of insertion.
+type sparseSet struct {
+	dense  []int
+	sparse []int
+}
+
+func newSparseSet(n int) *sparseSet {
+	return &sparseSet{
+		dense:  make([]int, 0, n),
+		sparse: make([]int, n),
+	}
+}
+
+// runSets are the sets of states run tracks, which are pooled, so that
+// matching does not allocate.
+type runSets [2]sparseSet
+
+var runSetsPool = sync.Pool{New: func() interface{} {
+	return new(runSets)
+}}
+
+// acquireRunSets returns empty sets for n states from the pool.
+func acquireRunSets(n int) *runSets {
+	sets := runSetsPool.Get().(*runSets)
+	for i := range sets {
+		sets[i].reset(n)
+	}
+	return sets
+}
+
+// reset empties the set and makes room for n states in it. States left in
+// sparse need not be cleared, as they are checked against dense.
+func (s *sparseSet) reset(n int) {
+	if cap(s.sparse) < n {
+		*s = *newSparseSet(n)
+		return
+	}
+	s.sparse = s.sparse[:n]
+	s.dense = s.dense[:0]
+}
+
+func (s *sparseSet) contains(i int) bool {
+	j := s.sparse[i]
+	return j < len(s.dense) && s.dense[j] == i
+}
+
+func (s *sparseSet) add(i int) bool {
+	if s.contains(i) {
+		return false
+	}
+	s.sparse[i] = len(s.dense)
+	s.dense = append(s.dense, i)
+	return true
+}
+
+func (s *sparseSet) clear() {
+	s.dense = s.dense[:0]
+}
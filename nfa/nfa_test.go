@@ -0,0 +1,250 @@
+package nfa
+
+import (
+	"testing"
+
+	"github.com/gobwas/glob/syntax"
+)
+
+func TestMatch(t *testing.T) {
+	for id, test := range []struct {
+		pattern    string
+		separators []rune
+		fixture    string
+		exp        bool
+	}{
+		{"", nil, "", true},
+		{"", nil, "a", false},
+		{"abc", nil, "abc", true},
+		{"abc", nil, "ab", false},
+		{"a*c", nil, "abbbc", true},
+		{"a*c", []rune{'/'}, "ab/bc", false},
+		{"a**c", []rune{'/'}, "ab/bc", true},
+		{"a?c", []rune{'/'}, "a/c", false},
+		{"[a-c]x", nil, "bx", true},
+		{"[!a-c]x", nil, "bx", false},
+		{"[abc]x", nil, "cx", true},
+		{"[!abc]x", nil, "dx", true},
+		{"{cat,dog}s", nil, "dogs", true},
+		{"{cat,dog}s", nil, "cows", false},
+		{"*{,.txt}", []rune{'.'}, "a.txt", true},
+		{"*{,.txt}", []rune{'.'}, "a.b", false},
+	} {
+		p, err := syntax.Parse(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := New(p.Tree, test.separators)
+		if act := n.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d %q Match(%q) = %t; want %t", id, test.pattern, test.fixture, act, test.exp)
+		}
+	}
+}
+
+func TestMatchPrefix(t *testing.T) {
+	for id, test := range []struct {
+		pattern    string
+		separators []rune
+		fixture    string
+		exp        bool
+	}{
+		{"abc", nil, "", true},
+		{"abc", nil, "ab", true},
+		{"abc", nil, "abc", true},
+		{"abc", nil, "abcd", false},
+		{"src/**/*.go", []rune{'/'}, "src/", true},
+		{"src/**/*.go", []rune{'/'}, "src/a/b/", true},
+		{"src/**/*.go", []rune{'/'}, "build/tmp/", false},
+		{"src/*.go", []rune{'/'}, "src/a/", false},
+		{"src/*.go", []rune{'/'}, "src/a", true},
+		{"{a,b}/*", []rune{'/'}, "c", false},
+		{"a[!\x01-\U0010ffff]", nil, "a\x00", true},
+	} {
+		p, err := syntax.Parse(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := New(p.Tree, test.separators)
+		if act := n.MatchPrefix(test.fixture); act != test.exp {
+			t.Errorf("#%d %q MatchPrefix(%q) = %t; want %t", id, test.pattern, test.fixture, act, test.exp)
+		}
+	}
+}
+
+func TestIntersects(t *testing.T) {
+	for id, test := range []struct {
+		a, b       string
+		separators []rune
+		exp        bool
+	}{
+		{"", "", nil, true},
+		{"", "*", nil, true},
+		{"", "?", nil, false},
+		{"abc", "a*", nil, true},
+		{"abc", "a*d", nil, false},
+		{"*.go", "cmd/*", []rune{'/'}, false},
+		{"**.go", "cmd/*", []rune{'/'}, true},
+		{"[a-c]x", "[!b]?", nil, true},
+		{"[b]x", "[!b]?", nil, false},
+		{"{cat,dog}s", "*ts", nil, true},
+		{"{cat,dog}s", "*gz", nil, false},
+		{"a*b", "*a", nil, false},
+		{"a*b*c", "*b*a*c", nil, true},
+	} {
+		a, err := syntax.Parse(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := syntax.Parse(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, m := New(a.Tree, test.separators), New(b.Tree, test.separators)
+		if act := n.Intersects(m); act != test.exp {
+			t.Errorf("#%d %q Intersects(%q) = %t; want %t", id, test.a, test.b, act, test.exp)
+		}
+		if act := m.Intersects(n); act != test.exp {
+			t.Errorf("#%d %q Intersects(%q) = %t; want %t", id, test.b, test.a, act, test.exp)
+		}
+	}
+}
+
+func TestIncludes(t *testing.T) {
+	for id, test := range []struct {
+		a, b       string
+		separators []rune
+		exp        bool
+	}{
+		{"", "", nil, true},
+		{"*", "", nil, true},
+		{"", "*", nil, false},
+		{"*", "abc", nil, true},
+		{"a*", "abc", nil, true},
+		{"a*", "b*", nil, false},
+		{"*.go", "cmd/*.go", nil, true},
+		{"*.go", "cmd/*.go", []rune{'/'}, false},
+		{"**.go", "cmd/*.go", []rune{'/'}, true},
+		{"*", "**", []rune{'/'}, false},
+		{"**", "*", []rune{'/'}, true},
+		{"[a-z]", "[b-d]", nil, true},
+		{"[a-z]", "[!b-d]", nil, false},
+		{"{a,b,c}?", "[ab][xy]", nil, true},
+		{"{a,b}?", "[abc]x", nil, false},
+		{"*a*", "*{ab,ba}*", nil, true},
+		{"a*b*c", "a*bb*c", nil, true},
+		{"a*bb*c", "a*b*c", nil, false},
+		{"?*", "*?", nil, true},
+	} {
+		a, err := syntax.Parse(test.a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := syntax.Parse(test.b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, m := New(a.Tree, test.separators), New(b.Tree, test.separators)
+		if act := n.Includes(m); act != test.exp {
+			t.Errorf("#%d %q Includes(%q) = %t; want %t", id, test.a, test.b, act, test.exp)
+		}
+	}
+}
+
+func TestComplement(t *testing.T) {
+	for id, test := range []struct {
+		pattern    string
+		separators []rune
+		fixture    string
+		exp        bool
+	}{
+		{"", nil, "", false},
+		{"", nil, "a", true},
+		{"*", nil, "abc", false},
+		{"a*", nil, "abc", false},
+		{"a*", nil, "bc", true},
+		{"a*", nil, "", true},
+		{"*.go", []rune{'/'}, "a/b.go", true},
+		{"*.go", []rune{'/'}, "b.go", false},
+		{"[!a]", nil, "a", true},
+		{"[!a]", nil, "b", false},
+		{"{a,bc}", nil, "b", true},
+		{"{a,bc}", nil, "bc", false},
+	} {
+		p, err := syntax.Parse(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := New(p.Tree, test.separators).Complement()
+		if act := n.Match(test.fixture); act != test.exp {
+			t.Errorf("#%d %q Complement().Match(%q) = %t; want %t", id, test.pattern, test.fixture, act, test.exp)
+		}
+	}
+}
+
+func TestLenBounds(t *testing.T) {
+	for id, test := range []struct {
+		pattern  string
+		min, max int
+	}{
+		{"", 0, 0},
+		{"abc", 3, 3},
+		{"a?c", 3, 6},
+		{"a*", 1, -1},
+		{"{a,bcd}", 1, 3},
+		{"[a-b]", 1, 1},
+		{"[!a]", 1, 4},
+		{"a[!\x01-\U0010ffff]", 2, 2},
+		{"[\u0800-\uffff]", 1, 3},
+	} {
+		p, err := syntax.Parse(test.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		min, max := New(p.Tree, nil).LenBounds()
+		if min != test.min || max != test.max {
+			t.Errorf("#%d %q LenBounds() = %d, %d; want %d, %d", id, test.pattern, min, max, test.min, test.max)
+		}
+	}
+
+	p, err := syntax.Parse("*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if min, max := New(p.Tree, nil).Complement().LenBounds(); min != -1 || max != -1 {
+		t.Errorf("LenBounds() of empty automaton = %d, %d; want -1, -1", min, max)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	build := func(pattern string) *NFA {
+		p, err := syntax.Parse(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return New(p.Tree, []rune{'/'})
+	}
+	for id, test := range []struct {
+		nfa   *NFA
+		match []string
+		miss  []string
+	}{
+		{Union(build("*.go"), build("*.md")), []string{"a.go", "b.md"}, []string{"a.c", "a/b.go"}},
+		{Union(), nil, []string{"", "a"}},
+		{Concat(build("*"), build("/"), build("*.go")), []string{"a/b.go", "/.go"}, []string{"a.go", "a/b/c.go"}},
+		{Concat(), []string{""}, []string{"a"}},
+		{Intersect(build("a*"), build("*b"), build("???")), []string{"axb", "abb"}, []string{"ab", "axxb", "bxb"}},
+		{Intersect(build("*.go"), build("*.md")), nil, []string{"a.go", "a.md"}},
+		{Intersect(), []string{"", "a/b"}, nil},
+	} {
+		for _, s := range test.match {
+			if !test.nfa.Match(s) {
+				t.Errorf("#%d should match %q", id, s)
+			}
+		}
+		for _, s := range test.miss {
+			if test.nfa.Match(s) {
+				t.Errorf("#%d should not match %q", id, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package glob
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+)
+
+// Normalizer transforms strings into their normal form. The forms of
+// golang.org/x/text/unicode/norm, like norm.NFC, are normalizers.
+type Normalizer interface {
+	String(s string) string
+}
+
+// WithNormalization makes the glob match strings by their normal form, so
+// that with norm.NFC the pattern `café*` matches "café.txt" whether é is
+// written as a single rune or as e followed by the combining acute accent.
+// The pattern is normalized once, by CompileWith, and strings are normalized
+// before matching, after other options, like WithURLPath, transform them.
+//
+// Normalizers which return strings in normal form as they are do not make
+// matching of such strings allocate. Callers which normalize strings on
+// their own could skip this option, provided that patterns are normalized
+// the same way. Globs compiled with this option are neither cached nor
+// saved.
+func WithNormalization(n Normalizer) Option {
+	return func(o *options) {
+		o.normalizer = n
+	}
+}
+
+// normalizeWith returns the function transforming strings by then, if it is
+// set, and normalizing the result by n.
+func normalizeWith(n Normalizer, then func(string) (string, bool)) func(string) (string, bool) {
+	return func(s string) (string, bool) {
+		if then != nil {
+			var ok bool
+			if s, ok = then(s); !ok {
+				return s, false
+			}
+		}
+		return n.String(s), true
+	}
+}
+
+// normalization returns the names of the functions normalizing strings as
+// set by the options, in the order they are applied, and the encoding of
+// the normalizer, which identify the normalization for Equal and Hash.
+func (o *options) normalization() string {
+	var parts []string
+	for _, f := range []func(string) (string, bool){o.normalize, o.invalid.normalize()} {
+		if f != nil {
+			name, _ := normalizerName(f)
+			parts = append(parts, name)
+		}
+	}
+	if o.normalizer != nil {
+		var buf bytes.Buffer
+		encodeValue(&buf, reflect.ValueOf(o.normalizer))
+		parts = append(parts, buf.String())
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,46 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+// composer composes e followed by the combining acute accent into é, as
+// norm.NFC does.
+type composer struct{}
+
+func (composer) String(s string) string {
+	return strings.Replace(s, "e\u0301", "\u00e9", -1)
+}
+
+func TestWithNormalization(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		s       string
+		exp     bool
+	}{
+		{"caf\u00e9*", nil, "cafe\u0301.txt", true},
+		{"cafe\u0301*", nil, "caf\u00e9.txt", true},
+		{"caf?.txt", nil, "cafe\u0301.txt", true},
+		{"caf[\u00e9]", nil, "cafe\u0301", true},
+		{"caf\u00e9", nil, "cafe", false},
+		{"/caf\u00e9/*", []Option{WithURLPath(true)}, "/cafe%CC%81/a", true},
+		{"/caf\u00e9/*", []Option{WithURLPath(true)}, "/cafe%CC/a", false},
+		{"CAF\u00c9", []Option{WithCaseFold()}, "cafe\u0301", true},
+	} {
+		g := MustCompileWith(test.pattern, append(test.opts, WithNormalization(composer{}))...)
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("#%d %q Match(%q) = %t; want %t", id, test.pattern, test.s, act, test.exp)
+		}
+	}
+
+	if MustCompile("caf\u00e9").Match("cafe\u0301") {
+		t.Errorf("strings should not be normalized without the option")
+	}
+	c := NewCache(1)
+	a, _ := c.Compile("caf\u00e9", WithNormalization(composer{}))
+	if b, _ := c.Compile("caf\u00e9", WithNormalization(composer{})); a == b {
+		t.Errorf("globs compiled with normalization should not be cached")
+	}
+}
@@ -0,0 +1,424 @@
+package glob
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Option configures compilation of a pattern by CompileWith.
+type Option func(*options)
+
+// options affecting compiled globs are part of cacheKey as well.
+type options struct {
+	separators []rune
+	checkSeps  bool
+	dialect    Dialect
+	caseFold   bool
+	noEscape   bool
+	period     bool
+	empty      EmptyPattern
+	bash       BashOption
+	extglob    bool
+	windows    bool
+	urlPath    bool
+	hostname   bool
+	multiLabel bool
+	mimeType   bool
+	normalize  func(string) (string, bool)
+	normalizer Normalizer
+	invalid    InvalidUTF8
+	graphemes  bool
+	collator   match.Collator
+	maxSteps   int
+	stats      StatsHook
+
+	captureNames []string
+
+	maxLength       int
+	maxAlternatives int
+	maxNesting      int
+	maxExpansions   int
+
+	placeholders []*ast.Placeholder
+
+	workers   int
+	threshold int
+	dfa       bool
+	dfaStates int
+
+	engine Engine
+
+	// batch, if set, allocates the glob.
+	batch *Batch
+}
+
+// WithSeparators sets runes that are not matched by `*` and `?`, each of
+// which is a separator of its own, as Separators describes. It is the same
+// as passing separators to Compile. Dialects that define separators on
+// their own ignore this option.
+func WithSeparators(separators ...rune) Option {
+	return func(o *options) {
+		o.separators = separators
+	}
+}
+
+// WithDialect sets the syntax and matching rules of the pattern.
+func WithDialect(d Dialect) Option {
+	return func(o *options) {
+		o.dialect = d
+	}
+}
+
+// WithCaseFold makes the glob match strings case-insensitively, under Unicode
+// simple case folding.
+func WithCaseFold() Option {
+	return func(o *options) {
+		o.caseFold = true
+	}
+}
+
+// WithNoEscape makes backslash an ordinary character, which could not be
+// used to escape special characters. It affects DialectDefault and
+// DialectFnmatch; other dialects define escaping on their own.
+func WithNoEscape() Option {
+	return func(o *options) {
+		o.noEscape = true
+	}
+}
+
+// WithLeadingPeriod makes period at the beginning of the string, or right
+// after a separator, match only period in the pattern, but not wildcards or
+// character classes. That is, `*` does not match hidden files.
+func WithLeadingPeriod() Option {
+	return func(o *options) {
+		o.period = true
+	}
+}
+
+// EmptyPattern is what the empty pattern matches.
+type EmptyPattern int
+
+const (
+	// EmptyDefault leaves the empty pattern to the dialect. Most dialects,
+	// including the default one, match the empty string only with it,
+	// while some, like DialectGitignore, reject it.
+	EmptyDefault EmptyPattern = iota
+
+	// EmptyMatchesEmpty makes the empty pattern match the empty string
+	// only, whatever the dialect is.
+	EmptyMatchesEmpty
+
+	// EmptyMatchesNone makes the empty pattern match no strings at all,
+	// as an empty list of patterns does. It suits patterns coming from
+	// configuration, where an empty value should select nothing.
+	EmptyMatchesNone
+)
+
+// WithEmptyPattern sets what the empty pattern matches. Other options, like
+// WithCaseFold, do not affect it, while placeholders are not substituted in
+// it.
+func WithEmptyPattern(e EmptyPattern) Option {
+	return func(o *options) {
+		o.empty = e
+	}
+}
+
+// tree returns syntax tree of the empty pattern, which is neither
+// parsed nor transformed by other options.
+func (e EmptyPattern) tree() *ast.Node {
+	tree := ast.NewNode(ast.KindPattern, nil)
+	if e == EmptyMatchesNone {
+		ast.Insert(tree, classOrNothing(nil))
+	}
+	return tree
+}
+
+// Dialect describes syntax and matching rules of a pattern.
+type Dialect int
+
+const (
+	// DialectDefault is the syntax described in the Compile documentation.
+	DialectDefault Dialect = iota
+
+	// DialectGitignore implements the rules of a single gitignore(5)
+	// pattern. Paths are matched relative to the directory of the
+	// .gitignore file, with '/' as the separator. Directory paths must end
+	// with '/'. Pattern without '/' except for a trailing one matches at any
+	// depth, and pattern with leading or middle '/' is anchored to the root.
+	// Pattern ending with '/' matches only directories. Leading `**/`,
+	// trailing `/**` and middle `/**/` match any number of directories, while
+	// other `**` are the same as `*`. Braces are matched literally. Because
+	// the contents of an ignored directory are ignored as well, the glob
+	// also matches paths inside of the directories the pattern matches.
+	DialectGitignore
+
+	// DialectDockerignore implements the rules of a single .dockerignore
+	// pattern, as used by Docker to filter the build context. Paths are
+	// matched relative to the root of the context, with '/' as the
+	// separator. Patterns and paths are cleaned as by path.Clean, and
+	// leading '/' is removed. Unlike gitignore, patterns are always
+	// anchored to the root. A `**` segment matches any number of
+	// directories, and `**` inside of a segment matches any characters,
+	// including '/'. Only `^` negates character classes, and they could
+	// match '/'. The glob also matches paths inside of the directories the
+	// pattern matches.
+	DialectDockerignore
+
+	// DialectRsync implements the rules of a single rsync(1) filter pattern.
+	// Paths are matched relative to the root of the transfer, with '/' as
+	// the separator. Directory paths must end with '/'. Pattern starting
+	// with '/' is anchored to the root, otherwise it matches the end of the
+	// path at any depth. Pattern ending with '/' matches only directories.
+	// `**` matches any characters, including '/', and trailing `/***`
+	// matches the directory itself and everything inside of it.
+	DialectRsync
+
+	// DialectFnmatch implements the syntax of fnmatch(3) patterns: `*`, `?`
+	// and bracket expressions, negated with `!` or `^` and possibly
+	// containing POSIX classes like `[:alpha:]`. Braces are matched
+	// literally. Bracket expressions do not match separators.
+	DialectFnmatch
+
+	// DialectPath implements the syntax of path.Match, which is the same as
+	// of filepath.Match on systems using '/' as the separator. The glob
+	// matches exactly the same names as path.Match does, and CompileWith
+	// returns path.ErrBadPattern for exactly the patterns it rejects. Note
+	// that in this dialect character classes are negated with `^`, and could
+	// match '/'. Since path.Match does not backtrack after a star, and skips
+	// bytes rather than runes, patterns with a class matching '/' between
+	// stars, or with `?` right after a star, could match other names than
+	// globs usually do. The rest of such patterns from the first star is
+	// matched by path.Match itself, so that Regexp, Overlaps and Subsumes
+	// treat it as a placeholder, and the globs could not be saved.
+	DialectPath
+
+	// DialectDoublestar implements the syntax of the doublestar package
+	// (github.com/bmatcuk/doublestar), with '/' as the separator. `**` as
+	// a whole path segment matches any number of directories, including
+	// none, so that `a/**` matches "a" too; inside of a segment it is the
+	// same as `*`. Braces, which could be nested and contain '/', denote
+	// alternatives. Character classes are negated with `!` or `^`, and do
+	// not match '/'. Unterminated classes and braces are reported with
+	// path.ErrBadPattern.
+	DialectDoublestar
+
+	// DialectEditorconfig implements the syntax of section names of
+	// .editorconfig files. Paths are matched relative to the directory of
+	// the file, with '/' as the separator. Section name without '/' matches
+	// at any depth, otherwise it is anchored to the directory. `**` matches
+	// any characters, including '/', and `/**/` matches a single '/' too.
+	// Braces denote alternatives, and `{num1..num2}` matches integers
+	// between the numbers, written without leading zeros. Character classes
+	// are negated with `!` or `^`, and do not match '/'. Brackets and
+	// braces which do not form a valid construct are matched literally.
+	DialectEditorconfig
+
+	// DialectBash implements pathname expansion of bash, with '/' as the
+	// separator. It is set by WithBash along with shell options. Character
+	// classes are negated with `!` or `^`, may contain POSIX classes like
+	// `[:alpha:]`, and do not match '/'. Braces are matched literally, as
+	// brace expansion is not a part of pathname expansion.
+	DialectBash
+)
+
+// parse returns syntax tree of the pattern written in the dialect.
+func (d Dialect) parse(pattern string, o *options) (*syntax.Pattern, error) {
+	var (
+		tree *ast.Node
+		err  error
+	)
+	switch d {
+	case DialectGitignore:
+		tree, err = gitignoreTree(pattern)
+
+	case DialectDockerignore:
+		tree, err = dockerignoreTree(pattern)
+
+	case DialectRsync:
+		tree, err = rsyncTree(pattern)
+
+	case DialectPath:
+		tree, err = pathTree(pattern)
+
+	case DialectDoublestar:
+		tree, err = doublestarTree(pattern)
+
+	case DialectEditorconfig:
+		tree, err = editorconfigTree(pattern)
+
+	case DialectBash:
+		tree, err = bashTree(pattern, o.bash)
+
+	case DialectFnmatch:
+		var nodes []*ast.Node
+		nodes, err = shell{seps: o.separators, noEscape: o.noEscape, extglob: o.extglob}.nodes(pattern)
+		tree = ast.NewNode(ast.KindPattern, nil, nodes...)
+
+	default:
+		if !o.noEscape {
+			return syntax.Parse(pattern)
+		}
+		escaped := strings.Replace(pattern, `\`, `\\`, -1)
+		p, err := syntax.Parse(escaped)
+		if err != nil {
+			if e, ok := err.(*syntax.Error); ok {
+				// Every backslash before the offset was doubled.
+				e.Offset -= strings.Count(escaped[:e.Offset], `\`) / 2
+			}
+			return nil, err
+		}
+		p.Source = pattern
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &syntax.Pattern{Source: pattern, Tree: tree}, nil
+}
+
+// separators returns separators the dialect uses, given the ones set by the
+// WithSeparators option.
+func (d Dialect) separators(sep []rune) []rune {
+	switch d {
+	case DialectGitignore, DialectDockerignore, DialectRsync, DialectPath, DialectDoublestar, DialectEditorconfig, DialectBash:
+		return []rune{'/'}
+	default:
+		return sep
+	}
+}
+
+// CompileWith creates Glob for given pattern configured by given options.
+func CompileWith(pattern string, opts ...Option) (Glob, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.checkSeps {
+		if err := Separators(o.separators).Validate(); err != nil {
+			return nil, err
+		}
+	}
+	if o.maxLength > 0 && len(pattern) > o.maxLength {
+		return nil, &TooComplexError{Limit: "pattern length", Value: len(pattern), Max: o.maxLength}
+	}
+	if pattern == "" && o.empty != EmptyDefault {
+		return o.compile(&syntax.Pattern{Tree: o.empty.tree()}, o.dialect.separators(o.separators))
+	}
+	source := pattern
+	if o.normalizer != nil {
+		pattern = o.normalizer.String(pattern)
+	}
+	if len(o.placeholders) > 0 {
+		escape := !o.noEscape || (o.dialect != DialectDefault && o.dialect != DialectFnmatch)
+		var err error
+		if pattern, err = substitutePlaceholders(pattern, o.placeholders, escape); err != nil {
+			return nil, err
+		}
+	}
+	p, err := o.dialect.parse(pattern, &o)
+	if err != nil {
+		if e, ok := err.(*syntax.Error); ok && len(o.placeholders) > 0 {
+			e.Offset = placeholderOffset(pattern, e.Offset, o.placeholders)
+		}
+		return nil, err
+	}
+	p.Source = source
+	if err := o.checkComplexity(p.Tree); err != nil {
+		return nil, err
+	}
+	separators := o.dialect.separators(o.separators)
+	if o.hostname {
+		tree, err := hostnameTree(p.Tree, o.multiLabel)
+		if err != nil {
+			return nil, err
+		}
+		p = &syntax.Pattern{Source: p.Source, Tree: tree}
+	}
+	if o.mimeType {
+		p = &syntax.Pattern{Source: p.Source, Tree: mimeTree(p.Tree)}
+	}
+	if o.windows {
+		p = &syntax.Pattern{Source: p.Source, Tree: windowsTree(p.Tree)}
+	}
+	if o.collator != nil {
+		p = &syntax.Pattern{Source: p.Source, Tree: collationTree(p.Tree, o.collator, o.caseFold)}
+	}
+	if o.graphemes {
+		p = &syntax.Pattern{Source: p.Source, Tree: graphemeTree(p.Tree, separators)}
+	}
+	if o.caseFold {
+		// Folding goes first, as negated classes become positive sets
+		// without periods and separators, which folding would extend.
+		p = &syntax.Pattern{Source: p.Source, Tree: foldTree(p.Tree)}
+	}
+	if o.period {
+		p = &syntax.Pattern{Source: p.Source, Tree: periodTree(p.Tree, separators)}
+	}
+	if o.extglob {
+		tree, err := extglobTree(p.Tree, separators)
+		if err != nil {
+			return nil, err
+		}
+		p = &syntax.Pattern{Source: p.Source, Tree: tree}
+	}
+	if o.urlPath {
+		if err := checkDotDot(p.Tree, true, true); err != nil {
+			return nil, err
+		}
+	}
+	if len(o.placeholders) > 0 {
+		tree, err := placeholderTree(p.Tree, o.placeholders)
+		if err != nil {
+			return nil, err
+		}
+		p = &syntax.Pattern{Source: p.Source, Tree: tree}
+	}
+	return o.compile(p, separators)
+}
+
+// compile returns the glob of the pattern parsed and transformed as set by
+// the options.
+func (o *options) compile(p *syntax.Pattern, separators []rune) (Glob, error) {
+	var c *compiled
+	if o.batch != nil {
+		c = o.batch.alloc()
+	} else {
+		c = new(compiled)
+	}
+	if err := compileAST(c, p, separators); err != nil {
+		return nil, err
+	}
+	c.normalize = o.normalize
+	if n := o.invalid.normalize(); n != nil {
+		c.normalize = normalizeThen(o.normalize, n)
+	}
+	if o.normalizer != nil {
+		c.normalize = normalizeWith(o.normalizer, c.normalize)
+	}
+	c.normalization = o.normalization()
+	c.maxSteps = o.maxSteps
+	if len(o.captureNames) > 0 {
+		c.captureNames = o.captureNames
+		if err := checkCaptureNames(c, o.captureNames); err != nil {
+			return nil, err
+		}
+	}
+	c.stats, c.source = o.stats, p.Source
+	if m, ok := engineMatcher(o.engine, c.tree, separators); ok {
+		c.Matcher = m
+	}
+	return c, nil
+}
+
+// MustCompileWith is the same as CompileWith, except that if CompileWith
+// returns error, this will panic.
+func MustCompileWith(pattern string, opts ...Option) Glob {
+	g, err := CompileWith(pattern, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
@@ -0,0 +1,82 @@
+package glob
+
+import "testing"
+
+func TestEmptyPattern(t *testing.T) {
+	for id, test := range []struct {
+		opts  []Option
+		err   bool
+		empty bool
+	}{
+		{nil, false, true},
+		{[]Option{WithEmptyPattern(EmptyMatchesEmpty)}, false, true},
+		{[]Option{WithEmptyPattern(EmptyMatchesNone)}, false, false},
+		{[]Option{WithDialect(DialectGitignore)}, true, false},
+		{[]Option{WithDialect(DialectGitignore), WithEmptyPattern(EmptyMatchesNone)}, false, false},
+		{[]Option{WithDialect(DialectDockerignore), WithEmptyPattern(EmptyMatchesEmpty)}, false, true},
+		{[]Option{WithEmptyPattern(EmptyMatchesNone), WithCaseFold()}, false, false},
+	} {
+		g, err := CompileWith("", test.opts...)
+		if test.err {
+			if err == nil {
+				t.Errorf("#%d expected error", id)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("#%d unexpected error: %v", id, err)
+			continue
+		}
+		if act := g.Match(""); act != test.empty {
+			t.Errorf("#%d unexpected result on empty string: exp: %v, act: %v", id, test.empty, act)
+		}
+		for _, s := range []string{"a", ".", "/"} {
+			if g.Match(s) {
+				t.Errorf("#%d unexpected match of %q", id, s)
+			}
+		}
+	}
+
+	// The option affects the empty pattern only.
+	g := MustCompileWith("a*", WithEmptyPattern(EmptyMatchesNone))
+	if !g.Match("abc") {
+		t.Errorf("unexpected result of %q", "a*")
+	}
+}
+
+func TestCompileWithCaseFold(t *testing.T) {
+	g := MustCompileWith("{foo,bar}*.[ch]", WithCaseFold(), WithSeparators('/'))
+	for _, test := range []struct {
+		s   string
+		exp bool
+	}{
+		{"FOO.C", true},
+		{"Bar_x.h", true},
+		{"baz.c", false},
+		{"foo/x.c", false},
+	} {
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("Match(%q) = %t; want %t", test.s, act, test.exp)
+		}
+	}
+	for id, test := range []struct {
+		pattern, s string
+		exp        bool
+	}{
+		{"abc*", "ABCd", true},
+		{"*.txt", "A.TXT", true},
+		{"*key*", "a\u212aEYb", true},
+		{"a*", "Ab", true},
+		{"ab*bc", "ABC", false},
+		{"ab*bc", "aBbC", true},
+		{"ab*bc", "xabbc", false},
+	} {
+		g := MustCompileWith(test.pattern, WithCaseFold())
+		if act := g.Match(test.s); act != test.exp {
+			t.Errorf("#%d %q Match(%q) = %t; want %t", id, test.pattern, test.s, act, test.exp)
+		}
+	}
+	if g := MustCompileWith(`a\*`, WithNoEscape()); !g.Match(`a\bc`) {
+		t.Errorf("WithNoEscape() pattern does not match backslash")
+	}
+}
@@ -0,0 +1,77 @@
+package glob
+
+import (
+	"fmt"
+)
+
+// OrderedSet is an ordered list of patterns in which later patterns
+// override earlier ones, as in gitignore(5), .dockerignore and most CI path
+// filters. Strings matching a pattern are excluded, and strings matching a
+// pattern starting with `!` are re-included.
+type OrderedSet struct {
+	patterns []string
+	rules    []ignoreRule
+}
+
+// NewOrderedSet compiles the patterns with given options by CompileWith into
+// an ordered set. A leading `!` of a pattern is removed and makes it a
+// re-including rule.
+func NewOrderedSet(patterns []string, opts ...Option) (*OrderedSet, error) {
+	s := &OrderedSet{
+		patterns: append([]string(nil), patterns...),
+		rules:    make([]ignoreRule, len(patterns)),
+	}
+	for i, p := range patterns {
+		var rule ignoreRule
+		if len(p) > 0 && p[0] == '!' {
+			rule.negate = true
+			p = p[1:]
+		}
+		g, err := CompileWith(p, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("pattern #%d %q: %s", i, s.patterns[i], err)
+		}
+		rule.glob = g
+		s.rules[i] = rule
+	}
+	return s, nil
+}
+
+// MustOrderedSet is the same as NewOrderedSet, except that if NewOrderedSet
+// returns error, this will panic.
+func MustOrderedSet(patterns []string, opts ...Option) *OrderedSet {
+	s, err := NewOrderedSet(patterns, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Len returns the number of patterns in the set.
+func (s *OrderedSet) Len() int {
+	return len(s.rules)
+}
+
+// Pattern returns the i-th pattern of the set, including the leading `!` of
+// re-including rules.
+func (s *OrderedSet) Pattern(i int) string {
+	return s.patterns[i]
+}
+
+// Match reports whether str is excluded by the set.
+func (s *OrderedSet) Match(str string) bool {
+	excluded, _ := s.Decide(str)
+	return excluded
+}
+
+// Decide reports whether str is excluded by the set, along with the index of
+// the deciding pattern, which is the last pattern str matches. If str
+// matches no pattern, it is not excluded and the index is -1.
+func (s *OrderedSet) Decide(str string) (excluded bool, rule int) {
+	for i := len(s.rules) - 1; i >= 0; i-- {
+		if r := s.rules[i]; r.glob.Match(str) {
+			return !r.negate, i
+		}
+	}
+	return false, -1
+}
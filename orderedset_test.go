@@ -0,0 +1,33 @@
+package glob
+
+import "testing"
+
+func TestOrderedSet(t *testing.T) {
+	set := MustOrderedSet([]string{"*.log", "!important.log", "build/**", "!build/keep/**", "build/keep/tmp"}, WithSeparators('/'))
+	for _, test := range []struct {
+		s        string
+		excluded bool
+		rule     int
+	}{
+		{"main.go", false, -1},
+		{"app.log", true, 0},
+		{"important.log", false, 1},
+		{"build/a.o", true, 2},
+		{"build/keep/a.o", false, 3},
+		{"build/keep/tmp", true, 4},
+	} {
+		excluded, rule := set.Decide(test.s)
+		if excluded != test.excluded || rule != test.rule {
+			t.Errorf("Decide(%q) = %t, %d; want %t, %d", test.s, excluded, rule, test.excluded, test.rule)
+		}
+		if act := set.Match(test.s); act != test.excluded {
+			t.Errorf("Match(%q) = %t; want %t", test.s, act, test.excluded)
+		}
+	}
+	if set.Len() != 5 || set.Pattern(1) != "!important.log" {
+		t.Errorf("unexpected set accessors results")
+	}
+	if _, err := NewOrderedSet([]string{"a", "![b"}); err == nil {
+		t.Errorf("expected error for invalid pattern")
+	}
+}
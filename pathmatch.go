@@ -0,0 +1,228 @@
+package glob
+
+import (
+	"fmt"
+	"path"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// pathTree returns syntax tree matching the same names as the pattern does
+// with path.Match. Malformed patterns are reported with path.ErrBadPattern.
+func pathTree(pattern string) (*ast.Node, error) {
+	var (
+		tree = ast.NewNode(ast.KindPattern, nil)
+		text []byte
+
+		// tail is the index of the first star in the pattern, and head is
+		// the number of nodes before it.
+		tail = -1
+		head int
+	)
+	flush := func() {
+		if len(text) > 0 {
+			ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: string(text)}))
+			text = text[:0]
+		}
+	}
+	for i := 0; i < len(pattern); {
+		switch c := pattern[i]; c {
+		case '*':
+			flush()
+			if tail == -1 {
+				tail, head = i, len(tree.Children)
+			}
+			for i < len(pattern) && pattern[i] == '*' {
+				i++
+			}
+			ast.Insert(tree, ast.NewNode(ast.KindAny, nil))
+
+		case '?':
+			flush()
+			ast.Insert(tree, ast.NewNode(ast.KindSingle, nil))
+			i++
+
+		case '[':
+			set, n, err := pathClass(pattern[i:])
+			if err != nil {
+				return nil, err
+			}
+			flush()
+			ast.Insert(tree, classOrNothing(set))
+			i += n
+
+		case '\\':
+			if i+1 == len(pattern) {
+				return nil, path.ErrBadPattern
+			}
+			text = append(text, pattern[i+1])
+			i += 2
+
+		default:
+			text = append(text, c)
+			i++
+		}
+	}
+	flush()
+	if tail != -1 && pathGreedy(pattern[tail:]) {
+		// The rest of the pattern is matched by path.Match itself, as the
+		// nodes could match names it does not.
+		tree.Children = tree.Children[:head]
+		ast.Insert(tree, ast.NewNode(ast.KindPlaceholder, &ast.Placeholder{
+			Name:    pattern[tail:],
+			Matcher: pathMatcher{Pattern: pattern[tail:]},
+		}))
+	}
+	return tree, nil
+}
+
+// pathGreedy reports whether path.Match could match names with the valid
+// pattern differently than the nodes built from it do. Unlike globs,
+// path.Match does not backtrack: after a star it matches the chunk of the
+// pattern up to the next star at the first position it could, skipping
+// bytes rather than runes. So the results differ if a chunk followed by a
+// star has a class matching '/', since the star after a later match of the
+// chunk could match where the star after the first one could not cross '/'.
+// They also differ if a chunk following a star could match starting in the
+// middle of a rune.
+func pathGreedy(pattern string) bool {
+	// star is set in chunks following a star, first is set until their
+	// first element, and slash is set if a class of the chunk matches '/'.
+	var star, first, slash bool
+	for i := 0; i < len(pattern); {
+		c := pattern[i]
+		if c == '*' {
+			if star && slash {
+				return true
+			}
+			for i < len(pattern) && pattern[i] == '*' {
+				i++
+			}
+			star, first, slash = true, true, false
+			continue
+		}
+		// mid is set if the element could match a byte in the middle of
+		// a rune.
+		var mid bool
+		switch c {
+		case '?':
+			mid = true
+			i++
+		case '[':
+			set, n, _ := pathClass(pattern[i:])
+			mid = set.Contains(utf8.RuneError)
+			slash = slash || set.Contains('/')
+			i += n
+		case '\\':
+			mid = !utf8.RuneStart(pattern[i+1])
+			i += 2
+		default:
+			mid = !utf8.RuneStart(c)
+			i++
+		}
+		if star && first && mid {
+			return true
+		}
+		first = false
+	}
+	return false
+}
+
+// pathMatcher matches names as path.Match does with the pattern. It stands
+// for the parts of patterns which could not be matched by other matchers.
+type pathMatcher struct {
+	Pattern string
+}
+
+func (m pathMatcher) Match(s string) bool {
+	ok, _ := path.Match(m.Pattern, s)
+	return ok
+}
+
+func (m pathMatcher) Index(s string) (int, []int) {
+	// Nothing is known about the structure of the pattern, so every
+	// substring is tried.
+	for i := 0; ; {
+		var segments []int
+		for j := i; ; {
+			if m.Match(s[i:j]) {
+				segments = append(segments, j-i)
+			}
+			if j == len(s) {
+				break
+			}
+			_, w := utf8.DecodeRuneInString(s[j:])
+			j += w
+		}
+		if len(segments) > 0 {
+			return i, segments
+		}
+		if i == len(s) {
+			return -1, nil
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+}
+
+func (m pathMatcher) Len() int {
+	return -1
+}
+
+func (m pathMatcher) String() string {
+	return fmt.Sprintf("<path:%s>", m.Pattern)
+}
+
+// pathClass parses character class at the beginning of s as path.Match does.
+// Unlike shell classes, it is negated only with `^`, and could match '/'.
+func pathClass(s string) (set runes.Set, n int, err error) {
+	i := 1
+	not := i < len(s) && s[i] == '^'
+	if not {
+		i++
+	}
+	for k := 0; ; k++ {
+		if i < len(s) && s[i] == ']' && k > 0 {
+			i++
+			break
+		}
+		lo, w, err := pathClassRune(s[i:])
+		if err != nil {
+			return nil, 0, err
+		}
+		i += w
+		hi := lo
+		if s[i] == '-' {
+			if hi, w, err = pathClassRune(s[i+1:]); err != nil {
+				return nil, 0, err
+			}
+			i += 1 + w
+		}
+		set = set.Union(runes.NewSet(runes.Range{Lo: lo, Hi: hi}))
+	}
+	if not {
+		set = set.Complement()
+	}
+	return set, i, nil
+}
+
+// pathClassRune returns the rune at the beginning of s, which is a part of
+// character class. As in path.Match, s must not be empty after the rune.
+func pathClassRune(s string) (r rune, n int, err error) {
+	if len(s) == 0 || s[0] == '-' || s[0] == ']' {
+		return 0, 0, path.ErrBadPattern
+	}
+	if s[0] == '\\' {
+		n = 1
+		if len(s) == 1 {
+			return 0, 0, path.ErrBadPattern
+		}
+	}
+	r, w := utf8.DecodeRuneInString(s[n:])
+	if r == utf8.RuneError && w == 1 || n+w == len(s) {
+		return 0, 0, path.ErrBadPattern
+	}
+	return r, n + w, nil
+}
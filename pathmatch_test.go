@@ -0,0 +1,73 @@
+package glob
+
+import (
+	"math/rand"
+	"path"
+	"testing"
+)
+
+func TestDialectPath(t *testing.T) {
+	patterns := []string{
+		"", "abc", "*", "*c", "a*", "a*/b", "a*b*c*d*e*/f", "ab[c]", "ab[b-d]", "ab[e-g]",
+		"ab[^c]", "ab[^b-d]", "ab[^e-g]", "a\\*b", "a?b", "a[^a]b", "a???b", "a[!a]b",
+		"[a-ζ]*", "*[a-ζ]", "a?b", "a*b", "[\\]a]", "[\\-]", "[x\\-]", "[\\-x]",
+		"[]a]", "[-]", "[x-]", "[-x]", "\\", "[a-b-c]", "[", "[^", "[^bc", "a[", "a/b[",
+		"*x", "a[/]b", "[^a]/b", "a\\", "[\\", "a[\\x", "ab[c-a]", "*x\\", "*[^a]*",
+	}
+	names := []string{
+		"", "abc", "a", "b", "abb", "abd", "abe", "a*b", "axb", "a/b", "a/bb", "axbxcxdxexxx/f",
+		"axbxcxdxe/f", "axbxcxdxexxx/fff", "abc/b", "ab/c", "α", "ζ", "z", "]", "-", "x", "a/", "xxx",
+		"ab\xff", "[/*[a", "\u20ac", "a/\u20acb",
+	}
+
+	// Patterns and names are made of parts which path.Match treats
+	// specially, including runes of several bytes and invalid ones. All
+	// the short names of some of the parts are tried, as well as random
+	// longer ones.
+	rnd := rand.New(rand.NewSource(1))
+	random := func(parts []string, n int) string {
+		var b []byte
+		for i := rnd.Intn(n + 1); i > 0; i-- {
+			b = append(b, parts[rnd.Intn(len(parts))]...)
+		}
+		return string(b)
+	}
+	patternParts := []string{
+		"a", "b", "/", "*", "*", "?", "[^a]", "[a-b]", "[/b]", "[\\]-]", "\\*", "\u00e9", "\ufffd",
+		"[", "\\",
+	}
+	for i := 0; i < 3000; i++ {
+		patterns = append(patterns, random(patternParts, 6))
+	}
+	short := []string{""}
+	for i := 0; len([]rune(short[i])) < 3; i++ {
+		for _, part := range []string{"a", "b", "/", "\u00e9", "\u20ac", "\xff"} {
+			short = append(short, short[i]+part)
+		}
+	}
+	names = append(names, short...)
+	nameParts := []string{"a", "b", "/", "*", "[", "]", "-", "\\", "\u00e9", "\u20ac", "\ufffd", "\xff", "\xa9"}
+	for i := 0; i < 200; i++ {
+		names = append(names, random(nameParts, 8))
+	}
+
+	for _, pattern := range patterns {
+		g, err := CompileWith(pattern, WithDialect(DialectPath))
+		for _, name := range names {
+			exp, expErr := path.Match(pattern, name)
+			if expErr != nil {
+				if err != expErr {
+					t.Errorf("CompileWith(%q) error = %v; want %v", pattern, err, expErr)
+				}
+				break
+			}
+			if err != nil {
+				t.Errorf("CompileWith(%q) unexpected error: %s", pattern, err)
+				break
+			}
+			if act := g.Match(name); act != exp {
+				t.Errorf("%q.Match(%q) = %t; path.Match() = %t", pattern, name, act, exp)
+			}
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package glob
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Pattern is a glob which could be used in configuration structs. It is
+// compiled while decoding from JSON, YAML or any format using
+// encoding.TextUnmarshaler, so invalid patterns fail at load time, and is
+// encoded back as the pattern string.
+//
+// Patterns which could not be compiled are reported as *UnmarshalError,
+// which wraps the error of CompileWith. UnmarshalYAML uses the signature
+// supported by both gopkg.in/yaml.v2 and gopkg.in/yaml.v3.
+type Pattern struct {
+	// Glob is the compiled pattern, which is nil until it is decoded.
+	Glob
+
+	// Options are used to compile the pattern, if set before decoding.
+	Options []Option
+
+	source string
+}
+
+// NewPattern compiles the pattern with given options.
+func NewPattern(pattern string, opts ...Option) (Pattern, error) {
+	p := Pattern{Options: opts}
+	if err := p.compile(pattern); err != nil {
+		return Pattern{}, err
+	}
+	return p, nil
+}
+
+// String returns the source pattern.
+func (p Pattern) String() string {
+	return p.source
+}
+
+// Match reports whether s matches the glob. It is false if the pattern is
+// not set.
+func (p Pattern) Match(s string) bool {
+	return p.Glob != nil && p.Glob.Match(s)
+}
+
+// MarshalText returns the source pattern.
+func (p Pattern) MarshalText() ([]byte, error) {
+	return []byte(p.source), nil
+}
+
+// UnmarshalText compiles the pattern.
+func (p *Pattern) UnmarshalText(text []byte) error {
+	return p.compile(string(text))
+}
+
+// UnmarshalJSON compiles the pattern from JSON string. Null leaves the
+// pattern unchanged.
+func (p *Pattern) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return p.compile(s)
+}
+
+// UnmarshalYAML compiles the pattern from YAML string.
+func (p *Pattern) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return p.compile(s)
+}
+
+func (p *Pattern) compile(s string) error {
+	g, err := CompileWith(s, p.Options...)
+	if err != nil {
+		return &UnmarshalError{Pattern: s, Err: err}
+	}
+	p.Glob, p.source = g, s
+	return nil
+}
+
+// UnmarshalError describes the pattern Pattern could not be decoded from.
+type UnmarshalError struct {
+	Pattern string
+
+	// Err is the error of CompileWith, like *syntax.Error.
+	Err error
+}
+
+func (e *UnmarshalError) Error() string {
+	return fmt.Sprintf("invalid pattern %q: %s", e.Pattern, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *UnmarshalError) Unwrap() error {
+	return e.Err
+}
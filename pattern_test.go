@@ -0,0 +1,63 @@
+package glob
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gobwas/glob/syntax"
+)
+
+func TestPattern(t *testing.T) {
+	type config struct {
+		Rules struct {
+			Include []Pattern `json:"include"`
+			Exclude Pattern   `json:"exclude"`
+		} `json:"rules"`
+	}
+	var c config
+	err := json.Unmarshal([]byte(`{"rules":{"include":["*.go","*.md"],"exclude":"*_test.go"}}`), &c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !c.Rules.Include[1].Match("readme.md") || !c.Rules.Exclude.Match("glob_test.go") {
+		t.Errorf("unexpected decoded patterns: %v", c.Rules)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"rules":{"include":["*.go","*.md"],"exclude":"*_test.go"}}`; string(data) != exp {
+		t.Errorf("Marshal() = %s; want %s", data, exp)
+	}
+
+	err = json.Unmarshal([]byte(`{"rules":{"exclude":"[a"}}`), &c)
+	if ue, ok := err.(*UnmarshalError); !ok || ue.Pattern != "[a" {
+		t.Errorf("Unmarshal() error = %#v; want *UnmarshalError for %q", err, "[a")
+	} else if _, ok := ue.Unwrap().(*syntax.Error); !ok {
+		t.Errorf("Unwrap() = %#v; want *syntax.Error", ue.Unwrap())
+	}
+	if c.Rules.Exclude.String() != "*_test.go" {
+		t.Errorf("invalid pattern changed the value to %q", c.Rules.Exclude)
+	}
+
+	var p Pattern
+	if err := p.UnmarshalYAML(func(v interface{}) error {
+		*v.(*string) = "a/*"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Match("a/b/c") {
+		t.Errorf("%q should match %q", p, "a/b/c")
+	}
+	p = Pattern{Options: []Option{WithSeparators('/')}}
+	if err := p.UnmarshalText([]byte("a/*")); err != nil {
+		t.Fatal(err)
+	}
+	if p.Match("a/b/c") {
+		t.Errorf("%q should not match %q", p, "a/b/c")
+	}
+	if (Pattern{}).Match("") {
+		t.Errorf("zero pattern should not match")
+	}
+}
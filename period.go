@@ -0,0 +1,184 @@
+package glob
+
+import (
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// periodTree returns a copy of the tree, in which wildcards and character
+// classes do not match a period at the beginning of the string or right
+// after a separator. Note that `**` is restricted only at its beginning.
+func periodTree(tree *ast.Node, seps []rune) *ast.Node {
+	p := period{seps: runes.Of(seps...)}
+	return ast.NewNode(ast.KindPattern, nil, p.nodes([]*ast.Node{tree}, true)...)
+}
+
+type period struct {
+	seps runes.Set
+
+	// afterWildcard reports that the sequence at the beginning of a path
+	// segment follows a wildcard matching the empty string there, so that
+	// it does not match period even explicitly, as in fnmatch(3).
+	afterWildcard bool
+}
+
+// nodes returns copy of the sequence of nodes. The start flag reports whether
+// the sequence starts at the beginning of a path segment.
+func (p period) nodes(nodes []*ast.Node, start bool) []*ast.Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+	n, rest := nodes[0], nodes[1:]
+	switch n.Kind {
+	case ast.KindPattern:
+		seq := make([]*ast.Node, 0, len(n.Children)+len(rest))
+		seq = append(seq, n.Children...)
+		return p.nodes(append(seq, rest...), start)
+
+	case ast.KindAnyOf:
+		// Alternatives could end at different positions, so the rest of
+		// the sequence is copied into each of them.
+		alt := ast.NewNode(ast.KindAnyOf, nil)
+		for _, c := range n.Children {
+			seq := []*ast.Node{c}
+			ast.Insert(alt, ast.NewNode(ast.KindPattern, nil, p.nodes(append(seq, rest...), start)...))
+		}
+		return []*ast.Node{alt}
+
+	case ast.KindText:
+		t := n.Value.(ast.Text).Text
+		if start && p.afterWildcard && t != "" && t[0] == '.' {
+			return []*ast.Node{classOrNothing(nil)}
+		}
+		if t != "" {
+			r, _ := utf8.DecodeLastRuneInString(t)
+			start = p.seps.Contains(r)
+		}
+		return p.prepend(ast.NewNode(ast.KindText, ast.Text{Text: t}), rest, start)
+
+	case ast.KindSingle:
+		if start {
+			return p.prepend(p.notPeriod(), rest, false)
+		}
+		return p.prepend(ast.NewNode(ast.KindSingle, nil), rest, false)
+
+	case ast.KindList, ast.KindRange, ast.KindClass:
+		set := nodeSet(n)
+		if start {
+			set = set.Subtract(runes.Of('.'))
+		}
+		sep, other := set.Intersect(p.seps), set.Subtract(p.seps)
+		switch {
+		case sep.Empty():
+			return p.prepend(classOrNothing(other), rest, false)
+		case other.Empty():
+			return p.prepend(setNode(sep), rest, true)
+		}
+		return []*ast.Node{ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindPattern, nil, p.prepend(setNode(other), rest, false)...),
+			ast.NewNode(ast.KindPattern, nil, p.prepend(setNode(sep), rest, true)...),
+		)}
+
+	case ast.KindPlaceholder:
+		if !start {
+			return p.prepend(n, rest, false)
+		}
+		// Grapheme clusters and classes of collated ranges do not match
+		// period either.
+		switch m := n.Value.(*ast.Placeholder).Matcher.(type) {
+		case match.Grapheme:
+			seps := append(append([]rune(nil), m.Separators...), '.')
+			return p.prepend(graphemeNode(seps), rest, false)
+		case match.Collated:
+			m.Except = append(append([]rune(nil), m.Except...), '.')
+			return p.prepend(ast.NewNode(ast.KindPlaceholder, &ast.Placeholder{
+				Name:    collationName,
+				Matcher: m,
+			}), rest, false)
+		case extglob:
+			return p.extglob(n, m, rest)
+		}
+		return p.prepend(n, rest, false)
+
+	case ast.KindAny, ast.KindSuper:
+		if !start {
+			return p.prepend(ast.NewNode(n.Kind, nil), rest, false)
+		}
+		// Either wildcard matches empty string, or it starts with
+		// something other than period.
+		first := p.notPeriod()
+		if n.Kind == ast.KindSuper {
+			first = setNode(runes.Of('.').Complement())
+		}
+		return []*ast.Node{ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindPattern, nil, p.wildcard().nodes(rest, true)...),
+			ast.NewNode(ast.KindPattern, nil, append(
+				[]*ast.Node{first, ast.NewNode(n.Kind, nil)},
+				p.nodes(rest, false)...,
+			)...),
+		)}
+
+	default:
+		return p.nodes(rest, start)
+	}
+}
+
+func (p period) prepend(n *ast.Node, rest []*ast.Node, start bool) []*ast.Node {
+	p.afterWildcard = false
+	return append([]*ast.Node{n}, p.nodes(rest, start)...)
+}
+
+// wildcard returns copy of p for the sequence following a wildcard, which
+// matches the empty string at the beginning of a path segment.
+func (p period) wildcard() period {
+	p.afterWildcard = true
+	return p
+}
+
+// extglob returns copy of the sequence of the extended pattern starting a
+// path segment, and the rest of nodes. As in bash, the complement does not
+// match period there, even explicitly after it, like wildcards, while the
+// alternatives of the first of repetitions match it only explicitly, as they
+// do at the beginning.
+func (p period) extglob(n *ast.Node, e extglob, rest []*ast.Node) []*ast.Node {
+	name := n.Value.(*ast.Placeholder).Name
+	switch {
+	case e.more:
+		return p.prepend(n, rest, false)
+	case e.op == '!':
+		empty, other := e, e
+		empty.empty, other.noPeriod = true, true
+		seq := append([]*ast.Node{extglobNode(name, empty)}, p.wildcard().nodes(rest, true)...)
+		return []*ast.Node{ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindPattern, nil, seq...),
+			ast.NewNode(ast.KindPattern, nil, p.prepend(extglobNode(name, other), rest, false)...),
+		)}
+	}
+	more := extglobNode("*"+name[1:], extglob{op: '*', alts: e.alts, more: true})
+	seq := append([]*ast.Node{e.alts, more}, rest...)
+	if e.op == '+' {
+		return p.nodes(seq, true)
+	}
+	return []*ast.Node{ast.NewNode(ast.KindAnyOf, nil,
+		ast.NewNode(ast.KindPattern, nil, p.nodes(rest, true)...),
+		ast.NewNode(ast.KindPattern, nil, p.nodes(seq, true)...),
+	)}
+}
+
+// notPeriod returns node matching single rune which is neither period nor
+// separator.
+func (p period) notPeriod() *ast.Node {
+	return setNode(runes.All.Subtract(p.seps.Union(runes.Of('.'))))
+}
+
+// classOrNothing returns node for the set, or node that never matches if the
+// set is empty.
+func classOrNothing(set runes.Set) *ast.Node {
+	if set.Empty() {
+		return ast.NewNode(ast.KindList, ast.List{})
+	}
+	return setNode(set)
+}
@@ -0,0 +1,263 @@
+package glob
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/nfa"
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// saveVersion is the version of the format written by Save. Load rejects
+// other versions, so caches written by other releases are recompiled.
+const saveVersion = 6
+
+func init() {
+	for _, m := range []match.Matcher{
+		match.Any{}, match.AnyOf{}, match.BTree{}, match.Between{},
+		match.CharClass{}, match.Collated{}, match.Contains{},
+		match.Empty{}, match.EveryOf{}, match.FoldContains{},
+		match.FoldPrefix{}, match.FoldPrefixSuffix{}, match.FoldSet{},
+		match.FoldSuffix{}, match.FoldText{}, match.Grapheme{},
+		match.List{}, match.Max{}, match.Min{}, match.Not{},
+		match.Nothing{}, match.Optional{}, match.Prefix{},
+		match.PrefixAny{}, match.PrefixSuffix{}, match.Range{},
+		match.Repeat{}, match.Row{}, match.Single{}, match.Set{},
+		match.Suffix{}, match.SuffixAny{}, match.Super{}, match.Text{},
+	} {
+		gob.Register(m)
+	}
+	gob.Register(dfaMatcher{})
+	gob.Register(pikeMatcher{})
+	gob.Register(ast.Text{})
+	gob.Register(ast.List{})
+	gob.Register(ast.Class{})
+	gob.Register(ast.Range{})
+}
+
+// normalizers are the functions options set to normalize strings, by the
+// names they are saved with.
+var normalizers = map[string]func(string) (string, bool){
+	"hostname":   trimTrailingDot,
+	"urlpath":    decodeURLPath,
+	"urlpathraw": checkURLPath,
+	"mimetype":   mediaType,
+	"utf8":       checkUTF8,
+	"utf8repl":   replaceInvalidUTF8,
+}
+
+func normalizerName(f func(string) (string, bool)) (string, bool) {
+	p := reflect.ValueOf(f).Pointer()
+	for name, n := range normalizers {
+		if reflect.ValueOf(n).Pointer() == p {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// savedSet mirrors GlobSet with exported fields, so that it could be
+// encoded with encoding/gob.
+type savedSet struct {
+	Version   int
+	Patterns  []string
+	Globs     []savedGlob
+	Trie      *savedTrie
+	Contains  []savedACState
+	Workers   int
+	Threshold int
+	DFA       *nfa.DFA
+	Rest      []int
+	DFAStates int
+}
+
+type savedGlob struct {
+	Matcher    match.Matcher
+	Tree       savedNode
+	Separators []rune
+	Prefix     string
+	Suffix     string
+	Literal    bool
+	MinLen     int
+	MaxLen     int
+	First      runes.Set
+	Last       runes.Set
+	Normalize  string
+	Captures   []string
+}
+
+// savedNode is a syntax tree node without the link to its parent, which
+// encoding/gob could not encode.
+type savedNode struct {
+	Kind     ast.Kind
+	Value    interface{}
+	Children []savedNode
+}
+
+type savedTrie struct {
+	Children map[byte]*savedTrie
+	Globs    []int
+	Literals []int
+}
+
+type savedACState struct {
+	Next map[byte]int
+	Fail int
+	Ends []int
+	Out  []int
+}
+
+// Save writes the compiled set to w, including its trie and automata, so
+// that LoadGlobSet could restore it without compiling the patterns again.
+// It suits long-lived processes and tools caching the compiled form on disk.
+// Sets of patterns with placeholders could not be saved, since custom
+// matchers are not known to Load.
+func (s *GlobSet) Save(w io.Writer) error {
+	out := savedSet{
+		Version:   saveVersion,
+		Patterns:  s.patterns,
+		Globs:     make([]savedGlob, len(s.globs)),
+		Trie:      saveTrie(&s.trie),
+		Contains:  make([]savedACState, len(s.contains.states)),
+		Workers:   s.workers,
+		Threshold: s.threshold,
+		DFA:       s.dfa,
+		Rest:      s.rest,
+		DFAStates: s.dfaStates,
+	}
+	for i, g := range s.globs {
+		c := g.(*compiled)
+		if countKind(c.tree, ast.KindPlaceholder) > 0 {
+			return fmt.Errorf("pattern #%d %q: could not save placeholders", i, s.patterns[i])
+		}
+		sg := savedGlob{
+			Matcher:    c.Matcher,
+			Tree:       saveNode(c.tree),
+			Separators: c.separators,
+			Prefix:     c.prefix,
+			Suffix:     c.suffix,
+			Literal:    c.literal,
+			MinLen:     c.minLen,
+			MaxLen:     c.maxLen,
+			First:      c.first,
+			Last:       c.last,
+			Captures:   c.captureNames,
+		}
+		if c.normalize != nil {
+			name, ok := normalizerName(c.normalize)
+			if !ok {
+				return fmt.Errorf("pattern #%d %q: could not save normalization", i, s.patterns[i])
+			}
+			sg.Normalize = name
+		}
+		out.Globs[i] = sg
+	}
+	for i, st := range s.contains.states {
+		out.Contains[i] = savedACState{Next: st.next, Fail: st.fail, Ends: st.ends, Out: st.out}
+	}
+	return gob.NewEncoder(w).Encode(&out)
+}
+
+// LoadGlobSet reads the set written by Save from r. Options could not be
+// saved, so the ones the set was compiled with should be given for Add to
+// compile new patterns the same way.
+func LoadGlobSet(r io.Reader, opts ...Option) (*GlobSet, error) {
+	var in savedSet
+	if err := gob.NewDecoder(r).Decode(&in); err != nil {
+		return nil, err
+	}
+	if in.Version != saveVersion {
+		return nil, fmt.Errorf("unsupported version %d of saved set", in.Version)
+	}
+	if len(in.Globs) != len(in.Patterns) {
+		return nil, fmt.Errorf("malformed saved set")
+	}
+	s := &GlobSet{
+		opts:      opts,
+		patterns:  in.Patterns,
+		globs:     make([]Glob, len(in.Globs)),
+		trie:      loadTrie(in.Trie),
+		workers:   in.Workers,
+		threshold: in.Threshold,
+		dfa:       in.DFA,
+		rest:      in.Rest,
+		dfaStates: in.DFAStates,
+	}
+	for i, sg := range in.Globs {
+		if sg.Matcher == nil {
+			return nil, fmt.Errorf("malformed saved set")
+		}
+		c := &compiled{
+			Matcher:    sg.Matcher,
+			tree:       loadNode(sg.Tree),
+			separators: sg.Separators,
+			prefix:     sg.Prefix,
+			suffix:     sg.Suffix,
+			literal:    sg.Literal,
+			minLen:     sg.MinLen,
+			maxLen:     sg.MaxLen,
+			first:      sg.First,
+			last:       sg.Last,
+		}
+		c.captureNames = sg.Captures
+		if sg.Normalize != "" {
+			n, ok := normalizers[sg.Normalize]
+			if !ok {
+				return nil, fmt.Errorf("unknown normalization %q of saved set", sg.Normalize)
+			}
+			c.normalize, c.normalization = n, sg.Normalize
+		}
+		s.globs[i] = c
+	}
+	s.contains.states = make([]acState, len(in.Contains))
+	for i, st := range in.Contains {
+		s.contains.states[i] = acState{next: st.Next, fail: st.Fail, ends: st.Ends, out: st.Out}
+	}
+	return s, nil
+}
+
+func saveNode(n *ast.Node) savedNode {
+	sn := savedNode{Kind: n.Kind, Value: n.Value}
+	for _, c := range n.Children {
+		sn.Children = append(sn.Children, saveNode(c))
+	}
+	return sn
+}
+
+func loadNode(sn savedNode) *ast.Node {
+	n := ast.NewNode(sn.Kind, sn.Value)
+	for _, c := range sn.Children {
+		ast.Insert(n, loadNode(c))
+	}
+	return n
+}
+
+func saveTrie(t *setTrie) *savedTrie {
+	st := &savedTrie{Globs: t.globs, Literals: t.literals}
+	if len(t.children) > 0 {
+		st.Children = make(map[byte]*savedTrie, len(t.children))
+		for c, child := range t.children {
+			st.Children[c] = saveTrie(child)
+		}
+	}
+	return st
+}
+
+func loadTrie(st *savedTrie) setTrie {
+	if st == nil {
+		return setTrie{}
+	}
+	t := setTrie{globs: st.Globs, literals: st.Literals}
+	if len(st.Children) > 0 {
+		t.children = make(map[byte]*setTrie, len(st.Children))
+		for c, child := range st.Children {
+			ct := loadTrie(child)
+			t.children[c] = &ct
+		}
+	}
+	return t
+}
@@ -0,0 +1,64 @@
+package glob
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestGlobSetSave(t *testing.T) {
+	patterns := []string{
+		"*.go", "main.go", "cmd/*", "*_test.go", "**/node_modules/**",
+		"{a,b}[0-9]?", "*vendor*", "[!x]*.md", "", "**",
+	}
+	subjects := []string{
+		"glob.go", "main.go", "cmd/x", "a_test.go", "x/node_modules/y", "a1x",
+		"my/vendor/z", "a.md", "x.md", "", "b2", "zaxbz", "xaybzc",
+	}
+	for _, test := range []struct {
+		patterns []string
+		opts     []Option
+	}{
+		{patterns, []Option{WithSeparators('/')}},
+		{patterns, []Option{WithSeparators('/'), WithCaseFold()}},
+		{[]string{"/a b/*", "/x/**"}, []Option{WithURLPath(true)}},
+		{[]string{"*.example.com"}, []Option{WithHostname(false)}},
+		{patterns, []Option{WithSeparators('/'), WithDFA(0)}},
+		{patterns, []Option{WithSeparators('/'), WithEngine(EngineDFA)}},
+		{patterns, []Option{WithSeparators('/'), WithEngine(EnginePikeVM)}},
+		{[]string{"*a?b*", "x*y?z*", "*a*b*c"}, nil},
+		{[]string{"[a-cx]*", "[[:digit:]]?", "x[!a-c[:space:]]"}, []Option{WithSeparators('/')}},
+		{[]string{"src/*/v1/*.go"}, []Option{WithSeparators('/'), WithCaptureNames("pkg")}},
+	} {
+		set := MustGlobSet(test.patterns, test.opts...)
+		var buf bytes.Buffer
+		if err := set.Save(&buf); err != nil {
+			t.Fatalf("Save(): unexpected error: %s", err)
+		}
+		loaded, err := LoadGlobSet(&buf)
+		if err != nil {
+			t.Fatalf("LoadGlobSet(): unexpected error: %s", err)
+		}
+		for i := 0; i < set.Len(); i++ {
+			a, b := loaded.Glob(i).(Analyzer), set.Glob(i).(Analyzer)
+			if loaded.Pattern(i) != set.Pattern(i) || !Equal(a, b) || a.Regexp() != b.Regexp() ||
+				a.ReplaceAll("src/api/v1/x.go", "$pkg:$1") != b.ReplaceAll("src/api/v1/x.go", "$pkg:$1") {
+				t.Errorf("glob #%d of %q differs after loading", i, set.Pattern(i))
+			}
+		}
+		for _, subj := range append(subjects, "/a%20b/c", "/x/y/z", "www.example.com.", "xd", "x ", "1a", "1/") {
+			if loaded.Which(subj) != set.Which(subj) || loaded.MatchAll(subj) != set.MatchAll(subj) {
+				t.Errorf("results for %q differ after loading", subj)
+			}
+		}
+	}
+
+	set := MustGlobSet([]string{"%{n}"}, WithPlaceholder("%{n}", runMatcher{func(rune) bool { return true }, -1}))
+	if err := set.Save(ioutil.Discard); err == nil {
+		t.Errorf("Save(): expected error for placeholders")
+	}
+	if _, err := LoadGlobSet(strings.NewReader("garbage")); err == nil {
+		t.Errorf("LoadGlobSet(): expected error for garbage")
+	}
+}
@@ -0,0 +1,185 @@
+package glob
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/match"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// WithPlaceholder makes the token in the pattern stand for the custom
+// matcher, so that domain-specific atoms like `%{uuid}` or `@semver` could
+// be used along with wildcards. The option could be given several times to
+// register several tokens; where tokens overlap in the pattern, the longest
+// one is taken. A token preceded by backslash, where the dialect escapes
+// characters, is matched literally, and a token inside of a character class
+// is an error.
+//
+// The matcher takes part in compilation like matchers of the syntax do: the
+// glob uses its Len and Index to split the string, and length bounds of the
+// glob account for its Len. Since strings the matcher accepts are not known,
+// Regexp renders the placeholder as `.*`, and Overlaps and Subsumes report
+// conservative results for globs with placeholders. Other options, like
+// WithCaseFold, do not affect the matcher.
+func WithPlaceholder(token string, m match.Matcher) Option {
+	return func(o *options) {
+		o.placeholders = append(o.placeholders, &ast.Placeholder{
+			Name:    token,
+			Matcher: m,
+		})
+	}
+}
+
+// placeholderBase is the first of the runes standing for placeholders while
+// the pattern is parsed. Runes of the Supplementary Private Use Area-B are
+// hardly ever found in patterns, and are reserved when placeholders are
+// registered.
+const placeholderBase = 0x100000
+
+// byTokenLength sorts indexes of placeholders by the length of their tokens,
+// the longest first.
+type byTokenLength struct {
+	index []int
+	ps    []*ast.Placeholder
+}
+
+func (b byTokenLength) Len() int      { return len(b.index) }
+func (b byTokenLength) Swap(i, j int) { b.index[i], b.index[j] = b.index[j], b.index[i] }
+func (b byTokenLength) Less(i, j int) bool {
+	return len(b.ps[b.index[i]].Name) > len(b.ps[b.index[j]].Name)
+}
+
+// substitutePlaceholders replaces unescaped tokens of placeholders in the
+// pattern with runes standing for them, starting from placeholderBase.
+func substitutePlaceholders(pattern string, ps []*ast.Placeholder, escape bool) (string, error) {
+	index := make([]int, len(ps))
+	for i, p := range ps {
+		if p.Name == "" {
+			return "", fmt.Errorf("placeholder token could not be empty")
+		}
+		if _, ok := p.Matcher.(match.Matcher); !ok {
+			return "", fmt.Errorf("placeholder %q has no matcher", p.Name)
+		}
+		index[i] = i
+	}
+	sort.Stable(byTokenLength{index, ps})
+
+	var buf []byte
+	for i := 0; i < len(pattern); {
+		r, w := utf8.DecodeRuneInString(pattern[i:])
+		if isPlaceholderRune(r, len(ps)) {
+			return "", fmt.Errorf("pattern contains rune %U reserved for placeholders", r)
+		}
+		if escape && r == '\\' && i+w < len(pattern) {
+			_, n := utf8.DecodeRuneInString(pattern[i+w:])
+			buf = append(buf, pattern[i:i+w+n]...)
+			i += w + n
+			continue
+		}
+		matched := false
+		for _, j := range index {
+			if strings.HasPrefix(pattern[i:], ps[j].Name) {
+				buf = appendRune(buf, placeholderBase+rune(j))
+				i += len(ps[j].Name)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			buf = append(buf, pattern[i:i+w]...)
+			i += w
+		}
+	}
+	return string(buf), nil
+}
+
+// placeholderOffset returns the offset in the original pattern of the byte
+// at the offset in the pattern with substituted placeholders.
+func placeholderOffset(pattern string, offset int, ps []*ast.Placeholder) int {
+	n := offset
+	for _, r := range pattern[:offset] {
+		if isPlaceholderRune(r, len(ps)) {
+			n += len(ps[r-placeholderBase].Name) - utf8.RuneLen(r)
+		}
+	}
+	return n
+}
+
+func appendRune(buf []byte, r rune) []byte {
+	var tmp [utf8.UTFMax]byte
+	n := utf8.EncodeRune(tmp[:], r)
+	return append(buf, tmp[:n]...)
+}
+
+func isPlaceholderRune(r rune, n int) bool {
+	return placeholderBase <= r && r < placeholderBase+rune(n)
+}
+
+// placeholderTree returns a copy of the tree, in which runes standing for
+// placeholders are replaced with placeholder nodes.
+func placeholderTree(tree *ast.Node, ps []*ast.Placeholder) (*ast.Node, error) {
+	switch tree.Kind {
+	case ast.KindText:
+		s := tree.Value.(ast.Text).Text
+		if strings.IndexFunc(s, func(r rune) bool { return isPlaceholderRune(r, len(ps)) }) == -1 {
+			return tree, nil
+		}
+		n := ast.NewNode(ast.KindPattern, nil)
+		var start int
+		for i, r := range s {
+			if !isPlaceholderRune(r, len(ps)) {
+				continue
+			}
+			if start < i {
+				ast.Insert(n, ast.NewNode(ast.KindText, ast.Text{Text: s[start:i]}))
+			}
+			ast.Insert(n, ast.NewNode(ast.KindPlaceholder, ps[r-placeholderBase]))
+			start = i + utf8.RuneLen(r)
+		}
+		if start < len(s) {
+			ast.Insert(n, ast.NewNode(ast.KindText, ast.Text{Text: s[start:]}))
+		}
+		return n, nil
+
+	case ast.KindList:
+		l := tree.Value.(ast.List)
+		for _, r := range l.Chars {
+			if isPlaceholderRune(r, len(ps)) {
+				return nil, fmt.Errorf("placeholder %q could not be used in character class", ps[r-placeholderBase].Name)
+			}
+		}
+		return tree, nil
+
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		for _, r := range c.Chars + c.Ranges {
+			if isPlaceholderRune(r, len(ps)) {
+				return nil, fmt.Errorf("placeholder %q could not be used in character class", ps[r-placeholderBase].Name)
+			}
+		}
+		return tree, nil
+
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		for _, c := range []rune{r.Lo, r.Hi} {
+			if isPlaceholderRune(c, len(ps)) {
+				return nil, fmt.Errorf("placeholder %q could not be used in character class", ps[c-placeholderBase].Name)
+			}
+		}
+		return tree, nil
+
+	default:
+		n := ast.NewNode(tree.Kind, tree.Value)
+		for _, c := range tree.Children {
+			c, err := placeholderTree(c, ps)
+			if err != nil {
+				return nil, err
+			}
+			ast.Insert(n, c)
+		}
+		return n, nil
+	}
+}
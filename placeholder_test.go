@@ -0,0 +1,118 @@
+package glob
+
+import (
+	"fmt"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/match"
+)
+
+// runMatcher matches n runes satisfying f, or one or more of them if n is
+// -1.
+type runMatcher struct {
+	f func(rune) bool
+	n int
+}
+
+func (m runMatcher) Match(s string) bool {
+	var n int
+	for _, r := range s {
+		if !m.f(r) {
+			return false
+		}
+		n++
+	}
+	return n > 0 && (m.n == -1 || n == m.n)
+}
+
+func (m runMatcher) Index(s string) (int, []int) {
+	for i := range s {
+		var segments []int
+		var n int
+		for j, r := range s[i:] {
+			if !m.f(r) || n == m.n {
+				break
+			}
+			n++
+			if m.n == -1 || n == m.n {
+				segments = append(segments, j+utf8.RuneLen(r))
+			}
+		}
+		if len(segments) > 0 {
+			return i, segments
+		}
+	}
+	return -1, nil
+}
+
+func (m runMatcher) Len() int { return m.n }
+
+func (m runMatcher) String() string { return fmt.Sprintf("<runs:%d>", m.n) }
+
+func TestWithPlaceholder(t *testing.T) {
+	isDigit := func(r rune) bool { return '0' <= r && r <= '9' }
+	isHex := func(r rune) bool { return isDigit(r) || 'a' <= r && r <= 'f' }
+	opts := []Option{
+		WithSeparators('/'),
+		WithPlaceholder("%{num}", runMatcher{isDigit, -1}),
+		WithPlaceholder("%{hex8}", runMatcher{isHex, 8}),
+		WithPlaceholder("%{hex}", runMatcher{isHex, -1}),
+		WithPlaceholder("%{lang}", match.NewSet("go", "rs")),
+		WithPlaceholder("%{Lang}", match.NewFoldSet("go", "rs")),
+	}
+	for id, test := range []struct {
+		pattern string
+		match   []string
+		miss    []string
+	}{
+		{"v%{num}", []string{"v1", "v123"}, []string{"v", "v1a", "va"}},
+		{"v%{num}.%{num}.*", []string{"v1.2.3", "v10.0.x"}, []string{"v1.x.3", "v1.2"}},
+		{"/users/%{hex8}/*", []string{"/users/deadbeef/a"}, []string{"/users/deadbee/a", "/users/deadbeef0/a"}},
+		{"%{hex}%{hex8}", []string{"a01234567", "aaaaaaaaaaaa"}, []string{"01234567", "x01234567"}},
+		{"{%{num},x}-*", []string{"12-a", "x-a"}, []string{"y-a", "-a"}},
+		{`\%{num}*`, []string{"%num", "%num1"}, []string{"1"}},
+		{`\%\{num\}`, []string{"%{num}"}, []string{"1"}},
+		{"*.%{lang}", []string{"a.go", "b.rs"}, []string{"a.GO", "a.py", "a/b.go"}},
+		{"*.%{Lang}", []string{"a.GO", "b.Rs"}, []string{"a.py", "a.gox"}},
+	} {
+		g, err := CompileWith(test.pattern, opts...)
+		if err != nil {
+			t.Errorf("#%d CompileWith(%q): unexpected error: %s", id, test.pattern, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+
+	g := MustCompileWith("/users/%{hex8}/%{num}.json", opts...).(Analyzer)
+	if g.Prefix() != "/users/" || g.Suffix() != ".json" || g.IsLiteral() {
+		t.Errorf("unexpected prefix %q, suffix %q or literal", g.Prefix(), g.Suffix())
+	}
+	if min, max := g.MinLen(), g.MaxLen(); min != 21 || max != -1 {
+		t.Errorf("MinLen, MaxLen = %d, %d; want 21, -1", min, max)
+	}
+	if Overlaps(g, MustCompile("/users/x")) {
+		t.Errorf("Overlaps() should be false for strings of different prefixes")
+	}
+	if !Overlaps(g, MustCompile("/users/*")) || Subsumes(MustCompile("/users/*"), g) {
+		t.Errorf("unexpected relations of glob with placeholders")
+	}
+
+	for _, pattern := range []string{"[%{num}]", "a\U00100000"} {
+		if _, err := CompileWith(pattern, opts...); err == nil {
+			t.Errorf("CompileWith(%q): expected error", pattern)
+		}
+	}
+	if _, err := CompileWith("a", WithPlaceholder("", runMatcher{isDigit, -1})); err == nil {
+		t.Errorf("CompileWith(): expected error for empty token")
+	}
+}
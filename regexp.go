@@ -0,0 +1,364 @@
+package glob
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/nfa"
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// regexpString renders the syntax tree as an anchored RE2 expression that
+// matches the same strings as the glob compiled with given separators, or a
+// superset of them if the tree has placeholders. If c is not nil, every wildcard, character class and
+// alternation is wrapped into a capturing group, named as c says.
+func regexpString(tree *ast.Node, sep []rune, c *captures) string {
+	var buf bytes.Buffer
+	buf.WriteString(`(?s)^`)
+	writeRegexp(&buf, tree, sep, c)
+	buf.WriteString(`$`)
+	return buf.String()
+}
+
+// WithCaptureNames names the wildcards of the pattern for ReplaceAll, in
+// the order they are numbered, so that $name and ${name} in templates
+// denote the text matched by the wildcard. Empty names leave wildcards
+// unnamed. CompileWith returns error if a name is not made of ASCII
+// letters, digits and underscores, starts with a digit, or if there are
+// more names than wildcards.
+func WithCaptureNames(names ...string) Option {
+	return func(o *options) {
+		o.captureNames = names
+	}
+}
+
+// checkCaptureNames returns error if the names could not be given to the
+// capturing groups of the glob. See WithCaptureNames for details.
+func checkCaptureNames(g *compiled, names []string) error {
+	for _, name := range names {
+		for i, r := range name {
+			word := r == '_' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || i > 0 && '0' <= r && r <= '9'
+			if !word {
+				return fmt.Errorf("invalid capture name %q", name)
+			}
+		}
+	}
+	if n := g.captureRegexp().NumSubexp(); len(names) > n {
+		return fmt.Errorf("%d capture names given for %d wildcards", len(names), n)
+	}
+	return nil
+}
+
+// captures names capturing groups in the order they are written, as set by
+// WithCaptureNames.
+type captures struct {
+	names []string
+	n     int
+}
+
+// open writes the opening parenthesis of the next capturing group.
+func (c *captures) open(buf *bytes.Buffer) {
+	if c.n < len(c.names) && c.names[c.n] != "" {
+		buf.WriteString("(?P<")
+		buf.WriteString(c.names[c.n])
+		buf.WriteByte('>')
+	} else {
+		buf.WriteByte('(')
+	}
+	c.n++
+}
+
+func writeRegexp(buf *bytes.Buffer, tree *ast.Node, sep []rune, c *captures) {
+	open := func() {
+		if c != nil {
+			c.open(buf)
+		} else {
+			buf.WriteString("(?:")
+		}
+	}
+	group := func(s string) {
+		if c != nil {
+			c.open(buf)
+			buf.WriteString(s)
+			buf.WriteByte(')')
+		} else {
+			buf.WriteString(s)
+		}
+	}
+
+	switch tree.Kind {
+	case ast.KindPattern:
+		for _, child := range tree.Children {
+			writeRegexp(buf, child, sep, c)
+		}
+
+	case ast.KindAnyOf:
+		open()
+		for i, child := range tree.Children {
+			if i > 0 {
+				buf.WriteByte('|')
+			}
+			writeRegexp(buf, child, sep, c)
+		}
+		buf.WriteByte(')')
+
+	case ast.KindText:
+		buf.WriteString(regexp.QuoteMeta(tree.Value.(ast.Text).Text))
+
+	case ast.KindAny:
+		if len(sep) == 0 {
+			group(`.*`)
+		} else {
+			group(regexpClass(sep, true) + `*`)
+		}
+
+	case ast.KindSuper, ast.KindPlaceholder:
+		// Strings matched by custom matchers are not known, so any string
+		// is matched in place of a placeholder.
+		group(`.*`)
+
+	case ast.KindSingle:
+		if len(sep) == 0 {
+			group(`.`)
+		} else {
+			group(regexpClass(sep, true))
+		}
+
+	case ast.KindList:
+		l := tree.Value.(ast.List)
+		group(regexpClass([]rune(l.Chars), l.Not))
+
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		var b bytes.Buffer
+		b.WriteByte('[')
+		if r.Not {
+			b.WriteByte('^')
+		}
+		writeClassRune(&b, r.Lo)
+		b.WriteByte('-')
+		writeClassRune(&b, r.Hi)
+		b.WriteByte(']')
+		group(b.String())
+
+	case ast.KindClass:
+		if set := nodeSet(tree); set.Empty() {
+			group(regexpClass(nil, false))
+		} else {
+			group(regexpSet(set))
+		}
+
+	case ast.KindNothing:
+		// Matches empty string.
+	}
+}
+
+func regexpClass(rs []rune, not bool) string {
+	if len(rs) == 0 {
+		// RE2 has no syntax for the empty class and its complement.
+		if not {
+			return `.`
+		}
+		return `[^\x00-\x{10ffff}]`
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	if not {
+		buf.WriteByte('^')
+	}
+	for _, r := range rs {
+		writeClassRune(&buf, r)
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
+
+func writeClassRune(buf *bytes.Buffer, r rune) {
+	switch {
+	case r == '\\' || r == ']' || r == '[' || r == '^' || r == '-':
+		buf.WriteByte('\\')
+		buf.WriteRune(r)
+	case r < ' ' || r == utf8.RuneError || r == 0x7f:
+		buf.WriteString(`\x{`)
+		buf.WriteString(strconv.FormatInt(int64(r), 16))
+		buf.WriteByte('}')
+	default:
+		buf.WriteRune(r)
+	}
+}
+
+// automatonRegexp renders the automaton as an anchored RE2 expression by the
+// state elimination method. The expression could be much longer than the
+// automaton.
+func automatonRegexp(n *nfa.NFA) string {
+	// Edges are labeled by expressions. The automaton is extended with the
+	// new start and final states, which are the only ones left at the end.
+	var (
+		start = len(n.States)
+		final = start + 1
+		out   = make(map[int]map[int]*rx)
+		in    = make(map[int]map[int]*rx)
+	)
+	edge := func(i, j int, e *rx) {
+		if out[i] == nil {
+			out[i] = make(map[int]*rx)
+		}
+		if in[j] == nil {
+			in[j] = make(map[int]*rx)
+		}
+		e = rxAlt(out[i][j], e)
+		out[i][j] = e
+		in[j][i] = e
+	}
+	edge(start, n.Start, &rx{prec: rxAtom})
+	edge(n.Final, final, &rx{prec: rxAtom})
+	for i, st := range n.States {
+		for _, e := range st.Epsilon {
+			edge(i, e, &rx{prec: rxAtom})
+		}
+		if st.Next != -1 && !st.Runes.Empty() {
+			edge(i, st.Next, &rx{regexpSet(st.Runes), rxAtom})
+		}
+	}
+	left := make(map[int]bool, len(n.States))
+	for k := range n.States {
+		left[k] = true
+	}
+	for len(left) > 0 {
+		// States with the fewest edges are eliminated first, which keeps
+		// the expression shorter.
+		k := -1
+		for _, i := range sortedStates(left) {
+			if k == -1 || len(in[i])*len(out[i]) < len(in[k])*len(out[k]) {
+				k = i
+			}
+		}
+		delete(left, k)
+		loop := rxStar(out[k][k])
+		// Edges are visited in order for the result to be deterministic.
+		for _, i := range sortedKeys(in[k]) {
+			if i == k {
+				continue
+			}
+			for _, j := range sortedKeys(out[k]) {
+				if j == k {
+					continue
+				}
+				edge(i, j, rxCat(rxCat(in[k][i], loop), out[k][j]))
+			}
+			delete(out[i], k)
+		}
+		for j := range out[k] {
+			delete(in[j], k)
+		}
+		delete(in, k)
+		delete(out, k)
+	}
+	e := out[start][final]
+	if e == nil {
+		// RE2 has no syntax for the empty class.
+		e = &rx{`[^\x00-\x{10ffff}]`, rxAtom}
+	}
+	return `(?s)^` + rxCat(e, &rx{prec: rxAtom}).s + `$`
+}
+
+func sortedStates(m map[int]bool) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func sortedKeys(m map[int]*rx) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+// rx is an expression being built from the automaton. The precedence of its
+// top-level operator tells when it must be grouped.
+type rx struct {
+	s    string
+	prec int
+}
+
+const (
+	rxAlternate = iota
+	rxConcat
+	rxAtom
+)
+
+func rxAlt(a, b *rx) *rx {
+	switch {
+	case a == nil:
+		return b
+	case b == nil || a.s == b.s:
+		return a
+	}
+	return &rx{a.s + `|` + b.s, rxAlternate}
+}
+
+func rxCat(a, b *rx) *rx {
+	switch {
+	case a.s == "":
+		return rxGroup(b, rxConcat)
+	case b.s == "":
+		return rxGroup(a, rxConcat)
+	}
+	return &rx{rxGroup(a, rxConcat).s + rxGroup(b, rxConcat).s, rxConcat}
+}
+
+func rxStar(a *rx) *rx {
+	if a == nil || a.s == "" {
+		return &rx{prec: rxAtom}
+	}
+	return &rx{rxGroup(a, rxAtom).s + `*`, rxConcat}
+}
+
+// rxGroup groups the expression if its precedence is lower than prec.
+func rxGroup(a *rx, prec int) *rx {
+	if a.prec >= prec {
+		return a
+	}
+	return &rx{`(?:` + a.s + `)`, rxAtom}
+}
+
+// regexpSet renders the set of runes as an expression matching one of them.
+func regexpSet(set runes.Set) string {
+	if set.Contains(nfa.Invalid) {
+		// Expressions see invalid bytes as U+FFFD, which automata tell
+		// apart.
+		set = set.Subtract(runes.Of(nfa.Invalid)).Union(runes.Of(utf8.RuneError))
+	}
+	if r, ok := set.Single(); ok {
+		return regexp.QuoteMeta(string(r))
+	}
+	if set.Equal(runes.All) {
+		return `.`
+	}
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	if c := set.Complement(); len(c) < len(set) {
+		buf.WriteByte('^')
+		set = c
+	}
+	for _, r := range set {
+		writeClassRune(&buf, r.Lo)
+		if r.Hi != r.Lo {
+			buf.WriteByte('-')
+			writeClassRune(&buf, r.Hi)
+		}
+	}
+	buf.WriteByte(']')
+	return buf.String()
+}
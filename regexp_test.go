@@ -0,0 +1,79 @@
+package glob
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRegexp(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		sep     []rune
+		exp     string
+		samples []string
+	}{
+		{`abc.d`, nil, `(?s)^abc\.d$`, []string{"abc.d", "abcxd"}},
+		{`*.go`, nil, `(?s)^.*\.go$`, []string{"a.go", "a/b.go", ".go"}},
+		{`*.go`, []rune{'/'}, `(?s)^[^/]*\.go$`, []string{"a.go", "a/b.go"}},
+		{`**/?.go`, []rune{'/'}, `(?s)^.*/[^/]\.go$`, []string{"a/b.go", "a/bc.go", "/b.go"}},
+		{`[!a-c]{x,y*}`, []rune{'.'}, `(?s)^[^a-c](?:x|y[^.]*)$`, []string{"dx", "ay", "dy.", "dyz"}},
+		{`[\]^\-]`, nil, "", []string{"]", "^", "-", "\\", "a"}},
+		{"a\x01?", nil, "", []string{"a\x01b", "a\x02b"}},
+	} {
+		g := MustCompile(test.pattern, test.sep...).(Analyzer)
+		act := g.Regexp()
+		if test.exp != "" && act != test.exp {
+			t.Errorf("#%d %q.Regexp() = %q; want %q", id, test.pattern, act, test.exp)
+		}
+		re, err := regexp.Compile(act)
+		if err != nil {
+			t.Errorf("#%d %q.Regexp() = %q is not valid: %s", id, test.pattern, act, err)
+			continue
+		}
+		for _, s := range test.samples {
+			if g.Match(s) != re.MatchString(s) {
+				t.Errorf("#%d %q and %q disagree on %q", id, test.pattern, act, s)
+			}
+		}
+	}
+}
+
+func TestRegexpApproximate(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		exp     string
+		both    []string // matched by the glob and the expression
+		re      []string // matched by the expression only
+		glob    []string // matched by the glob only, before normalization
+	}{
+		{`a?`, []Option{WithGraphemes()}, `(?s)^a.*$`, []string{"ab", "aé"}, []string{"a", "abc"}, nil},
+		{`f(!(x))`, []Option{WithDialect(DialectBash), WithBash(BashExtglob)}, `(?s)^f\(.*\)$`, []string{"f(y)"}, []string{"f(x)"}, nil},
+		{`*.example.com`, []Option{WithHostname(false)}, "", []string{"www.example.com", "WWW.Example.com"}, nil, []string{"www.example.com."}},
+		{`/a/b`, []Option{WithURLPath(true)}, `(?s)^/a/b$`, []string{"/a/b"}, nil, []string{"/a/%62"}},
+	} {
+		g, err := CompileWith(test.pattern, test.opts...)
+		if err != nil {
+			t.Fatalf("#%d %q: %s", id, test.pattern, err)
+		}
+		act := g.(Analyzer).Regexp()
+		if test.exp != "" && act != test.exp {
+			t.Errorf("#%d %q.Regexp() = %q; want %q", id, test.pattern, act, test.exp)
+		}
+		re := regexp.MustCompile(act)
+		for _, c := range []struct {
+			samples   []string
+			glob, exp bool
+		}{
+			{test.both, true, true},
+			{test.re, false, true},
+			{test.glob, true, false},
+		} {
+			for _, s := range c.samples {
+				if g.Match(s) != c.glob || re.MatchString(s) != c.exp {
+					t.Errorf("#%d %q and %q on %q: %t and %t; want %t and %t", id, test.pattern, act, s, g.Match(s), re.MatchString(s), c.glob, c.exp)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package glob
+
+import (
+	"strings"
+)
+
+// Overlaps reports whether some string is matched by both globs, so that
+// config validators could warn about rules claiming the same input. Globs
+// normalizing strings before matching, like those compiled WithURLPath, are
+// compared by the normalized strings. For globs not created by this
+// package Overlaps conservatively returns true.
+func Overlaps(a, b Glob) bool {
+	p, q := analyzerOf(a), analyzerOf(b)
+	if !canOverlap(p.Prefix(), q.Prefix(), p.Suffix(), q.Suffix()) {
+		return false
+	}
+	if p.MaxLen() != -1 && p.MaxLen() < q.MinLen() || q.MaxLen() != -1 && q.MaxLen() < p.MinLen() {
+		return false
+	}
+	x, y := automatonOf(a), automatonOf(b)
+	if x == nil || y == nil {
+		return true
+	}
+	return x.Intersects(y)
+}
+
+// canOverlap reports whether strings with given literal prefixes and
+// suffixes could be the same.
+func canOverlap(p1, p2, s1, s2 string) bool {
+	if len(p1) > len(p2) {
+		p1, p2 = p2, p1
+	}
+	if len(s1) > len(s2) {
+		s1, s2 = s2, s1
+	}
+	return p2[:len(p1)] == p1 && s2[len(s2)-len(s1):] == s1
+}
+
+// Subsumes reports whether every string matched by b is matched by a as
+// well. For instance, `*.go` subsumes `cmd/*.go` if both globs are compiled
+// without separators, but not with '/' separator. It could be used to find
+// duplicate or shadowed rules. Globs normalizing strings before matching
+// are compared by the normalized strings. For globs not created by this
+// package Subsumes conservatively returns false.
+func Subsumes(a, b Glob) bool {
+	p, q := analyzerOf(a), analyzerOf(b)
+	x, y := automatonOf(a), automatonOf(b)
+	if x == nil || y == nil {
+		return false
+	}
+	if !strings.HasPrefix(q.Prefix(), p.Prefix()) || !strings.HasSuffix(q.Suffix(), p.Suffix()) {
+		return false
+	}
+	if q.MinLen() < p.MinLen() || p.MaxLen() != -1 && (q.MaxLen() == -1 || q.MaxLen() > p.MaxLen()) {
+		return false
+	}
+	return x.Includes(y)
+}
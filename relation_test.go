@@ -0,0 +1,60 @@
+package glob
+
+import "testing"
+
+func TestOverlaps(t *testing.T) {
+	for id, test := range []struct {
+		a, b string
+		opts []Option
+		exp  bool
+	}{
+		{"*.go", "*_test.go", nil, true},
+		{"*.go", "*.md", nil, false},
+		{"cmd/*", "*.go", []Option{WithSeparators('/')}, false},
+		{"cmd/*", "**.go", []Option{WithSeparators('/')}, true},
+		{"cmd/*", "cmd/*/*", []Option{WithSeparators('/')}, false},
+		{"cmd/**", "cmd/*/*", []Option{WithSeparators('/')}, true},
+		{"src/*.go", "doc/*", nil, false},
+		{"a?", "a??", nil, false},
+		{"{api,web}/*", "web/index.html", []Option{WithSeparators('/')}, true},
+		{"[a-m]*", "[n-z]*", nil, false},
+		{"README.*", "readme.md", []Option{WithCaseFold()}, true},
+		{"*a*b*", "*b*a*", nil, true},
+	} {
+		a := MustCompileWith(test.a, test.opts...)
+		b := MustCompileWith(test.b, test.opts...)
+		if act := Overlaps(a, b); act != test.exp {
+			t.Errorf("#%d Overlaps(%q, %q) = %t; want %t", id, test.a, test.b, act, test.exp)
+		}
+		if act := Overlaps(b, a); act != test.exp {
+			t.Errorf("#%d Overlaps(%q, %q) = %t; want %t", id, test.b, test.a, act, test.exp)
+		}
+	}
+}
+
+func TestSubsumes(t *testing.T) {
+	for id, test := range []struct {
+		a, b string
+		opts []Option
+		exp  bool
+	}{
+		{"*.go", "cmd/*.go", nil, true},
+		{"*.go", "cmd/*.go", []Option{WithSeparators('/')}, false},
+		{"**.go", "cmd/*.go", []Option{WithSeparators('/')}, true},
+		{"cmd/*.go", "*.go", nil, false},
+		{"*", "*", nil, true},
+		{"a?", "a*", nil, false},
+		{"a*", "a?", nil, true},
+		{"{a,b}*", "[ab]x", nil, true},
+		{"*.[ch]", "*.{c,h}", nil, true},
+		{"*.[ch]", "*.{c,cc}", nil, false},
+		{"readme*", "README.md", []Option{WithCaseFold()}, true},
+		{"README.md", "readme*", []Option{WithCaseFold()}, false},
+	} {
+		a := MustCompileWith(test.a, test.opts...)
+		b := MustCompileWith(test.b, test.opts...)
+		if act := Subsumes(a, b); act != test.exp {
+			t.Errorf("#%d Subsumes(%q, %q) = %t; want %t", id, test.a, test.b, act, test.exp)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+//go:build go1.18
+// +build go1.18
+
+package glob
+
+import (
+	"sort"
+)
+
+// Router maps patterns to values and looks up the value of the most
+// specific pattern matching a string. It suits API gateways and config
+// overrides, where several patterns like `/api/*` and `/api/users` could
+// match the same input.
+//
+// Patterns are ordered by priority first, and then as Compare does. Of
+// equally specific patterns of the same priority the one added earlier
+// wins.
+//
+// Router is not safe for Add concurrent with other calls.
+type Router[T any] struct {
+	opts     []Option
+	literals map[string]*route[T]
+	routes   []*route[T]
+}
+
+type route[T any] struct {
+	pattern  string
+	glob     Glob
+	value    T
+	priority int
+}
+
+// NewRouter returns an empty Router, which compiles patterns with given
+// options by CompileWith.
+func NewRouter[T any](opts ...Option) *Router[T] {
+	return &Router[T]{
+		opts:     opts,
+		literals: make(map[string]*route[T]),
+	}
+}
+
+// Add maps the pattern to the value with priority 0. If the pattern was
+// already added, its value is replaced and it keeps its place among equally
+// specific patterns.
+func (r *Router[T]) Add(pattern string, value T) error {
+	return r.AddPriority(pattern, 0, value)
+}
+
+// AddPriority maps the pattern to the value with the priority. Patterns of
+// higher priority are tried before patterns of lower one regardless of
+// specificity, which suits override hierarchies like global < team < user
+// rules. If the pattern was already added, its value and priority are
+// replaced; it keeps its place among equally specific patterns unless the
+// priority changes.
+func (r *Router[T]) AddPriority(pattern string, priority int, value T) error {
+	for i, rt := range r.routes {
+		if rt.pattern != pattern {
+			continue
+		}
+		rt.value = value
+		if rt.priority != priority {
+			r.routes = append(r.routes[:i], r.routes[i+1:]...)
+			if lit, ok := r.literal(rt); ok && r.literals[lit] == rt {
+				delete(r.literals, lit)
+				for _, x := range r.routes {
+					if l, ok := r.literal(x); ok && l == lit {
+						r.literals[lit] = x
+						break
+					}
+				}
+			}
+			rt.priority = priority
+			r.insert(rt)
+		}
+		return nil
+	}
+	g, err := CompileWith(pattern, r.opts...)
+	if err != nil {
+		return err
+	}
+	r.insert(&route[T]{pattern, g, value, priority})
+	return nil
+}
+
+// insert inserts the route after routes it does not precede.
+func (r *Router[T]) insert(rt *route[T]) {
+	if lit, ok := r.literal(rt); ok {
+		if x := r.literals[lit]; x == nil || r.less(rt, x) {
+			r.literals[lit] = rt
+		}
+	}
+	i := sort.Search(len(r.routes), func(i int) bool {
+		return r.less(rt, r.routes[i])
+	})
+	r.routes = append(r.routes, nil)
+	copy(r.routes[i+1:], r.routes[i:])
+	r.routes[i] = rt
+}
+
+// less reports whether a is tried before b.
+func (r *Router[T]) less(a, b *route[T]) bool {
+	if a.priority != b.priority {
+		return a.priority > b.priority
+	}
+	return Compare(a.glob, b.glob) < 0
+}
+
+// literal returns the string the route matches, if it matches just one.
+func (r *Router[T]) literal(rt *route[T]) (string, bool) {
+	c := rt.glob.(*compiled)
+	lit, ok := c.Literal()
+	return lit, ok && c.normalize == nil
+}
+
+// Lookup returns the value of the most specific pattern of the highest
+// priority matching s and the pattern itself. If no pattern matches s, ok is
+// false.
+func (r *Router[T]) Lookup(s string) (value T, pattern string, ok bool) {
+	// Literal patterns precede other patterns of the same priority.
+	if rt := r.literals[s]; rt != nil && rt.priority == r.routes[0].priority {
+		return rt.value, rt.pattern, true
+	}
+	for _, rt := range r.routes {
+		if rt.glob.Match(s) {
+			return rt.value, rt.pattern, true
+		}
+	}
+	return value, "", false
+}
+
+// Len returns the number of patterns of the router.
+func (r *Router[T]) Len() int {
+	return len(r.routes)
+}
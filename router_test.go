@@ -0,0 +1,109 @@
+//go:build go1.18
+// +build go1.18
+
+package glob
+
+import (
+	"testing"
+)
+
+func TestRouter(t *testing.T) {
+	r := NewRouter[int](WithSeparators('/'))
+	for i, p := range []string{
+		"/api/**",
+		"/api/*",
+		"/api/users",
+		"/api/users/*",
+		"*.json",
+		"/api/*/export",
+		"/api/users/*.json",
+		"/**",
+		"/api/*.json",
+	} {
+		if err := r.Add(p, i); err != nil {
+			t.Fatalf("Add(%q): unexpected error: %s", p, err)
+		}
+	}
+	for _, test := range []struct {
+		s       string
+		pattern string
+		value   int
+		ok      bool
+	}{
+		{"/api/users", "/api/users", 2, true},
+		{"/api/orders", "/api/*", 1, true},
+		{"/api/users/1", "/api/users/*", 3, true},
+		{"/api/users/1.json", "/api/users/*.json", 6, true},
+		{"/api/orders/export", "/api/*/export", 5, true},
+		{"/api/orders/1", "/api/**", 0, true},
+		{"/api/x.json", "/api/*.json", 8, true},
+		{"/x.json", "/**", 7, true},
+		{"x.json", "*.json", 4, true},
+		{"x", "", 0, false},
+	} {
+		value, pattern, ok := r.Lookup(test.s)
+		if value != test.value || pattern != test.pattern || ok != test.ok {
+			t.Errorf("Lookup(%q) = %d, %q, %t; want %d, %q, %t",
+				test.s, value, pattern, ok, test.value, test.pattern, test.ok)
+		}
+	}
+
+	if err := r.Add("/api/*", 10); err != nil {
+		t.Fatalf("Add(): unexpected error: %s", err)
+	}
+	if value, _, _ := r.Lookup("/api/orders"); value != 10 || r.Len() != 9 {
+		t.Errorf("unexpected results after replacing the value")
+	}
+	if err := r.Add("[a", 0); err == nil {
+		t.Errorf("Add(): expected error for invalid pattern")
+	}
+
+	// Equally specific patterns are tried in order of addition.
+	r = NewRouter[int]()
+	r.Add("a*c", 0)
+	r.Add("a?c", 1)
+	r.Add("a*", 2)
+	r.Add("ab*", 3)
+	for _, test := range []struct {
+		s     string
+		value int
+	}{
+		{"abc", 3},
+		{"axc", 1},
+		{"axxc", 0},
+		{"ax", 2},
+	} {
+		if value, _, _ := r.Lookup(test.s); value != test.value {
+			t.Errorf("Lookup(%q) = %d; want %d", test.s, value, test.value)
+		}
+	}
+
+	// Priorities precede specificity.
+	r = NewRouter[int](WithSeparators('/'))
+	r.AddPriority("**", 0, 0)
+	r.AddPriority("team/*", 1, 1)
+	r.AddPriority("team/a", 0, 2)
+	r.AddPriority("team/a/*", 2, 3)
+	r.AddPriority("team/b", 1, 4)
+	for _, test := range []struct {
+		s     string
+		value int
+	}{
+		{"team/a", 1},
+		{"team/b", 4},
+		{"team/a/x", 3},
+		{"x", 0},
+	} {
+		if value, _, _ := r.Lookup(test.s); value != test.value {
+			t.Errorf("Lookup(%q) = %d; want %d", test.s, value, test.value)
+		}
+	}
+	r.AddPriority("team/a", 5, 2)
+	r.AddPriority("team/b", 0, 4)
+	if value, _, _ := r.Lookup("team/a"); value != 2 || r.Len() != 5 {
+		t.Errorf("Lookup() = %d after raising priority; want 2", value)
+	}
+	if value, _, _ := r.Lookup("team/b"); value != 1 {
+		t.Errorf("Lookup() = %d after lowering priority; want 1", value)
+	}
+}
@@ -0,0 +1,164 @@
+package glob
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gobwas/glob/syntax"
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// rsyncTree returns syntax tree matching the same paths as the single rsync
+// filter pattern. See DialectRsync for details.
+func rsyncTree(pattern string) (*ast.Node, error) {
+	tree, dirOnly, inside, err := rsyncRule(pattern)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case inside:
+		ast.Insert(tree, anyInside())
+	case dirOnly:
+		ast.Insert(tree, ast.NewNode(ast.KindText, ast.Text{Text: "/"}))
+	default:
+		// Directory paths end with separator.
+		ast.Insert(tree, ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindPattern, nil),
+			ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindText, ast.Text{Text: "/"})),
+		))
+	}
+	return tree, nil
+}
+
+// rsyncRule returns syntax tree matching the paths which the single rsync
+// filter pattern matches by itself, with directories named without trailing
+// '/'. It also reports whether the pattern matches only directories, and
+// whether it ends with `/***`, so that everything inside of the directories
+// is matched as well.
+func rsyncRule(pattern string) (tree *ast.Node, dirOnly, inside bool, err error) {
+	p := pattern
+	if p == "" {
+		return nil, false, false, fmt.Errorf("rsync pattern is empty")
+	}
+	inside = strings.HasSuffix(p, "/***")
+	p = strings.TrimSuffix(p, "/***")
+	dirOnly = inside || strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.HasPrefix(p, "/")
+	p = strings.TrimPrefix(p, "/")
+
+	tree = ast.NewNode(ast.KindPattern, nil)
+	if !anchored {
+		ast.Insert(tree, anyDirs())
+	}
+	nodes, err := shell{seps: []rune{'/'}, doubleStar: true}.nodes(p)
+	if err != nil {
+		return nil, false, false, err
+	}
+	if last := p[strings.LastIndexByte(p, '/')+1:]; last != "" && strings.Trim(last, "*") == "" {
+		// Names are never empty, so that `dir/*` does not match "dir/",
+		// which stands for the directory itself.
+		n := len(nodes) - 1
+		nodes = append(nodes[:n:n], ast.NewNode(ast.KindSingle, nil), nodes[n])
+	}
+	ast.Insert(tree, nodes...)
+	return tree, dirOnly, inside, nil
+}
+
+// RsyncFilter is an ordered list of rsync include and exclude rules.
+type RsyncFilter struct {
+	rules []ignoreRule
+}
+
+// LoadRsyncFilter reads rsync(1) filter rules from r, as given to the
+// --filter or --exclude-from options. Each line is either an include rule
+// (`+ pattern` or `include pattern`), an exclude rule (`- pattern` or
+// `exclude pattern`), or `!`, which clears the rules read before. Blank lines
+// and lines starting with `#` or `;` are skipped. Other rules, such as merge
+// or protect rules, and rule modifiers are not supported.
+func LoadRsyncFilter(r io.Reader) (*RsyncFilter, error) {
+	var (
+		f RsyncFilter
+		n int
+	)
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		n++
+		line := strings.TrimSuffix(s.Text(), "\r")
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line == "!" {
+			f.rules = f.rules[:0]
+			continue
+		}
+		var rule ignoreRule
+		switch {
+		case strings.HasPrefix(line, "+ "), strings.HasPrefix(line, "+_"):
+			rule.negate = true
+			line = line[2:]
+		case strings.HasPrefix(line, "include "):
+			rule.negate = true
+			line = line[len("include "):]
+		case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "-_"):
+			line = line[2:]
+		case strings.HasPrefix(line, "exclude "):
+			line = line[len("exclude "):]
+		default:
+			return nil, fmt.Errorf("line %d: unsupported filter rule %q", n, line)
+		}
+		tree, dirOnly, inside, err := rsyncRule(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s", n, err)
+		}
+		trees := []*ast.Node{tree}
+		if inside {
+			// `dir/***` matches the directory as `dir/` does, and
+			// everything inside of it.
+			tree, _, _, _ := rsyncRule(line)
+			ast.Insert(tree,
+				ast.NewNode(ast.KindText, ast.Text{Text: "/"}),
+				ast.NewNode(ast.KindSuper, nil),
+			)
+			trees = append(trees, tree)
+		}
+		for i, tree := range trees {
+			g, err := CompileAST(&syntax.Pattern{Source: line, Tree: tree}, '/')
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %s", n, err)
+			}
+			rule.glob, rule.dirOnly = g, dirOnly && i == 0
+			f.rules = append(f.rules, rule)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// Match reports whether the path is excluded from the transfer. The path is
+// relative to the root of the transfer and uses '/' as the separator. The
+// first rule matching the path is applied. As rsync does not descend into
+// excluded directories, the path is excluded if any of its parent
+// directories is excluded.
+func (f *RsyncFilter) Match(path string, isDir bool) bool {
+	path = strings.TrimSuffix(path, "/")
+	for i := 0; i < len(path); i++ {
+		if path[i] == '/' && f.match(path[:i], true) {
+			return true
+		}
+	}
+	return f.match(path, isDir)
+}
+
+func (f *RsyncFilter) match(path string, isDir bool) bool {
+	for _, r := range f.rules {
+		if (isDir || !r.dirOnly) && r.glob.Match(path) {
+			return !r.negate
+		}
+	}
+	return false
+}
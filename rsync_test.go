@@ -0,0 +1,135 @@
+package glob
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDialectRsync(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: `*.o`,
+			match:   []string{"a.o", "x/y/a.o", "a.o/"},
+			miss:    []string{"a.oo", "a.o/b"},
+		},
+		{
+			pattern: `/foo`,
+			match:   []string{"foo", "foo/"},
+			miss:    []string{"x/foo"},
+		},
+		{
+			pattern: `foo/`,
+			match:   []string{"foo/", "x/foo/"},
+			miss:    []string{"foo", "foo/x"},
+		},
+		{
+			pattern: `foo/bar`,
+			match:   []string{"foo/bar", "x/foo/bar"},
+			miss:    []string{"xfoo/bar"},
+		},
+		{
+			pattern: `/a/**/b`,
+			match:   []string{"a/x/b", "a/x/y/b"},
+			miss:    []string{"a/b"},
+		},
+		{
+			pattern: `dir/***`,
+			match:   []string{"dir", "dir/", "dir/x", "y/dir/x/z"},
+			miss:    []string{"dirx"},
+		},
+		{
+			pattern: `foo/*`,
+			match:   []string{"foo/x", "foo/x/", "a/foo/x"},
+			miss:    []string{"foo", "foo/", "foo/x/y"},
+		},
+		{
+			pattern: `[!a]*`,
+			match:   []string{"b", "x/bc"},
+			miss:    []string{"a", "x/ab"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithDialect(DialectRsync))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+}
+
+func TestLoadRsyncFilter(t *testing.T) {
+	f, err := LoadRsyncFilter(strings.NewReader(strings.Join([]string{
+		"# comment",
+		"- *.tmp",
+		"!",
+		"+ *.go",
+		"+ */",
+		"include /keep/***",
+		"- /vendor/",
+		"exclude *",
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, test := range []struct {
+		path  string
+		isDir bool
+		exp   bool
+	}{
+		{"main.go", false, false},
+		{"src/", true, false},
+		{"src/main.go", false, false},
+		{"src/main.c", false, true},
+		{"a.tmp", false, true},
+		{"keep/x.c", false, false},
+		{"vendor", true, false},
+		{"vendor/x.go", false, false},
+		{"readme", false, true},
+	} {
+		if act := f.Match(test.path, test.isDir); act != test.exp {
+			t.Errorf("#%d Match(%q, %t) = %t; want %t", id, test.path, test.isDir, act, test.exp)
+		}
+	}
+
+	f, err = LoadRsyncFilter(strings.NewReader("- foo/*\n- /dir/***\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, test := range []struct {
+		path  string
+		isDir bool
+		exp   bool
+	}{
+		{"foo", true, false},
+		{"foo/", true, false},
+		{"foo/x", false, true},
+		{"foo/x", true, true},
+		{"foo/x/y", false, true},
+		{"a/foo", true, false},
+		{"a/foo/x", false, true},
+		{"dir", true, true},
+		{"dir", false, false},
+		{"dir/x", false, true},
+		{"a/dir/x", false, false},
+	} {
+		if act := f.Match(test.path, test.isDir); act != test.exp {
+			t.Errorf("#%d Match(%q, %t) = %t; want %t", id, test.path, test.isDir, act, test.exp)
+		}
+	}
+	if _, err := LoadRsyncFilter(strings.NewReader("+ a\n. merge\n")); err == nil || !strings.HasPrefix(err.Error(), "line 2:") {
+		t.Errorf("LoadRsyncFilter() error = %v; want error at line 2", err)
+	}
+}
@@ -0,0 +1,65 @@
+package glob
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrSeparators is the error of separators which could not be used.
+var ErrSeparators = errors.New("invalid separators")
+
+// Separators are the runes which `*` and `?` do not match. Each rune is a
+// separator of its own: with Separators{'/', ':'} wildcards match neither
+// "/" nor ":". There are no separators of several characters, so
+// Separators("::") is ':' listed twice rather than the separator "::",
+// which Validate rejects. Such separators are written in patterns as text
+// instead, like `*::*` with ':' as the separator.
+type Separators []rune
+
+// Validate returns *SeparatorError if the separators have the zero rune, a
+// rune which is not valid, or the same rune more than once, which usually
+// means that a string was taken for a separator of several characters.
+func (s Separators) Validate() error {
+	for i, r := range s {
+		switch {
+		case r == 0:
+			return &SeparatorError{Index: i, Rune: r, Reason: "empty separator"}
+		case !utf8.ValidRune(r) || r == utf8.RuneError:
+			return &SeparatorError{Index: i, Rune: r, Reason: "invalid rune"}
+		}
+		for j := 0; j < i; j++ {
+			if s[j] == r {
+				return &SeparatorError{Index: i, Rune: r, Reason: fmt.Sprintf("duplicate of separator #%d", j)}
+			}
+		}
+	}
+	return nil
+}
+
+// SeparatorError describes the separator Validate rejects.
+type SeparatorError struct {
+	// Index is the position of the separator in the list, and Rune is the
+	// separator itself.
+	Index  int
+	Rune   rune
+	Reason string
+}
+
+func (e *SeparatorError) Error() string {
+	return fmt.Sprintf("%s: separator #%d %q: %s", ErrSeparators, e.Index, e.Rune, e.Reason)
+}
+
+// Unwrap returns ErrSeparators.
+func (e *SeparatorError) Unwrap() error {
+	return ErrSeparators
+}
+
+// WithSeparatorList is like WithSeparators, except that CompileWith rejects
+// the separators Validate rejects.
+func WithSeparatorList(s Separators) Option {
+	return func(o *options) {
+		o.separators = s
+		o.checkSeps = true
+	}
+}
@@ -0,0 +1,52 @@
+package glob
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWithSeparatorList(t *testing.T) {
+	for id, test := range []struct {
+		separators Separators
+		index      int
+		reason     string
+	}{
+		{Separators{'/', ':'}, 0, ""},
+		{nil, 0, ""},
+		{Separators("::"), 1, "duplicate of separator #0"},
+		{Separators{'/', 0}, 1, "empty separator"},
+		{Separators{0xd800}, 0, "invalid rune"},
+		{Separators{utf8.MaxRune + 1}, 0, "invalid rune"},
+	} {
+		_, err := CompileWith("*", WithSeparatorList(test.separators))
+		if test.reason == "" {
+			if err != nil {
+				t.Errorf("#%d unexpected error: %v", id, err)
+			}
+			continue
+		}
+		e, ok := err.(*SeparatorError)
+		if !ok {
+			t.Errorf("#%d unexpected error: exp: *SeparatorError, act: %v", id, err)
+			continue
+		}
+		if e.Index != test.index || e.Reason != test.reason || e.Unwrap() != ErrSeparators {
+			t.Errorf("#%d unexpected error: exp: #%d %s, act: %v", id, test.index, test.reason, e)
+		}
+	}
+
+	g := MustCompileWith("*", WithSeparatorList(Separators{'/', ':'}))
+	for _, s := range []string{"a/b", "a:b", "a::b"} {
+		if g.Match(s) {
+			t.Errorf("%q should not be matched across any of the separators", s)
+		}
+	}
+
+	c := NewCache(2)
+	if _, err := c.Compile("*", WithSeparators(':', ':')); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if _, err := c.Compile("*", WithSeparatorList(Separators{':', ':'})); err == nil {
+		t.Errorf("expected error of duplicate separators")
+	}
+}
@@ -0,0 +1,80 @@
+package glob
+
+import (
+	"sort"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Compare compares the specificity of globs, so that rule engines could
+// resolve overlapping patterns consistently. It returns -1 if a is more
+// specific than b, +1 if b is more specific than a, and 0 if neither is.
+// A glob is more specific than another one if the first of these rules
+// distinguishing them holds:
+//
+//  1. it is literal, and the other one is not;
+//  2. its literal prefix is longer;
+//  3. its literal suffix is longer;
+//  4. it has fewer `**` wildcards;
+//  5. the strings it matches are longer at least (see Analyzer.MinLen).
+//
+// Lengths are compared in bytes.
+func Compare(a, b Glob) int {
+	x, y := analyzerOf(a), analyzerOf(b)
+	if x.IsLiteral() != y.IsLiteral() {
+		return precedes(x.IsLiteral())
+	}
+	if n, m := len(x.Prefix()), len(y.Prefix()); n != m {
+		return precedes(n > m)
+	}
+	if n, m := len(x.Suffix()), len(y.Suffix()); n != m {
+		return precedes(n > m)
+	}
+	if n, m := countSupers(a), countSupers(b); n != m {
+		return precedes(n < m)
+	}
+	if n, m := x.MinLen(), y.MinLen(); n != m {
+		return precedes(n > m)
+	}
+	return 0
+}
+
+// precedes returns the result of Compare: -1 if the first glob is more
+// specific, as ok reports, and +1 otherwise.
+func precedes(ok bool) int {
+	if ok {
+		return -1
+	}
+	return 1
+}
+
+// Sort sorts the globs from the most to the least specific, as Compare
+// orders them. Equally specific globs keep their order.
+func Sort(globs []Glob) {
+	sort.Stable(bySpecificity(globs))
+}
+
+type bySpecificity []Glob
+
+func (s bySpecificity) Len() int           { return len(s) }
+func (s bySpecificity) Less(i, j int) bool { return Compare(s[i], s[j]) < 0 }
+func (s bySpecificity) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// countSupers returns the number of `**` wildcards of the glob.
+func countSupers(g Glob) int {
+	if c, ok := g.(*compiled); ok {
+		return countKind(c.tree, ast.KindSuper)
+	}
+	return 0
+}
+
+func countKind(tree *ast.Node, kind ast.Kind) int {
+	var n int
+	if tree.Kind == kind {
+		n++
+	}
+	for _, c := range tree.Children {
+		n += countKind(c, kind)
+	}
+	return n
+}
@@ -0,0 +1,52 @@
+package glob
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		exp  int
+	}{
+		{"abc", "abc", 0},
+		{"abc", "a*", -1},
+		{"a*", "abc", 1},
+		{"abc", "abcd", 1},
+		{"ab*", "a*", -1},
+		{"*bc", "*c", -1},
+		{"a*c", "a**c", -1},
+		{"a??", "a*", -1},
+		{"a*", "a?", 1},
+		{"{ab,ac}*", "a*", -1},
+		{"a?", "b?", 0},
+	} {
+		a, b := MustCompile(test.a), MustCompile(test.b)
+		if act := Compare(a, b); act != test.exp {
+			t.Errorf("Compare(%q, %q) = %d; want %d", test.a, test.b, act, test.exp)
+		}
+		if act := Compare(b, a); act != -test.exp {
+			t.Errorf("Compare(%q, %q) = %d; want %d", test.b, test.a, act, -test.exp)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	patterns := []string{"**", "*.go", "a*", "src/**", "a?", "src/main.go", "src/*.go", "*_test.go", "b?"}
+	globs := make([]Glob, len(patterns))
+	source := make(map[Glob]string)
+	for i, p := range patterns {
+		globs[i] = MustCompile(p, '/')
+		source[globs[i]] = p
+	}
+	Sort(globs)
+	act := make([]string, len(globs))
+	for i, g := range globs {
+		act[i] = source[g]
+	}
+	exp := []string{"src/main.go", "src/*.go", "src/**", "a?", "b?", "a*", "*_test.go", "*.go", "**"}
+	if !reflect.DeepEqual(act, exp) {
+		t.Errorf("Sort() = %q; want %q", act, exp)
+	}
+}
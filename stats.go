@@ -0,0 +1,48 @@
+package glob
+
+import "github.com/gobwas/glob/match"
+
+// MatchStats is the work done by a single match of a glob.
+type MatchStats struct {
+	// Evaluations is the number of matches of the nodes of the compiled
+	// matcher against parts of the string.
+	Evaluations int
+
+	// IndexCalls is the number of searches of literal parts of the
+	// pattern, around which wildcards are matched.
+	IndexCalls int
+
+	// Backtracks is the number of times wildcards went on to match other
+	// parts of the string, after the ones tried did not match. Patterns
+	// with many of them per match, like `*a*a*a*b`, are the pathological
+	// ones.
+	Backtracks int
+}
+
+// StatsHook receives the work done by matches of globs compiled with
+// WithStats.
+type StatsHook interface {
+	// MatchStats is called after every match by Match and MatchContext,
+	// with the pattern the glob is compiled from. It is called from the
+	// goroutine matching the string, so it should be safe for concurrent
+	// use.
+	MatchStats(pattern string, stats MatchStats)
+}
+
+// WithStats makes the glob report the work done by every match to the hook,
+// so that pathological patterns could be found in production workloads.
+// Matches of such globs are slower, and allocate, so it is meant for
+// sampling or profiling. Globs compiled with the hook are never cached.
+func WithStats(hook StatsHook) Option {
+	return func(o *options) {
+		o.stats = hook
+	}
+}
+
+// matchStats matches s reporting the work to the hook of the glob.
+func (g *compiled) matchStats(s string) bool {
+	var l match.Limit
+	matched := g.MatchLimit(s, &l)
+	g.stats.MatchStats(g.source, MatchStats(l.Stats()))
+	return matched
+}
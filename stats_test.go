@@ -0,0 +1,57 @@
+package glob
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+type statsRecorder struct {
+	mu    sync.Mutex
+	stats map[string][]MatchStats
+}
+
+func (r *statsRecorder) MatchStats(pattern string, stats MatchStats) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats[pattern] = append(r.stats[pattern], stats)
+}
+
+func TestWithStats(t *testing.T) {
+	r := &statsRecorder{stats: make(map[string][]MatchStats)}
+	g := MustCompileWith("*b*a*a", WithStats(r)).(Analyzer)
+	if !g.Match("baxa") {
+		t.Errorf("unexpected mismatch")
+	}
+	if g.Match("xyz") {
+		t.Errorf("unexpected match")
+	}
+	if matched, err := g.MatchContext(context.Background(), "bbaa"); !matched || err != nil {
+		t.Errorf("MatchContext() = %t, %v; want match", matched, err)
+	}
+	stats := r.stats["*b*a*a"]
+	if len(stats) != 3 {
+		t.Fatalf("unexpected number of stats: exp: %d, act: %d", 3, len(stats))
+	}
+	if stats[0].Evaluations == 0 || stats[0].IndexCalls == 0 {
+		t.Errorf("unexpected stats of match: %+v", stats[0])
+	}
+	if stats[1] != (MatchStats{}) {
+		// The string is rejected by its last rune before matching.
+		t.Errorf("unexpected stats of mismatch: %+v", stats[1])
+	}
+
+	// Globs reporting stats are compiled every time.
+	c := NewCache(1)
+	for i := 0; i < 2; i++ {
+		if _, err := c.Match("*", "a", WithStats(r)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := c.Len(); n != 0 {
+		t.Errorf("unexpected cached globs: %d", n)
+	}
+	if n := len(r.stats["*"]); n != 2 {
+		t.Errorf("unexpected number of stats: exp: %d, act: %d", 2, n)
+	}
+}
@@ -3,6 +3,9 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob/util/runes"
 )
 
 type Node struct {
@@ -82,6 +85,45 @@ type Text struct {
 	Text string
 }
 
+// Class is the value of KindClass node, which is a character class made of
+// several parts, like `[a-f0-9_.-]` or `[[:alpha:]_]`. It matches a single
+// rune of any of the parts, or of none of them if Not is true. The parts are
+// kept in strings, so that classes could be compared.
+type Class struct {
+	Not bool
+
+	// Chars are the runes listed one by one.
+	Chars string
+
+	// Ranges are the ranges of runes, as pairs of their lo and hi runes.
+	Ranges string
+
+	// Names are the names of POSIX classes, like "alpha", separated by
+	// colons.
+	Names string
+}
+
+// Set returns the set of runes of the parts of the class, which is not
+// complemented if Not is true.
+func (c Class) Set() runes.Set {
+	set := runes.Of([]rune(c.Chars)...).Union(runes.OfPairs([]rune(c.Ranges)...))
+	if c.Names != "" {
+		for _, name := range strings.Split(c.Names, ":") {
+			s, _ := runes.Class(name)
+			set = set.Union(s)
+		}
+	}
+	return set
+}
+
+// Placeholder is the value of KindPlaceholder node, which stands for a
+// custom matcher registered under the Name. The Matcher must implement
+// match.Matcher to be compiled.
+type Placeholder struct {
+	Name    string
+	Matcher interface{}
+}
+
 type Kind int
 
 const (
@@ -94,6 +136,8 @@ const (
 	KindSuper
 	KindSingle
 	KindAnyOf
+	KindPlaceholder
+	KindClass
 )
 
 func (k Kind) String() string {
@@ -116,6 +160,10 @@ func (k Kind) String() string {
 		return "Single"
 	case KindAnyOf:
 		return "AnyOf"
+	case KindPlaceholder:
+		return "Placeholder"
+	case KindClass:
+		return "Class"
 	default:
 		return ""
 	}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gobwas/glob/syntax/lexer"
+	"github.com/gobwas/glob/util/runes"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -88,10 +90,11 @@ func parserMain(tree *Node, lex Lexer) (parseFn, *Node, error) {
 
 func parserRange(tree *Node, lex Lexer) (parseFn, *Node, error) {
 	var (
-		not   bool
-		lo    rune
-		hi    rune
-		chars string
+		not    bool
+		lo     rune
+		chars  string
+		ranges []rune
+		names  []string
 	)
 	for {
 		token := lex.Next()
@@ -121,34 +124,50 @@ func parserRange(tree *Node, lex Lexer) (parseFn, *Node, error) {
 				return nil, tree, fmt.Errorf("unexpected length of lo character")
 			}
 
-			hi = r
+			hi := r
 
 			if hi < lo {
 				return nil, tree, fmt.Errorf("hi character '%s' should be greater than lo '%s'", string(hi), string(lo))
 			}
 
+			ranges = append(ranges, lo, hi)
+
 		case lexer.Text:
-			chars = token.Raw
+			chars += token.Raw
 
-		case lexer.RangeClose:
-			isRange := lo != 0 && hi != 0
-			isChars := chars != ""
+		case lexer.ClassName:
+			if _, ok := runes.Class(token.Raw); !ok {
+				return nil, tree, fmt.Errorf("unknown character class %q", token.Raw)
+			}
+			names = append(names, token.Raw)
 
-			if isChars == isRange {
+		case lexer.RangeClose:
+			// Classes of a single range or of runes only are kept as Range
+			// and List, which all the code working with trees knows.
+			switch {
+			case chars == "" && len(ranges) == 0 && len(names) == 0:
 				return nil, tree, fmt.Errorf("could not parse range")
-			}
 
-			if isRange {
+			case chars == "" && len(ranges) == 2 && len(names) == 0:
 				Insert(tree, NewNode(KindRange, Range{
-					Lo:  lo,
-					Hi:  hi,
+					Lo:  ranges[0],
+					Hi:  ranges[1],
 					Not: not,
 				}))
-			} else {
+
+			case len(ranges) == 0 && len(names) == 0:
 				Insert(tree, NewNode(KindList, List{
 					Chars: chars,
 					Not:   not,
 				}))
+
+			default:
+				Insert(tree, NewNode(KindClass, Class{
+					Not:    not,
+					Chars:  chars,
+					Ranges: string(ranges),
+					Names:  strings.Join(names, ":"),
+				}))
 			}
 
 			return parserMain, tree, nil
@@ -105,6 +105,26 @@ func TestParseString(t *testing.T) {
 				NewNode(KindList, List{Chars: "az"}),
 			),
 		},
+		{
+			//pattern: "[!_a-f0-9[:space:]]",
+			tokens: []lexer.Token{
+				{lexer.RangeOpen, "["},
+				{lexer.Not, "!"},
+				{lexer.Text, "_"},
+				{lexer.RangeLo, "a"},
+				{lexer.RangeBetween, "-"},
+				{lexer.RangeHi, "f"},
+				{lexer.RangeLo, "0"},
+				{lexer.RangeBetween, "-"},
+				{lexer.RangeHi, "9"},
+				{lexer.ClassName, "space"},
+				{lexer.RangeClose, "]"},
+				{lexer.EOF, ""},
+			},
+			tree: NewNode(KindPattern, nil,
+				NewNode(KindClass, Class{Not: true, Chars: "_", Ranges: "af09", Names: "space"}),
+			),
+		},
 		{
 			//pattern: "{a,z}",
 			tokens: []lexer.Token{
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"github.com/gobwas/glob/util/runes"
+	"strings"
 	"unicode/utf8"
 )
 
@@ -34,31 +35,38 @@ func Special(c byte) bool {
 	return bytes.IndexByte(specials, c) != -1
 }
 
-type tokens []Token
+// item is a token with its position in the source.
+type item struct {
+	token    Token
+	pos, end int
+}
+
+type items []item
 
-func (i *tokens) shift() (ret Token) {
+func (i *items) shift() (ret item) {
 	ret = (*i)[0]
 	copy(*i, (*i)[1:])
 	*i = (*i)[:len(*i)-1]
 	return
 }
 
-func (i *tokens) push(v Token) {
+func (i *items) push(v item) {
 	*i = append(*i, v)
 }
 
-func (i *tokens) empty() bool {
+func (i *items) empty() bool {
 	return len(*i) == 0
 }
 
 var eof rune = 0
 
-type lexer struct {
+// Lexer splits glob pattern into tokens.
+type Lexer struct {
 	data string
 	pos  int
 	err  error
 
-	tokens     tokens
+	items      items
 	termsLevel int
 
 	lastRune     rune
@@ -66,33 +74,50 @@ type lexer struct {
 	hasRune      bool
 }
 
-func NewLexer(source string) *lexer {
-	l := &lexer{
-		data:   source,
-		tokens: tokens(make([]Token, 0, 4)),
+// NewLexer creates lexer for the given pattern.
+func NewLexer(source string) *Lexer {
+	l := &Lexer{
+		data:  source,
+		items: items(make([]item, 0, 4)),
 	}
 	return l
 }
 
-func (l *lexer) Next() Token {
-	if l.err != nil {
-		return Token{Error, l.err.Error()}
+// Next returns the next token of the pattern. After the end of the pattern
+// it returns EOF tokens, and after an error it returns Error tokens.
+func (l *Lexer) Next() Token {
+	tok, _, _ := l.Scan()
+	return tok
+}
+
+// Scan returns the next token of the pattern along with the byte offsets of
+// its start and its end in the pattern text. Offsets of the Error token
+// point to the place where the error occurred.
+func (l *Lexer) Scan() (tok Token, pos, end int) {
+	if !l.items.empty() {
+		it := l.items.shift()
+		return it.token, it.pos, it.end
 	}
-	if !l.tokens.empty() {
-		return l.tokens.shift()
+	if l.err != nil {
+		return Token{Error, l.err.Error()}, l.pos, l.pos
 	}
 
 	l.fetchItem()
-	return l.Next()
+	return l.Scan()
+}
+
+// push adds token that started at pos and ends at the current position.
+func (l *Lexer) push(t Token, pos int) {
+	l.items.push(item{t, pos, l.pos})
 }
 
-func (l *lexer) peek() (r rune, w int) {
+func (l *Lexer) peek() (r rune, w int) {
 	if l.pos == len(l.data) {
 		return eof, 0
 	}
 
 	r, w = utf8.DecodeRuneInString(l.data[l.pos:])
-	if r == utf8.RuneError {
+	if r == utf8.RuneError && w == 1 {
 		l.errorf("could not read rune")
 		r = eof
 		w = 0
@@ -101,7 +126,7 @@ func (l *lexer) peek() (r rune, w int) {
 	return
 }
 
-func (l *lexer) read() rune {
+func (l *Lexer) read() rune {
 	if l.hasRune {
 		l.hasRune = false
 		l.seek(l.lastRuneSize)
@@ -117,11 +142,11 @@ func (l *lexer) read() rune {
 	return r
 }
 
-func (l *lexer) seek(w int) {
+func (l *Lexer) seek(w int) {
 	l.pos += w
 }
 
-func (l *lexer) unread() {
+func (l *Lexer) unread() {
 	if l.hasRune {
 		l.errorf("could not unread rune")
 		return
@@ -130,55 +155,56 @@ func (l *lexer) unread() {
 	l.hasRune = true
 }
 
-func (l *lexer) errorf(f string, v ...interface{}) {
+func (l *Lexer) errorf(f string, v ...interface{}) {
 	l.err = fmt.Errorf(f, v...)
 }
 
-func (l *lexer) inTerms() bool {
+func (l *Lexer) inTerms() bool {
 	return l.termsLevel > 0
 }
 
-func (l *lexer) termsEnter() {
+func (l *Lexer) termsEnter() {
 	l.termsLevel++
 }
 
-func (l *lexer) termsLeave() {
+func (l *Lexer) termsLeave() {
 	l.termsLevel--
 }
 
 var inTextBreakers = []rune{char_single, char_any, char_range_open, char_terms_open}
 var inTermsBreakers = append(inTextBreakers, char_terms_close, char_comma)
 
-func (l *lexer) fetchItem() {
+func (l *Lexer) fetchItem() {
+	pos := l.pos
 	r := l.read()
 	switch {
 	case r == eof:
-		l.tokens.push(Token{EOF, ""})
+		l.push(Token{EOF, ""}, pos)
 
 	case r == char_terms_open:
 		l.termsEnter()
-		l.tokens.push(Token{TermsOpen, string(r)})
+		l.push(Token{TermsOpen, string(r)}, pos)
 
 	case r == char_comma && l.inTerms():
-		l.tokens.push(Token{Separator, string(r)})
+		l.push(Token{Separator, string(r)}, pos)
 
 	case r == char_terms_close && l.inTerms():
-		l.tokens.push(Token{TermsClose, string(r)})
+		l.push(Token{TermsClose, string(r)}, pos)
 		l.termsLeave()
 
 	case r == char_range_open:
-		l.tokens.push(Token{RangeOpen, string(r)})
+		l.push(Token{RangeOpen, string(r)}, pos)
 		l.fetchRange()
 
 	case r == char_single:
-		l.tokens.push(Token{Single, string(r)})
+		l.push(Token{Single, string(r)}, pos)
 
 	case r == char_any:
 		if l.read() == char_any {
-			l.tokens.push(Token{Super, string(r) + string(r)})
+			l.push(Token{Super, string(r) + string(r)}, pos)
 		} else {
 			l.unread()
-			l.tokens.push(Token{Any, string(r)})
+			l.push(Token{Any, string(r)}, pos)
 		}
 
 	default:
@@ -194,55 +220,108 @@ func (l *lexer) fetchItem() {
 	}
 }
 
-func (l *lexer) fetchRange() {
-	var wantHi bool
-	var wantClose bool
-	var seenNot bool
-	for {
+// fetchRange fetches the contents of a character class, which are runes,
+// ranges of runes and names of POSIX classes, like `[:alpha:]`, in any
+// order. Runes are fetched as Text, unless they start a range; escaped runes
+// never do, so that `[\!-~]` lists three runes. Hyphen is matched literally
+// if it could not be a range, like in `[a-]`.
+func (l *Lexer) fetchRange() {
+	var (
+		data     []rune
+		pos, end int
+	)
+	flush := func() {
+		if len(data) > 0 {
+			l.items.push(item{Token{Text, string(data)}, pos, end})
+			data = data[:0]
+		}
+	}
+	for first := true; ; first = false {
+		start := l.pos
 		r := l.read()
-		if r == eof {
+		switch {
+		case r == eof:
+			flush()
 			l.errorf("unexpected end of input")
 			return
-		}
 
-		if wantClose {
-			if r != char_range_close {
-				l.errorf("expected close range character")
-			} else {
-				l.tokens.push(Token{RangeClose, string(r)})
-			}
+		case r == char_range_close:
+			flush()
+			l.push(Token{RangeClose, string(r)}, start)
 			return
-		}
 
-		if wantHi {
-			l.tokens.push(Token{RangeHi, string(r)})
-			wantClose = true
+		case first && r == char_range_not:
+			l.push(Token{Not, string(r)}, start)
 			continue
+
+		case r == char_range_open:
+			if name, ok := l.className(); ok {
+				flush()
+				l.push(Token{ClassName, name}, start)
+				continue
+			}
+
 		}
 
-		if !seenNot && r == char_range_not {
-			l.tokens.push(Token{Not, string(r)})
-			seenNot = true
+		escaped := r == char_escape
+		if escaped {
+			if r = l.read(); r == eof {
+				flush()
+				l.errorf("unexpected end of input")
+				return
+			}
+		}
+		if escaped || !l.rangeFollows() {
+			if len(data) == 0 {
+				pos = start
+			}
+			data = append(data, r)
+			end = l.pos
 			continue
 		}
 
-		if n, w := l.peek(); n == char_range_between {
-			l.seek(w)
-			l.tokens.push(Token{RangeLo, string(r)})
-			l.tokens.push(Token{RangeBetween, string(n)})
-			wantHi = true
-			continue
+		flush()
+		l.push(Token{RangeLo, string(r)}, start)
+		start = l.pos
+		l.seek(1)
+		l.push(Token{RangeBetween, string(char_range_between)}, start)
+		start = l.pos
+		hi := l.read()
+		if hi == eof {
+			l.errorf("unexpected end of input")
+			return
 		}
+		l.push(Token{RangeHi, string(hi)}, start)
+	}
+}
 
-		l.unread() // unread first peek and fetch as text
-		l.fetchText([]rune{char_range_close})
-		wantClose = true
+// rangeFollows reports whether the rune just read is the lo rune of a range,
+// which is followed by hyphen and then by a rune other than the end of the
+// class.
+func (l *Lexer) rangeFollows() bool {
+	rest := l.data[l.pos:]
+	return len(rest) > 1 && rest[0] == char_range_between && rest[1] != char_range_close
+}
+
+// className fetches the name of POSIX class, like `[:alpha:]`, right after
+// its opening bracket, if there is one.
+func (l *Lexer) className() (string, bool) {
+	rest := l.data[l.pos:]
+	if !strings.HasPrefix(rest, ":") {
+		return "", false
+	}
+	n := strings.Index(rest[1:], ":]")
+	if n <= 0 {
+		return "", false
 	}
+	l.seek(n + 3)
+	return rest[1 : n+1], true
 }
 
-func (l *lexer) fetchText(breakers []rune) {
+func (l *Lexer) fetchText(breakers []rune) {
 	var data []rune
 	var escaped bool
+	pos := l.pos
 
 reading:
 	for {
@@ -268,6 +347,6 @@ reading:
 	}
 
 	if len(data) > 0 {
-		l.tokens.push(Token{Text, string(data)})
+		l.push(Token{Text, string(data)}, pos)
 	}
 }
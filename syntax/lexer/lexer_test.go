@@ -127,6 +127,32 @@ func TestLexGood(t *testing.T) {
 				{EOF, ""},
 			},
 		},
+		{
+			pattern: "[a-f0-9_.-]",
+			items: []Token{
+				{RangeOpen, "["},
+				{RangeLo, "a"},
+				{RangeBetween, "-"},
+				{RangeHi, "f"},
+				{RangeLo, "0"},
+				{RangeBetween, "-"},
+				{RangeHi, "9"},
+				{Text, "_.-"},
+				{RangeClose, "]"},
+				{EOF, ""},
+			},
+		},
+		{
+			pattern: "[![:alpha:]\\-x[:]",
+			items: []Token{
+				{RangeOpen, "["},
+				{Not, "!"},
+				{ClassName, "alpha"},
+				{Text, "-x[:"},
+				{RangeClose, "]"},
+				{EOF, ""},
+			},
+		},
 		{
 			pattern: "{a,b}",
 			items: []Token{
@@ -177,6 +203,16 @@ func TestLexGood(t *testing.T) {
 				{EOF, ""},
 			},
 		},
+		{
+			pattern: "a\ufffd[\ufffd]",
+			items: []Token{
+				{Text, "a\ufffd"},
+				{RangeOpen, "["},
+				{Text, "\ufffd"},
+				{RangeClose, "]"},
+				{EOF, ""},
+			},
+		},
 	} {
 		lexer := NewLexer(test.pattern)
 		for i, exp := range test.items {
@@ -190,3 +226,73 @@ func TestLexGood(t *testing.T) {
 		}
 	}
 }
+
+func TestLexPositions(t *testing.T) {
+	type span struct {
+		typ      TokenType
+		pos, end int
+	}
+	for id, test := range []struct {
+		pattern string
+		spans   []span
+	}{
+		{
+			pattern: `a\*b*?`,
+			spans: []span{
+				{Text, 0, 4},
+				{Any, 4, 5},
+				{Single, 5, 6},
+				{EOF, 6, 6},
+			},
+		},
+		{
+			pattern: "日**{x,[!a-я]}",
+			spans: []span{
+				{Text, 0, 3},
+				{Super, 3, 5},
+				{TermsOpen, 5, 6},
+				{Text, 6, 7},
+				{Separator, 7, 8},
+				{RangeOpen, 8, 9},
+				{Not, 9, 10},
+				{RangeLo, 10, 11},
+				{RangeBetween, 11, 12},
+				{RangeHi, 12, 14},
+				{RangeClose, 14, 15},
+				{TermsClose, 15, 16},
+				{EOF, 16, 16},
+			},
+		},
+		{
+			pattern: "[ab-c[:digit:]]",
+			spans: []span{
+				{RangeOpen, 0, 1},
+				{Text, 1, 2},
+				{RangeLo, 2, 3},
+				{RangeBetween, 3, 4},
+				{RangeHi, 4, 5},
+				{ClassName, 5, 14},
+				{RangeClose, 14, 15},
+				{EOF, 15, 15},
+			},
+		},
+		{
+			pattern: "ab[cd",
+			spans: []span{
+				{Text, 0, 2},
+				{RangeOpen, 2, 3},
+				{Text, 3, 5},
+				{Error, 5, 5},
+				{Error, 5, 5},
+			},
+		},
+	} {
+		lexer := NewLexer(test.pattern)
+		for i, exp := range test.spans {
+			tok, pos, end := lexer.Scan()
+			if tok.Type != exp.typ || pos != exp.pos || end != exp.end {
+				t.Errorf("#%d %q: %d-th token is %s at [%d:%d]; want %s at [%d:%d]", id, test.pattern, i, tok, pos, end, exp.typ, exp.pos, exp.end)
+			}
+		}
+	}
+}
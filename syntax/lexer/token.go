@@ -21,6 +21,7 @@ const (
 	RangeBetween
 	TermsOpen
 	TermsClose
+	ClassName
 )
 
 func (tt TokenType) String() string {
@@ -73,6 +74,9 @@ func (tt TokenType) String() string {
 	case TermsClose:
 		return "terms_close"
 
+	case ClassName:
+		return "class_name"
+
 	default:
 		return "undef"
 	}
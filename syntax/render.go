@@ -0,0 +1,170 @@
+package syntax
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Render returns pattern text that is parsed into a tree matching the same
+// strings as the given one.
+//
+// Note that some trees could not be expressed as text, such as the ones
+// that contain zero runes.
+func Render(tree *ast.Node) string {
+	var buf bytes.Buffer
+	render(&buf, tree, 0)
+	return buf.String()
+}
+
+func render(buf *bytes.Buffer, tree *ast.Node, terms int) {
+	switch tree.Kind {
+	case ast.KindPattern:
+		for i, c := range tree.Children {
+			// Two adjacent `*` would be read as `**`, while matching the
+			// same strings as the single one.
+			if c.Kind == ast.KindAny && i > 0 && tree.Children[i-1].Kind == ast.KindAny {
+				continue
+			}
+			render(buf, c, terms)
+		}
+
+	case ast.KindAnyOf:
+		buf.WriteByte('{')
+		for i, c := range tree.Children {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			render(buf, c, terms+1)
+		}
+		buf.WriteByte('}')
+
+	case ast.KindText:
+		renderText(buf, tree.Value.(ast.Text).Text, terms > 0)
+
+	case ast.KindAny:
+		buf.WriteByte('*')
+
+	case ast.KindSuper:
+		buf.WriteString("**")
+
+	case ast.KindSingle:
+		buf.WriteByte('?')
+
+	case ast.KindList:
+		renderList(buf, tree.Value.(ast.List))
+
+	case ast.KindRange:
+		renderRange(buf, tree.Value.(ast.Range), terms)
+
+	case ast.KindClass:
+		renderClass(buf, tree.Value.(ast.Class))
+
+	case ast.KindPlaceholder:
+		buf.WriteString(tree.Value.(*ast.Placeholder).Name)
+	}
+}
+
+func renderText(buf *bytes.Buffer, s string, inTerms bool) {
+	for _, r := range s {
+		if r < 0x80 && Special(byte(r)) || inTerms && r == ',' {
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+}
+
+func renderList(buf *bytes.Buffer, l ast.List) {
+	chars := l.Chars
+	// Range is recognized when the second character of the class is `-`,
+	// and negation when the first one is `!`. Placing `-` first avoids both.
+	if strings.ContainsRune(chars, '-') {
+		chars = "-" + strings.Replace(chars, "-", "", -1)
+	}
+	buf.WriteByte('[')
+	if l.Not {
+		buf.WriteByte('!')
+	}
+	for i, r := range chars {
+		switch {
+		case r == '\\' || r == ']':
+			buf.WriteByte('\\')
+		case r == '!' && i == 0 && !l.Not:
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	buf.WriteByte(']')
+}
+
+func renderRange(buf *bytes.Buffer, r ast.Range, terms int) {
+	if r.Lo == '!' && !r.Not {
+		// Leading `!` could not be escaped in a range, so we express the
+		// range as alternatives of `!` and the rest of it.
+		alt := ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindText, ast.Text{Text: "!"}),
+		)
+		if r.Hi > r.Lo {
+			ast.Insert(alt, ast.NewNode(ast.KindRange, ast.Range{Lo: r.Lo + 1, Hi: r.Hi}))
+		}
+		render(buf, alt, terms)
+		return
+	}
+	buf.WriteByte('[')
+	if r.Not {
+		buf.WriteByte('!')
+	}
+	buf.WriteRune(r.Lo)
+	buf.WriteByte('-')
+	buf.WriteRune(r.Hi)
+	buf.WriteByte(']')
+}
+
+func renderClass(buf *bytes.Buffer, c ast.Class) {
+	// Runes listed one by one are escaped, but the lo runes of ranges could
+	// not be, and `]` could not be the hi rune. Such bounds are listed
+	// instead, along with ranges of a single rune.
+	var chars, ranges []rune
+	chars = append(chars, []rune(c.Chars)...)
+	rs := []rune(c.Ranges)
+	for i := 0; i+1 < len(rs); i += 2 {
+		lo, hi := rs[i], rs[i+1]
+		for lo <= hi && (lo == ']' || lo == '\\' || lo == '!') {
+			chars = append(chars, lo)
+			lo++
+		}
+		if hi == ']' && lo <= hi {
+			chars = append(chars, hi)
+			hi--
+		}
+		switch {
+		case lo == hi:
+			chars = append(chars, lo)
+		case lo < hi:
+			ranges = append(ranges, lo, hi)
+		}
+	}
+	buf.WriteByte('[')
+	if c.Not {
+		buf.WriteByte('!')
+	}
+	for _, r := range chars {
+		switch r {
+		case '\\', ']', '[', '-', '!':
+			buf.WriteByte('\\')
+		}
+		buf.WriteRune(r)
+	}
+	if c.Names != "" {
+		for _, name := range strings.Split(c.Names, ":") {
+			buf.WriteString("[:" + name + ":]")
+		}
+	}
+	for i := 0; i < len(ranges); i += 2 {
+		buf.WriteRune(ranges[i])
+		buf.WriteByte('-')
+		buf.WriteRune(ranges[i+1])
+	}
+	buf.WriteByte(']')
+}
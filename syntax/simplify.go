@@ -0,0 +1,285 @@
+package syntax
+
+import (
+	"unicode/utf8"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// Simplify returns a new tree which matches the same strings as the given
+// one, but has less nodes. It merges adjacent texts and wildcards, removes
+// duplicate and single pattern alternatives and factors common parts out of
+// alternatives. The result does not depend on the separators the pattern
+// is compiled with.
+func Simplify(tree *ast.Node) *ast.Node {
+	switch tree.Kind {
+	case ast.KindPattern:
+		var items []*ast.Node
+		for _, c := range tree.Children {
+			s := Simplify(c)
+			if s.Kind == ast.KindPattern {
+				items = append(items, s.Children...)
+			} else {
+				items = append(items, s)
+			}
+		}
+		return ast.NewNode(ast.KindPattern, nil, sequence(items)...)
+
+	case ast.KindAnyOf:
+		return simplifyAnyOf(tree)
+
+	// Classes of a single rune are texts, except for U+FFFD, which classes
+	// match invalid bytes with, while texts do not.
+	case ast.KindList:
+		l := tree.Value.(ast.List)
+		if !l.Not && utf8.RuneCountInString(l.Chars) == 1 && l.Chars != string(utf8.RuneError) {
+			return text(l.Chars)
+		}
+		return ast.NewNode(tree.Kind, l)
+
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		if !r.Not && r.Lo == r.Hi && r.Lo != utf8.RuneError {
+			return text(string(r.Lo))
+		}
+		return ast.NewNode(tree.Kind, r)
+
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		if r, ok := c.Set().Single(); ok && !c.Not && r != utf8.RuneError {
+			return text(string(r))
+		}
+		return ast.NewNode(tree.Kind, c)
+
+	default:
+		return ast.NewNode(tree.Kind, tree.Value)
+	}
+}
+
+func text(s string) *ast.Node {
+	return ast.NewNode(ast.KindText, ast.Text{Text: s})
+}
+
+func isWildcard(n *ast.Node) bool {
+	return n.Kind == ast.KindAny || n.Kind == ast.KindSuper
+}
+
+// sequence merges adjacent nodes of already simplified pattern items.
+func sequence(items []*ast.Node) []*ast.Node {
+	out := make([]*ast.Node, 0, len(items))
+	for _, n := range items {
+		if n.Kind == ast.KindNothing || n.Kind == ast.KindText && n.Value.(ast.Text).Text == "" {
+			continue
+		}
+		if len(out) == 0 {
+			out = append(out, n)
+			continue
+		}
+		last := out[len(out)-1]
+		switch {
+		case last.Kind == ast.KindText && n.Kind == ast.KindText:
+			out[len(out)-1] = text(last.Value.(ast.Text).Text + n.Value.(ast.Text).Text)
+
+		case isWildcard(last) && isWildcard(n):
+			// Sequence of `*` and `**` matches the same strings as `**`,
+			// if there is at least one `**`, and as `*` otherwise.
+			if n.Kind == ast.KindSuper {
+				out[len(out)-1] = n
+			}
+
+		default:
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func simplifyAnyOf(tree *ast.Node) *ast.Node {
+	var alts [][]*ast.Node
+	for _, c := range tree.Children {
+		s := Simplify(c)
+		if s.Kind == ast.KindPattern && len(s.Children) == 1 && s.Children[0].Kind == ast.KindAnyOf {
+			s = s.Children[0]
+		}
+		if s.Kind == ast.KindAnyOf {
+			for _, a := range s.Children {
+				alts = appendUnique(alts, a.Children)
+			}
+			continue
+		}
+		if s.Kind == ast.KindPattern {
+			alts = appendUnique(alts, s.Children)
+		} else {
+			alts = appendUnique(alts, sequence([]*ast.Node{s}))
+		}
+	}
+	if len(alts) == 1 {
+		return ast.NewNode(ast.KindPattern, nil, alts[0]...)
+	}
+
+	prefix, alts := commonHead(alts)
+	suffix, alts := commonTail(alts)
+	var rest [][]*ast.Node
+	for _, a := range alts {
+		rest = appendUnique(rest, a)
+	}
+
+	items := prefix
+	switch {
+	case len(rest) == 1:
+		items = append(items, rest[0]...)
+	case len(rest) > 1:
+		anyOf := ast.NewNode(ast.KindAnyOf, nil)
+		for _, a := range rest {
+			ast.Insert(anyOf, ast.NewNode(ast.KindPattern, nil, a...))
+		}
+		items = append(items, anyOf)
+	}
+	items = append(items, suffix...)
+
+	return ast.NewNode(ast.KindPattern, nil, sequence(items)...)
+}
+
+func appendUnique(alts [][]*ast.Node, alt []*ast.Node) [][]*ast.Node {
+	for _, a := range alts {
+		if nodesEqual(a, alt) {
+			return alts
+		}
+	}
+	return append(alts, alt)
+}
+
+func nodesEqual(a, b []*ast.Node) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// commonHead returns nodes all alternatives start with, including the common
+// prefix of texts they continue with, and alternatives without them.
+func commonHead(alts [][]*ast.Node) (head []*ast.Node, rest [][]*ast.Node) {
+	n := 0
+	for equalAt(alts, func(a []*ast.Node) int { return n }) {
+		n++
+	}
+	head = append(head, alts[0][:n]...)
+	rest = make([][]*ast.Node, len(alts))
+	for i, a := range alts {
+		rest[i] = a[n:]
+	}
+	p, ok := textAt(rest, func(a []*ast.Node) int { return 0 })
+	if !ok {
+		return head, rest
+	}
+	for _, a := range rest[1:] {
+		p = commonTextPrefix(p, a[0].Value.(ast.Text).Text)
+	}
+	if p == "" {
+		return head, rest
+	}
+	for i, a := range rest {
+		rest[i] = replaceText(a, 0, a[0].Value.(ast.Text).Text[len(p):])
+	}
+	return append(head, text(p)), rest
+}
+
+// commonTail is the same as commonHead, but for the ends of alternatives.
+func commonTail(alts [][]*ast.Node) (tail []*ast.Node, rest [][]*ast.Node) {
+	n := 0
+	for equalAt(alts, func(a []*ast.Node) int { return len(a) - n - 1 }) {
+		n++
+	}
+	tail = append(tail, alts[0][len(alts[0])-n:]...)
+	rest = make([][]*ast.Node, len(alts))
+	for i, a := range alts {
+		rest[i] = a[:len(a)-n]
+	}
+	last := func(a []*ast.Node) int { return len(a) - 1 }
+	s, ok := textAt(rest, last)
+	if !ok {
+		return tail, rest
+	}
+	for _, a := range rest[1:] {
+		s = commonTextSuffix(s, a[last(a)].Value.(ast.Text).Text)
+	}
+	if s == "" {
+		return tail, rest
+	}
+	for i, a := range rest {
+		t := a[last(a)].Value.(ast.Text).Text
+		rest[i] = replaceText(a, last(a), t[:len(t)-len(s)])
+	}
+	return append([]*ast.Node{text(s)}, tail...), rest
+}
+
+// equalAt reports whether all alternatives have equal nodes at positions
+// returned by pos.
+func equalAt(alts [][]*ast.Node, pos func([]*ast.Node) int) bool {
+	var first *ast.Node
+	for _, a := range alts {
+		i := pos(a)
+		if i < 0 || i >= len(a) {
+			return false
+		}
+		if first == nil {
+			first = a[i]
+		} else if !a[i].Equal(first) {
+			return false
+		}
+	}
+	return true
+}
+
+// textAt returns text of the first alternative at position returned by pos,
+// if all alternatives have texts there.
+func textAt(alts [][]*ast.Node, pos func([]*ast.Node) int) (string, bool) {
+	for _, a := range alts {
+		i := pos(a)
+		if i < 0 || i >= len(a) || a[i].Kind != ast.KindText {
+			return "", false
+		}
+	}
+	a := alts[0]
+	return a[pos(a)].Value.(ast.Text).Text, true
+}
+
+// replaceText returns a copy of a with i-th node replaced by text t, or
+// removed if t is empty.
+func replaceText(a []*ast.Node, i int, t string) []*ast.Node {
+	out := make([]*ast.Node, 0, len(a))
+	out = append(out, a[:i]...)
+	if t != "" {
+		out = append(out, text(t))
+	}
+	return append(out, a[i+1:]...)
+}
+
+func commonTextPrefix(a, b string) string {
+	var n int
+	for n < len(a) && n < len(b) {
+		r, w := utf8.DecodeRuneInString(a[n:])
+		if r2, w2 := utf8.DecodeRuneInString(b[n:]); r != r2 || w != w2 {
+			break
+		}
+		n += w
+	}
+	return a[:n]
+}
+
+func commonTextSuffix(a, b string) string {
+	var n int
+	for n < len(a) && n < len(b) {
+		r, w := utf8.DecodeLastRuneInString(a[:len(a)-n])
+		if r2, w2 := utf8.DecodeLastRuneInString(b[:len(b)-n]); r != r2 || w != w2 {
+			break
+		}
+		n += w
+	}
+	return a[len(a)-n:]
+}
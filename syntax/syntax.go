@@ -1,12 +1,75 @@
+// Package syntax parses glob patterns into syntax trees. The trees could be
+// analyzed, transformed or built from scratch and then compiled with
+// glob.CompileAST.
 package syntax
 
 import (
+	"fmt"
+
 	"github.com/gobwas/glob/syntax/ast"
 	"github.com/gobwas/glob/syntax/lexer"
 )
 
-func Parse(s string) (*ast.Node, error) {
-	return ast.Parse(lexer.NewLexer(s))
+// Pattern represents parsed glob pattern.
+type Pattern struct {
+	// Source is the text pattern was parsed from. It is empty for patterns
+	// built programmatically.
+	Source string
+
+	// Tree is the root of the syntax tree. It is a node of ast.KindPattern.
+	Tree *ast.Node
+}
+
+// New returns pattern with given syntax tree.
+func New(tree *ast.Node) *Pattern {
+	return &Pattern{Tree: tree}
+}
+
+// Error describes a syntax error of the pattern.
+type Error struct {
+	// Offset is the byte offset in the pattern text where the error
+	// occurred.
+	Offset int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// scanner keeps the offset of the last token read by the parser.
+type scanner struct {
+	lexer *lexer.Lexer
+	pos   int
+}
+
+func (s *scanner) Next() lexer.Token {
+	tok, pos, _ := s.lexer.Scan()
+	s.pos = pos
+	return tok
+}
+
+// Parse parses glob pattern. Syntax errors are reported with *Error.
+func Parse(s string) (*Pattern, error) {
+	sc := &scanner{lexer: lexer.NewLexer(s)}
+	tree, err := ast.Parse(sc)
+	if err != nil {
+		return nil, &Error{Offset: sc.pos, Err: err}
+	}
+	return &Pattern{
+		Source: s,
+		Tree:   tree,
+	}, nil
+}
+
+// String returns the text of the pattern rendered from its syntax tree.
+func (p *Pattern) String() string {
+	return Render(p.Tree)
 }
 
 func Special(b byte) bool {
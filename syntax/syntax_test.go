@@ -0,0 +1,114 @@
+package syntax
+
+import (
+	"testing"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+func TestParse(t *testing.T) {
+	p, err := Parse("a*{b,c}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := ast.NewNode(ast.KindPattern, nil,
+		ast.NewNode(ast.KindText, ast.Text{Text: "a"}),
+		ast.NewNode(ast.KindAny, nil),
+		ast.NewNode(ast.KindAnyOf, nil,
+			ast.NewNode(ast.KindPattern, nil,
+				ast.NewNode(ast.KindText, ast.Text{Text: "b"}),
+			),
+			ast.NewNode(ast.KindPattern, nil,
+				ast.NewNode(ast.KindText, ast.Text{Text: "c"}),
+			),
+		),
+	)
+	if p.Source != "a*{b,c}" {
+		t.Errorf("Source = %q; want %q", p.Source, "a*{b,c}")
+	}
+	if !p.Tree.Equal(exp) {
+		t.Errorf("Tree = %s; want %s", p.Tree, exp)
+	}
+	if _, err := Parse("[a"); err == nil {
+		t.Errorf("expected error")
+	}
+}
+
+func TestRender(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		exp     string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"a*b**c?d", "a*b**c?d"},
+		{`\*\?\[\]\{\}\\`, `\*\?\[\]\{\}\\`},
+		{"a,b", "a,b"},
+		{`{a\,b,c}`, `{a\,b,c}`},
+		{"{,a,b{c,d}}", "{,a,b{c,d}}"},
+		{"[abc][!abc]", "[abc][!abc]"},
+		{"[a-z][!a-z]", "[a-z][!a-z]"},
+		{`[\]\\]`, `[\]\\]`},
+		{`[a\-]`, `[-a]`},
+		{`[\!a]`, `[\!a]`},
+		{`[!!a]`, `[!!a]`},
+		{"[!-~]", "[!-~]"},
+		{`[\!-~]`, `[-!~]`},
+		{"[a-f0-9_.-]", `[_.\-a-f0-9]`},
+		{"[![:alpha:][:digit:]_]", "[!_[:alpha:][:digit:]]"},
+		{`[x!-#]`, `[x\!"-#]`},
+		{`[xa-]`, `[-xa]`},
+	} {
+		p, err := Parse(test.pattern)
+		if err != nil {
+			t.Fatalf("#%d Parse(%q) error: %s", id, test.pattern, err)
+		}
+		act := p.String()
+		if act != test.exp {
+			t.Errorf("#%d Parse(%q).String() = %q; want %q", id, test.pattern, act, test.exp)
+		}
+		q, err := Parse(act)
+		if err != nil {
+			t.Errorf("#%d Parse(%q) error: %s", id, act, err)
+			continue
+		}
+		if again := q.String(); again != act {
+			t.Errorf("#%d Parse(%q).String() = %q; want %q", id, act, again, act)
+		}
+	}
+}
+
+func TestRenderRange(t *testing.T) {
+	tree := ast.NewNode(ast.KindPattern, nil,
+		ast.NewNode(ast.KindRange, ast.Range{Lo: '!', Hi: '#'}),
+	)
+	if act, exp := Render(tree), `{!,["-#]}`; act != exp {
+		t.Errorf("Render() = %q; want %q", act, exp)
+	}
+}
+
+func TestRenderClass(t *testing.T) {
+	for id, test := range []struct {
+		class ast.Class
+		exp   string
+	}{
+		{ast.Class{Chars: "x", Ranges: "]a"}, `[x\]^-a]`},
+		{ast.Class{Chars: "x", Ranges: "A]"}, `[x\]A-\]`},
+		{ast.Class{Chars: "x", Ranges: "\\_"}, `[x\\\]^-_]`},
+		{ast.Class{Not: true, Ranges: "!#", Names: "digit"}, `[!\![:digit:]"-#]`},
+	} {
+		tree := ast.NewNode(ast.KindPattern, nil, ast.NewNode(ast.KindClass, test.class))
+		act := Render(tree)
+		if act != test.exp {
+			t.Errorf("#%d Render() = %q; want %q", id, act, test.exp)
+		}
+		p, err := Parse(act)
+		if err != nil {
+			t.Errorf("#%d Parse(%q) error: %s", id, act, err)
+			continue
+		}
+		if c, ok := p.Tree.Children[0].Value.(ast.Class); !ok || c.Not != test.class.Not || !c.Set().Equal(test.class.Set()) {
+			t.Errorf("#%d Parse(%q) = %s; want %v", id, act, p.Tree, test.class)
+		}
+	}
+}
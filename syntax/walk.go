@@ -0,0 +1,44 @@
+package syntax
+
+import "github.com/gobwas/glob/syntax/ast"
+
+// Visitor's Visit method is invoked for each node encountered by Walk. If the
+// result visitor w is not nil, Walk visits each of the children of node with
+// the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node *ast.Node) (w Visitor)
+}
+
+// Walk traverses syntax tree in depth-first order: it starts by calling
+// v.Visit(node); node must not be nil. If the visitor w returned by
+// v.Visit(node) is not nil, Walk is invoked recursively with visitor w for
+// each of the children of node, followed by a call of w.Visit(nil).
+//
+// Visitor could modify the node it visits, for example change its kind or
+// value, which makes Walk suitable for rewriting trees in place.
+func Walk(v Visitor, node *ast.Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+	for _, c := range node.Children {
+		Walk(v, c)
+	}
+	v.Visit(nil)
+}
+
+type inspector func(*ast.Node) bool
+
+func (f inspector) Visit(node *ast.Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses syntax tree in depth-first order: it starts by calling
+// f(node); node must not be nil. If f returns true, Inspect invokes f
+// recursively for each of the children of node, followed by a call of
+// f(nil).
+func Inspect(node *ast.Node, f func(*ast.Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,57 @@
+package syntax
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+func TestInspect(t *testing.T) {
+	p, err := Parse("src/{cmd,pkg/*}/**.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var texts []string
+	Inspect(p.Tree, func(n *ast.Node) bool {
+		if n != nil && n.Kind == ast.KindText {
+			texts = append(texts, n.Value.(ast.Text).Text)
+		}
+		return true
+	})
+	if exp := []string{"src/", "cmd", "pkg/", "/", ".go"}; !reflect.DeepEqual(texts, exp) {
+		t.Errorf("collected texts %q; want %q", texts, exp)
+	}
+
+	var depth, max int
+	Inspect(p.Tree, func(n *ast.Node) bool {
+		if n == nil {
+			depth--
+			return false
+		}
+		if depth++; depth > max {
+			max = depth
+		}
+		return true
+	})
+	if depth != 0 || max != 4 {
+		t.Errorf("depth after walk is %d, max is %d; want 0 and 4", depth, max)
+	}
+}
+
+func TestWalkRewrite(t *testing.T) {
+	p, err := Parse("a/**/b/**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	Inspect(p.Tree, func(n *ast.Node) bool {
+		if n != nil && n.Kind == ast.KindSuper {
+			n.Kind = ast.KindAny
+		}
+		return true
+	})
+	if act, exp := p.String(), "a/*/b/*"; act != exp {
+		t.Errorf("rewritten pattern is %q; want %q", act, exp)
+	}
+}
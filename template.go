@@ -0,0 +1,95 @@
+package glob
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"text/template"
+)
+
+// funcMapCacheSize limits the number of globs cached by functions of
+// FuncMap. The cache is cleared once the limit is reached.
+const funcMapCacheSize = 256
+
+// FuncMap returns template functions using globs compiled with given
+// options:
+//
+//    glob PATTERN              returns the compiled Glob
+//    globMatch PATTERN STRING  reports whether the string matches
+//    globFilter PATTERN LIST   returns strings of the list that match
+//
+// The LIST could be a slice or an array of any string type. Invalid
+// pattern stops execution of the template with an error. Compiled globs are
+// cached, so that patterns are not compiled on every call. The result could
+// be converted to the FuncMap of html/template as well.
+func FuncMap(opts ...Option) template.FuncMap {
+	c := &funcCache{
+		opts:  opts,
+		globs: make(map[string]Glob),
+	}
+	return template.FuncMap{
+		"glob":       c.compile,
+		"globMatch":  c.match,
+		"globFilter": c.filter,
+	}
+}
+
+type funcCache struct {
+	opts []Option
+
+	mu    sync.Mutex
+	globs map[string]Glob
+}
+
+func (c *funcCache) compile(pattern string) (Glob, error) {
+	c.mu.Lock()
+	g, ok := c.globs[pattern]
+	c.mu.Unlock()
+	if ok {
+		return g, nil
+	}
+	g, err := CompileWith(pattern, c.opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	if len(c.globs) >= funcMapCacheSize {
+		c.globs = make(map[string]Glob)
+	}
+	c.globs[pattern] = g
+	c.mu.Unlock()
+	return g, nil
+}
+
+func (c *funcCache) match(pattern, s string) (bool, error) {
+	g, err := c.compile(pattern)
+	if err != nil {
+		return false, err
+	}
+	return g.Match(s), nil
+}
+
+func (c *funcCache) filter(pattern string, list interface{}) ([]string, error) {
+	g, err := c.compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(list)
+	if k := v.Kind(); k != reflect.Slice && k != reflect.Array {
+		return nil, fmt.Errorf("globFilter: could not filter %T", list)
+	}
+	var res []string
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i)
+		if e.Kind() == reflect.Interface {
+			e = e.Elem()
+		}
+		if e.Kind() != reflect.String {
+			return nil, fmt.Errorf("globFilter: element %d of %T is not a string", i, list)
+		}
+		if s := e.String(); g.Match(s) {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
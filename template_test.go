@@ -0,0 +1,47 @@
+package glob
+
+import (
+	"bytes"
+	"testing"
+	"text/template"
+)
+
+func TestFuncMap(t *testing.T) {
+	type name string
+	data := map[string]interface{}{
+		"Files": []string{"a.go", "b.md", "c/d.go"},
+		"Names": []name{"x.go", "y.c"},
+		"Any":   []interface{}{"e.go", "f"},
+		"Name":  "main.go",
+	}
+	for _, test := range []struct {
+		text string
+		exp  string
+		err  bool
+	}{
+		{text: `{{globFilter "*.go" .Files}}`, exp: "[a.go]"},
+		{text: `{{.Names | globFilter "*.go"}}`, exp: "[x.go]"},
+		{text: `{{globFilter "*.go" .Any}}`, exp: "[e.go]"},
+		{text: `{{if globMatch "*.go" .Name}}go{{end}}`, exp: "go"},
+		{text: `{{(glob "main.*").Match .Name}}`, exp: "true"},
+		{text: `{{globMatch "[a" .Name}}`, err: true},
+		{text: `{{globFilter "*" .Name}}`, err: true},
+	} {
+		tmpl := template.Must(template.New("").Funcs(FuncMap(WithSeparators('/'))).Parse(test.text))
+		var buf bytes.Buffer
+		err := tmpl.Execute(&buf, data)
+		if test.err {
+			if err == nil {
+				t.Errorf("%q: expected error", test.text)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", test.text, err)
+			continue
+		}
+		if act := buf.String(); act != test.exp {
+			t.Errorf("%q: got %q; want %q", test.text, act, test.exp)
+		}
+	}
+}
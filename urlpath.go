@@ -0,0 +1,133 @@
+package glob
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+)
+
+// WithURLPath configures matching of URL paths, with '/' as the separator.
+// Pattern containing `..` path segment is rejected by CompileWith, and
+// paths containing such segment are never matched, so that globs could not
+// be bypassed with path traversal.
+//
+// If decode is true, percent-encoded octets of the path are decoded before
+// matching, except for encoded '/', which is kept as `%2F` to not change
+// the path structure. Paths with malformed escapes are not matched. Note
+// that other methods of the glob, like Prefix or MinLen, describe decoded
+// paths then.
+func WithURLPath(decode bool) Option {
+	return func(o *options) {
+		o.separators = []rune{'/'}
+		o.urlPath = true
+		if decode {
+			o.normalize = decodeURLPath
+		} else {
+			o.normalize = checkURLPath
+		}
+	}
+}
+
+// checkURLPath reports whether the path has no `..` segments.
+func checkURLPath(p string) (string, bool) {
+	return p, !hasDotDot(p)
+}
+
+// decodeURLPath decodes percent-encoded octets of the path, except for
+// encoded '/'. It returns false if the path has malformed escapes or `..`
+// segments after decoding.
+func decodeURLPath(p string) (string, bool) {
+	if strings.IndexByte(p, '%') == -1 {
+		return checkURLPath(p)
+	}
+	b := make([]byte, 0, len(p))
+	for i := 0; i < len(p); i++ {
+		if p[i] != '%' {
+			b = append(b, p[i])
+			continue
+		}
+		if i+2 >= len(p) || !isHex(p[i+1]) || !isHex(p[i+2]) {
+			return "", false
+		}
+		c := unhex(p[i+1])<<4 | unhex(p[i+2])
+		if c == '/' {
+			b = append(b, "%2F"...)
+		} else {
+			b = append(b, c)
+		}
+		i += 2
+	}
+	return checkURLPath(string(b))
+}
+
+// hasDotDot reports whether s has `..` path segment.
+func hasDotDot(s string) bool {
+	for {
+		i := strings.Index(s, "..")
+		if i == -1 {
+			return false
+		}
+		if (i == 0 || s[i-1] == '/') && (i+2 == len(s) || s[i+2] == '/') {
+			return true
+		}
+		s = s[i+1:]
+	}
+}
+
+// checkDotDot returns error if texts of the tree contain `..` path segment.
+// The start and end flags report whether the tree is preceded and followed
+// by '/' or by the ends of the pattern.
+func checkDotDot(tree *ast.Node, start, end bool) error {
+	switch tree.Kind {
+	case ast.KindText:
+		s := tree.Value.(ast.Text).Text
+		if !start {
+			s = "x" + s
+		}
+		if !end {
+			s = s + "x"
+		}
+		if hasDotDot(s) {
+			return fmt.Errorf("pattern contains %q path segment", "..")
+		}
+
+	case ast.KindPattern:
+		nodes := tree.Children
+		for i, c := range nodes {
+			s, e := start, end
+			if i > 0 {
+				s = nodes[i-1].Kind == ast.KindText && strings.HasSuffix(nodes[i-1].Value.(ast.Text).Text, "/")
+			}
+			if i < len(nodes)-1 {
+				e = nodes[i+1].Kind == ast.KindText && strings.HasPrefix(nodes[i+1].Value.(ast.Text).Text, "/")
+			}
+			if err := checkDotDot(c, s, e); err != nil {
+				return err
+			}
+		}
+
+	case ast.KindAnyOf:
+		for _, c := range tree.Children {
+			if err := checkDotDot(c, start, end); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
+
+func unhex(c byte) byte {
+	switch {
+	case c <= '9':
+		return c - '0'
+	case c <= 'F':
+		return c - 'A' + 10
+	default:
+		return c - 'a' + 10
+	}
+}
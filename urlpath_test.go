@@ -0,0 +1,66 @@
+package glob
+
+import "testing"
+
+func TestWithURLPath(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		decode  bool
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: "/static/**",
+			match:   []string{"/static/a", "/static/a/b.css", "/static/%41"},
+			miss:    []string{"/static/../etc/passwd", "/static/a/..", "/other/a"},
+		},
+		{
+			pattern: "/static/**",
+			decode:  true,
+			match:   []string{"/static/a", "/static/a%20b", "/static/..a"},
+			miss:    []string{"/static/%2e%2e/etc", "/static/%2", "/static/%zz"},
+		},
+		{
+			pattern: "/users/*/profile",
+			decode:  true,
+			match:   []string{"/users/j%C3%B6rg/profile", "/users/a%2Fb/profile"},
+			miss:    []string{"/users/a/b/profile", "/users/%2e%2e/profile"},
+		},
+		{
+			pattern: "/a%20b",
+			decode:  true,
+			match:   []string{"/a%2520b"},
+			miss:    []string{"/a%20b", "/a b"},
+		},
+	} {
+		g, err := CompileWith(test.pattern, WithURLPath(test.decode))
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+	for _, pattern := range []string{"/a/../b", "../*", "/*/..", "/{..,a}/b"} {
+		if _, err := CompileWith(pattern, WithURLPath(false)); err == nil {
+			t.Errorf("CompileWith(%q): expected error", pattern)
+		}
+	}
+	for _, pattern := range []string{"/a..b", "/*../b", "/a/..*"} {
+		if _, err := CompileWith(pattern, WithURLPath(false)); err != nil {
+			t.Errorf("CompileWith(%q): unexpected error: %s", pattern, err)
+		}
+	}
+	g := MustCompileWith("/u/*", WithURLPath(true)).(Analyzer)
+	if act := g.ReplaceAll("/u/a%20b", "$1"); act != "a b" {
+		t.Errorf("ReplaceAll() = %q; want %q", act, "a b")
+	}
+}
@@ -0,0 +1,35 @@
+package runes
+
+// Class returns the set of the POSIX character class with the name, like
+// "alpha" of `[:alpha:]`, or false if there is no such class. The classes
+// hold ASCII runes only, as in the C locale.
+func Class(name string) (Set, bool) {
+	s, ok := posixClasses[name]
+	return s, ok
+}
+
+// OfPairs creates set containing the ranges given as pairs of their lo and
+// hi runes.
+func OfPairs(pairs ...rune) Set {
+	rs := make([]Range, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		rs = append(rs, Range{pairs[i], pairs[i+1]})
+	}
+	return NewSet(rs...)
+}
+
+// posixClasses are the ASCII sets of POSIX character classes.
+var posixClasses = map[string]Set{
+	"alnum":  OfPairs('0', '9', 'A', 'Z', 'a', 'z'),
+	"alpha":  OfPairs('A', 'Z', 'a', 'z'),
+	"blank":  OfPairs('\t', '\t', ' ', ' '),
+	"cntrl":  OfPairs(0, 31, 127, 127),
+	"digit":  OfPairs('0', '9'),
+	"graph":  OfPairs('!', '~'),
+	"lower":  OfPairs('a', 'z'),
+	"print":  OfPairs(' ', '~'),
+	"punct":  OfPairs('!', '/', ':', '@', '[', '`', '{', '~'),
+	"space":  OfPairs('\t', '\r', ' ', ' '),
+	"upper":  OfPairs('A', 'Z'),
+	"xdigit": OfPairs('0', '9', 'A', 'F', 'a', 'f'),
+}
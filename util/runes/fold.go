@@ -0,0 +1,180 @@
+package runes
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Runes equal under Unicode simple case folding form orbits, which
+// unicode.SimpleFold walks. Fold maps every rune of an orbit to the least
+// one, so that strings are compared case-insensitively by comparing folded
+// runes. Patterns are folded once, when they are compiled, and runes of
+// matched strings are folded one by one as they are compared.
+
+// foldASCII is Fold of ASCII runes, which are looked up instead of walking
+// their orbits. Folds of ASCII runes are ASCII as well.
+var foldASCII = func() (t [utf8.RuneSelf]byte) {
+	for i := range t {
+		t[i] = byte(foldOrbit(rune(i)))
+	}
+	return t
+}()
+
+// Fold returns the least rune of the orbit of r under simple case folding.
+func Fold(r rune) rune {
+	if 0 <= r && r < utf8.RuneSelf {
+		return rune(foldASCII[r])
+	}
+	return foldOrbit(r)
+}
+
+func foldOrbit(r rune) rune {
+	min := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		if f < min {
+			min = f
+		}
+	}
+	return min
+}
+
+// FoldString returns s with every rune replaced by Fold of it. Bytes which
+// are not valid UTF-8 are kept as they are.
+func FoldString(s string) string {
+	return string(AppendFold(make([]byte, 0, len(s)), s))
+}
+
+// AppendFold appends s folded as FoldString does to buf, and returns the
+// extended buffer.
+func AppendFold(buf []byte, s string) []byte {
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			buf = append(buf, foldASCII[c])
+			i++
+			continue
+		}
+		r, w := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && w == 1 {
+			buf = append(buf, s[i])
+		} else {
+			var b [utf8.UTFMax]byte
+			n := utf8.EncodeRune(b[:], Fold(r))
+			buf = append(buf, b[:n]...)
+		}
+		i += w
+	}
+	return buf
+}
+
+// PrefixFold returns the length in bytes of the prefix of s which is equal
+// to folded under simple case folding, or -1 if there is none. The folded
+// string must be returned by FoldString. Runes of s are folded as they are
+// compared, and bytes which are not valid UTF-8 only match themselves.
+func PrefixFold(s, folded string) int {
+	i, j := 0, 0
+	for j < len(folded) {
+		if i == len(s) {
+			return -1
+		}
+		if c, d := s[i], folded[j]; c < utf8.RuneSelf && d < utf8.RuneSelf {
+			if foldASCII[c] != d {
+				return -1
+			}
+			i++
+			j++
+			continue
+		}
+		r, w := utf8.DecodeRuneInString(s[i:])
+		f, v := utf8.DecodeRuneInString(folded[j:])
+		if r == utf8.RuneError && w == 1 || f == utf8.RuneError && v == 1 {
+			if w != v || s[i] != folded[j] {
+				return -1
+			}
+		} else if Fold(r) != f {
+			return -1
+		}
+		i += w
+		j += v
+	}
+	return i
+}
+
+// SuffixFold returns the length in bytes of the suffix of s which is equal
+// to folded, as PrefixFold compares them, or -1 if there is none.
+func SuffixFold(s, folded string) int {
+	i, j := len(s), len(folded)
+	for j > 0 {
+		if i == 0 {
+			return -1
+		}
+		if c, d := s[i-1], folded[j-1]; c < utf8.RuneSelf && d < utf8.RuneSelf {
+			if foldASCII[c] != d {
+				return -1
+			}
+			i--
+			j--
+			continue
+		}
+		r, w := utf8.DecodeLastRuneInString(s[:i])
+		f, v := utf8.DecodeLastRuneInString(folded[:j])
+		if r == utf8.RuneError && w == 1 || f == utf8.RuneError && v == 1 {
+			if w != v || s[i-1] != folded[j-1] {
+				return -1
+			}
+		} else if Fold(r) != f {
+			return -1
+		}
+		i -= w
+		j -= v
+	}
+	return len(s) - i
+}
+
+// EqualFold reports whether s is equal to folded under simple case
+// folding, as PrefixFold compares them.
+func EqualFold(s, folded string) bool {
+	return PrefixFold(s, folded) == len(s)
+}
+
+// IndexFold returns the index of the first instance of folded in s under
+// simple case folding, as PrefixFold compares them, and the length of the
+// instance in bytes, or -1 and 0 if there is none.
+func IndexFold(s, folded string) (int, int) {
+	if folded == "" {
+		return 0, 0
+	}
+	first := folded[0]
+	for i := 0; i < len(s); {
+		c := s[i]
+		// Runes folding to ASCII ones are mostly ASCII themselves, so
+		// other ASCII runes are skipped at once.
+		if c < utf8.RuneSelf {
+			if foldASCII[c] == first {
+				if n := PrefixFold(s[i:], folded); n != -1 {
+					return i, n
+				}
+			}
+			i++
+			continue
+		}
+		if n := PrefixFold(s[i:], folded); n != -1 {
+			return i, n
+		}
+		_, w := utf8.DecodeRuneInString(s[i:])
+		i += w
+	}
+	return -1, 0
+}
+
+// Fold returns the set extended with all cases of its runes.
+func (s Set) Fold() Set {
+	var add []rune
+	for _, r := range s {
+		for c := r.Lo; c <= r.Hi; c++ {
+			for f := unicode.SimpleFold(c); f != c; f = unicode.SimpleFold(f) {
+				add = append(add, f)
+			}
+		}
+	}
+	return s.Union(Of(add...))
+}
@@ -0,0 +1,110 @@
+package runes
+
+import (
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	for id, test := range []struct {
+		in, exp rune
+	}{
+		{'a', 'A'},
+		{'A', 'A'},
+		{'1', '1'},
+		{'k', 'K'},
+		{'\u212a', 'K'}, // Kelvin sign
+		{'ſ', 'S'},
+		{'ж', 'Ж'},
+		{'ß', 'ß'},
+	} {
+		if act := Fold(test.in); act != test.exp {
+			t.Errorf("#%d Fold(%q) = %q; want %q", id, test.in, act, test.exp)
+		}
+	}
+}
+
+func TestFoldString(t *testing.T) {
+	for id, test := range []struct {
+		in, exp string
+	}{
+		{"", ""},
+		{"abc.TXT", "ABC.TXT"},
+		{"\u212aelvin", "KELVIN"},
+		{"ſtraße", "STRAßE"},
+		{"a\xffb", "A\xffB"},
+	} {
+		if act := FoldString(test.in); act != test.exp {
+			t.Errorf("#%d FoldString(%q) = %q; want %q", id, test.in, act, test.exp)
+		}
+	}
+}
+
+func TestPrefixFold(t *testing.T) {
+	for id, test := range []struct {
+		s, folded string
+		exp       int
+	}{
+		{"", "", 0},
+		{"abc", "", 0},
+		{"abc", "AB", 2},
+		{"aBc", "ABC", 3},
+		{"ab", "ABC", -1},
+		{"abd", "ABC", -1},
+		{"\u212ay", "KY", 4},
+		{"ſs", "SS", 3},
+		{"\xffa", "\xffA", 2},
+		{"\xffa", "�A", -1},
+		{"�a", "\xffA", -1},
+	} {
+		if act := PrefixFold(test.s, test.folded); act != test.exp {
+			t.Errorf("#%d PrefixFold(%q, %q) = %d; want %d", id, test.s, test.folded, act, test.exp)
+		}
+	}
+}
+
+func TestSuffixFold(t *testing.T) {
+	for id, test := range []struct {
+		s, folded string
+		exp       int
+	}{
+		{"", "", 0},
+		{"abc", "", 0},
+		{"abc", "BC", 2},
+		{"aBc", "ABC", 3},
+		{"bc", "ABC", -1},
+		{"abd", "BC", -1},
+		{"x\u212a", "K", 3},
+		{"sſ", "SS", 3},
+		{"a\xff", "A\xff", 2},
+		{"a\xff", "A�", -1},
+	} {
+		if act := SuffixFold(test.s, test.folded); act != test.exp {
+			t.Errorf("#%d SuffixFold(%q, %q) = %d; want %d", id, test.s, test.folded, act, test.exp)
+		}
+	}
+}
+
+func TestIndexFold(t *testing.T) {
+	for id, test := range []struct {
+		s, folded string
+		index, n  int
+	}{
+		{"abc", "", 0, 0},
+		{"abc", "BC", 1, 2},
+		{"xx\u212aEY", "KEY", 2, 5},
+		{"ſ", "S", 0, 2},
+		{"abc", "CD", -1, 0},
+		{"a\xffb", "\xffB", 1, 2},
+	} {
+		index, n := IndexFold(test.s, test.folded)
+		if index != test.index || n != test.n {
+			t.Errorf("#%d IndexFold(%q, %q) = %d, %d; want %d, %d", id, test.s, test.folded, index, n, test.index, test.n)
+		}
+	}
+}
+
+func TestSetFold(t *testing.T) {
+	if act, exp := Of('a', 'k').Fold(), Of('A', 'K', 'a', 'k', '\u212a'); !act.Equal(exp) {
+		t.Errorf("Fold() = %v; want %v", act, exp)
+	}
+}
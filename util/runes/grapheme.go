@@ -0,0 +1,126 @@
+package runes
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// GraphemeLen returns the length in bytes of the grapheme cluster s starts
+// with, that is, of what is seen as a single character: a rune followed by
+// combining marks, variation selectors and emoji modifiers, emoji joined by
+// zero width joiner, a pair of regional indicators forming a flag, or a
+// Hangul syllable written with jamo. It approximates the extended grapheme
+// clusters of Unicode Standard Annex #29 with the tables of the unicode
+// package, which lack the property of pictographic runes and the prepended
+// ones. Bytes which are not valid UTF-8 are clusters of their own.
+func GraphemeLen(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	if c := s[0]; c < utf8.RuneSelf {
+		switch {
+		case c == '\r' && len(s) > 1 && s[1] == '\n':
+			return 2
+		case c < ' ' || c == 0x7f:
+			return 1
+		case len(s) == 1 || s[1] < utf8.RuneSelf:
+			// Runes following ASCII ones extend them only if they are
+			// not ASCII.
+			return 1
+		}
+	}
+	first, i := utf8.DecodeRuneInString(s)
+	if first == utf8.RuneError && i == 1 || unicode.IsControl(first) {
+		return i
+	}
+	prev, pairs := first, 0
+	if isRegional(first) {
+		pairs = 1
+	}
+	for i < len(s) {
+		r, w := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && w == 1:
+			return i
+		case isExtend(r), r == zwj:
+		case prev == zwj && isPictographic(r) && isPictographic(first):
+		case pairs == 1 && isRegional(r):
+			pairs++
+		case joinsHangul(prev, r):
+		default:
+			return i
+		}
+		prev = r
+		i += w
+	}
+	return i
+}
+
+// zwj is the zero width joiner, which joins emoji into a single one.
+const zwj = '\u200d'
+
+// Extends reports whether r extends the cluster it follows, like combining
+// marks and zero width joiner do, so that the cluster starting with it is a
+// part of the preceding one, unless it is at the beginning of the text.
+func Extends(r rune) bool {
+	return isExtend(r) || r == zwj
+}
+
+// isExtend reports whether r extends the cluster it follows.
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc, unicode.Other_Grapheme_Extend) ||
+		0x1f3fb <= r && r <= 0x1f3ff // emoji modifiers
+}
+
+// isPictographic approximates the Extended_Pictographic property with the
+// symbols of the blocks of emoji.
+func isPictographic(r rune) bool {
+	return unicode.Is(unicode.So, r) || 0x1f000 <= r && r <= 0x1faff
+}
+
+func isRegional(r rune) bool {
+	return 0x1f1e6 <= r && r <= 0x1f1ff
+}
+
+// Kinds of Hangul jamo and syllables.
+const (
+	hangulNone = iota
+	hangulL
+	hangulV
+	hangulT
+	hangulLV
+	hangulLVT
+)
+
+func hangul(r rune) int {
+	switch {
+	case 0x1100 <= r && r <= 0x115f, 0xa960 <= r && r <= 0xa97c:
+		return hangulL
+	case 0x1160 <= r && r <= 0x11a7, 0xd7b0 <= r && r <= 0xd7c6:
+		return hangulV
+	case 0x11a8 <= r && r <= 0x11ff, 0xd7cb <= r && r <= 0xd7fb:
+		return hangulT
+	case 0xac00 <= r && r <= 0xd7a3:
+		if (r-0xac00)%28 == 0 {
+			return hangulLV
+		}
+		return hangulLVT
+	}
+	return hangulNone
+}
+
+// joinsHangul reports whether r continues the Hangul syllable ending with
+// prev.
+func joinsHangul(prev, r rune) bool {
+	switch hangul(prev) {
+	case hangulL:
+		k := hangul(r)
+		return k == hangulL || k == hangulV || k == hangulLV || k == hangulLVT
+	case hangulV, hangulLV:
+		k := hangul(r)
+		return k == hangulV || k == hangulT
+	case hangulT, hangulLVT:
+		return hangul(r) == hangulT
+	}
+	return false
+}
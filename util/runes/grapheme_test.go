@@ -0,0 +1,31 @@
+package runes
+
+import "testing"
+
+func TestGraphemeLen(t *testing.T) {
+	for id, test := range []struct {
+		s   string
+		exp int
+	}{
+		{"", 0},
+		{"ab", 1},
+		{"\r\nx", 2},
+		{"\n\u0301", 1},
+		{"e\u0301x", 3},
+		{"e\u0301\u0323", 5},
+		{"\u00e9", 2},
+		{"\U0001f44d\U0001f3fdx", 8},
+		{"\u2764\ufe0f", 6},
+		{"\U0001f468\u200d\U0001f469\u200d\U0001f467", 18},
+		{"a\u200db", 4},
+		{"\U0001f1fa\U0001f1f8\U0001f1e9", 8},
+		{"\u1112\u1161\u11ab", 9},
+		{"\ud55c\u11a8", 6},
+		{"\u0915\u093f", 6},
+		{"\xff\u0301", 1},
+	} {
+		if act := GraphemeLen(test.s); act != test.exp {
+			t.Errorf("#%d GraphemeLen(%q) = %d; want %d", id, test.s, act, test.exp)
+		}
+	}
+}
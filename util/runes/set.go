@@ -0,0 +1,171 @@
+package runes
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Range represents inclusive range of runes.
+type Range struct {
+	Lo, Hi rune
+}
+
+// Set represents set of runes as sorted list of disjoint and non-adjacent
+// ranges.
+type Set []Range
+
+// All is the set of all runes.
+var All = Set{{0, utf8.MaxRune}}
+
+// NewSet creates set containing given ranges.
+func NewSet(rs ...Range) Set {
+	s := make(Set, 0, len(rs))
+	for _, r := range rs {
+		if r.Lo <= r.Hi {
+			s = append(s, r)
+		}
+	}
+	return s.normalize()
+}
+
+// Of creates set containing given runes.
+func Of(rs ...rune) Set {
+	s := make(Set, len(rs))
+	for i, r := range rs {
+		s[i] = Range{r, r}
+	}
+	return s.normalize()
+}
+
+type byLo Set
+
+func (s byLo) Len() int           { return len(s) }
+func (s byLo) Less(i, j int) bool { return s[i].Lo < s[j].Lo }
+func (s byLo) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func (s Set) normalize() Set {
+	if len(s) <= 1 {
+		return s
+	}
+	sort.Sort(byLo(s))
+
+	out := s[:1]
+	for _, r := range s[1:] {
+		last := &out[len(out)-1]
+		if r.Lo <= last.Hi+1 {
+			if r.Hi > last.Hi {
+				last.Hi = r.Hi
+			}
+			continue
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// Contains reports whether r is in s.
+func (s Set) Contains(r rune) bool {
+	i := sort.Search(len(s), func(i int) bool {
+		return s[i].Hi >= r
+	})
+	return i < len(s) && s[i].Lo <= r
+}
+
+// Empty reports whether s contains no runes.
+func (s Set) Empty() bool {
+	return len(s) == 0
+}
+
+// Single returns the only rune of s and true, if s contains exactly one rune.
+func (s Set) Single() (rune, bool) {
+	if len(s) == 1 && s[0].Lo == s[0].Hi {
+		return s[0].Lo, true
+	}
+	return 0, false
+}
+
+// Complement returns set of runes that are not in s.
+func (s Set) Complement() Set {
+	var out Set
+	next := rune(0)
+	for _, r := range s {
+		if r.Lo > next {
+			out = append(out, Range{next, r.Lo - 1})
+		}
+		next = r.Hi + 1
+	}
+	if next <= utf8.MaxRune {
+		out = append(out, Range{next, utf8.MaxRune})
+	}
+	return out
+}
+
+// Union returns set of runes that are in s or in t.
+func (s Set) Union(t Set) Set {
+	out := make(Set, 0, len(s)+len(t))
+	out = append(out, s...)
+	out = append(out, t...)
+	return out.normalize()
+}
+
+// Intersect returns set of runes that are both in s and in t.
+func (s Set) Intersect(t Set) Set {
+	var out Set
+	for i, j := 0, 0; i < len(s) && j < len(t); {
+		lo, hi := s[i].Lo, s[i].Hi
+		if t[j].Lo > lo {
+			lo = t[j].Lo
+		}
+		if t[j].Hi < hi {
+			hi = t[j].Hi
+		}
+		if lo <= hi {
+			out = append(out, Range{lo, hi})
+		}
+		if s[i].Hi < t[j].Hi {
+			i++
+		} else {
+			j++
+		}
+	}
+	return out
+}
+
+// Subtract returns set of runes that are in s but not in t. Unlike
+// Complement, it keeps values of s above utf8.MaxRune, which some callers
+// use as runes of their own.
+func (s Set) Subtract(t Set) Set {
+	var out Set
+	j := 0
+	for _, r := range s {
+		for j < len(t) && t[j].Hi < r.Lo {
+			j++
+		}
+		lo := r.Lo
+		for k := j; k < len(t) && t[k].Lo <= r.Hi; k++ {
+			if t[k].Lo > lo {
+				out = append(out, Range{lo, t[k].Lo - 1})
+			}
+			if t[k].Hi >= lo {
+				lo = t[k].Hi + 1
+			}
+		}
+		if lo <= r.Hi {
+			out = append(out, Range{lo, r.Hi})
+		}
+	}
+	return out
+}
+
+// Equal reports whether s and t contain the same runes.
+func (s Set) Equal(t Set) bool {
+	if len(s) != len(t) {
+		return false
+	}
+	for i := range s {
+		if s[i] != t[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package runes
+
+import (
+	"reflect"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNewSet(t *testing.T) {
+	for id, test := range []struct {
+		in  []Range
+		exp Set
+	}{
+		{
+			in:  nil,
+			exp: Set{},
+		},
+		{
+			in:  []Range{{'c', 'd'}, {'a', 'b'}},
+			exp: Set{{'a', 'd'}},
+		},
+		{
+			in:  []Range{{'x', 'z'}, {'a', 'c'}, {'b', 'f'}, {'z', 'a'}},
+			exp: Set{{'a', 'f'}, {'x', 'z'}},
+		},
+	} {
+		if act := NewSet(test.in...); !reflect.DeepEqual(act, test.exp) {
+			t.Errorf("#%d NewSet(%v) = %v; want %v", id, test.in, act, test.exp)
+		}
+	}
+}
+
+func TestClass(t *testing.T) {
+	set, ok := Class("xdigit")
+	if exp := OfPairs('0', '9', 'A', 'F', 'a', 'f'); !ok || !set.Equal(exp) {
+		t.Errorf("Class(%q) = %v, %t; want %v", "xdigit", set, ok, exp)
+	}
+	if _, ok := Class("unknown"); ok {
+		t.Errorf("unexpected class %q", "unknown")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := NewSet(Range{'a', 'f'}, Range{'x', 'z'})
+	b := Of('c', 'd', 'y', '0')
+
+	if act, exp := a.Union(b), (Set{{'0', '0'}, {'a', 'f'}, {'x', 'z'}}); !act.Equal(exp) {
+		t.Errorf("Union() = %v; want %v", act, exp)
+	}
+	if act, exp := a.Intersect(b), (Set{{'c', 'd'}, {'y', 'y'}}); !act.Equal(exp) {
+		t.Errorf("Intersect() = %v; want %v", act, exp)
+	}
+	if act, exp := a.Subtract(b), (Set{{'a', 'b'}, {'e', 'f'}, {'x', 'x'}, {'z', 'z'}}); !act.Equal(exp) {
+		t.Errorf("Subtract() = %v; want %v", act, exp)
+	}
+	if act, exp := Of(utf8.MaxRune+1, 'a').Subtract(All), (Set{{utf8.MaxRune + 1, utf8.MaxRune + 1}}); !act.Equal(exp) {
+		t.Errorf("Subtract() = %v; want %v", act, exp)
+	}
+	if act, exp := a.Complement(), (Set{{0, 'a' - 1}, {'g', 'x' - 1}, {'z' + 1, utf8.MaxRune}}); !act.Equal(exp) {
+		t.Errorf("Complement() = %v; want %v", act, exp)
+	}
+	if act := All.Complement(); !act.Empty() {
+		t.Errorf("All.Complement() = %v; want empty set", act)
+	}
+	for _, test := range []struct {
+		r   rune
+		exp bool
+	}{
+		{'a', true},
+		{'c', true},
+		{'f', true},
+		{'g', false},
+		{'y', true},
+		{'0', false},
+		{utf8.MaxRune, false},
+	} {
+		if act := a.Contains(test.r); act != test.exp {
+			t.Errorf("Contains(%q) = %t; want %t", test.r, act, test.exp)
+		}
+	}
+}
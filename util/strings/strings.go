@@ -5,35 +5,40 @@ import (
 	"unicode/utf8"
 )
 
+// IndexAnyRunes returns the index of the first instance of any of the runes
+// in s, or -1 if there is none. The common case of a single ASCII rune, like
+// the path separator, is scanned bytewise with strings.IndexByte.
 func IndexAnyRunes(s string, rs []rune) int {
+	if len(rs) == 1 && 0 <= rs[0] && rs[0] < utf8.RuneSelf {
+		return strings.IndexByte(s, byte(rs[0]))
+	}
+	index := -1
 	for _, r := range rs {
-		if i := strings.IndexRune(s, r); i != -1 {
-			return i
+		sub := s
+		if index != -1 {
+			sub = s[:index]
+		}
+		if i := strings.IndexRune(sub, r); i != -1 {
+			index = i
 		}
 	}
-
-	return -1
+	return index
 }
 
+// LastIndexAnyRunes returns the index of the last instance of any of the
+// runes in s, or -1 if there is none.
 func LastIndexAnyRunes(s string, rs []rune) int {
+	index := -1
 	for _, r := range rs {
-		i := -1
+		var i int
 		if 0 <= r && r < utf8.RuneSelf {
-			i = strings.LastIndexByte(s, byte(r))
+			i = strings.LastIndexByte(s[index+1:], byte(r))
 		} else {
-			sub := s
-			for len(sub) > 0 {
-				j := strings.IndexRune(s, r)
-				if j == -1 {
-					break
-				}
-				i = j
-				sub = sub[i+1:]
-			}
+			i = strings.LastIndex(s[index+1:], string(r))
 		}
 		if i != -1 {
-			return i
+			index += 1 + i
 		}
 	}
-	return -1
+	return index
 }
@@ -0,0 +1,63 @@
+package strings
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIndexAnyRunes(t *testing.T) {
+	for id, test := range []struct {
+		s     string
+		runes []rune
+		index int
+	}{
+		{"", []rune{'/'}, -1},
+		{"a/b/c", []rune{'/'}, 1},
+		{"a/b.c", []rune{'.', '/'}, 1},
+		{"aäbäc", []rune{'ä'}, 1},
+		{"a.bäc", []rune{'ä', '.'}, 1},
+		{"abc", []rune{'.', '/'}, -1},
+		{"abc", nil, -1},
+	} {
+		if act := IndexAnyRunes(test.s, test.runes); act != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, act)
+		}
+	}
+}
+
+func TestLastIndexAnyRunes(t *testing.T) {
+	for id, test := range []struct {
+		s     string
+		runes []rune
+		index int
+	}{
+		{"", []rune{'/'}, -1},
+		{"a/b/c", []rune{'/'}, 3},
+		{"a.b/c", []rune{'/', '.'}, 3},
+		{"aäbäc", []rune{'ä'}, 4},
+		{"äbäc.d", []rune{'.', 'ä'}, 6},
+		{"abc", []rune{'.', '/'}, -1},
+	} {
+		if act := LastIndexAnyRunes(test.s, test.runes); act != test.index {
+			t.Errorf("#%d unexpected index: exp: %d, act: %d", id, test.index, act)
+		}
+	}
+}
+
+var benchPath = strings.Repeat("a", 256) + "/b"
+
+func BenchmarkIndexAnyRunesASCII(b *testing.B) {
+	rs := []rune{'/'}
+	b.SetBytes(int64(len(benchPath)))
+	for i := 0; i < b.N; i++ {
+		_ = IndexAnyRunes(benchPath, rs)
+	}
+}
+
+func BenchmarkIndexAnyRunesMany(b *testing.B) {
+	rs := []rune{'.', '/'}
+	b.SetBytes(int64(len(benchPath)))
+	for i := 0; i < b.N; i++ {
+		_ = IndexAnyRunes(benchPath, rs)
+	}
+}
@@ -0,0 +1,54 @@
+//go:build go1.16
+// +build go1.16
+
+package glob
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Walk walks the file tree rooted at root, calling fn for each file or
+// directory whose path relative to root matches the pattern. The pattern is
+// compiled by CompileWith with '/' as the separator and given options, and
+// is matched against slash-separated relative paths on all systems.
+//
+// Only the directory under the literal prefix of the pattern is walked,
+// and directories which could not contain matching paths are skipped, so
+// that `src/**.go` does not walk anything besides the src directory. As with
+// filepath.WalkDir, fn receives paths joined with root, and could return
+// filepath.SkipDir to skip a matched directory. Errors of reading
+// directories which could contain matching paths are passed to fn as well.
+func Walk(root, pattern string, fn fs.WalkDirFunc, opts ...Option) error {
+	g, err := CompileWith(pattern, append([]Option{WithSeparators('/')}, opts...)...)
+	if err != nil {
+		return err
+	}
+	start := filepath.Join(root, filepath.FromSlash(walkRoot(g)))
+	if start != filepath.Clean(root) {
+		if _, err := os.Lstat(start); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+	}
+	return filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		rel, rerr := filepath.Rel(root, p)
+		if rerr != nil {
+			return rerr
+		}
+		rel = filepath.ToSlash(rel)
+		if err != nil {
+			return fn(p, d, err)
+		}
+		if rel != "." && g.Match(rel) {
+			if err := fn(p, d, nil); err != nil {
+				return err
+			}
+		}
+		if d.IsDir() && rel != "." && !g.(Analyzer).CouldMatchPrefix(rel+"/") {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
@@ -0,0 +1,83 @@
+package glob
+
+import (
+	"strings"
+
+	"github.com/gobwas/glob/syntax/ast"
+	"github.com/gobwas/glob/util/runes"
+)
+
+// WithWindowsPaths configures matching of Windows paths: '/' and '\' are
+// both separators and are interchangeable, so pattern written with one of
+// them matches paths written with the other. Matching is case-insensitive,
+// and backslash is not an escape character. Hence drive letters match in
+// either case, and UNC prefixes like `\\server\share` could be written as
+// `//server/share` as well. It is meant to be used with DialectDefault and
+// DialectFnmatch.
+func WithWindowsPaths() Option {
+	return func(o *options) {
+		o.separators = []rune{'/', '\\'}
+		o.caseFold = true
+		o.noEscape = true
+		o.windows = true
+	}
+}
+
+// windowsSeparators are the runes separating Windows path elements.
+var windowsSeparators = runes.Of('/', '\\')
+
+// windowsTree returns a copy of the tree, in which every separator of the
+// texts and character classes is replaced with both of them.
+func windowsTree(tree *ast.Node) *ast.Node {
+	switch tree.Kind {
+	case ast.KindText:
+		n := ast.NewNode(ast.KindPattern, nil)
+		s := tree.Value.(ast.Text).Text
+		for {
+			i := strings.IndexAny(s, `/\`)
+			if i == -1 {
+				break
+			}
+			if i > 0 {
+				ast.Insert(n, ast.NewNode(ast.KindText, ast.Text{Text: s[:i]}))
+			}
+			ast.Insert(n, setNode(windowsSeparators))
+			s = s[i+1:]
+		}
+		if s != "" {
+			ast.Insert(n, ast.NewNode(ast.KindText, ast.Text{Text: s}))
+		}
+		return n
+
+	case ast.KindList:
+		l := tree.Value.(ast.List)
+		return windowsClass(runes.Of([]rune(l.Chars)...), l.Not)
+
+	case ast.KindRange:
+		r := tree.Value.(ast.Range)
+		return windowsClass(runes.NewSet(runes.Range{Lo: r.Lo, Hi: r.Hi}), r.Not)
+
+	case ast.KindClass:
+		c := tree.Value.(ast.Class)
+		return windowsClass(c.Set(), c.Not)
+
+	default:
+		n := ast.NewNode(tree.Kind, tree.Value)
+		for _, c := range tree.Children {
+			ast.Insert(n, windowsTree(c))
+		}
+		return n
+	}
+}
+
+// windowsClass returns node for character class of the set, which contains
+// both separators if it contains any of them.
+func windowsClass(set runes.Set, not bool) *ast.Node {
+	if !set.Intersect(windowsSeparators).Empty() {
+		set = set.Union(windowsSeparators)
+	}
+	if not {
+		set = set.Complement()
+	}
+	return classOrNothing(set)
+}
@@ -0,0 +1,60 @@
+package glob
+
+import "testing"
+
+func TestWithWindowsPaths(t *testing.T) {
+	for id, test := range []struct {
+		pattern string
+		opts    []Option
+		match   []string
+		miss    []string
+	}{
+		{
+			pattern: `C:\Users\*\*.txt`,
+			match:   []string{`C:\Users\bob\a.txt`, `c:/users/bob/A.TXT`, `C:\Users/bob\a.txt`},
+			miss:    []string{`C:\Users\bob\x\a.txt`, `D:\Users\bob\a.txt`, `C:\Users\a.txt`},
+		},
+		{
+			pattern: `\\server\share\**`,
+			match:   []string{`\\server\share\a\b`, `//SERVER/share/a`},
+			miss:    []string{`\\other\share\a`, `\server\share\a`},
+		},
+		{
+			pattern: `*.go`,
+			match:   []string{"a.go", "A.GO"},
+			miss:    []string{`a\b.go`, "a/b.go"},
+		},
+		{
+			pattern: `a[/]b`,
+			match:   []string{"a/b", `a\b`},
+			miss:    []string{"a.b"},
+		},
+		{
+			pattern: `a?b`,
+			match:   []string{"a.b"},
+			miss:    []string{"a/b", `a\b`},
+		},
+		{
+			pattern: `a[!/]b`,
+			opts:    FnmatchOptions(0),
+			match:   []string{"a.b"},
+			miss:    []string{"a/b", `a\b`},
+		},
+	} {
+		g, err := CompileWith(test.pattern, append(test.opts, WithWindowsPaths())...)
+		if err != nil {
+			t.Errorf("#%d unexpected error: %s", id, err)
+			continue
+		}
+		for _, s := range test.match {
+			if !g.Match(s) {
+				t.Errorf("#%d %q should match %q", id, test.pattern, s)
+			}
+		}
+		for _, s := range test.miss {
+			if g.Match(s) {
+				t.Errorf("#%d %q should not match %q", id, test.pattern, s)
+			}
+		}
+	}
+}